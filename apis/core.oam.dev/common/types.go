@@ -243,6 +243,32 @@ type ApplicationComponentStatus struct {
 	Message            string                   `json:"message,omitempty"`
 	Traits             []ApplicationTraitStatus `json:"traits,omitempty"`
 	Scopes             []corev1.ObjectReference `json:"scopes,omitempty"`
+	// Replicas records the ready and desired replicas of the workload, only set if the workload has replicas
+	Replicas *WorkloadReplicas `json:"replicas,omitempty"`
+}
+
+// WorkloadReplicas records the replicas of the workload
+type WorkloadReplicas struct {
+	Ready   int32 `json:"ready"`
+	Desired int32 `json:"desired"`
+}
+
+// ApplicationStatusSummary summarizes the health of the application services across clusters
+type ApplicationStatusSummary struct {
+	Clusters          int `json:"clusters"`
+	HealthyClusters   int `json:"healthyClusters"`
+	Components        int `json:"components"`
+	HealthyComponents int `json:"healthyComponents"`
+	// ClusterStatus records the health counts of the components in each cluster
+	ClusterStatus []ClusterStatusSummary `json:"clusterStatus,omitempty"`
+}
+
+// ClusterStatusSummary records the health counts of the components in one cluster
+type ClusterStatusSummary struct {
+	Cluster           string `json:"cluster"`
+	Env               string `json:"env,omitempty"`
+	Components        int    `json:"components"`
+	HealthyComponents int    `json:"healthyComponents"`
 }
 
 // ApplicationTraitStatus records the trait health status
@@ -313,6 +339,9 @@ type AppStatus struct {
 	// Services record the status of the application services
 	Services []ApplicationComponentStatus `json:"services,omitempty"`
 
+	// Summary summarizes the health of the services across clusters
+	Summary *ApplicationStatusSummary `json:"summary,omitempty"`
+
 	// Workflow record the status of workflow
 	Workflow *WorkflowStatus `json:"workflow,omitempty"`
 
@@ -326,6 +355,21 @@ type AppStatus struct {
 	// PolicyStatus records the status of policy
 	// Deprecated This field is only used by EnvBinding Policy which is deprecated.
 	PolicyStatus []PolicyStatus `json:"policy,omitempty"`
+
+	// Drifts record the configuration drift detected on the managed resources, only used when the drift is
+	// configured to be detected only
+	Drifts []ResourceDrift `json:"drifts,omitempty"`
+}
+
+// ResourceDrift records the configuration drift detected on one managed resource
+type ResourceDrift struct {
+	ClusterObjectReference `json:",inline"`
+	// Patch is the JSON patch from the recorded manifest to the live state of the resource
+	Patch string `json:"patch,omitempty"`
+	// Missing indicates the resource has been removed from the cluster
+	Missing bool `json:"missing,omitempty"`
+	// DetectedTime is the time when the drift is detected at first
+	DetectedTime metav1.Time `json:"detectedTime,omitempty"`
 }
 
 // PolicyStatus records the status of policy
@@ -648,16 +692,19 @@ const (
 	RolloutCondition
 	// ReadyCondition indicates whether whole application processing is successful.
 	ReadyCondition
+	// DependencyCondition indicates whether the applications that the application depends on are healthy.
+	DependencyCondition
 )
 
 var conditions = map[ApplicationConditionType]string{
-	ParsedCondition:   "Parsed",
-	RevisionCondition: "Revision",
-	PolicyCondition:   "Policy",
-	RenderCondition:   "Render",
-	WorkflowCondition: "Workflow",
-	RolloutCondition:  "Rollout",
-	ReadyCondition:    "Ready",
+	ParsedCondition:     "Parsed",
+	RevisionCondition:   "Revision",
+	PolicyCondition:     "Policy",
+	RenderCondition:     "Render",
+	WorkflowCondition:   "Workflow",
+	RolloutCondition:    "Rollout",
+	ReadyCondition:      "Ready",
+	DependencyCondition: "Dependency",
 }
 
 // String returns the string corresponding to the condition type.
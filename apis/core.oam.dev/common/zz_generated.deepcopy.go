@@ -59,6 +59,11 @@ func (in *AppStatus) DeepCopyInto(out *AppStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(ApplicationStatusSummary)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Workflow != nil {
 		in, out := &in.Workflow, &out.Workflow
 		*out = new(WorkflowStatus)
@@ -81,6 +86,13 @@ func (in *AppStatus) DeepCopyInto(out *AppStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]ResourceDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppStatus.
@@ -156,6 +168,11 @@ func (in *ApplicationComponentStatus) DeepCopyInto(out *ApplicationComponentStat
 		*out = make([]v1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(WorkloadReplicas)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationComponentStatus.
@@ -168,6 +185,26 @@ func (in *ApplicationComponentStatus) DeepCopy() *ApplicationComponentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationStatusSummary) DeepCopyInto(out *ApplicationStatusSummary) {
+	*out = *in
+	if in.ClusterStatus != nil {
+		in, out := &in.ClusterStatus, &out.ClusterStatus
+		*out = make([]ClusterStatusSummary, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationStatusSummary.
+func (in *ApplicationStatusSummary) DeepCopy() *ApplicationStatusSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationStatusSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationTrait) DeepCopyInto(out *ApplicationTrait) {
 	*out = *in
@@ -299,6 +336,21 @@ func (in *ClusterSelector) DeepCopy() *ClusterSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStatusSummary) DeepCopyInto(out *ClusterStatusSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatusSummary.
+func (in *ClusterStatusSummary) DeepCopy() *ClusterStatusSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStatusSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DefinitionReference) DeepCopyInto(out *DefinitionReference) {
 	*out = *in
@@ -508,6 +560,23 @@ func (in *ReferredObjectList) DeepCopy() *ReferredObjectList {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDrift) DeepCopyInto(out *ResourceDrift) {
+	*out = *in
+	out.ClusterObjectReference = in.ClusterObjectReference
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDrift.
+func (in *ResourceDrift) DeepCopy() *ResourceDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Revision) DeepCopyInto(out *Revision) {
 	*out = *in
@@ -837,6 +906,21 @@ func (in *WorkloadGVK) DeepCopy() *WorkloadGVK {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReplicas) DeepCopyInto(out *WorkloadReplicas) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReplicas.
+func (in *WorkloadReplicas) DeepCopy() *WorkloadReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadTypeDescriptor) DeepCopyInto(out *WorkloadTypeDescriptor) {
 	*out = *in
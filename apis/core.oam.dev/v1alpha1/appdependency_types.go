@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// AppDependencyPolicyType refers to the type of app-dependency policy
+	AppDependencyPolicyType = "app-dependency"
+)
+
+// AppDependencyPolicySpec defines the spec of app-dependency policy
+type AppDependencyPolicySpec struct {
+	// Applications are the applications that the current application depends on
+	Applications []AppDependency `json:"applications"`
+}
+
+// AppDependency refers to an application that the current application depends on
+type AppDependency struct {
+	// Name is the name of the dependency application
+	Name string `json:"name"`
+	// Namespace is the namespace of the dependency application, default to the namespace of the current application.
+	// Only the applications in the same namespace could be depended on, so that one tenant could not block the deletion
+	// of the applications in other namespaces.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// ApplicationQuota limits the resources that applications can dispatch into clusters
+// +kubebuilder:resource:scope=Cluster,categories={oam},shortName=appquota
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="PROJECT",type=string,JSONPath=`.spec.project`
+// +kubebuilder:printcolumn:name="APP",type=string,JSONPath=`.spec.application.name`
+// +kubebuilder:printcolumn:name="APP-NS",type=string,JSONPath=`.spec.application.namespace`
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ApplicationQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationQuotaSpec   `json:"spec,omitempty"`
+	Status ApplicationQuotaStatus `json:"status,omitempty"`
+}
+
+// ApplicationQuotaSpec defines the applications restricted by the quota and the limits
+type ApplicationQuotaSpec struct {
+	// Project selects all the applications belonging to the project, the usage of these applications will be aggregated
+	// +optional
+	Project string `json:"project,omitempty"`
+	// Application selects the single application restricted by the quota
+	// +optional
+	Application *ApplicationQuotaTarget `json:"application,omitempty"`
+	// Hard is the set of limits for the selected applications
+	Hard ApplicationQuotaHard `json:"hard"`
+}
+
+// ApplicationQuotaTarget refers to the application restricted by the quota
+type ApplicationQuotaTarget struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// ApplicationQuotaHard defines the limits of the quota
+type ApplicationQuotaHard struct {
+	// Objects limits the number of objects for each kind, the key is the kind of object, like Deployment
+	// +optional
+	Objects map[string]int64 `json:"objects,omitempty"`
+	// CPU limits the total requested cpu of the workloads
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Memory limits the total requested memory of the workloads
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// Clusters limits the number of clusters that resources are dispatched to
+	// +optional
+	Clusters *int64 `json:"clusters,omitempty"`
+}
+
+// ApplicationQuotaUsage records the resources used by applications
+type ApplicationQuotaUsage struct {
+	// Objects is the number of objects for each kind
+	Objects map[string]int64 `json:"objects,omitempty"`
+	// CPU is the total requested cpu of the workloads
+	CPU resource.Quantity `json:"cpu,omitempty"`
+	// Memory is the total requested memory of the workloads
+	Memory resource.Quantity `json:"memory,omitempty"`
+	// Clusters are the clusters that resources are dispatched to
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// ApplicationQuotaStatus records the usage of the quota
+type ApplicationQuotaStatus struct {
+	// Used is the aggregated usage of all the selected applications
+	Used ApplicationQuotaUsage `json:"used,omitempty"`
+	// Applications records the usage of each selected application
+	Applications []ApplicationUsage `json:"applications,omitempty"`
+}
+
+// ApplicationUsage records the resources used by one application
+type ApplicationUsage struct {
+	Name      string                `json:"name"`
+	Namespace string                `json:"namespace"`
+	Used      ApplicationQuotaUsage `json:"used"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationQuotaList contains a list of ApplicationQuota
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ApplicationQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationQuota `json:"items"`
+}
@@ -30,6 +30,10 @@ type ApplyOncePolicySpec struct {
 	Enable bool `json:"enable"`
 	// +optional
 	Rules []ApplyOncePolicyRule `json:"rules,omitempty"`
+	// DetectOnly if is set, the configuration drift of resources will not be corrected, instead, it will be detected
+	// and reported in the application status, events and metrics
+	// +optional
+	DetectOnly bool `json:"detectOnly,omitempty"`
 }
 
 // ApplyOncePolicyRule defines a single apply-once policy rule
@@ -115,6 +115,12 @@ type EnvBindingSpec struct {
 type PlacementDecision struct {
 	Cluster   string `json:"cluster"`
 	Namespace string `json:"namespace"`
+	// Replicas is the number of replicas assigned to the cluster by the scheduling in topology policy
+	Replicas int `json:"replicas,omitempty"`
+	// ExcludedComponents is the components not to be dispatched to the cluster due to component anti-affinity
+	ExcludedComponents []string `json:"excludedComponents,omitempty"`
+	// Identity is the identity to impersonate in the cluster, given by the topology policy
+	Identity *ClusterIdentity `json:"identity,omitempty"`
 }
 
 // String encode placement decision
@@ -38,6 +38,10 @@ type GarbageCollectPolicySpec struct {
 	// Order defines the order of garbage collect
 	Order GarbageCollectOrder `json:"order,omitempty"`
 
+	// Groups defines the ordered groups of resources to recycle, only works when order is set to `groups`
+	// resources in one group will not be recycled until all resources in the previous groups are gone
+	Groups []GarbageCollectOrderGroup `json:"groups,omitempty"`
+
 	// Rules defines list of rules to control gc strategy at resource level
 	// if one resource is controlled by multiple rules, first rule will be used
 	Rules []GarbageCollectPolicyRule `json:"rules,omitempty"`
@@ -49,8 +53,36 @@ type GarbageCollectOrder string
 const (
 	// OrderDependency is the order of dependency
 	OrderDependency GarbageCollectOrder = "dependency"
+	// OrderGroups is the order of the user defined groups
+	OrderGroups GarbageCollectOrder = "groups"
 )
 
+// GarbageCollectOrderGroup defines a group of resources to be recycled together
+// if both selector and labels are specified, combination logic is AND
+// if none of them is specified, the group matches all resources, an empty selector is the same as no selector
+type GarbageCollectOrderGroup struct {
+	// Name is the name of the group
+	Name string `json:"name,omitempty"`
+	// Selector select the resources in the group
+	Selector *ResourcePolicyRuleSelector `json:"selector,omitempty"`
+	// Labels select the resources in the group by their labels
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Match check if the target resource belongs to the group
+func (in *GarbageCollectOrderGroup) Match(manifest *unstructured.Unstructured) bool {
+	if in.Selector != nil && !in.Selector.isEmpty() && !in.Selector.Match(manifest) {
+		return false
+	}
+	labels := manifest.GetLabels()
+	for k, v := range in.Labels {
+		if val, found := labels[k]; !found || val != v {
+			return false
+		}
+	}
+	return true
+}
+
 // GarbageCollectPolicyRule defines a single garbage-collect policy rule
 type GarbageCollectPolicyRule struct {
 	Selector ResourcePolicyRuleSelector `json:"selector"`
@@ -110,6 +142,12 @@ func (in *ResourcePolicyRuleSelector) Match(manifest *unstructured.Unstructured)
 	return hasMatched
 }
 
+// isEmpty check if no condition is specified in the selector
+func (in *ResourcePolicyRuleSelector) isEmpty() bool {
+	return len(in.CompNames) == 0 && len(in.CompTypes) == 0 && len(in.OAMResourceTypes) == 0 &&
+		len(in.TraitTypes) == 0 && len(in.ResourceTypes) == 0 && len(in.ResourceNames) == 0
+}
+
 // GarbageCollectStrategy the strategy for target resource to recycle
 type GarbageCollectStrategy string
 
@@ -123,6 +161,17 @@ const (
 	GarbageCollectStrategyOnAppUpdate GarbageCollectStrategy = "onAppUpdate"
 )
 
+// FindOrderGroup find the index of the first order group that the target resource belongs to
+// if no group matches, the number of groups will be returned, which means the resource will be recycled at last
+func (in GarbageCollectPolicySpec) FindOrderGroup(manifest *unstructured.Unstructured) int {
+	for i := range in.Groups {
+		if in.Groups[i].Match(manifest) {
+			return i
+		}
+	}
+	return len(in.Groups)
+}
+
 // FindStrategy find gc strategy for target resource
 func (in GarbageCollectPolicySpec) FindStrategy(manifest *unstructured.Unstructured) *GarbageCollectStrategy {
 	for _, rule := range in.Rules {
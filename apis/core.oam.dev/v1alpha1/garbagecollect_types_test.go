@@ -135,3 +135,64 @@ func TestGarbageCollectPolicySpec_FindStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestGarbageCollectPolicySpec_FindOrderGroup(t *testing.T) {
+	newManifest := func(kind string, labels map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":     kind,
+			"metadata": map[string]interface{}{"labels": labels},
+		}}
+	}
+	groups := []GarbageCollectOrderGroup{{
+		Name:     "network",
+		Selector: &ResourcePolicyRuleSelector{ResourceTypes: []string{"Ingress", "Service"}},
+	}, {
+		Name:   "workload",
+		Labels: map[string]string{"tier": "workload"},
+	}, {
+		Name:     "config",
+		Selector: &ResourcePolicyRuleSelector{ResourceTypes: []string{"ConfigMap", "Secret"}},
+		Labels:   map[string]string{"tier": "config"},
+	}}
+	testCases := map[string]struct {
+		input       *unstructured.Unstructured
+		expectIndex int
+	}{
+		"match by resource type": {
+			input:       newManifest("Service", nil),
+			expectIndex: 0,
+		},
+		"match by labels": {
+			input:       newManifest("Deployment", map[string]interface{}{"tier": "workload"}),
+			expectIndex: 1,
+		},
+		"match both selector and labels": {
+			input:       newManifest("Secret", map[string]interface{}{"tier": "config"}),
+			expectIndex: 2,
+		},
+		"selector matched but labels mismatch": {
+			input:       newManifest("Secret", map[string]interface{}{"tier": "other"}),
+			expectIndex: 3,
+		},
+		"first group wins": {
+			input:       newManifest("Service", map[string]interface{}{"tier": "workload"}),
+			expectIndex: 0,
+		},
+		"no group matched": {
+			input:       newManifest("CustomResourceDefinition", nil),
+			expectIndex: 3,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			spec := GarbageCollectPolicySpec{Groups: groups}
+			r.Equal(tc.expectIndex, spec.FindOrderGroup(tc.input))
+		})
+	}
+	r := require.New(t)
+	catchAll := GarbageCollectPolicySpec{Groups: []GarbageCollectOrderGroup{{Name: "all"}}}
+	r.Equal(0, catchAll.FindOrderGroup(newManifest("Namespace", nil)))
+	emptySelector := GarbageCollectPolicySpec{Groups: []GarbageCollectOrderGroup{{Name: "all", Selector: &ResourcePolicyRuleSelector{}}}}
+	r.Equal(0, emptySelector.FindOrderGroup(newManifest("Namespace", nil)))
+}
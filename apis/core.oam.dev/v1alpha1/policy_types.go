@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1alpha1
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
 
 const (
 	// TopologyPolicyType refers to the type of topology policy
@@ -33,9 +36,110 @@ const (
 type TopologyPolicySpec struct {
 	// Placement embeds the selectors for choosing cluster
 	Placement `json:",inline"`
+	// ClusterGroups is the names of the cluster groups to select, the members of the groups are selected.
+	// Exclusive to "clusters" and "clusterLabelSelector"
+	// +optional
+	ClusterGroups []string `json:"clusterGroups,omitempty"`
 	// Namespace is the target namespace to deploy in the selected clusters.
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
+	// Failover re-places the components from unhealthy clusters onto healthy standby clusters.
+	// +optional
+	Failover *TopologyFailover `json:"failover,omitempty"`
+	// Scheduling schedules the components onto the selected clusters by their free capacity.
+	// +optional
+	Scheduling *TopologyScheduling `json:"scheduling,omitempty"`
+	// Identity is the identity to impersonate while dispatching the components to the selected clusters. It
+	// overrides the identity configured on the cluster and the identity of the application. The requester of the
+	// application must be allowed to impersonate the identity in the hub cluster.
+	// +optional
+	Identity *ClusterIdentity `json:"identity,omitempty"`
+}
+
+// ClusterIdentity describes the identity to impersonate in the cluster
+type ClusterIdentity struct {
+	// User is the name of the user to impersonate.
+	User string `json:"user,omitempty"`
+	// Groups is the groups of the user to impersonate.
+	Groups []string `json:"groups,omitempty"`
+	// ServiceAccount is the name of the service account to impersonate. Exclusive to "user" and "groups".
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// ServiceAccountNamespace is the namespace of the service account, default to the namespace of the application.
+	ServiceAccountNamespace string `json:"serviceAccountNamespace,omitempty"`
+}
+
+// TopologyPolicyStatus records the identities impersonated in the clusters selected by the topology policy, so that
+// the resources dispatched can be recycled with the same identities
+type TopologyPolicyStatus struct {
+	ClusterIdentities map[string]ClusterIdentity `json:"clusterIdentities,omitempty"`
+	// Scheduling records the last scheduling decision of the topology policy.
+	Scheduling *TopologySchedulingStatus `json:"scheduling,omitempty"`
+}
+
+// TopologySchedulingStatus records the scheduling decision of the topology policy. The decision is kept until the
+// scheduling spec or the candidate clusters change, so that the placements do not move with the free capacity.
+type TopologySchedulingStatus struct {
+	// Spec is the scheduling spec used to make the decision.
+	Spec TopologyScheduling `json:"spec"`
+	// Clusters is the sorted names of the candidate clusters used to make the decision.
+	Clusters []string `json:"clusters,omitempty"`
+	// Placements is the placements decided.
+	Placements []PlacementDecision `json:"placements,omitempty"`
+}
+
+// TopologyFailover describes the standby clusters to use when selected clusters are unhealthy
+type TopologyFailover struct {
+	// StandbyClusters is the names of the standby clusters, used in order.
+	StandbyClusters []string `json:"standbyClusters,omitempty"`
+
+	// StandbyClusterLabelSelector is the label selector for standby clusters.
+	// Exclusive to "standbyClusters"
+	StandbyClusterLabelSelector map[string]string `json:"standbyClusterLabelSelector,omitempty"`
+}
+
+// TopologySchedulingStrategy is the strategy to schedule the selected clusters in topology
+type TopologySchedulingStrategy string
+
+const (
+	// TopologySchedulingStrategySpread uses all the selected clusters, weighted by their free capacity
+	TopologySchedulingStrategySpread TopologySchedulingStrategy = "spread"
+	// TopologySchedulingStrategyLeastLoaded picks the top-K selected clusters with the most free capacity
+	TopologySchedulingStrategyLeastLoaded TopologySchedulingStrategy = "least-loaded"
+)
+
+// ComponentAffinityType is the type of the affinity between components
+type ComponentAffinityType string
+
+const (
+	// ComponentAffinityTypeAffinity places the components into the same clusters
+	ComponentAffinityTypeAffinity ComponentAffinityType = "affinity"
+	// ComponentAffinityTypeAntiAffinity places the components into different clusters
+	ComponentAffinityTypeAntiAffinity ComponentAffinityType = "anti-affinity"
+)
+
+// TopologyScheduling describes how to schedule the components onto the selected clusters
+type TopologyScheduling struct {
+	// Strategy is the scheduling strategy, "spread" or "least-loaded", default "spread".
+	Strategy TopologySchedulingStrategy `json:"strategy,omitempty"`
+
+	// TopK is the number of clusters to pick in the "least-loaded" strategy.
+	TopK int `json:"topK,omitempty"`
+
+	// Resource is the resource to measure the free capacity of clusters, "cpu" or "memory", default "cpu".
+	Resource corev1.ResourceName `json:"resource,omitempty"`
+
+	// Replicas is the number of replicas to split across the scheduled clusters by their weights.
+	// The split replicas can be applied to components by the "replicaSplit" in override policy.
+	Replicas int `json:"replicas,omitempty"`
+
+	// ComponentAffinity is the affinity rules between components.
+	ComponentAffinity []ComponentAffinity `json:"componentAffinity,omitempty"`
+}
+
+// ComponentAffinity describes the affinity or anti-affinity between components
+type ComponentAffinity struct {
+	Type       ComponentAffinityType `json:"type"`
+	Components []string              `json:"components"`
 }
 
 // Placement describes which clusters to be selected in this topology
@@ -56,6 +160,19 @@ type Placement struct {
 type OverridePolicySpec struct {
 	Components []EnvComponentPatch `json:"components,omitempty"`
 	Selector   []string            `json:"selector,omitempty"`
+	// ReplicaSplit sets the replicas of components in each cluster to the replicas scheduled by topology policy.
+	// +optional
+	ReplicaSplit *OverrideReplicaSplit `json:"replicaSplit,omitempty"`
+}
+
+// OverrideReplicaSplit describes how to apply the replicas scheduled by topology policy to components
+type OverrideReplicaSplit struct {
+	// Components is the names of the components to split replicas, if empty, all components will be selected.
+	Components []string `json:"components,omitempty"`
+
+	// Trait is the type of the trait to set replicas, like "scaler".
+	// If empty, the replicas will be set in the properties of components.
+	Trait string `json:"trait,omitempty"`
 }
 
 // SharedResourcePolicySpec defines the spec of shared-resource policy
@@ -52,7 +52,14 @@ var (
 	WorkflowGroupVersionKind = SchemeGroupVersion.WithKind(WorkflowKind)
 )
 
+// ApplicationQuota meta
+var (
+	ApplicationQuotaKind             = "ApplicationQuota"
+	ApplicationQuotaGroupVersionKind = SchemeGroupVersion.WithKind(ApplicationQuotaKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&Policy{}, &PolicyList{})
 	SchemeBuilder.Register(&Workflow{}, &WorkflowList{})
+	SchemeBuilder.Register(&ApplicationQuota{}, &ApplicationQuotaList{})
 }
@@ -27,6 +27,41 @@ import (
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppDependency) DeepCopyInto(out *AppDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppDependency.
+func (in *AppDependency) DeepCopy() *AppDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(AppDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppDependencyPolicySpec) DeepCopyInto(out *AppDependencyPolicySpec) {
+	*out = *in
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]AppDependency, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppDependencyPolicySpec.
+func (in *AppDependencyPolicySpec) DeepCopy() *AppDependencyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppDependencyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplyOncePolicyRule) DeepCopyInto(out *ApplyOncePolicyRule) {
 	*out = *in
@@ -48,6 +83,206 @@ func (in *ApplyOncePolicyRule) DeepCopy() *ApplyOncePolicyRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuota) DeepCopyInto(out *ApplicationQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuota.
+func (in *ApplicationQuota) DeepCopy() *ApplicationQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuotaHard) DeepCopyInto(out *ApplicationQuotaHard) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuotaHard.
+func (in *ApplicationQuotaHard) DeepCopy() *ApplicationQuotaHard {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuotaHard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuotaList) DeepCopyInto(out *ApplicationQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ApplicationQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuotaList.
+func (in *ApplicationQuotaList) DeepCopy() *ApplicationQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApplicationQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuotaSpec) DeepCopyInto(out *ApplicationQuotaSpec) {
+	*out = *in
+	if in.Application != nil {
+		in, out := &in.Application, &out.Application
+		*out = new(ApplicationQuotaTarget)
+		**out = **in
+	}
+	in.Hard.DeepCopyInto(&out.Hard)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuotaSpec.
+func (in *ApplicationQuotaSpec) DeepCopy() *ApplicationQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuotaStatus) DeepCopyInto(out *ApplicationQuotaStatus) {
+	*out = *in
+	in.Used.DeepCopyInto(&out.Used)
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]ApplicationUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuotaStatus.
+func (in *ApplicationQuotaStatus) DeepCopy() *ApplicationQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuotaTarget) DeepCopyInto(out *ApplicationQuotaTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuotaTarget.
+func (in *ApplicationQuotaTarget) DeepCopy() *ApplicationQuotaTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuotaTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuotaUsage) DeepCopyInto(out *ApplicationQuotaUsage) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.CPU = in.CPU.DeepCopy()
+	out.Memory = in.Memory.DeepCopy()
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuotaUsage.
+func (in *ApplicationQuotaUsage) DeepCopy() *ApplicationQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationUsage) DeepCopyInto(out *ApplicationUsage) {
+	*out = *in
+	in.Used.DeepCopyInto(&out.Used)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationUsage.
+func (in *ApplicationUsage) DeepCopy() *ApplicationUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplyOncePolicySpec) DeepCopyInto(out *ApplyOncePolicySpec) {
 	*out = *in
@@ -105,6 +340,46 @@ func (in *ClusterConnection) DeepCopy() *ClusterConnection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterIdentity) DeepCopyInto(out *ClusterIdentity) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterIdentity.
+func (in *ClusterIdentity) DeepCopy() *ClusterIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentAffinity) DeepCopyInto(out *ComponentAffinity) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentAffinity.
+func (in *ComponentAffinity) DeepCopy() *ComponentAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvBindingSpec) DeepCopyInto(out *EnvBindingSpec) {
 	*out = *in
@@ -276,7 +551,9 @@ func (in *EnvStatus) DeepCopyInto(out *EnvStatus) {
 	if in.Placements != nil {
 		in, out := &in.Placements, &out.Placements
 		*out = make([]PlacementDecision, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -310,6 +587,33 @@ func (in *EnvTraitPatch) DeepCopy() *EnvTraitPatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectOrderGroup) DeepCopyInto(out *GarbageCollectOrderGroup) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(ResourcePolicyRuleSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectOrderGroup.
+func (in *GarbageCollectOrderGroup) DeepCopy() *GarbageCollectOrderGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectOrderGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GarbageCollectPolicyRule) DeepCopyInto(out *GarbageCollectPolicyRule) {
 	*out = *in
@@ -329,6 +633,13 @@ func (in *GarbageCollectPolicyRule) DeepCopy() *GarbageCollectPolicyRule {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GarbageCollectPolicySpec) DeepCopyInto(out *GarbageCollectPolicySpec) {
 	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]GarbageCollectOrderGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Rules != nil {
 		in, out := &in.Rules, &out.Rules
 		*out = make([]GarbageCollectPolicyRule, len(*in))
@@ -462,6 +773,11 @@ func (in *OverridePolicySpec) DeepCopyInto(out *OverridePolicySpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReplicaSplit != nil {
+		in, out := &in.ReplicaSplit, &out.ReplicaSplit
+		*out = new(OverrideReplicaSplit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverridePolicySpec.
@@ -474,6 +790,26 @@ func (in *OverridePolicySpec) DeepCopy() *OverridePolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverrideReplicaSplit) DeepCopyInto(out *OverrideReplicaSplit) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverrideReplicaSplit.
+func (in *OverrideReplicaSplit) DeepCopy() *OverrideReplicaSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(OverrideReplicaSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Placement) DeepCopyInto(out *Placement) {
 	*out = *in
@@ -511,6 +847,16 @@ func (in *Placement) DeepCopy() *Placement {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlacementDecision) DeepCopyInto(out *PlacementDecision) {
 	*out = *in
+	if in.ExcludedComponents != nil {
+		in, out := &in.ExcludedComponents, &out.ExcludedComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(ClusterIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementDecision.
@@ -695,10 +1041,57 @@ func (in *SharedResourcePolicySpec) DeepCopy() *SharedResourcePolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyFailover) DeepCopyInto(out *TopologyFailover) {
+	*out = *in
+	if in.StandbyClusters != nil {
+		in, out := &in.StandbyClusters, &out.StandbyClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StandbyClusterLabelSelector != nil {
+		in, out := &in.StandbyClusterLabelSelector, &out.StandbyClusterLabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyFailover.
+func (in *TopologyFailover) DeepCopy() *TopologyFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologyPolicySpec) DeepCopyInto(out *TopologyPolicySpec) {
 	*out = *in
 	in.Placement.DeepCopyInto(&out.Placement)
+	if in.ClusterGroups != nil {
+		in, out := &in.ClusterGroups, &out.ClusterGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(TopologyFailover)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(TopologyScheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(ClusterIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyPolicySpec.
@@ -711,6 +1104,83 @@ func (in *TopologyPolicySpec) DeepCopy() *TopologyPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyPolicyStatus) DeepCopyInto(out *TopologyPolicyStatus) {
+	*out = *in
+	if in.ClusterIdentities != nil {
+		in, out := &in.ClusterIdentities, &out.ClusterIdentities
+		*out = make(map[string]ClusterIdentity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(TopologySchedulingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyPolicyStatus.
+func (in *TopologyPolicyStatus) DeepCopy() *TopologyPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyScheduling) DeepCopyInto(out *TopologyScheduling) {
+	*out = *in
+	if in.ComponentAffinity != nil {
+		in, out := &in.ComponentAffinity, &out.ComponentAffinity
+		*out = make([]ComponentAffinity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyScheduling.
+func (in *TopologyScheduling) DeepCopy() *TopologyScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologySchedulingStatus) DeepCopyInto(out *TopologySchedulingStatus) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Placements != nil {
+		in, out := &in.Placements, &out.Placements
+		*out = make([]PlacementDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySchedulingStatus.
+func (in *TopologySchedulingStatus) DeepCopy() *TopologySchedulingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologySchedulingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Workflow) DeepCopyInto(out *Workflow) {
 	*out = *in
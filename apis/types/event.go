@@ -26,6 +26,8 @@ const (
 	ReasonHealthCheck     = "HealthChecked"
 	ReasonDeployed        = "Deployed"
 	ReasonRollout         = "Rollout"
+	ReasonDriftDetected   = "DriftDetected"
+	ReasonFailover        = "Failover"
 
 	ReasonFailedParse       = "FailedParse"
 	ReasonFailedRender      = "FailedRender"
@@ -56,4 +58,6 @@ const (
 	MessageFailedApply       = "fail to apply component, err: %v"
 	MessageFailedHealthCheck = "fail to health check, err: %v"
 	MessageFailedGC          = "fail to garbage collection, err: %v"
+	MessageDriftDetected     = "configuration drift detected on %s %s/%s in cluster %s"
+	MessageFailover          = "cluster %s is unhealthy, failover to cluster %s in topology %s"
 )
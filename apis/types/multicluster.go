@@ -39,4 +39,31 @@ const (
 var (
 	// AnnotationClusterAlias the annotation key for cluster alias
 	AnnotationClusterAlias = config.MetaApiGroupName + "/cluster-alias"
+	// AnnotationClusterHealth the annotation key for the health status of cluster
+	AnnotationClusterHealth = config.MetaApiGroupName + "/cluster-health"
+	// AnnotationClusterHealthReason the annotation key for the reason of the cluster health status
+	AnnotationClusterHealthReason = config.MetaApiGroupName + "/cluster-health-reason"
+	// AnnotationClusterHealthLastTransitionTime the annotation key for the last time the cluster health status changed
+	AnnotationClusterHealthLastTransitionTime = config.MetaApiGroupName + "/cluster-health-last-transition-time"
+	// AnnotationClusterServiceAccount the annotation key for the service account (namespace/name) in the managed
+	// cluster whose token is used as the credential of cluster
+	AnnotationClusterServiceAccount = config.MetaApiGroupName + "/cluster-service-account"
+	// AnnotationClusterCredentialExpiration the annotation key for the expiration time of the credential of cluster
+	AnnotationClusterCredentialExpiration = config.MetaApiGroupName + "/cluster-credential-expiration"
+	// AnnotationClusterMaintenance the annotation key for the maintenance status (Cordoned or Drained) of cluster
+	AnnotationClusterMaintenance = config.MetaApiGroupName + "/cluster-maintenance"
+	// AnnotationClusterImpersonateUser the annotation key for the user to impersonate while dispatching resources of
+	// applications to the cluster
+	AnnotationClusterImpersonateUser = config.MetaApiGroupName + "/impersonate-user"
+	// AnnotationClusterImpersonateGroups the annotation key for the groups (separated by comma) of the user to
+	// impersonate in the cluster
+	AnnotationClusterImpersonateGroups = config.MetaApiGroupName + "/impersonate-groups"
+	// AnnotationClusterImpersonateServiceAccount the annotation key for the service account (namespace/name) to
+	// impersonate in the cluster
+	AnnotationClusterImpersonateServiceAccount = config.MetaApiGroupName + "/impersonate-service-account"
+	// AnnotationClusterInsecureSkipTLSVerify the annotation key to explicitly allow accessing the cluster without
+	// verifying its serving certificate, when no CA is found in the cluster secret
+	AnnotationClusterInsecureSkipTLSVerify = config.MetaApiGroupName + "/insecure-skip-tls-verify"
+	// LabelClusterGroup the label key for the name of the cluster group stored in the configmap
+	LabelClusterGroup = config.MetaApiGroupName + "/cluster-group"
 )
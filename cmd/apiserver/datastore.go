@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/clients"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/archive"
+)
+
+const (
+	// exportDatastoreCommand exports the datastore to an archive file
+	exportDatastoreCommand = "export-datastore"
+	// importDatastoreCommand imports the archive file to the datastore
+	importDatastoreCommand = "import-datastore"
+	// stdioFile means the archive is written to the stdout or read from the stdin
+	stdioFile = "-"
+)
+
+func (s *Server) newDataStore(ctx context.Context) (datastore.DataStore, error) {
+	if s.serverConfig.Datastore.Type == "kubeapi" {
+		if err := clients.SetKubeConfig(s.serverConfig); err != nil {
+			return nil, err
+		}
+	}
+	return apiserver.NewDataStore(ctx, s.serverConfig.Datastore)
+}
+
+// exportDatastore exports all the entities in the datastore to the archive file
+func (s *Server) exportDatastore(ctx context.Context, file string) (err error) {
+	ds, err := s.newDataStore(ctx)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if file != stdioFile {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := f.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+			// don't leave a broken archive
+			if err != nil {
+				_ = os.Remove(file)
+			}
+		}()
+		w = f
+	}
+	metadata, err := archive.Export(ctx, ds, s.serverConfig.Datastore.Type, w)
+	if err != nil {
+		return fmt.Errorf("export the datastore failure %w", err)
+	}
+	var total int
+	for _, count := range metadata.Tables {
+		total += count
+	}
+	fmt.Fprintf(os.Stderr, "exported %d entities in %d tables from the %s datastore (schema version %d)\n",
+		total, len(metadata.Tables), s.serverConfig.Datastore.Type, metadata.SchemaVersion)
+	return nil
+}
+
+// importDatastore imports the entities in the archive file to the datastore
+func (s *Server) importDatastore(ctx context.Context, file string) error {
+	ds, err := s.newDataStore(ctx)
+	if err != nil {
+		return err
+	}
+	var r io.Reader = os.Stdin
+	if file != stdioFile {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	}
+	result, err := archive.Import(ctx, ds, r, archive.ImportOptions{Idempotent: s.importIdempotent})
+	if err != nil {
+		if result != nil {
+			fmt.Fprintf(os.Stderr, "%d entities created and %d entities updated before failing\n", result.Created, result.Updated)
+		}
+		return fmt.Errorf("import the datastore failure %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "imported the archive created by KubeVela %s from the %s datastore: %d entities created, %d entities updated\n",
+		result.Metadata.VelaVersion, result.Metadata.DatastoreType, result.Created, result.Updated)
+	return nil
+}
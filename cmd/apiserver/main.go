@@ -27,6 +27,7 @@ import (
 
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/fatih/color"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-openapi/spec"
 	"github.com/google/uuid"
 	flag "github.com/spf13/pflag"
@@ -42,9 +43,9 @@ func main() {
 	s := &Server{}
 	flag.StringVar(&s.serverConfig.BindAddr, "bind-addr", "0.0.0.0:8000", "The bind address used to serve the http APIs.")
 	flag.StringVar(&s.serverConfig.MetricPath, "metrics-path", "/metrics", "The path to expose the metrics.")
-	flag.StringVar(&s.serverConfig.Datastore.Type, "datastore-type", "kubeapi", "Metadata storage driver type, support kubeapi and mongodb")
+	flag.StringVar(&s.serverConfig.Datastore.Type, "datastore-type", "kubeapi", "Metadata storage driver type, support kubeapi, mongodb, postgres, mysql and sqlite. The sqlite driver is only available when built with CGO_ENABLED=1")
 	flag.StringVar(&s.serverConfig.Datastore.Database, "datastore-database", "kubevela", "Metadata storage database name, takes effect when the storage driver is mongodb.")
-	flag.StringVar(&s.serverConfig.Datastore.URL, "datastore-url", "", "Metadata storage database url,takes effect when the storage driver is mongodb.")
+	flag.StringVar(&s.serverConfig.Datastore.URL, "datastore-url", "", "Metadata storage database url,takes effect when the storage driver is mongodb, postgres, mysql or sqlite. For the sql drivers, it is the data source name of the database.")
 	flag.StringVar(&s.serverConfig.LeaderConfig.ID, "id", uuid.New().String(), "the holder identity name")
 	flag.StringVar(&s.serverConfig.LeaderConfig.LockName, "lock-name", "apiserver-lock", "the lease lock resource name")
 	flag.DurationVar(&s.serverConfig.LeaderConfig.Duration, "duration", time.Second*5, "the lease lock resource name")
@@ -52,16 +53,26 @@ func main() {
 	flag.BoolVar(&s.serverConfig.DisableStatisticCronJob, "disable-statistic-cronJob", false, "close the system statistic info calculating cronJob")
 	flag.Float64Var(&s.serverConfig.KubeQPS, "kube-api-qps", 100, "the qps for kube clients. Low qps may lead to low throughput. High qps may give stress to api-server.")
 	flag.IntVar(&s.serverConfig.KubeBurst, "kube-api-burst", 300, "the burst for kube clients. Recommend setting it qps*3.")
+	flag.DurationVar(&s.serverConfig.AuditLog.Retention, "audit-log-retention", time.Hour*24*30, "How long the audit logs are kept, the audit logs are kept forever if it is 0.")
+	flag.StringVar(&s.serverConfig.AuditLog.WebhookURL, "audit-log-webhook-url", "", "The webhook url that the audit logs are forwarded to.")
+	flag.BoolVar(&s.importIdempotent, "idempotent", false, "Overwrite the existing records instead of failing, takes effect with the import-datastore command.")
 	features.APIServerMutableFeatureGate.AddFlag(flag.CommandLine)
 	flag.Parse()
 
-	if len(os.Args) > 2 && os.Args[1] == "build-swagger" {
+	// build-swagger writes the swagger 2.0 document, build-openapi writes the OpenAPI 3 document served at /openapi.json
+	if len(os.Args) > 2 && (os.Args[1] == "build-swagger" || os.Args[1] == "build-openapi") {
 		func() {
-			swagger, err := s.buildSwagger()
+			var doc interface{}
+			var err error
+			if os.Args[1] == "build-openapi" {
+				doc, err = s.buildOpenAPI()
+			} else {
+				doc, err = s.buildSwagger()
+			}
 			if err != nil {
 				log.Logger.Fatal(err.Error())
 			}
-			outData, err := json.MarshalIndent(swagger, "", "\t")
+			outData, err := json.MarshalIndent(doc, "", "\t")
 			if err != nil {
 				log.Logger.Fatal(err.Error())
 			}
@@ -78,11 +89,30 @@ func main() {
 			if err != nil {
 				log.Logger.Fatal(err.Error())
 			}
-			fmt.Println("build swagger config file success")
+			fmt.Println("build the api document file success")
 		}()
 		return
 	}
 
+	// export-datastore and import-datastore back up and restore the datastore, or migrate between the datastore types:
+	// vela-apiserver export-datastore <file> --datastore-type=kubeapi
+	// vela-apiserver import-datastore <file> --datastore-type=mongodb --datastore-url=... [--idempotent]
+	if args := flag.Args(); len(args) > 0 && (args[0] == exportDatastoreCommand || args[0] == importDatastoreCommand) {
+		if len(args) != 2 {
+			log.Logger.Fatalf("usage: vela-apiserver %s <archive file, or - for the standard input/output>", args[0])
+		}
+		var err error
+		if args[0] == exportDatastoreCommand {
+			err = s.exportDatastore(context.Background(), args[1])
+		} else {
+			err = s.importDatastore(context.Background(), args[1])
+		}
+		if err != nil {
+			log.Logger.Fatal(err.Error())
+		}
+		return
+	}
+
 	// The server is not terminal, there is no color default.
 	// Force set to false, this is useful for the dry-run API.
 	color.NoColor = false
@@ -110,6 +140,8 @@ func main() {
 // Server apiserver
 type Server struct {
 	serverConfig config.Config
+	// importIdempotent overwrites the existing records when importing the datastore
+	importIdempotent bool
 }
 
 func (s *Server) run(ctx context.Context, errChan chan error) error {
@@ -128,3 +160,12 @@ func (s *Server) buildSwagger() (*spec.Swagger, error) {
 	}
 	return restfulspec.BuildSwagger(*config), nil
 }
+
+func (s *Server) buildOpenAPI() (*openapi3.T, error) {
+	server := apiserver.New(s.serverConfig)
+	config, err := server.BuildRestfulConfig()
+	if err != nil {
+		return nil, err
+	}
+	return apiserver.BuildOpenAPIV3(*config)
+}
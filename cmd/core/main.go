@@ -32,9 +32,11 @@ import (
 
 	flag "github.com/spf13/pflag"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
@@ -89,6 +91,12 @@ func main() {
 	var enableClusterGateway bool
 	var enableClusterMetrics bool
 	var clusterMetricsInterval time.Duration
+	var enableClusterHealthProbe bool
+	var clusterHealthProbeInterval time.Duration
+	var clusterHealthFailureThreshold int
+	var enableClusterCredentialRotation bool
+	var clusterCredentialRotationInterval time.Duration
+	var clusterTokenExpiration time.Duration
 
 	flag.BoolVar(&useWebhook, "use-webhook", false, "Enable Admission Webhook")
 	flag.StringVar(&certDir, "webhook-cert-dir", "/k8s-webhook-server/serving-certs", "Admission webhook cert/key dir.")
@@ -138,6 +146,15 @@ func main() {
 	flag.BoolVar(&enableClusterGateway, "enable-cluster-gateway", false, "Enable cluster-gateway to use multicluster, disabled by default.")
 	flag.BoolVar(&enableClusterMetrics, "enable-cluster-metrics", false, "Enable cluster-metrics-management to collect metrics from clusters with cluster-gateway, disabled by default. When this param is enabled, enable-cluster-gateway should be enabled")
 	flag.DurationVar(&clusterMetricsInterval, "cluster-metrics-interval", 15*time.Second, "The interval that ClusterMetricsMgr will collect metrics from clusters, default value is 15 seconds.")
+	flag.BoolVar(&enableClusterHealthProbe, "enable-cluster-health-probe", false, "Enable cluster-health-management to periodically probe clusters with cluster-gateway and record their health status, disabled by default. When this param is enabled, enable-cluster-gateway should be enabled")
+	flag.DurationVar(&clusterHealthProbeInterval, "cluster-health-probe-interval", 30*time.Second, "The interval that ClusterHealthMgr will probe clusters, default value is 30 seconds.")
+	flag.IntVar(&clusterHealthFailureThreshold, "cluster-health-failure-threshold", 3, "The number of continuous probe failures before a cluster is marked as unhealthy, default value is 3.")
+	flag.BoolVar(&enableClusterCredentialRotation, "enable-cluster-credential-rotation", false, "Enable cluster-credential-management to rotate the service account tokens of clusters with cluster-gateway before they expire, disabled by default. When this param is enabled, enable-cluster-gateway should be enabled")
+	flag.DurationVar(&clusterCredentialRotationInterval, "cluster-credential-rotation-interval", 10*time.Minute, "The interval that ClusterCredentialMgr will check the expiration of cluster tokens, default value is 10 minutes.")
+	flag.DurationVar(&clusterTokenExpiration, "cluster-token-expiration", multicluster.DefaultClusterTokenExpiration, "The expiration of the service account tokens requested by ClusterCredentialMgr, default value is 24 hours.")
+	flag.StringVar(&multicluster.ClusterClientMode, "cluster-client-mode", multicluster.ClusterClientModeGateway, "The mode to access the managed clusters, available options: gateway, direct. The gateway mode sends requests through cluster-gateway, the direct mode connects clusters directly with the credentials in the cluster secrets.")
+	flag.Float32Var(&multicluster.DirectClusterQPS, "direct-cluster-qps", 50, "The qps limit of the requests to each managed cluster in direct cluster client mode.")
+	flag.IntVar(&multicluster.DirectClusterBurst, "direct-cluster-burst", 100, "The burst limit of the requests to each managed cluster in direct cluster client mode.")
 	flag.BoolVar(&controllerArgs.EnableCompatibility, "enable-asi-compatibility", false, "enable compatibility for asi")
 	flag.BoolVar(&controllerArgs.IgnoreAppWithoutControllerRequirement, "ignore-app-without-controller-version", false, "If true, application controller will not process the app without 'app.oam.dev/controller-version-require' annotation")
 	flag.BoolVar(&controllerArgs.IgnoreDefinitionWithoutControllerRequirement, "ignore-definition-without-controller-version", false, "If true, trait/component/workflowstep definition controller will not process the definition without 'definition.oam.dev/controller-version-require' annotation")
@@ -205,7 +222,13 @@ func main() {
 	restConfig.UserAgent = types.KubeVelaName + "/" + version.GitRevision
 	restConfig.QPS = float32(qps)
 	restConfig.Burst = burst
-	restConfig.Wrap(auth.NewImpersonatingRoundTripper)
+	// the identities configured on clusters are read by a client without impersonation
+	identityClient, err := client.New(rest.CopyConfig(restConfig), client.Options{Scheme: scheme})
+	if err != nil {
+		klog.ErrorS(err, "Unable to create client to read the identities of clusters")
+		os.Exit(1)
+	}
+	restConfig.Wrap(auth.NewClusterImpersonatingRoundTripperGenerator(identityClient))
 	klog.InfoS("Kubernetes Config Loaded",
 		"UserAgent", restConfig.UserAgent,
 		"QPS", restConfig.QPS,
@@ -227,6 +250,22 @@ func main() {
 				os.Exit(1)
 			}
 		}
+
+		if enableClusterHealthProbe {
+			_, err := multicluster.NewClusterHealthMgr(context.Background(), client, restConfig, clusterHealthProbeInterval, clusterHealthFailureThreshold)
+			if err != nil {
+				klog.ErrorS(err, "failed to enable multi-cluster-health capability")
+				os.Exit(1)
+			}
+		}
+
+		if enableClusterCredentialRotation {
+			_, err := multicluster.NewClusterCredentialMgr(context.Background(), client, restConfig, clusterCredentialRotationInterval, clusterTokenExpiration)
+			if err != nil {
+				klog.ErrorS(err, "failed to enable multi-cluster-credential capability")
+				os.Exit(1)
+			}
+		}
 	}
 	ctrl.SetLogger(klogr.New())
 
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+// ListAddons lists the addons of the registries, registry and query filter the addons if they are not empty
+func (c *Client) ListAddons(ctx context.Context, registry, query string) (*apisv1.ListAddonResponse, error) {
+	var res apisv1.ListAddonResponse
+	if err := c.get(ctx, "/addons", addonQuery(registry, query), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListEnabledAddons lists the enabled addons
+func (c *Client) ListEnabledAddons(ctx context.Context) (*apisv1.ListAddonResponse, error) {
+	var res apisv1.ListAddonResponse
+	if err := c.get(ctx, "/enabled_addon", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetAddon gets the addon, the latest version is returned if the version is empty
+func (c *Client) GetAddon(ctx context.Context, addonName, registry, version string) (*apisv1.DetailAddonResponse, error) {
+	query := addonQuery(registry, "")
+	if version != "" {
+		query.Set("version", version)
+	}
+	var res apisv1.DetailAddonResponse
+	if err := c.get(ctx, fmt.Sprintf("/addons/%s", url.PathEscape(addonName)), query, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetAddonStatus gets the status of the addon
+func (c *Client) GetAddonStatus(ctx context.Context, addonName string) (*apisv1.AddonStatusResponse, error) {
+	var res apisv1.AddonStatusResponse
+	if err := c.get(ctx, fmt.Sprintf("/addons/%s/status", url.PathEscape(addonName)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// EnableAddon enables the addon
+func (c *Client) EnableAddon(ctx context.Context, addonName string, req apisv1.EnableAddonRequest) (*apisv1.AddonStatusResponse, error) {
+	var res apisv1.AddonStatusResponse
+	if err := c.post(ctx, fmt.Sprintf("/addons/%s/enable", url.PathEscape(addonName)), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateAddon updates the args of the enabled addon
+func (c *Client) UpdateAddon(ctx context.Context, addonName string, req apisv1.EnableAddonRequest) (*apisv1.AddonStatusResponse, error) {
+	var res apisv1.AddonStatusResponse
+	if err := c.put(ctx, fmt.Sprintf("/addons/%s/update", url.PathEscape(addonName)), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DisableAddon disables the addon, force disables it even if the definitions of the addon are used by the applications
+func (c *Client) DisableAddon(ctx context.Context, addonName string, force bool) (*apisv1.AddonStatusResponse, error) {
+	var res apisv1.AddonStatusResponse
+	query := url.Values{}
+	if force {
+		query.Set("force", strconv.FormatBool(force))
+	}
+	if err := c.do(ctx, http.MethodPost, c.url(fmt.Sprintf("/addons/%s/disable", url.PathEscape(addonName)), query), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func addonQuery(registry, query string) url.Values {
+	values := url.Values{}
+	if registry != "" {
+		values.Set("registry", registry)
+	}
+	if query != "" {
+		values.Set("query", query)
+	}
+	return values
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+// ListApplicationOptions filter the listed applications, the empty fields are ignored
+type ListApplicationOptions struct {
+	Project    string
+	Env        string
+	TargetName string
+	// Query is the fuzzy search of the name and the description
+	Query string
+}
+
+// ListApplications lists the applications
+func (c *Client) ListApplications(ctx context.Context, opts ListApplicationOptions) (*apisv1.ListApplicationResponse, error) {
+	query := url.Values{}
+	for key, value := range map[string]string{"project": opts.Project, "env": opts.Env, "targetName": opts.TargetName, "query": opts.Query} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	var res apisv1.ListApplicationResponse
+	if err := c.get(ctx, "/applications", query, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateApplication creates an application
+func (c *Client) CreateApplication(ctx context.Context, req apisv1.CreateApplicationRequest) (*apisv1.ApplicationBase, error) {
+	var res apisv1.ApplicationBase
+	if err := c.post(ctx, "/applications", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetApplication gets the application
+func (c *Client) GetApplication(ctx context.Context, appName string) (*apisv1.DetailApplicationResponse, error) {
+	var res apisv1.DetailApplicationResponse
+	if err := c.get(ctx, appPath(appName), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateApplication updates the application
+func (c *Client) UpdateApplication(ctx context.Context, appName string, req apisv1.UpdateApplicationRequest) (*apisv1.ApplicationBase, error) {
+	var res apisv1.ApplicationBase
+	if err := c.put(ctx, appPath(appName), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteApplication deletes the application
+func (c *Client) DeleteApplication(ctx context.Context, appName string) error {
+	return c.delete(ctx, appPath(appName), nil)
+}
+
+// DeployApplication runs the workflow of the application
+func (c *Client) DeployApplication(ctx context.Context, appName string, req apisv1.ApplicationDeployRequest) (*apisv1.ApplicationDeployResponse, error) {
+	var res apisv1.ApplicationDeployResponse
+	if err := c.post(ctx, appPath(appName)+"/deploy", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListApplicationComponents lists the components of the application, only the components of the env are listed if the envName is not empty
+func (c *Client) ListApplicationComponents(ctx context.Context, appName, envName string) (*apisv1.ComponentListResponse, error) {
+	query := url.Values{}
+	if envName != "" {
+		query.Set("envName", envName)
+	}
+	var res apisv1.ComponentListResponse
+	if err := c.get(ctx, appPath(appName)+"/components", query, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateApplicationComponent adds a component to the application
+func (c *Client) CreateApplicationComponent(ctx context.Context, appName string, req apisv1.CreateComponentRequest) (*apisv1.ComponentBase, error) {
+	var res apisv1.ComponentBase
+	if err := c.post(ctx, appPath(appName)+"/components", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetApplicationComponent gets the component of the application
+func (c *Client) GetApplicationComponent(ctx context.Context, appName, compName string) (*apisv1.DetailComponentResponse, error) {
+	var res apisv1.DetailComponentResponse
+	if err := c.get(ctx, fmt.Sprintf("%s/components/%s", appPath(appName), url.PathEscape(compName)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateApplicationComponent updates the component of the application
+func (c *Client) UpdateApplicationComponent(ctx context.Context, appName, compName string, req apisv1.UpdateApplicationComponentRequest) (*apisv1.ComponentBase, error) {
+	var res apisv1.ComponentBase
+	if err := c.put(ctx, fmt.Sprintf("%s/components/%s", appPath(appName), url.PathEscape(compName)), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteApplicationComponent deletes the component of the application
+func (c *Client) DeleteApplicationComponent(ctx context.Context, appName, compName string) error {
+	return c.delete(ctx, fmt.Sprintf("%s/components/%s", appPath(appName), url.PathEscape(compName)), nil)
+}
+
+// ListApplicationEnvs lists the envs the application is bound to
+func (c *Client) ListApplicationEnvs(ctx context.Context, appName string) (*apisv1.ListApplicationEnvBinding, error) {
+	var res apisv1.ListApplicationEnvBinding
+	if err := c.get(ctx, appPath(appName)+"/envs", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateApplicationEnv binds the application to an env
+func (c *Client) CreateApplicationEnv(ctx context.Context, appName string, req apisv1.CreateApplicationEnvbindingRequest) (*apisv1.EnvBinding, error) {
+	var res apisv1.EnvBinding
+	if err := c.post(ctx, appPath(appName)+"/envs", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteApplicationEnv unbinds the application from the env
+func (c *Client) DeleteApplicationEnv(ctx context.Context, appName, envName string) error {
+	return c.delete(ctx, fmt.Sprintf("%s/envs/%s", appPath(appName), url.PathEscape(envName)), nil)
+}
+
+// GetApplicationStatus gets the status of the application in the env
+func (c *Client) GetApplicationStatus(ctx context.Context, appName, envName string) (*apisv1.ApplicationStatusResponse, error) {
+	var res apisv1.ApplicationStatusResponse
+	if err := c.get(ctx, fmt.Sprintf("%s/envs/%s/status", appPath(appName), url.PathEscape(envName)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RecycleApplicationEnv deletes the resources of the application deployed in the env
+func (c *Client) RecycleApplicationEnv(ctx context.Context, appName, envName string) error {
+	return c.post(ctx, fmt.Sprintf("%s/envs/%s/recycle", appPath(appName), url.PathEscape(envName)), nil, nil)
+}
+
+// ListApplicationTriggers lists the triggers of the application
+func (c *Client) ListApplicationTriggers(ctx context.Context, appName string) (*apisv1.ListApplicationTriggerResponse, error) {
+	var res apisv1.ListApplicationTriggerResponse
+	if err := c.get(ctx, appPath(appName)+"/triggers", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateApplicationTrigger creates a trigger of the application
+func (c *Client) CreateApplicationTrigger(ctx context.Context, appName string, req apisv1.CreateApplicationTriggerRequest) (*apisv1.ApplicationTriggerBase, error) {
+	var res apisv1.ApplicationTriggerBase
+	if err := c.post(ctx, appPath(appName)+"/triggers", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteApplicationTrigger deletes the trigger of the application
+func (c *Client) DeleteApplicationTrigger(ctx context.Context, appName, token string) error {
+	return c.delete(ctx, fmt.Sprintf("%s/triggers/%s", appPath(appName), url.PathEscape(token)), nil)
+}
+
+// ListApplicationRevisions lists the revisions of the application, envName and status filter the revisions if they are not empty
+func (c *Client) ListApplicationRevisions(ctx context.Context, appName, envName, status string, opts ListOptions) (*apisv1.ListRevisionsResponse, error) {
+	query := opts.query()
+	if envName != "" {
+		query.Set("envName", envName)
+	}
+	if status != "" {
+		query.Set("status", status)
+	}
+	var res apisv1.ListRevisionsResponse
+	if err := c.get(ctx, appPath(appName)+"/revisions", query, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetApplicationRevision gets the revision of the application
+func (c *Client) GetApplicationRevision(ctx context.Context, appName, revision string) (*apisv1.DetailRevisionResponse, error) {
+	var res apisv1.DetailRevisionResponse
+	if err := c.get(ctx, fmt.Sprintf("%s/revisions/%s", appPath(appName), url.PathEscape(revision)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func appPath(appName string) string {
+	return fmt.Sprintf("/applications/%s", url.PathEscape(appName))
+}
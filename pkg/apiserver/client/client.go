@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client is the typed client of the apiserver APIs, the requests and responses are the types of
+// pkg/apiserver/interfaces/api/dto/v1. The failed requests return the *bcode.Bcode responded by the apiserver.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+const versionPrefix = "/api/v1"
+
+// Client is the client of the apiserver
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mutex sync.RWMutex
+	token string
+}
+
+// Option configures the client
+type Option func(*Client)
+
+// WithHTTPClient sets the http client sending the requests, http.DefaultClient is used by default
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets the access token or the API token used to authenticate the requests
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// New creates the client of the apiserver, the baseURL is the address of the apiserver such as http://127.0.0.1:8000
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetToken replaces the token used to authenticate the requests
+func (c *Client) SetToken(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.token = token
+}
+
+// Login logs in with the username and password, the access token of the response is used by the following requests
+func (c *Client) Login(ctx context.Context, username, password string) (*apisv1.LoginResponse, error) {
+	var res apisv1.LoginResponse
+	if err := c.post(ctx, "/auth/login", apisv1.LoginRequest{Username: username, Password: password}, &res); err != nil {
+		return nil, err
+	}
+	c.SetToken(res.AccessToken)
+	return &res, nil
+}
+
+// OpenAPI returns the OpenAPI 3 document of the apiserver
+func (c *Client) OpenAPI(ctx context.Context) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, c.baseURL+"/openapi.json", nil, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, res interface{}) error {
+	return c.do(ctx, http.MethodGet, c.url(path, query), nil, res)
+}
+
+func (c *Client) post(ctx context.Context, path string, body, res interface{}) error {
+	return c.do(ctx, http.MethodPost, c.url(path, nil), body, res)
+}
+
+func (c *Client) put(ctx context.Context, path string, body, res interface{}) error {
+	return c.do(ctx, http.MethodPut, c.url(path, nil), body, res)
+}
+
+func (c *Client) delete(ctx context.Context, path string, query url.Values) error {
+	return c.do(ctx, http.MethodDelete, c.url(path, query), nil, nil)
+}
+
+func (c *Client) url(path string, query url.Values) string {
+	u := c.baseURL + versionPrefix + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+func (c *Client) do(ctx context.Context, method, u string, body, res interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if method == http.MethodPost || method == http.MethodPut {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.mutex.RLock()
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	c.mutex.RUnlock()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return decodeError(resp.StatusCode, raw)
+	}
+	if res == nil || len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, res); err != nil {
+		return fmt.Errorf("fail to decode the response of %s %s: %w", method, u, err)
+	}
+	return nil
+}
+
+// decodeError returns the business code responded by the apiserver, the status code is used if the body is not a business code
+func decodeError(statusCode int, raw []byte) error {
+	code := &bcode.Bcode{}
+	if err := json.Unmarshal(raw, code); err != nil || code.BusinessCode == 0 {
+		code = &bcode.Bcode{BusinessCode: int32(statusCode), Message: strings.TrimSpace(string(raw))}
+	}
+	code.HTTPCode = int32(statusCode)
+	if code.Message == "" {
+		code.Message = http.StatusText(statusCode)
+	}
+	return code
+}
+
+// IsCode returns true if the error is the business code, such as IsCode(err, bcode.ErrProjectIsNotExist)
+func IsCode(err error, code *bcode.Bcode) bool {
+	var b *bcode.Bcode
+	if !errors.As(err, &b) {
+		return false
+	}
+	return b.BusinessCode == code.BusinessCode
+}
+
+// ListOptions are the paging options of the list requests, zero means all
+type ListOptions struct {
+	Page     int
+	PageSize int
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(o.PageSize))
+	}
+	return query
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+type recordedRequest struct {
+	method      string
+	uri         string
+	auth        string
+	contentType string
+	body        string
+}
+
+func newTestServer(t *testing.T, requests *[]recordedRequest, handler func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		*requests = append(*requests, recordedRequest{
+			method:      r.Method,
+			uri:         r.URL.RequestURI(),
+			auth:        r.Header.Get("Authorization"),
+			contentType: r.Header.Get("Content-Type"),
+			body:        string(body),
+		})
+		handler(w, r)
+	}))
+}
+
+func TestClientRequests(t *testing.T) {
+	var requests []recordedRequest
+	server := newTestServer(t, &requests, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/auth/login":
+			_ = json.NewEncoder(w).Encode(apisv1.LoginResponse{AccessToken: "access-token"})
+		case "/api/v1/projects":
+			_ = json.NewEncoder(w).Encode(apisv1.ListProjectResponse{Projects: []*apisv1.ProjectBase{{Name: "default"}}, Total: 1})
+		case "/api/v1/applications/web%20app/deploy":
+			_ = json.NewEncoder(w).Encode(apisv1.ApplicationDeployResponse{})
+		default:
+			_ = json.NewEncoder(w).Encode(apisv1.EmptyResponse{})
+		}
+	})
+	defer server.Close()
+	ctx := context.Background()
+	c := New(server.URL + "/")
+
+	login, err := c.Login(ctx, "admin", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-token", login.AccessToken)
+
+	projects, err := c.ListProjects(ctx, ListOptions{Page: 2, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), projects.Total)
+	assert.Equal(t, "default", projects.Projects[0].Name)
+
+	_, err = c.ListApplications(ctx, ListApplicationOptions{Project: "default", Env: "prod"})
+	assert.NoError(t, err)
+	_, err = c.DeployApplication(ctx, "web app", apisv1.ApplicationDeployRequest{WorkflowName: "workflow-prod"})
+	assert.NoError(t, err)
+	assert.NoError(t, c.RollbackWorkflowRecord(ctx, "web", "workflow-prod", "record-1", "web-v1"))
+	assert.NoError(t, c.DeleteTarget(ctx, "dev"))
+	_, err = c.DisableAddon(ctx, "fluxcd", true)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodPost, uri: "/api/v1/auth/login", contentType: "application/json", body: `{"username":"admin","password":"password"}`},
+		{method: http.MethodGet, uri: "/api/v1/projects?page=2&pageSize=10", auth: "Bearer access-token"},
+		{method: http.MethodGet, uri: "/api/v1/applications?env=prod&project=default", auth: "Bearer access-token"},
+		{method: http.MethodPost, uri: "/api/v1/applications/web%20app/deploy", auth: "Bearer access-token", contentType: "application/json",
+			body: `{"workflowName":"workflow-prod","note":"","triggerType":"","force":false}`},
+		{method: http.MethodGet, uri: "/api/v1/applications/web/workflows/workflow-prod/records/record-1/rollback?rollbackVersion=web-v1", auth: "Bearer access-token"},
+		{method: http.MethodDelete, uri: "/api/v1/targets/dev", auth: "Bearer access-token"},
+		{method: http.MethodPost, uri: "/api/v1/addons/fluxcd/disable?force=true", auth: "Bearer access-token", contentType: "application/json"},
+	}, requests)
+}
+
+func TestClientErrors(t *testing.T) {
+	var requests []recordedRequest
+	server := newTestServer(t, &requests, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/projects/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(bcode.ErrProjectIsNotExist)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("bad gateway\n"))
+		}
+	})
+	defer server.Close()
+	ctx := context.Background()
+	c := New(server.URL, WithToken("api-token"), WithHTTPClient(server.Client()))
+
+	_, err := c.GetProject(ctx, "missing")
+	assert.True(t, IsCode(err, bcode.ErrProjectIsNotExist))
+	assert.Equal(t, int32(http.StatusNotFound), err.(*bcode.Bcode).HTTPCode)
+
+	_, err = c.GetTarget(ctx, "dev")
+	assert.Equal(t, &bcode.Bcode{HTTPCode: http.StatusBadGateway, BusinessCode: http.StatusBadGateway, Message: "bad gateway"}, err)
+	assert.False(t, IsCode(err, bcode.ErrProjectIsNotExist))
+	assert.Equal(t, "Bearer api-token", requests[0].auth)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+// ListEnvs lists the envs, all envs are listed if the project is empty
+func (c *Client) ListEnvs(ctx context.Context, project string, opts ListOptions) (*apisv1.ListEnvResponse, error) {
+	query := opts.query()
+	if project != "" {
+		query.Set("project", project)
+	}
+	var res apisv1.ListEnvResponse
+	if err := c.get(ctx, "/envs", query, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateEnv creates an env
+func (c *Client) CreateEnv(ctx context.Context, req apisv1.CreateEnvRequest) (*apisv1.Env, error) {
+	var res apisv1.Env
+	if err := c.post(ctx, "/envs", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateEnv updates the env
+func (c *Client) UpdateEnv(ctx context.Context, envName string, req apisv1.UpdateEnvRequest) (*apisv1.Env, error) {
+	var res apisv1.Env
+	if err := c.put(ctx, fmt.Sprintf("/envs/%s", url.PathEscape(envName)), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteEnv deletes the env, it fails if there are applications in the env
+func (c *Client) DeleteEnv(ctx context.Context, envName string) error {
+	return c.delete(ctx, fmt.Sprintf("/envs/%s", url.PathEscape(envName)), nil)
+}
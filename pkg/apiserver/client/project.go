@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+// ListProjects lists the projects
+func (c *Client) ListProjects(ctx context.Context, opts ListOptions) (*apisv1.ListProjectResponse, error) {
+	var res apisv1.ListProjectResponse
+	if err := c.get(ctx, "/projects", opts.query(), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateProject creates a project
+func (c *Client) CreateProject(ctx context.Context, req apisv1.CreateProjectRequest) (*apisv1.ProjectBase, error) {
+	var res apisv1.ProjectBase
+	if err := c.post(ctx, "/projects", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetProject gets the project
+func (c *Client) GetProject(ctx context.Context, projectName string) (*apisv1.ProjectBase, error) {
+	var res apisv1.ProjectBase
+	if err := c.get(ctx, fmt.Sprintf("/projects/%s", url.PathEscape(projectName)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateProject updates the project
+func (c *Client) UpdateProject(ctx context.Context, projectName string, req apisv1.UpdateProjectRequest) (*apisv1.ProjectBase, error) {
+	var res apisv1.ProjectBase
+	if err := c.put(ctx, fmt.Sprintf("/projects/%s", url.PathEscape(projectName)), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteProject deletes the project
+func (c *Client) DeleteProject(ctx context.Context, projectName string) error {
+	return c.delete(ctx, fmt.Sprintf("/projects/%s", url.PathEscape(projectName)), nil)
+}
+
+// ListProjectTargets lists the targets of the project
+func (c *Client) ListProjectTargets(ctx context.Context, projectName string) (*apisv1.ListTargetResponse, error) {
+	var res apisv1.ListTargetResponse
+	if err := c.get(ctx, fmt.Sprintf("/projects/%s/targets", url.PathEscape(projectName)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListProjectUsers lists the users of the project
+func (c *Client) ListProjectUsers(ctx context.Context, projectName string) (*apisv1.ListProjectUsersResponse, error) {
+	var res apisv1.ListProjectUsersResponse
+	if err := c.get(ctx, fmt.Sprintf("/projects/%s/users", url.PathEscape(projectName)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// AddProjectUser adds a user to the project
+func (c *Client) AddProjectUser(ctx context.Context, projectName string, req apisv1.AddProjectUserRequest) (*apisv1.ProjectUserBase, error) {
+	var res apisv1.ProjectUserBase
+	if err := c.post(ctx, fmt.Sprintf("/projects/%s/users", url.PathEscape(projectName)), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteProjectUser removes the user from the project
+func (c *Client) DeleteProjectUser(ctx context.Context, projectName, userName string) error {
+	return c.delete(ctx, fmt.Sprintf("/projects/%s/users/%s", url.PathEscape(projectName), url.PathEscape(userName)), nil)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+// ListTargets lists the targets, all targets are listed if the project is empty
+func (c *Client) ListTargets(ctx context.Context, project string, opts ListOptions) (*apisv1.ListTargetResponse, error) {
+	query := opts.query()
+	if project != "" {
+		query.Set("project", project)
+	}
+	var res apisv1.ListTargetResponse
+	if err := c.get(ctx, "/targets", query, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateTarget creates a target
+func (c *Client) CreateTarget(ctx context.Context, req apisv1.CreateTargetRequest) (*apisv1.DetailTargetResponse, error) {
+	var res apisv1.DetailTargetResponse
+	if err := c.post(ctx, "/targets", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetTarget gets the target
+func (c *Client) GetTarget(ctx context.Context, targetName string) (*apisv1.DetailTargetResponse, error) {
+	var res apisv1.DetailTargetResponse
+	if err := c.get(ctx, fmt.Sprintf("/targets/%s", url.PathEscape(targetName)), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateTarget updates the target
+func (c *Client) UpdateTarget(ctx context.Context, targetName string, req apisv1.UpdateTargetRequest) (*apisv1.DetailTargetResponse, error) {
+	var res apisv1.DetailTargetResponse
+	if err := c.put(ctx, fmt.Sprintf("/targets/%s", url.PathEscape(targetName)), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteTarget deletes the target
+func (c *Client) DeleteTarget(ctx context.Context, targetName string) error {
+	return c.delete(ctx, fmt.Sprintf("/targets/%s", url.PathEscape(targetName)), nil)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+// ListApplicationWorkflows lists the workflows of the application
+func (c *Client) ListApplicationWorkflows(ctx context.Context, appName string) (*apisv1.ListWorkflowResponse, error) {
+	var res apisv1.ListWorkflowResponse
+	if err := c.get(ctx, appPath(appName)+"/workflows", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateOrUpdateApplicationWorkflow creates the workflow of the application, or updates it if it exists
+func (c *Client) CreateOrUpdateApplicationWorkflow(ctx context.Context, appName string, req apisv1.CreateWorkflowRequest) (*apisv1.DetailWorkflowResponse, error) {
+	var res apisv1.DetailWorkflowResponse
+	if err := c.post(ctx, appPath(appName)+"/workflows", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetApplicationWorkflow gets the workflow of the application
+func (c *Client) GetApplicationWorkflow(ctx context.Context, appName, workflowName string) (*apisv1.DetailWorkflowResponse, error) {
+	var res apisv1.DetailWorkflowResponse
+	if err := c.get(ctx, workflowPath(appName, workflowName), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateApplicationWorkflow updates the workflow of the application
+func (c *Client) UpdateApplicationWorkflow(ctx context.Context, appName, workflowName string, req apisv1.UpdateWorkflowRequest) (*apisv1.DetailWorkflowResponse, error) {
+	var res apisv1.DetailWorkflowResponse
+	if err := c.put(ctx, workflowPath(appName, workflowName), req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteApplicationWorkflow deletes the workflow of the application
+func (c *Client) DeleteApplicationWorkflow(ctx context.Context, appName, workflowName string) error {
+	return c.delete(ctx, workflowPath(appName, workflowName), nil)
+}
+
+// ListWorkflowRecords lists the records of the workflow
+func (c *Client) ListWorkflowRecords(ctx context.Context, appName, workflowName string, opts ListOptions) (*apisv1.ListWorkflowRecordsResponse, error) {
+	var res apisv1.ListWorkflowRecordsResponse
+	if err := c.get(ctx, workflowPath(appName, workflowName)+"/records", opts.query(), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetWorkflowRecord gets the record of the workflow
+func (c *Client) GetWorkflowRecord(ctx context.Context, appName, workflowName, record string) (*apisv1.DetailWorkflowRecordResponse, error) {
+	var res apisv1.DetailWorkflowRecordResponse
+	if err := c.get(ctx, recordPath(appName, workflowName, record), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ResumeWorkflowRecord resumes the suspended workflow record
+func (c *Client) ResumeWorkflowRecord(ctx context.Context, appName, workflowName, record string) error {
+	return c.get(ctx, recordPath(appName, workflowName, record)+"/resume", nil, nil)
+}
+
+// TerminateWorkflowRecord terminates the workflow record
+func (c *Client) TerminateWorkflowRecord(ctx context.Context, appName, workflowName, record string) error {
+	return c.get(ctx, recordPath(appName, workflowName, record)+"/terminate", nil, nil)
+}
+
+// RollbackWorkflowRecord rolls the application back to the revision, the latest successful revision is used if it is empty
+func (c *Client) RollbackWorkflowRecord(ctx context.Context, appName, workflowName, record, rollbackVersion string) error {
+	query := url.Values{}
+	if rollbackVersion != "" {
+		query.Set("rollbackVersion", rollbackVersion)
+	}
+	return c.get(ctx, recordPath(appName, workflowName, record)+"/rollback", query, nil)
+}
+
+func workflowPath(appName, workflowName string) string {
+	return fmt.Sprintf("%s/workflows/%s", appPath(appName), url.PathEscape(workflowName))
+}
+
+func recordPath(appName, workflowName, record string) string {
+	return fmt.Sprintf("%s/records/%s", workflowPath(appName, workflowName), url.PathEscape(record))
+}
@@ -46,6 +46,17 @@ type Config struct {
 
 	// KubeQPS the QPS of kube client
 	KubeQPS float64
+
+	// AuditLog config for the audit log
+	AuditLog AuditLogConfig
+}
+
+// AuditLogConfig config for the audit log
+type AuditLogConfig struct {
+	// Retention is how long the audit logs are kept, the audit logs are kept forever if it is zero
+	Retention time.Duration
+	// WebhookURL the audit logs are forwarded to the webhook sink if it is not empty
+	WebhookURL string
 }
 
 type leaderConfig struct {
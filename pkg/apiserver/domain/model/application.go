@@ -381,6 +381,32 @@ type ApplicationTrigger struct {
 	Type          string `json:"type"`
 	PayloadType   string `json:"payloadType"`
 	ComponentName string `json:"componentName"`
+	// Secret is used to validate the signature of the Git webhook requests
+	Secret string `json:"secret,omitempty"`
+	// GitRules map the pushed branches and tags to the workflows and the component properties,
+	// the first matched rule is used
+	GitRules []GitTriggerRule `json:"gitRules,omitempty"`
+}
+
+// GitTriggerRule defines how a Git push event deploys the application
+type GitTriggerRule struct {
+	// Branch is the glob pattern of the pushed branch, such as main or release-*
+	Branch string `json:"branch,omitempty"`
+	// Tag is the glob pattern of the pushed tag, such as v*
+	Tag string `json:"tag,omitempty"`
+	// EnvName selects the workflow of the env if the WorkflowName is empty
+	EnvName string `json:"envName,omitempty"`
+	// WorkflowName is the workflow to run, use the workflow of the trigger if both WorkflowName and EnvName are empty
+	WorkflowName string `json:"workflowName,omitempty"`
+	// ComponentName is the component to patch, use the component of the trigger if empty
+	ComponentName string `json:"componentName,omitempty"`
+	// Properties are the patched component properties, the key is the property path split by dots
+	// and the value is a Go template rendered with the push event, such as {"image": "app:{{ .ShortSHA }}"}.
+	// The rendered values are strings unless the keys are listed in the TypedProperties
+	Properties map[string]string `json:"properties,omitempty"`
+	// TypedProperties are the keys of the Properties whose rendered values are decoded as JSON or YAML,
+	// so that the numbers, the booleans, the lists and the objects could be set
+	TypedProperties []string `json:"typedProperties,omitempty"`
 }
 
 const (
@@ -394,6 +420,12 @@ const (
 	PayloadTypeHarbor = "harbor"
 	// PayloadTypeJFrog is the payload type jfrog
 	PayloadTypeJFrog = "jfrog"
+	// PayloadTypeGitHub is the payload type github
+	PayloadTypeGitHub = "github"
+	// PayloadTypeGitLab is the payload type gitlab
+	PayloadTypeGitLab = "gitlab"
+	// PayloadTypeGitea is the payload type gitea
+	PayloadTypeGitea = "gitea"
 
 	// ComponentTypeWebservice is the component type webservice
 	ComponentTypeWebservice = "webservice"
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+func init() {
+	RegisterModel(&AuditLog{})
+}
+
+// AuditLog is the model of the audit log, it records a mutating operation of the apiserver
+type AuditLog struct {
+	BaseModel
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Project   string `json:"project,omitempty"`
+	Action    string `json:"action"`
+	Operation string `json:"operation,omitempty"`
+	// Resource is the path template of the route, such as /api/v1/projects/{projectName}
+	Resource   string `json:"resource"`
+	Path       string `json:"path"`
+	Method     string `json:"method"`
+	BodyDigest string `json:"bodyDigest,omitempty"`
+	StatusCode int    `json:"statusCode"`
+	ClientIP   string `json:"clientIP,omitempty"`
+}
+
+// TableName return custom table name
+func (a *AuditLog) TableName() string {
+	return tableNamePrefix + "audit_log"
+}
+
+// ShortTableName return custom table name
+func (a *AuditLog) ShortTableName() string {
+	return "adt"
+}
+
+// PrimaryKey return custom primary key
+func (a *AuditLog) PrimaryKey() string {
+	return a.ID
+}
+
+// Index return custom index
+func (a *AuditLog) Index() map[string]string {
+	index := make(map[string]string)
+	if a.ID != "" {
+		index["id"] = a.ID
+	}
+	if a.Username != "" {
+		index["username"] = a.Username
+	}
+	if a.Project != "" {
+		index["project"] = a.Project
+	}
+	if a.Action != "" {
+		index["action"] = a.Action
+	}
+	if a.Method != "" {
+		index["method"] = a.Method
+	}
+	return index
+}
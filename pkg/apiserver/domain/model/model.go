@@ -126,6 +126,8 @@ func (j *JSONStruct) RawExtension() *runtime.RawExtension {
 type BaseModel struct {
 	CreateTime time.Time `json:"createTime"`
 	UpdateTime time.Time `json:"updateTime"`
+	// ResourceVersion is increased by the datastore on every update, it's used for the optimistic concurrency control
+	ResourceVersion int64 `json:"resourceVersion"`
 }
 
 // SetCreateTime set create time
@@ -138,6 +140,16 @@ func (m *BaseModel) SetUpdateTime(time time.Time) {
 	m.UpdateTime = time
 }
 
+// GetResourceVersion get resource version
+func (m *BaseModel) GetResourceVersion() int64 {
+	return m.ResourceVersion
+}
+
+// SetResourceVersion set resource version
+func (m *BaseModel) SetResourceVersion(version int64) {
+	m.ResourceVersion = version
+}
+
 func deepCopy(src interface{}) interface{} {
 	dst := reflect.New(reflect.TypeOf(src).Elem())
 
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+func init() {
+	RegisterModel(&OutboundWebhook{})
+	RegisterModel(&WebhookDelivery{})
+}
+
+const (
+	// WebhookEventApplicationDeployed means the workflow of the application is finished successfully
+	WebhookEventApplicationDeployed = "application.deployed"
+	// WebhookEventWorkflowFailed means a step of the workflow is failed
+	WebhookEventWorkflowFailed = "workflow.failed"
+	// WebhookEventWorkflowSuspended means the workflow is suspended by a suspend step and is waiting for the approval
+	WebhookEventWorkflowSuspended = "workflow.suspended"
+	// WebhookEventAddonEnabled means an addon is enabled, it is sent to the webhooks of all projects
+	WebhookEventAddonEnabled = "addon.enabled"
+)
+
+// WebhookEvents all events could be subscribed by the outbound webhooks
+var WebhookEvents = []string{WebhookEventApplicationDeployed, WebhookEventWorkflowFailed, WebhookEventWorkflowSuspended, WebhookEventAddonEnabled}
+
+const (
+	// WebhookDeliveryStatusPending means the delivery is waiting for the next attempt
+	WebhookDeliveryStatusPending = "pending"
+	// WebhookDeliveryStatusSucceeded means the event is delivered
+	WebhookDeliveryStatusSucceeded = "succeeded"
+	// WebhookDeliveryStatusFailed means all attempts of the delivery are failed
+	WebhookDeliveryStatusFailed = "failed"
+)
+
+// OutboundWebhook is the model of the outbound webhook, the subscribed events of the project are sent to the URL
+type OutboundWebhook struct {
+	BaseModel
+	Name        string   `json:"name"`
+	Project     string   `json:"project"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	// Secret is the key of the HMAC signature of the payloads
+	Secret   string `json:"secret"`
+	Disabled bool   `json:"disabled"`
+}
+
+// TableName return custom table name
+func (w *OutboundWebhook) TableName() string {
+	return tableNamePrefix + "outbound_webhook"
+}
+
+// ShortTableName return custom table name
+func (w *OutboundWebhook) ShortTableName() string {
+	return "owh"
+}
+
+// PrimaryKey return custom primary key
+func (w *OutboundWebhook) PrimaryKey() string {
+	return fmt.Sprintf("%s-%s", w.Project, w.Name)
+}
+
+// Index return custom index
+func (w *OutboundWebhook) Index() map[string]string {
+	index := make(map[string]string)
+	if w.Name != "" {
+		index["name"] = w.Name
+	}
+	if w.Project != "" {
+		index["project"] = w.Project
+	}
+	return index
+}
+
+// Subscribed returns whether the event is sent to the webhook
+func (w *OutboundWebhook) Subscribed(event string) bool {
+	if w.Disabled {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is the model of the delivery of an event to an outbound webhook, it is retried with backoff
+type WebhookDelivery struct {
+	BaseModel
+	ID          string `json:"id"`
+	Project     string `json:"project"`
+	WebhookName string `json:"webhookName"`
+	Event       string `json:"event"`
+	Payload     string `json:"payload"`
+	Status      string `json:"status"`
+	Attempts    int    `json:"attempts"`
+	// NextAttemptTime is the time of the next attempt if the status is pending
+	NextAttemptTime time.Time `json:"nextAttemptTime,omitempty"`
+	StatusCode      int       `json:"statusCode,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// TableName return custom table name
+func (d *WebhookDelivery) TableName() string {
+	return tableNamePrefix + "webhook_delivery"
+}
+
+// ShortTableName return custom table name
+func (d *WebhookDelivery) ShortTableName() string {
+	return "whd"
+}
+
+// PrimaryKey return custom primary key
+func (d *WebhookDelivery) PrimaryKey() string {
+	return d.ID
+}
+
+// Index return custom index
+func (d *WebhookDelivery) Index() map[string]string {
+	index := make(map[string]string)
+	if d.ID != "" {
+		index["id"] = d.ID
+	}
+	if d.Project != "" {
+		index["project"] = d.Project
+	}
+	if d.WebhookName != "" {
+		index["webhookName"] = d.WebhookName
+	}
+	if d.Status != "" {
+		index["status"] = d.Status
+	}
+	return index
+}
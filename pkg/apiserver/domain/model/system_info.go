@@ -27,6 +27,8 @@ const (
 	LoginTypeDex string = "dex"
 	// LoginTypeLocal is the local login type
 	LoginTypeLocal string = "local"
+	// LoginTypeLDAP is the LDAP login type
+	LoginTypeLDAP string = "ldap"
 )
 
 // SystemInfo systemInfo model
@@ -38,6 +40,56 @@ type SystemInfo struct {
 	LoginType                   string        `json:"loginType"`
 	DexUserDefaultProjects      []ProjectRef  `json:"projects"`
 	DexUserDefaultPlatformRoles []string      `json:"dexUserDefaultPlatformRoles"`
+	LDAP                        *LDAPConfig   `json:"ldap,omitempty"`
+}
+
+// LDAPConfig the config of the LDAP login
+type LDAPConfig struct {
+	// URL is the address of the LDAP server, such as ldaps://ldap.example.com:636
+	URL string `json:"url"`
+	// StartTLS upgrades the ldap:// connection with the StartTLS operation
+	StartTLS           bool `json:"startTLS,omitempty"`
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// BindDN and BindPassword are used to search the users and the groups, search anonymously if it is empty
+	BindDN       string          `json:"bindDN,omitempty"`
+	BindPassword string          `json:"bindPassword,omitempty"`
+	UserSearch   LDAPUserSearch  `json:"userSearch"`
+	GroupSearch  LDAPGroupSearch `json:"groupSearch,omitempty"`
+	// GroupMappings map the LDAP groups to the roles, the mapped roles are synced on each login
+	GroupMappings []LDAPGroupMapping `json:"groupMappings,omitempty"`
+}
+
+// LDAPUserSearch the config of searching the login user
+type LDAPUserSearch struct {
+	BaseDN string `json:"baseDN"`
+	// Filter is the extra filter of the users, such as (objectClass=person)
+	Filter string `json:"filter,omitempty"`
+	// Username is the attribute matched with the login username, such as uid or sAMAccountName
+	Username string `json:"username"`
+	// EmailAttr is the attribute of the email, the default value is mail
+	EmailAttr string `json:"emailAttr,omitempty"`
+	// NameAttr is the attribute of the display name, the default value is cn
+	NameAttr string `json:"nameAttr,omitempty"`
+}
+
+// LDAPGroupSearch the config of searching the groups of the login user, the groups are not searched if the BaseDN is empty
+type LDAPGroupSearch struct {
+	BaseDN string `json:"baseDN,omitempty"`
+	// Filter is the extra filter of the groups, such as (objectClass=groupOfNames)
+	Filter string `json:"filter,omitempty"`
+	// UserAttr is the attribute of the user matched with the GroupAttr of the group, DN means the DN of the user
+	UserAttr string `json:"userAttr,omitempty"`
+	// GroupAttr is the attribute of the group that contains the members, such as member
+	GroupAttr string `json:"groupAttr,omitempty"`
+	// NameAttr is the attribute of the group name, the default value is cn
+	NameAttr string `json:"nameAttr,omitempty"`
+}
+
+// LDAPGroupMapping grants the platform roles and the project roles to the members of the group
+type LDAPGroupMapping struct {
+	Group         string       `json:"group"`
+	PlatformRoles []string     `json:"platformRoles,omitempty"`
+	Projects      []ProjectRef `json:"projects,omitempty"`
 }
 
 // ProjectRef set the project name and roles
@@ -125,7 +177,8 @@ type Telemetry struct {
 type DexStaticClient struct {
 	ID           string   `json:"id"`
 	Name         string   `json:"name"`
-	Secret       string   `json:"secret"`
+	Secret       string   `json:"secret,omitempty"`
+	Public       bool     `json:"public,omitempty"`
 	RedirectURIs []string `json:"redirectURIs"`
 }
 
@@ -24,12 +24,13 @@ func init() {
 // It includes kubernetes clusters or cloud service providers
 type Target struct {
 	BaseModel
-	Name        string                 `json:"name"`
-	Alias       string                 `json:"alias,omitempty"`
-	Project     string                 `json:"project"`
-	Description string                 `json:"description,omitempty"`
-	Cluster     *ClusterTarget         `json:"cluster,omitempty"`
-	Variable    map[string]interface{} `json:"variable,omitempty"`
+	Name         string                 `json:"name"`
+	Alias        string                 `json:"alias,omitempty"`
+	Project      string                 `json:"project"`
+	Description  string                 `json:"description,omitempty"`
+	Cluster      *ClusterTarget         `json:"cluster,omitempty"`
+	ClusterGroup *ClusterGroupTarget    `json:"clusterGroup,omitempty"`
+	Variable     map[string]interface{} `json:"variable,omitempty"`
 }
 
 // TableName return custom table name
@@ -64,3 +65,9 @@ type ClusterTarget struct {
 	ClusterName string `json:"clusterName" validate:"checkname"`
 	Namespace   string `json:"namespace" optional:"true"`
 }
+
+// ClusterGroupTarget the clusters in one cluster group delivery target
+type ClusterGroupTarget struct {
+	GroupName string `json:"groupName" validate:"checkname"`
+	Namespace string `json:"namespace" optional:"true"`
+}
@@ -29,6 +29,7 @@ func init() {
 	RegisterModel(&Role{})
 	RegisterModel(&Permission{})
 	RegisterModel(&PermissionTemplate{})
+	RegisterModel(&APIToken{})
 }
 
 // DefaultAdminUserName default admin user name
@@ -37,6 +38,15 @@ const DefaultAdminUserName = "admin"
 // DefaultAdminUserAlias default admin user alias
 const DefaultAdminUserAlias = "Administrator"
 
+const (
+	// UserSourceLocal means the user is created in the platform and logs in with the password
+	UserSourceLocal = "local"
+	// UserSourceDex means the user is created by the dex login
+	UserSourceDex = "dex"
+	// UserSourceLDAP means the user is created by the LDAP login
+	UserSourceLDAP = "ldap"
+)
+
 // User is the model of user
 type User struct {
 	BaseModel
@@ -49,6 +59,11 @@ type User struct {
 	// UserRoles binding the platform level roles
 	UserRoles []string `json:"userRoles"`
 	DexSub    string   `json:"dexSub,omitempty"`
+	// ServiceAccountProject is the project that the service account belongs to, the service account can not login
+	// and could only access the APIs with the API tokens.
+	ServiceAccountProject string `json:"serviceAccountProject,omitempty"`
+	// Source is where the user comes from, the users created before it is recorded are the local users
+	Source string `json:"source,omitempty"`
 }
 
 // TableName return custom table name
@@ -78,9 +93,28 @@ func (u *User) Index() map[string]string {
 	if u.DexSub != "" {
 		index["dexSub"] = u.DexSub
 	}
+	if u.ServiceAccountProject != "" {
+		index["serviceAccountProject"] = u.ServiceAccountProject
+	}
+	if u.Source != "" {
+		index["source"] = u.Source
+	}
 	return index
 }
 
+// GetSource return where the user comes from
+func (u *User) GetSource() string {
+	if u.Source == "" {
+		return UserSourceLocal
+	}
+	return u.Source
+}
+
+// IsServiceAccount return whether the user is a service account
+func (u *User) IsServiceAccount() bool {
+	return u.ServiceAccountProject != ""
+}
+
 // ProjectUser is the model of user in project
 type ProjectUser struct {
 	BaseModel
@@ -260,3 +294,55 @@ func (p *PermissionTemplate) Index() map[string]string {
 	}
 	return index
 }
+
+// APIToken is the model of the long-lived API token, only the hash of the token is stored
+type APIToken struct {
+	BaseModel
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Username is the owner of the token, it could be a user or a service account
+	Username string `json:"username"`
+	// Project is the project of the service account that owns the token
+	Project string `json:"project,omitempty"`
+	// Scopes are the names of the permissions that the token could use, the token could use all permissions
+	// of the owner if it is empty
+	Scopes       []string  `json:"scopes,omitempty"`
+	TokenHash    string    `json:"tokenHash"`
+	ExpireTime   time.Time `json:"expireTime,omitempty"`
+	LastUsedTime time.Time `json:"lastUsedTime,omitempty"`
+}
+
+// TableName return custom table name
+func (t *APIToken) TableName() string {
+	return tableNamePrefix + "api_token"
+}
+
+// ShortTableName return custom table name
+func (t *APIToken) ShortTableName() string {
+	return "tkn"
+}
+
+// PrimaryKey return custom primary key
+func (t *APIToken) PrimaryKey() string {
+	return t.ID
+}
+
+// Index return custom index
+func (t *APIToken) Index() map[string]string {
+	index := make(map[string]string)
+	if t.ID != "" {
+		index["id"] = t.ID
+	}
+	if t.Username != "" {
+		index["username"] = t.Username
+	}
+	if t.Project != "" {
+		index["project"] = t.Project
+	}
+	return index
+}
+
+// IsExpired return whether the token is expired, the token never expires if the expire time is not set
+func (t *APIToken) IsExpired() bool {
+	return !t.ExpireTime.IsZero() && time.Now().After(t.ExpireTime)
+}
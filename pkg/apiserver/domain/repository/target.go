@@ -18,6 +18,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	apierror "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,6 +53,33 @@ func CreateTargetNamespace(ctx context.Context, k8sClient client.Client, cluster
 	return nil
 }
 
+// GetTargetClusters returns the clusters of the target, the members of the cluster group are resolved for the target
+// of cluster group
+func GetTargetClusters(ctx context.Context, k8sClient client.Client, target *model.Target) ([]*model.ClusterTarget, error) {
+	if target.Cluster != nil {
+		return []*model.ClusterTarget{target.Cluster}, nil
+	}
+	if target.ClusterGroup == nil {
+		return nil, nil
+	}
+	group, err := multicluster.GetClusterGroup(ctx, k8sClient, target.ClusterGroup.GroupName)
+	if err != nil {
+		if errors.Is(err, multicluster.ErrClusterGroupNotExists) {
+			return nil, bcode.ErrClusterGroupNotExist
+		}
+		return nil, err
+	}
+	clusters, err := group.GetClusters(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	var clusterTargets []*model.ClusterTarget
+	for _, cluster := range clusters {
+		clusterTargets = append(clusterTargets, &model.ClusterTarget{ClusterName: cluster, Namespace: target.ClusterGroup.Namespace})
+	}
+	return clusterTargets, nil
+}
+
 // DeleteTargetNamespace delete the namespace of the target
 func DeleteTargetNamespace(ctx context.Context, k8sClient client.Client, clusterName, namespace, targetName string) error {
 	err := utils.UpdateNamespace(multicluster.ContextWithClusterName(ctx, clusterName), k8sClient, namespace,
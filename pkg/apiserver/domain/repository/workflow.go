@@ -559,7 +559,7 @@ func GenEnvWorkflowStepsAndPolicies(ctx context.Context, kubeClient client.Clien
 		// gen workflow step and policies for all targets
 		for i := range targets {
 			target := targets[i].(*model.Target)
-			if target.Cluster == nil {
+			if target.Cluster == nil && target.ClusterGroup == nil {
 				continue
 			}
 			step := v1beta1.WorkflowStep{
@@ -578,12 +578,15 @@ func GenEnvWorkflowStepsAndPolicies(ctx context.Context, kubeClient client.Clien
 				Creator:       userName,
 				EnvName:       env.Name,
 			}
-			properties, err := model.NewJSONStructByStruct(v1alpha1.TopologyPolicySpec{
-				Placement: v1alpha1.Placement{
-					Clusters: []string{target.Cluster.ClusterName},
-				},
-				Namespace: target.Cluster.Namespace,
-			})
+			var topology v1alpha1.TopologyPolicySpec
+			if target.Cluster != nil {
+				topology.Clusters = []string{target.Cluster.ClusterName}
+				topology.Namespace = target.Cluster.Namespace
+			} else {
+				topology.ClusterGroups = []string{target.ClusterGroup.GroupName}
+				topology.Namespace = target.ClusterGroup.Namespace
+			}
+			properties, err := model.NewJSONStructByStruct(topology)
 			if err != nil {
 				log.Logger.Errorf("fail to create the properties of the topology policy, %s", err.Error())
 				continue
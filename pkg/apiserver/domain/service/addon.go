@@ -40,6 +40,7 @@ import (
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
 	pkgaddon "github.com/oam-dev/kubevela/pkg/addon"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
 	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/clients"
 	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
@@ -153,6 +154,8 @@ type addonServiceImpl struct {
 	apply              apply.Applicator
 	discoveryClient    *discovery.DiscoveryClient
 
+	OutboundWebhookService OutboundWebhookService `inject:""`
+
 	mutex *sync.RWMutex
 }
 
@@ -403,6 +406,7 @@ func (u *addonServiceImpl) EnableAddon(ctx context.Context, name string, args ap
 	for _, r := range registries {
 		err = pkgaddon.EnableAddon(ctx, name, args.Version, u.kubeClient, u.discoveryClient, u.apply, u.config, r, args.Args, u.addonRegistryCache)
 		if err == nil {
+			publishWebhookEvent(ctx, u.OutboundWebhookService, apis.WebhookEvent{Type: model.WebhookEventAddonEnabled, Addon: name})
 			return nil
 		}
 
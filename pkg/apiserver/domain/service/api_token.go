@@ -0,0 +1,379 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
+)
+
+const (
+	// APITokenPrefix is the prefix of the API tokens, it distinguishes the API tokens from the JWT tokens
+	APITokenPrefix = "vela_"
+	// apiTokenLastUsedInterval the last used time of the API token is updated at most once in the interval
+	apiTokenLastUsedInterval = time.Minute
+)
+
+// APITokenService manages the long-lived API tokens and the service accounts that own them
+type APITokenService interface {
+	CreateAPIToken(ctx context.Context, username string, req apisv1.CreateAPITokenRequest) (*apisv1.CreateAPITokenResponse, error)
+	ListAPITokens(ctx context.Context, username string) (*apisv1.ListAPITokenResponse, error)
+	DeleteAPIToken(ctx context.Context, username, tokenID string) error
+	Authenticate(ctx context.Context, token string) (*model.APIToken, error)
+	CreateServiceAccount(ctx context.Context, projectName string, req apisv1.CreateServiceAccountRequest) (*apisv1.ServiceAccountBase, error)
+	GetServiceAccount(ctx context.Context, projectName, name string) (*model.User, error)
+	ListServiceAccounts(ctx context.Context, projectName string) (*apisv1.ListServiceAccountResponse, error)
+	DeleteServiceAccount(ctx context.Context, projectName, name string) error
+}
+
+type apiTokenServiceImpl struct {
+	Store datastore.DataStore `inject:"datastore"`
+}
+
+// NewAPITokenService new API token service
+func NewAPITokenService() APITokenService {
+	return &apiTokenServiceImpl{}
+}
+
+// CreateAPIToken create an API token for the user or the service account, the token is only returned once
+func (t *apiTokenServiceImpl) CreateAPIToken(ctx context.Context, username string, req apisv1.CreateAPITokenRequest) (*apisv1.CreateAPITokenResponse, error) {
+	user := &model.User{Name: username}
+	if err := t.Store.Get(ctx, user); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrUsernameNotExist
+		}
+		return nil, err
+	}
+	if err := t.checkScopes(ctx, user, req.Scopes); err != nil {
+		return nil, err
+	}
+	apiToken := &model.APIToken{
+		Name:     req.Name,
+		Username: user.Name,
+		Project:  user.ServiceAccountProject,
+		Scopes:   req.Scopes,
+	}
+	if req.ExpireTime != nil {
+		if !req.ExpireTime.After(time.Now()) {
+			return nil, bcode.ErrAPITokenExpireTimeInvalid
+		}
+		apiToken.ExpireTime = *req.ExpireTime
+	}
+	id, token, err := generateAPIToken()
+	if err != nil {
+		return nil, err
+	}
+	apiToken.ID = id
+	apiToken.TokenHash = hashAPIToken(token)
+	if err := t.Store.Add(ctx, apiToken); err != nil {
+		return nil, err
+	}
+	return &apisv1.CreateAPITokenResponse{
+		APITokenBase: *convertAPITokenModel(apiToken),
+		Token:        token,
+	}, nil
+}
+
+// ListAPITokens list the API tokens of the user or the service account
+func (t *apiTokenServiceImpl) ListAPITokens(ctx context.Context, username string) (*apisv1.ListAPITokenResponse, error) {
+	entities, err := t.Store.List(ctx, &model.APIToken{Username: username}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListAPITokenResponse{Tokens: []*apisv1.APITokenBase{}}
+	for _, entity := range entities {
+		resp.Tokens = append(resp.Tokens, convertAPITokenModel(entity.(*model.APIToken)))
+	}
+	return resp, nil
+}
+
+// DeleteAPIToken revoke an API token of the user or the service account
+func (t *apiTokenServiceImpl) DeleteAPIToken(ctx context.Context, username, tokenID string) error {
+	apiToken := &model.APIToken{ID: tokenID}
+	if err := t.Store.Get(ctx, apiToken); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return bcode.ErrAPITokenNotExist
+		}
+		return err
+	}
+	if apiToken.Username != username {
+		return bcode.ErrAPITokenNotExist
+	}
+	if err := t.Store.Delete(ctx, apiToken); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return bcode.ErrAPITokenNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+// Authenticate check the API token and return it if it's valid, the last used time of the token is recorded
+func (t *apiTokenServiceImpl) Authenticate(ctx context.Context, token string) (*model.APIToken, error) {
+	id, ok := parseAPITokenID(token)
+	if !ok {
+		return nil, bcode.ErrAPITokenInvalid
+	}
+	apiToken := &model.APIToken{ID: id}
+	if err := t.Store.Get(ctx, apiToken); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrAPITokenInvalid
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIToken(token)), []byte(apiToken.TokenHash)) != 1 {
+		return nil, bcode.ErrAPITokenInvalid
+	}
+	if apiToken.IsExpired() {
+		return nil, bcode.ErrAPITokenExpired
+	}
+	user := &model.User{Name: apiToken.Username}
+	if err := t.Store.Get(ctx, user); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrAPITokenInvalid
+		}
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, bcode.ErrUserAlreadyDisabled
+	}
+	if now := time.Now(); now.Sub(apiToken.LastUsedTime) > apiTokenLastUsedInterval {
+		apiToken.LastUsedTime = now
+		// the last used time is not important enough to conflict with the other requests
+		apiToken.SetResourceVersion(0)
+		if err := t.Store.Put(ctx, apiToken); err != nil {
+			log.Logger.Warnf("failed to update the last used time of the API token %s: %s", apiToken.ID, err.Error())
+		}
+	}
+	return apiToken, nil
+}
+
+// CreateServiceAccount create a service account in the project and bind the project roles to it
+func (t *apiTokenServiceImpl) CreateServiceAccount(ctx context.Context, projectName string, req apisv1.CreateServiceAccountRequest) (*apisv1.ServiceAccountBase, error) {
+	if err := t.Store.Get(ctx, &model.Project{Name: projectName}); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrProjectIsNotExist
+		}
+		return nil, err
+	}
+	for _, role := range req.UserRoles {
+		if err := t.Store.Get(ctx, &model.Role{Name: role, Project: projectName}); err != nil {
+			if errors.Is(err, datastore.ErrRecordNotExist) {
+				return nil, bcode.ErrProjectRoleCheckFailure
+			}
+			return nil, err
+		}
+	}
+	serviceAccount := &model.User{
+		Name:                  req.Name,
+		Alias:                 req.Alias,
+		ServiceAccountProject: projectName,
+	}
+	projectUser := &model.ProjectUser{
+		Username:    req.Name,
+		ProjectName: projectName,
+		UserRoles:   req.UserRoles,
+	}
+	err := t.Store.Transaction(ctx, func(tx datastore.DataStore) error {
+		if err := tx.Add(ctx, serviceAccount); err != nil {
+			return err
+		}
+		return tx.Add(ctx, projectUser)
+	})
+	if err != nil {
+		if errors.Is(err, datastore.ErrRecordExist) {
+			return nil, bcode.ErrServiceAccountIsExist
+		}
+		return nil, err
+	}
+	return convertServiceAccountModel(serviceAccount, projectUser), nil
+}
+
+// GetServiceAccount get the service account of the project
+func (t *apiTokenServiceImpl) GetServiceAccount(ctx context.Context, projectName, name string) (*model.User, error) {
+	serviceAccount := &model.User{Name: name}
+	if err := t.Store.Get(ctx, serviceAccount); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrServiceAccountNotExist
+		}
+		return nil, err
+	}
+	if serviceAccount.ServiceAccountProject != projectName {
+		return nil, bcode.ErrServiceAccountNotExist
+	}
+	return serviceAccount, nil
+}
+
+// ListServiceAccounts list the service accounts of the project
+func (t *apiTokenServiceImpl) ListServiceAccounts(ctx context.Context, projectName string) (*apisv1.ListServiceAccountResponse, error) {
+	entities, err := t.Store.List(ctx, &model.User{ServiceAccountProject: projectName}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListServiceAccountResponse{ServiceAccounts: []*apisv1.ServiceAccountBase{}}
+	for _, entity := range entities {
+		serviceAccount := entity.(*model.User)
+		projectUser := &model.ProjectUser{Username: serviceAccount.Name, ProjectName: projectName}
+		if err := t.Store.Get(ctx, projectUser); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, err
+		}
+		resp.ServiceAccounts = append(resp.ServiceAccounts, convertServiceAccountModel(serviceAccount, projectUser))
+	}
+	return resp, nil
+}
+
+// DeleteServiceAccount delete the service account and revoke all its API tokens
+func (t *apiTokenServiceImpl) DeleteServiceAccount(ctx context.Context, projectName, name string) error {
+	serviceAccount, err := t.GetServiceAccount(ctx, projectName, name)
+	if err != nil {
+		return err
+	}
+	return t.Store.Transaction(ctx, func(tx datastore.DataStore) error {
+		return deleteServiceAccount(ctx, tx, serviceAccount)
+	})
+}
+
+// checkScopes check all the scopes are the names of the permissions that the user could be granted
+func (t *apiTokenServiceImpl) checkScopes(ctx context.Context, user *model.User, scopes []string) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+	// the service account could only be granted the permissions of its project
+	entities, err := t.Store.List(ctx, &model.Permission{Project: user.ServiceAccountProject}, &datastore.ListOptions{
+		FilterOptions: datastore.FilterOptions{In: []datastore.InQueryOption{{Key: "name", Values: scopes}}},
+	})
+	if err != nil {
+		return err
+	}
+	permissions := map[string]bool{}
+	for _, entity := range entities {
+		permissions[entity.(*model.Permission).Name] = true
+	}
+	for _, scope := range scopes {
+		if !permissions[scope] {
+			return bcode.ErrAPITokenScopeInvalid
+		}
+	}
+	return nil
+}
+
+// deleteServiceAccount delete the service account with its project roles and API tokens
+func deleteServiceAccount(ctx context.Context, store datastore.DataStore, serviceAccount *model.User) error {
+	if err := deleteAPITokens(ctx, store, serviceAccount.Name); err != nil {
+		return err
+	}
+	projectUser := &model.ProjectUser{Username: serviceAccount.Name, ProjectName: serviceAccount.ServiceAccountProject}
+	if err := store.Delete(ctx, projectUser); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return err
+	}
+	if err := store.Delete(ctx, serviceAccount); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return err
+	}
+	return nil
+}
+
+// deleteAPITokens revoke all the API tokens of the user
+func deleteAPITokens(ctx context.Context, store datastore.DataStore, username string) error {
+	entities, err := store.List(ctx, &model.APIToken{Username: username}, &datastore.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		if err := store.Delete(ctx, entity); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+			log.Logger.Errorf("failed to delete the API token %s of %s: %s", entity.PrimaryKey(), pkgUtils.Sanitize(username), err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// generateAPIToken generate an API token in the format of vela_<id>_<secret>
+func generateAPIToken() (string, string, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	tokenID := hex.EncodeToString(id)
+	return tokenID, APITokenPrefix + tokenID + "_" + hex.EncodeToString(secret), nil
+}
+
+func parseAPITokenID(token string) (string, bool) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(token, APITokenPrefix), "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0], true
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func convertAPITokenModel(apiToken *model.APIToken) *apisv1.APITokenBase {
+	base := &apisv1.APITokenBase{
+		ID:         apiToken.ID,
+		Name:       apiToken.Name,
+		Username:   apiToken.Username,
+		Project:    apiToken.Project,
+		Scopes:     apiToken.Scopes,
+		CreateTime: apiToken.CreateTime,
+	}
+	if !apiToken.ExpireTime.IsZero() {
+		expireTime := apiToken.ExpireTime
+		base.ExpireTime = &expireTime
+	}
+	if !apiToken.LastUsedTime.IsZero() {
+		lastUsedTime := apiToken.LastUsedTime
+		base.LastUsedTime = &lastUsedTime
+	}
+	return base
+}
+
+func convertServiceAccountModel(serviceAccount *model.User, projectUser *model.ProjectUser) *apisv1.ServiceAccountBase {
+	return &apisv1.ServiceAccountBase{
+		Name:       serviceAccount.Name,
+		Alias:      serviceAccount.Alias,
+		Project:    serviceAccount.ServiceAccountProject,
+		UserRoles:  projectUser.UserRoles,
+		CreateTime: serviceAccount.CreateTime,
+	}
+}
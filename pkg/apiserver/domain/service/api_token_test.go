@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+func TestAPITokenService(t *testing.T) {
+	ctx := context.Background()
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NoError(t, err)
+	assert.NoError(t, ds.BatchAdd(ctx, []datastore.Entity{
+		&model.User{Name: "dev"},
+		&model.Project{Name: "ci"},
+		&model.Role{Name: "app-developer", Project: "ci"},
+		&model.Permission{Name: "project-list"},
+		&model.Permission{Name: "app-management", Project: "ci"},
+	}))
+	tokenService := &apiTokenServiceImpl{Store: ds}
+
+	_, err = tokenService.CreateAPIToken(ctx, "dev", apisv1.CreateAPITokenRequest{Name: "ci", Scopes: []string{"not-exist"}})
+	assert.Equal(t, bcode.ErrAPITokenScopeInvalid, err)
+	past := time.Now().Add(-time.Hour)
+	_, err = tokenService.CreateAPIToken(ctx, "dev", apisv1.CreateAPITokenRequest{Name: "ci", ExpireTime: &past})
+	assert.Equal(t, bcode.ErrAPITokenExpireTimeInvalid, err)
+
+	created, err := tokenService.CreateAPIToken(ctx, "dev", apisv1.CreateAPITokenRequest{Name: "ci", Scopes: []string{"project-list", "app-management"}})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, APITokenPrefix))
+	assert.Equal(t, "dev", created.Username)
+
+	token, err := tokenService.Authenticate(ctx, created.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, token.ID)
+	assert.Equal(t, []string{"project-list", "app-management"}, token.Scopes)
+	assert.False(t, token.LastUsedTime.IsZero())
+	_, err = tokenService.Authenticate(ctx, created.Token+"0")
+	assert.Equal(t, bcode.ErrAPITokenInvalid, err)
+	_, err = tokenService.Authenticate(ctx, APITokenPrefix+"unknown_secret")
+	assert.Equal(t, bcode.ErrAPITokenInvalid, err)
+
+	tokens, err := tokenService.ListAPITokens(ctx, "dev")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tokens.Tokens))
+	assert.NotNil(t, tokens.Tokens[0].LastUsedTime)
+
+	expired := &model.APIToken{ID: token.ID}
+	assert.NoError(t, ds.Get(ctx, expired))
+	expired.ExpireTime = time.Now().Add(-time.Minute)
+	assert.NoError(t, ds.Put(ctx, expired))
+	_, err = tokenService.Authenticate(ctx, created.Token)
+	assert.Equal(t, bcode.ErrAPITokenExpired, err)
+
+	assert.Equal(t, bcode.ErrAPITokenNotExist, tokenService.DeleteAPIToken(ctx, "admin", created.ID))
+	assert.NoError(t, tokenService.DeleteAPIToken(ctx, "dev", created.ID))
+	_, err = tokenService.Authenticate(ctx, created.Token)
+	assert.Equal(t, bcode.ErrAPITokenInvalid, err)
+
+	_, err = tokenService.CreateServiceAccount(ctx, "ci", apisv1.CreateServiceAccountRequest{Name: "deployer", UserRoles: []string{"not-exist"}})
+	assert.Equal(t, bcode.ErrProjectRoleCheckFailure, err)
+	_, err = tokenService.CreateServiceAccount(ctx, "ci", apisv1.CreateServiceAccountRequest{Name: "dev", UserRoles: []string{"app-developer"}})
+	assert.Equal(t, bcode.ErrServiceAccountIsExist, err)
+	serviceAccount, err := tokenService.CreateServiceAccount(ctx, "ci", apisv1.CreateServiceAccountRequest{Name: "deployer", UserRoles: []string{"app-developer"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", serviceAccount.Project)
+	projectUser := &model.ProjectUser{Username: "deployer", ProjectName: "ci"}
+	assert.NoError(t, ds.Get(ctx, projectUser))
+	assert.Equal(t, []string{"app-developer"}, projectUser.UserRoles)
+
+	serviceAccounts, err := tokenService.ListServiceAccounts(ctx, "ci")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(serviceAccounts.ServiceAccounts))
+	assert.Equal(t, []string{"app-developer"}, serviceAccounts.ServiceAccounts[0].UserRoles)
+	_, err = tokenService.GetServiceAccount(ctx, "other", "deployer")
+	assert.Equal(t, bcode.ErrServiceAccountNotExist, err)
+	_, err = tokenService.GetServiceAccount(ctx, "ci", "dev")
+	assert.Equal(t, bcode.ErrServiceAccountNotExist, err)
+
+	// the service account could only be granted the permissions of its project
+	_, err = tokenService.CreateAPIToken(ctx, "deployer", apisv1.CreateAPITokenRequest{Name: "ci", Scopes: []string{"project-list"}})
+	assert.Equal(t, bcode.ErrAPITokenScopeInvalid, err)
+	created, err = tokenService.CreateAPIToken(ctx, "deployer", apisv1.CreateAPITokenRequest{Name: "ci", Scopes: []string{"app-management"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", created.Project)
+	_, err = tokenService.Authenticate(ctx, created.Token)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tokenService.DeleteServiceAccount(ctx, "ci", "deployer"))
+	_, err = tokenService.Authenticate(ctx, created.Token)
+	assert.Equal(t, bcode.ErrAPITokenInvalid, err)
+	assert.ErrorIs(t, ds.Get(ctx, &model.ProjectUser{Username: "deployer", ProjectName: "ci"}), datastore.ErrRecordNotExist)
+	assert.ErrorIs(t, ds.Get(ctx, &model.User{Name: "deployer"}), datastore.ErrRecordNotExist)
+}
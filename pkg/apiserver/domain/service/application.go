@@ -406,6 +406,9 @@ func (c *applicationServiceImpl) CreateApplication(ctx context.Context, req apis
 
 // CreateApplicationTrigger create application trigger
 func (c *applicationServiceImpl) CreateApplicationTrigger(ctx context.Context, app *model.Application, req apisv1.CreateApplicationTriggerRequest) (*apisv1.ApplicationTriggerBase, error) {
+	if err := validateGitTriggerRules(req.PayloadType, req.GitRules); err != nil {
+		return nil, err
+	}
 	trigger := &model.ApplicationTrigger{
 		AppPrimaryKey: app.Name,
 		WorkflowName:  req.WorkflowName,
@@ -416,6 +419,8 @@ func (c *applicationServiceImpl) CreateApplicationTrigger(ctx context.Context, a
 		PayloadType:   req.PayloadType,
 		ComponentName: req.ComponentName,
 		Token:         genWebhookToken(),
+		Secret:        req.Secret,
+		GitRules:      req.GitRules,
 	}
 	if err := c.Store.Add(ctx, trigger); err != nil {
 		log.Logger.Errorf("failed to create application trigger, %s", err.Error())
@@ -433,6 +438,8 @@ func (c *applicationServiceImpl) CreateApplicationTrigger(ctx context.Context, a
 		ComponentName: trigger.ComponentName,
 		CreateTime:    trigger.CreateTime,
 		UpdateTime:    trigger.UpdateTime,
+		HasSecret:     trigger.Secret != "",
+		GitRules:      trigger.GitRules,
 	}, nil
 }
 
@@ -480,6 +487,8 @@ func (c *applicationServiceImpl) ListApplicationTriggers(ctx context.Context, ap
 				UpdateTime:    trigger.UpdateTime,
 				CreateTime:    trigger.CreateTime,
 				ComponentName: trigger.ComponentName,
+				HasSecret:     trigger.Secret != "",
+				GitRules:      trigger.GitRules,
 			})
 		}
 	}
@@ -495,6 +504,9 @@ func (c *applicationServiceImpl) saveApplicationEnvBinding(ctx context.Context,
 }
 
 func (c *applicationServiceImpl) UpdateApplication(ctx context.Context, app *model.Application, req apisv1.UpdateApplicationRequest) (*apisv1.ApplicationBase, error) {
+	if err := checkResourceVersion(app, req.ResourceVersion); err != nil {
+		return nil, err
+	}
 	var project *apisv1.ProjectBase
 	if app.Project != "" {
 		var err error
@@ -674,38 +686,6 @@ func (c *applicationServiceImpl) Deploy(ctx context.Context, app *model.Applicat
 	}
 
 	// step2: check and create deploy event
-	if !req.Force {
-		var lastVersion = model.ApplicationRevision{
-			AppPrimaryKey: app.PrimaryKey(),
-			EnvName:       workflow.EnvName,
-		}
-		list, err := c.Store.List(ctx, &lastVersion, &datastore.ListOptions{
-			PageSize: 1, Page: 1, SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}}})
-		if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
-			log.Logger.Errorf("query app latest revision failure %s", err.Error())
-			return nil, bcode.ErrDeployConflict
-		}
-		if len(list) > 0 {
-			revision := list[0].(*model.ApplicationRevision)
-			var status string
-			if revision.Status == model.RevisionStatusRollback {
-				rollbackRevision := &model.ApplicationRevision{
-					AppPrimaryKey: revision.AppPrimaryKey,
-					Version:       revision.RollbackVersion,
-				}
-				if err := c.Store.Get(ctx, rollbackRevision); err == nil {
-					status = rollbackRevision.Status
-				}
-			} else {
-				status = revision.Status
-			}
-			if status != model.RevisionStatusComplete && status != model.RevisionStatusTerminated {
-				log.Logger.Warnf("last app revision can not complete %s/%s", list[0].(*model.ApplicationRevision).AppPrimaryKey, list[0].(*model.ApplicationRevision).Version)
-				return nil, bcode.ErrDeployConflict
-			}
-		}
-	}
-
 	var appRevision = &model.ApplicationRevision{
 		AppPrimaryKey:  app.PrimaryKey(),
 		Version:        version,
@@ -720,7 +700,21 @@ func (c *applicationServiceImpl) Deploy(ctx context.Context, app *model.Applicat
 		CodeInfo:       req.CodeInfo,
 		ImageInfo:      req.ImageInfo,
 	}
-	if err := c.Store.Add(ctx, appRevision); err != nil {
+	if err := c.Store.Transaction(ctx, func(tx datastore.DataStore) error {
+		if !req.Force {
+			if err := checkLatestRevision(ctx, tx, app, workflow.EnvName); err != nil {
+				return err
+			}
+			// update the app with the version read before, the concurrent deployments are conflicted
+			if err := tx.Put(ctx, app); err != nil {
+				if errors.Is(err, datastore.ErrRecordConflict) {
+					return bcode.ErrDeployConflict
+				}
+				return err
+			}
+		}
+		return tx.Add(ctx, appRevision)
+	}); err != nil {
 		return nil, err
 	}
 	// step3: check and create namespace
@@ -750,19 +744,28 @@ func (c *applicationServiceImpl) Deploy(ctx context.Context, app *model.Applicat
 		log.Logger.Warnf("create workflow record failure %s", err.Error())
 	}
 
-	// step6: update app revision status
+	// step6: update app revision status and change the source of trust
 	appRevision.Status = model.RevisionStatusRunning
-	if err := c.Store.Put(ctx, appRevision); err != nil {
-		log.Logger.Warnf("update app revision failure %s", err.Error())
-	}
-
-	// step7: change the source of trust
-	if app.Labels == nil {
-		app.Labels = make(map[string]string)
-	}
-	app.Labels[model.LabelSourceOfTruth] = model.FromUX
-	if err := c.Store.Put(ctx, app); err != nil {
-		log.Logger.Warnf("failed to update app %s", err.Error())
+	if err := c.Store.Transaction(ctx, func(tx datastore.DataStore) error {
+		if err := tx.Put(ctx, appRevision); err != nil {
+			return fmt.Errorf("update app revision failure %w", err)
+		}
+		// the app may be changed during the deployment, so update the latest one
+		latestApp := &model.Application{Name: app.Name}
+		if err := tx.Get(ctx, latestApp); err != nil {
+			return fmt.Errorf("get app failure %w", err)
+		}
+		if latestApp.Labels == nil {
+			latestApp.Labels = make(map[string]string)
+		}
+		latestApp.Labels[model.LabelSourceOfTruth] = model.FromUX
+		if err := tx.Put(ctx, latestApp); err != nil {
+			return fmt.Errorf("update app failure %w", err)
+		}
+		*app = *latestApp
+		return nil
+	}); err != nil {
+		log.Logger.Warnf("failed to update the app and revision after deploying %s", err.Error())
 	}
 
 	return &apisv1.ApplicationDeployResponse{
@@ -770,6 +773,40 @@ func (c *applicationServiceImpl) Deploy(ctx context.Context, app *model.Applicat
 	}, nil
 }
 
+// checkLatestRevision checks whether the latest revision of the app in the env is finished
+func checkLatestRevision(ctx context.Context, ds datastore.DataStore, app *model.Application, envName string) error {
+	var lastVersion = model.ApplicationRevision{
+		AppPrimaryKey: app.PrimaryKey(),
+		EnvName:       envName,
+	}
+	list, err := ds.List(ctx, &lastVersion, &datastore.ListOptions{
+		PageSize: 1, Page: 1, SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}}})
+	if err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		log.Logger.Errorf("query app latest revision failure %s", err.Error())
+		return bcode.ErrDeployConflict
+	}
+	if len(list) > 0 {
+		revision := list[0].(*model.ApplicationRevision)
+		var status string
+		if revision.Status == model.RevisionStatusRollback {
+			rollbackRevision := &model.ApplicationRevision{
+				AppPrimaryKey: revision.AppPrimaryKey,
+				Version:       revision.RollbackVersion,
+			}
+			if err := ds.Get(ctx, rollbackRevision); err == nil {
+				status = rollbackRevision.Status
+			}
+		} else {
+			status = revision.Status
+		}
+		if status != model.RevisionStatusComplete && status != model.RevisionStatusTerminated {
+			log.Logger.Warnf("last app revision can not complete %s/%s", revision.AppPrimaryKey, revision.Version)
+			return bcode.ErrDeployConflict
+		}
+	}
+	return nil
+}
+
 // sync configs to clusters
 func (c *applicationServiceImpl) syncConfigs4Application(ctx context.Context, app *v1beta1.Application, projectName, envName string) error {
 	var areTerraformComponents = true
@@ -797,9 +834,11 @@ func (c *applicationServiceImpl) syncConfigs4Application(ctx context.Context, ap
 		if err != nil {
 			return err
 		}
-		if target.Cluster != nil {
-			clusterTargets = append(clusterTargets, target.Cluster)
+		targetClusters, err := repository.GetTargetClusters(ctx, c.KubeClient, target)
+		if err != nil {
+			return err
 		}
+		clusterTargets = append(clusterTargets, targetClusters...)
 	}
 
 	if err := SyncConfigs(ctx, c.KubeClient, projectName, clusterTargets); err != nil {
@@ -842,6 +881,7 @@ func (c *applicationServiceImpl) renderOAMApplication(ctx context.Context, appMo
 		labels[key] = value
 	}
 	labels[oam.AnnotationAppName] = appModel.Name
+	labels[oam.LabelProject] = appModel.Project
 	// To take over the application
 	labels[model.LabelSourceOfTruth] = model.FromUX
 
@@ -1054,6 +1094,9 @@ func (c *applicationServiceImpl) GetApplicationComponent(ctx context.Context, ap
 }
 
 func (c *applicationServiceImpl) UpdateComponent(ctx context.Context, app *model.Application, component *model.ApplicationComponent, req apisv1.UpdateApplicationComponentRequest) (*apisv1.ComponentBase, error) {
+	if err := checkResourceVersion(component, req.ResourceVersion); err != nil {
+		return nil, err
+	}
 	if req.Alias != nil {
 		component.Alias = *req.Alias
 	}
@@ -1256,6 +1299,9 @@ func (c *applicationServiceImpl) UpdatePolicy(ctx context.Context, app *model.Ap
 		log.Logger.Warnf("update app policy %s failure %s", app.PrimaryKey(), err.Error())
 		return nil, err
 	}
+	if err := checkResourceVersion(&policy, policyUpdate.ResourceVersion); err != nil {
+		return nil, err
+	}
 	policy.Type = policyUpdate.Type
 	properties, err := model.NewJSONStructByString(policyUpdate.Properties)
 	if err != nil {
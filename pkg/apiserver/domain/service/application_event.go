@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
+)
+
+// applicationEventBuffer is the number of the events buffered for a subscriber, the events are dropped if the
+// subscriber is too slow to receive them
+const applicationEventBuffer = 64
+
+// ApplicationEventService broadcasts the status changes of the applications watched by the informer to the subscribers
+type ApplicationEventService interface {
+	// Notify compares the old and the new application and broadcasts the changes, the old one is nil if the
+	// application is added and the new one is nil if the application is deleted.
+	Notify(old, new *v1beta1.Application)
+	// Subscribe returns the events of the application in the environment, the first event is the current status.
+	// The cancel function must be called to release the subscription.
+	Subscribe(ctx context.Context, app *model.Application, envName string) (<-chan apisv1.ApplicationEvent, func(), error)
+}
+
+type applicationEventServiceImpl struct {
+	EnvService         EnvService         `inject:""`
+	EnvBindingService  EnvBindingService  `inject:""`
+	ApplicationService ApplicationService `inject:""`
+	mutex              sync.RWMutex
+	subscribers        map[string]map[chan apisv1.ApplicationEvent]struct{}
+}
+
+// NewApplicationEventService new application event service
+func NewApplicationEventService() ApplicationEventService {
+	return &applicationEventServiceImpl{subscribers: map[string]map[chan apisv1.ApplicationEvent]struct{}{}}
+}
+
+func applicationEventKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// Notify broadcasts the changes to the subscribers of the application without blocking the informer
+func (a *applicationEventServiceImpl) Notify(old, new *v1beta1.Application) {
+	app := new
+	if app == nil {
+		app = old
+	}
+	if app == nil {
+		return
+	}
+	key := applicationEventKey(app.Namespace, app.Name)
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	subscribers := a.subscribers[key]
+	if len(subscribers) == 0 {
+		return
+	}
+	for _, event := range diffApplicationStatus(old, new) {
+		for subscriber := range subscribers {
+			select {
+			case subscriber <- event:
+			default:
+				log.Logger.Warnf("drop the %s event of the application %s, the subscriber is too slow", event.Type, pkgUtils.Sanitize(key))
+			}
+		}
+	}
+}
+
+// Subscribe subscribes the application deployed in the namespace of the environment
+func (a *applicationEventServiceImpl) Subscribe(ctx context.Context, app *model.Application, envName string) (<-chan apisv1.ApplicationEvent, func(), error) {
+	env, err := a.EnvService.GetEnv(ctx, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+	envBinding, err := a.EnvBindingService.GetEnvBinding(ctx, app, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+	deployName := envBinding.AppDeployName
+	if deployName == "" {
+		deployName = app.Name
+	}
+	status, err := a.ApplicationService.GetApplicationStatus(ctx, app, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := applicationEventKey(env.Namespace, deployName)
+	events := make(chan apisv1.ApplicationEvent, applicationEventBuffer)
+	events <- apisv1.ApplicationEvent{Type: apisv1.ApplicationEventStatus, Time: time.Now(), Status: status}
+	a.mutex.Lock()
+	if a.subscribers[key] == nil {
+		a.subscribers[key] = map[chan apisv1.ApplicationEvent]struct{}{}
+	}
+	a.subscribers[key][events] = struct{}{}
+	a.mutex.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			a.mutex.Lock()
+			defer a.mutex.Unlock()
+			delete(a.subscribers[key], events)
+			if len(a.subscribers[key]) == 0 {
+				delete(a.subscribers, key)
+			}
+			close(events)
+		})
+	}
+	return events, cancel, nil
+}
+
+// diffApplicationStatus returns the changes of the application phase, the workflow steps and the component health
+func diffApplicationStatus(old, new *v1beta1.Application) []apisv1.ApplicationEvent {
+	now := time.Now()
+	if new == nil {
+		return []apisv1.ApplicationEvent{{Type: apisv1.ApplicationEventDeleted, Time: now}}
+	}
+	var oldStatus common.AppStatus
+	if old != nil {
+		oldStatus = old.Status
+	}
+	var events []apisv1.ApplicationEvent
+	if oldStatus.Phase != new.Status.Phase {
+		events = append(events, apisv1.ApplicationEvent{
+			Type: apisv1.ApplicationEventPhase, Time: now, Phase: new.Status.Phase, PreviousPhase: oldStatus.Phase,
+		})
+	}
+
+	// the steps of a new workflow run are compared with nothing
+	oldSteps := map[string]common.WorkflowStepPhase{}
+	if oldStatus.Workflow != nil && new.Status.Workflow != nil && oldStatus.Workflow.AppRevision == new.Status.Workflow.AppRevision &&
+		oldStatus.Workflow.StartTime.Equal(&new.Status.Workflow.StartTime) {
+		for _, step := range oldStatus.Workflow.Steps {
+			oldSteps[step.Name] = step.Phase
+			for _, sub := range step.SubStepsStatus {
+				oldSteps[step.Name+"/"+sub.Name] = sub.Phase
+			}
+		}
+	}
+	if new.Status.Workflow != nil {
+		appendStep := func(key string, step common.StepStatus) {
+			if phase, ok := oldSteps[key]; step.Phase != "" && (!ok || phase != step.Phase) {
+				step := step
+				events = append(events, apisv1.ApplicationEvent{Type: apisv1.ApplicationEventWorkflowStep, Time: now, WorkflowStep: &step})
+			}
+		}
+		for _, step := range new.Status.Workflow.Steps {
+			appendStep(step.Name, step.StepStatus)
+			for _, sub := range step.SubStepsStatus {
+				appendStep(step.Name+"/"+sub.Name, sub.StepStatus)
+			}
+		}
+	}
+
+	oldComponents := map[string]bool{}
+	for _, component := range oldStatus.Services {
+		oldComponents[componentStatusKey(component)] = component.Healthy
+	}
+	for _, component := range new.Status.Services {
+		if healthy, ok := oldComponents[componentStatusKey(component)]; !ok || healthy != component.Healthy {
+			component := component
+			events = append(events, apisv1.ApplicationEvent{Type: apisv1.ApplicationEventComponentHealth, Time: now, Component: &component})
+		}
+	}
+	return events
+}
+
+func componentStatusKey(component common.ApplicationComponentStatus) string {
+	return component.Cluster + "/" + component.Env + "/" + component.Namespace + "/" + component.Name
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+type stubEnvService struct {
+	EnvService
+}
+
+func (s *stubEnvService) GetEnv(ctx context.Context, envName string) (*model.Env, error) {
+	return &model.Env{Name: envName, Namespace: "prod-ns"}, nil
+}
+
+type stubEnvBindingService struct {
+	EnvBindingService
+}
+
+func (s *stubEnvBindingService) GetEnvBinding(ctx context.Context, app *model.Application, envName string) (*model.EnvBinding, error) {
+	return &model.EnvBinding{Name: envName, AppDeployName: app.Name + "-" + envName}, nil
+}
+
+type stubApplicationService struct {
+	ApplicationService
+}
+
+func (s *stubApplicationService) GetApplicationStatus(ctx context.Context, app *model.Application, envName string) (*common.AppStatus, error) {
+	return &common.AppStatus{Phase: common.ApplicationRunning}, nil
+}
+
+func newStubApplicationEventService() *applicationEventServiceImpl {
+	eventService := NewApplicationEventService().(*applicationEventServiceImpl)
+	eventService.EnvService = &stubEnvService{}
+	eventService.EnvBindingService = &stubEnvBindingService{}
+	eventService.ApplicationService = &stubApplicationService{}
+	return eventService
+}
+
+func TestApplicationEventSubscribe(t *testing.T) {
+	eventService := newStubApplicationEventService()
+	events, cancel, err := eventService.Subscribe(context.Background(), &model.Application{Name: "web"}, "prod")
+	assert.NoError(t, err)
+	event := <-events
+	assert.Equal(t, apisv1.ApplicationEventStatus, event.Type)
+	assert.Equal(t, common.ApplicationRunning, event.Status.Phase)
+
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "web-prod", Namespace: "prod-ns"}}
+	app.Status.Phase = common.ApplicationRunning
+	updated := app.DeepCopy()
+	updated.Status.Phase = common.ApplicationRunningWorkflow
+	// the applications of the other environments are not streamed
+	other := updated.DeepCopy()
+	other.Namespace = "default"
+	eventService.Notify(app, other)
+	eventService.Notify(app, updated)
+	event = <-events
+	assert.Equal(t, apisv1.ApplicationEventPhase, event.Type)
+	assert.Equal(t, common.ApplicationRunningWorkflow, event.Phase)
+	assert.Equal(t, common.ApplicationRunning, event.PreviousPhase)
+	eventService.Notify(updated, nil)
+	assert.Equal(t, apisv1.ApplicationEventDeleted, (<-events).Type)
+
+	cancel()
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.Empty(t, eventService.subscribers)
+	// notify without any subscriber
+	eventService.Notify(app, updated)
+}
+
+func TestApplicationEventSlowSubscriber(t *testing.T) {
+	eventService := newStubApplicationEventService()
+	events, cancel, err := eventService.Subscribe(context.Background(), &model.Application{Name: "web"}, "prod")
+	assert.NoError(t, err)
+	defer cancel()
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "web-prod", Namespace: "prod-ns"}}
+	app.Status.Phase = common.ApplicationRunning
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < applicationEventBuffer*2; i++ {
+			eventService.Notify(nil, app)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the notify is blocked by the slow subscriber")
+	}
+	assert.Len(t, events, applicationEventBuffer)
+}
+
+func TestDiffApplicationStatus(t *testing.T) {
+	startTime := metav1.Now()
+	old := &v1beta1.Application{Status: common.AppStatus{
+		Phase: common.ApplicationRunningWorkflow,
+		Workflow: &common.WorkflowStatus{AppRevision: "web-v1", StartTime: startTime, Steps: []common.WorkflowStepStatus{
+			{StepStatus: common.StepStatus{Name: "deploy", Phase: common.WorkflowStepPhaseSucceeded}},
+			{StepStatus: common.StepStatus{Name: "notify", Phase: common.WorkflowStepPhaseRunning}},
+		}},
+		Services: []common.ApplicationComponentStatus{
+			{Name: "frontend", Namespace: "default", Healthy: true},
+			{Name: "backend", Namespace: "default", Healthy: false},
+		},
+	}}
+	new := old.DeepCopy()
+	new.Status.Phase = common.ApplicationRunning
+	new.Status.Workflow.Steps[1].Phase = common.WorkflowStepPhaseSucceeded
+	new.Status.Services[1].Healthy = true
+	new.Status.Services[0].Message = "the message changes without the health"
+
+	events := diffApplicationStatus(old, new)
+	assert.Len(t, events, 3)
+	assert.Equal(t, apisv1.ApplicationEventPhase, events[0].Type)
+	assert.Equal(t, common.ApplicationRunning, events[0].Phase)
+	assert.Equal(t, apisv1.ApplicationEventWorkflowStep, events[1].Type)
+	assert.Equal(t, "notify", events[1].WorkflowStep.Name)
+	assert.Equal(t, apisv1.ApplicationEventComponentHealth, events[2].Type)
+	assert.Equal(t, "backend", events[2].Component.Name)
+	assert.Empty(t, diffApplicationStatus(new, new))
+
+	// all steps of a new workflow run are streamed
+	rerun := new.DeepCopy()
+	rerun.Status.Workflow.AppRevision = "web-v2"
+	events = diffApplicationStatus(new, rerun)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "deploy", events[0].WorkflowStep.Name)
+
+	events = diffApplicationStatus(nil, new)
+	assert.Len(t, events, 5)
+	events = diffApplicationStatus(new, nil)
+	assert.Equal(t, []string{apisv1.ApplicationEventDeleted}, []string{events[0].Type})
+}
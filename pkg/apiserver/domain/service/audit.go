@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/config"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
+)
+
+const (
+	// auditLogCleanBatchSize the number of the audit logs deleted in one round
+	auditLogCleanBatchSize = 100
+	// auditLogSinkQueueSize the number of the audit logs waiting to be forwarded
+	auditLogSinkQueueSize = 1024
+)
+
+// AuditService the audit log service
+type AuditService interface {
+	RecordAuditLog(ctx context.Context, auditLog *model.AuditLog) error
+	ListAuditLogs(ctx context.Context, page, pageSize int, options apisv1.ListAuditLogOptions) (*apisv1.ListAuditLogResponse, error)
+	CleanAuditLogs(ctx context.Context, before time.Time) (int, error)
+}
+
+type auditServiceImpl struct {
+	Store datastore.DataStore `inject:"datastore"`
+	sink  *auditWebhookSink
+}
+
+// NewAuditService new audit service
+func NewAuditService(c config.AuditLogConfig) AuditService {
+	a := &auditServiceImpl{}
+	if c.WebhookURL != "" {
+		a.sink = &auditWebhookSink{
+			url:    c.WebhookURL,
+			client: &http.Client{Timeout: 10 * time.Second},
+			queue:  make(chan *apisv1.AuditLogBase, auditLogSinkQueueSize),
+		}
+	}
+	return a
+}
+
+// RecordAuditLog save the audit log and forward it to the webhook sink
+func (a *auditServiceImpl) RecordAuditLog(ctx context.Context, auditLog *model.AuditLog) error {
+	if auditLog.ID == "" {
+		auditLog.ID = fmt.Sprintf("%d-%s", time.Now().UnixNano(), pkgUtils.RandomString(8))
+	}
+	if err := a.Store.Add(ctx, auditLog); err != nil {
+		return err
+	}
+	if a.sink != nil {
+		a.sink.send(convertAuditLogModel(auditLog))
+	}
+	return nil
+}
+
+// ListAuditLogs list the audit logs, the latest is the first
+func (a *auditServiceImpl) ListAuditLogs(ctx context.Context, page, pageSize int, options apisv1.ListAuditLogOptions) (*apisv1.ListAuditLogResponse, error) {
+	var in []datastore.InQueryOption
+	for key, value := range map[string]string{
+		"username": options.Username,
+		"project":  options.Project,
+		"action":   options.Action,
+		"method":   options.Method,
+	} {
+		if value != "" {
+			in = append(in, datastore.InQueryOption{Key: key, Values: []string{value}})
+		}
+	}
+	fo := datastore.FilterOptions{In: in}
+	entities, err := a.Store.List(ctx, &model.AuditLog{}, &datastore.ListOptions{
+		Page:          page,
+		PageSize:      pageSize,
+		SortBy:        []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+		FilterOptions: fo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListAuditLogResponse{AuditLogs: []*apisv1.AuditLogBase{}}
+	for _, entity := range entities {
+		if auditLog, ok := entity.(*model.AuditLog); ok {
+			resp.AuditLogs = append(resp.AuditLogs, convertAuditLogModel(auditLog))
+		}
+	}
+	count, err := a.Store.Count(ctx, &model.AuditLog{}, &fo)
+	if err != nil {
+		return nil, err
+	}
+	resp.Total = count
+	return resp, nil
+}
+
+// CleanAuditLogs delete the audit logs created before the given time, return the number of the deleted logs
+func (a *auditServiceImpl) CleanAuditLogs(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	for {
+		entities, err := a.Store.List(ctx, &model.AuditLog{}, &datastore.ListOptions{
+			Page:     1,
+			PageSize: auditLogCleanBatchSize,
+			SortBy:   []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderAscending}},
+		})
+		if err != nil {
+			return deleted, err
+		}
+		for _, entity := range entities {
+			auditLog, ok := entity.(*model.AuditLog)
+			if !ok {
+				continue
+			}
+			if !auditLog.CreateTime.Before(before) {
+				return deleted, nil
+			}
+			if err := a.Store.Delete(ctx, auditLog); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(entities) < auditLogCleanBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+func convertAuditLogModel(auditLog *model.AuditLog) *apisv1.AuditLogBase {
+	return &apisv1.AuditLogBase{
+		ID:         auditLog.ID,
+		Username:   auditLog.Username,
+		Project:    auditLog.Project,
+		Action:     auditLog.Action,
+		Operation:  auditLog.Operation,
+		Resource:   auditLog.Resource,
+		Path:       auditLog.Path,
+		Method:     auditLog.Method,
+		BodyDigest: auditLog.BodyDigest,
+		StatusCode: auditLog.StatusCode,
+		ClientIP:   auditLog.ClientIP,
+		CreateTime: auditLog.CreateTime,
+	}
+}
+
+// auditWebhookSink forwards the audit logs to a webhook asynchronously,
+// the audit logs are dropped if the webhook can not keep up with the requests.
+type auditWebhookSink struct {
+	url    string
+	client *http.Client
+	queue  chan *apisv1.AuditLogBase
+	once   sync.Once
+}
+
+func (s *auditWebhookSink) send(auditLog *apisv1.AuditLogBase) {
+	s.once.Do(func() {
+		go s.run()
+	})
+	select {
+	case s.queue <- auditLog:
+	default:
+		log.Logger.Warnf("the audit log sink queue is full, drop the audit log %s", auditLog.ID)
+	}
+}
+
+func (s *auditWebhookSink) run() {
+	for auditLog := range s.queue {
+		if err := s.post(auditLog); err != nil {
+			log.Logger.Errorf("failed to forward the audit log %s to the webhook: %s", auditLog.ID, err.Error())
+		}
+	}
+}
+
+func (s *auditWebhookSink) post(auditLog *apisv1.AuditLogBase) error {
+	body, err := json.Marshal(auditLog)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook responded with the status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/config"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+func TestAuditService(t *testing.T) {
+	ctx := context.Background()
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NoError(t, err)
+
+	received := make(chan apisv1.AuditLogBase, 3)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var auditLog apisv1.AuditLogBase
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&auditLog))
+		received <- auditLog
+	}))
+	defer sink.Close()
+
+	auditService := NewAuditService(config.AuditLogConfig{WebhookURL: sink.URL}).(*auditServiceImpl)
+	auditService.Store = ds
+
+	for _, auditLog := range []*model.AuditLog{
+		{Username: "admin", Project: "default", Action: "create", Method: http.MethodPost, Path: "/api/v1/projects/default/applications", StatusCode: 200},
+		{Username: "admin", Action: "delete", Method: http.MethodDelete, Path: "/api/v1/users/dev", StatusCode: 200},
+		{Username: "dev", Project: "default", Action: "update", Method: http.MethodPut, Path: "/api/v1/applications/app", StatusCode: 403},
+	} {
+		assert.NoError(t, auditService.RecordAuditLog(ctx, auditLog))
+		assert.NotEmpty(t, auditLog.ID)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case auditLog := <-received:
+			assert.NotEmpty(t, auditLog.Username)
+		case <-time.After(10 * time.Second):
+			t.Fatal("the audit log is not forwarded to the webhook")
+		}
+	}
+
+	resp, err := auditService.ListAuditLogs(ctx, 0, 0, apisv1.ListAuditLogOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Total)
+	assert.Equal(t, "dev", resp.AuditLogs[0].Username)
+
+	resp, err = auditService.ListAuditLogs(ctx, 1, 1, apisv1.ListAuditLogOptions{Username: "admin"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Equal(t, 1, len(resp.AuditLogs))
+	assert.Equal(t, "delete", resp.AuditLogs[0].Action)
+
+	resp, err = auditService.ListAuditLogs(ctx, 0, 0, apisv1.ListAuditLogOptions{Project: "default", Action: "create"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Total)
+
+	deleted, err := auditService.CleanAuditLogs(ctx, resp.AuditLogs[0].CreateTime.Add(time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	deleted, err = auditService.CleanAuditLogs(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	resp, err = auditService.ListAuditLogs(ctx, 0, 0, apisv1.ListAuditLogOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), resp.Total)
+}
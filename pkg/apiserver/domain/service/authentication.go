@@ -43,12 +43,13 @@ import (
 	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	"github.com/oam-dev/kubevela/pkg/auth"
 )
 
 const (
 	keyDex             = "dex"
-	dexConfigName      = "dex-config"
-	secretDexConfigKey = "config.yaml"
+	dexConfigName      = auth.DexConfigSecretName
+	secretDexConfigKey = auth.DexConfigSecretKey
 	dexAddonName       = "addon-dex"
 	jwtIssuer          = "vela-issuer"
 
@@ -168,6 +169,11 @@ func (a *authenticationServiceImpl) Login(ctx context.Context, loginReq apisv1.L
 		if err != nil {
 			return nil, err
 		}
+	case loginType == model.LoginTypeLDAP:
+		handler, err = a.newLDAPHandler(sysInfo.LDAP, loginReq)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, bcode.ErrUnsupportedLoginType
 	}
@@ -279,10 +285,14 @@ func generateDexConfig(ctx context.Context, kubeClient client.Client, update *mo
 	if err := yaml.Unmarshal(secret.Data[secretDexConfigKey], dexConfig); err != nil {
 		return err
 	}
+	if len(dexConfig.StaticClients) < 1 {
+		return bcode.ErrInvalidDexConfig
+	}
 	if update.VelaAddress != "" {
 		dexConfig.Issuer = fmt.Sprintf("%s/dex", update.VelaAddress)
 		dexConfig.StaticClients[0].RedirectURIs = []string{fmt.Sprintf("%s/callback", update.VelaAddress)}
 	}
+	dexConfig.StaticClients = ensureDexKubeLoginClient(dexConfig.StaticClients)
 	if update.Connectors != nil {
 		dexConfig.Connectors = update.Connectors
 	}
@@ -314,6 +324,27 @@ func generateDexConfig(ctx context.Context, kubeClient client.Client, update *mo
 	return nil
 }
 
+// newDexKubeLoginClient returns the public client used by the kubectl oidc-login plugin in the OIDC kubeconfig, which
+// has no secret as the plugin runs on the machines of users
+func newDexKubeLoginClient() model.DexStaticClient {
+	return model.DexStaticClient{
+		ID:           auth.DexKubeLoginClientID,
+		Name:         auth.DexKubeLoginClientName,
+		Public:       true,
+		RedirectURIs: auth.DexKubeLoginRedirectURIs,
+	}
+}
+
+// ensureDexKubeLoginClient add the kubelogin client into the static clients if not exists
+func ensureDexKubeLoginClient(clients []model.DexStaticClient) []model.DexStaticClient {
+	for _, c := range clients {
+		if c.ID == auth.DexKubeLoginClientID {
+			return clients
+		}
+	}
+	return append(clients, newDexKubeLoginClient())
+}
+
 func initDexConfig(ctx context.Context, kubeClient client.Client, velaAddress string) (*corev1.Secret, error) {
 	dexConfig := model.DexConfig{
 		Issuer: fmt.Sprintf("%s/dex", velaAddress),
@@ -333,6 +364,7 @@ func initDexConfig(ctx context.Context, kubeClient client.Client, velaAddress st
 				Secret:       "velaux-secret",
 				RedirectURIs: []string{fmt.Sprintf("%s/callback", velaAddress)},
 			},
+			newDexKubeLoginClient(),
 		},
 		EnablePasswordDB: true,
 	}
@@ -417,6 +449,10 @@ func getDexConfig(ctx context.Context, kubeClient client.Client) (*model.DexConf
 			return nil, err
 		}
 	}
+	return parseDexConfig(dexConfigSecret)
+}
+
+func parseDexConfig(dexConfigSecret *corev1.Secret) (*model.DexConfig, error) {
 	if dexConfigSecret.Data == nil {
 		return nil, bcode.ErrInvalidDexConfig
 	}
@@ -494,6 +530,7 @@ func (d *dexHandlerImpl) login(ctx context.Context) (*apisv1.UserBase, error) {
 			DexSub:        claims.Sub,
 			Alias:         claims.Name,
 			LastLoginTime: time.Now(),
+			Source:        model.UserSourceDex,
 		}
 		if systemInfo != nil {
 			user.UserRoles = systemInfo.DexUserDefaultPlatformRoles
@@ -527,6 +564,10 @@ func (l *localHandlerImpl) login(ctx context.Context) (*apisv1.UserBase, error)
 		}
 		return nil, err
 	}
+	// the service accounts could only access the APIs with the API tokens
+	if user.IsServiceAccount() {
+		return nil, bcode.ErrUsernameNotExist
+	}
 	if err := compareHashWithPassword(user.Password, l.password); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,341 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
+)
+
+const (
+	ldapTimeout = 10 * time.Second
+	// ldapUserAttrDN means the DN of the user entry is matched with the members of the groups
+	ldapUserAttrDN = "DN"
+)
+
+type ldapHandlerImpl struct {
+	Store    datastore.DataStore
+	config   *model.LDAPConfig
+	username string
+	password string
+}
+
+// ldapUser is the user found in the LDAP server
+type ldapUser struct {
+	dn     string
+	name   string
+	email  string
+	alias  string
+	groups []string
+}
+
+func (a *authenticationServiceImpl) newLDAPHandler(config *model.LDAPConfig, req apisv1.LoginRequest) (*ldapHandlerImpl, error) {
+	// the LDAP server treats the bind with an empty password as an unauthenticated bind, which always succeeds
+	if req.Username == "" || req.Password == "" {
+		return nil, bcode.ErrInvalidLoginRequest
+	}
+	if config == nil {
+		return nil, bcode.ErrInvalidLDAPConfig
+	}
+	return &ldapHandlerImpl{
+		Store:    a.Store,
+		config:   config,
+		username: req.Username,
+		password: req.Password,
+	}, nil
+}
+
+func (l *ldapHandlerImpl) login(ctx context.Context) (*apisv1.UserBase, error) {
+	found, err := searchLDAPUser(l.config, l.username, l.password)
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{Name: found.name}
+	if err := l.Store.Get(ctx, user); err != nil {
+		if !errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, err
+		}
+		user = &model.User{Name: found.name, Source: model.UserSourceLDAP}
+	} else if user.IsServiceAccount() {
+		return nil, bcode.ErrUsernameNotExist
+	} else if user.GetSource() != model.UserSourceLDAP {
+		// the LDAP identity must not take over the local or dex user with the same name
+		log.Logger.Warnf("the LDAP user %s conflicts with the %s user", pkgUtils.Sanitize(user.Name), user.GetSource())
+		return nil, bcode.ErrUserSourceConflict
+	}
+	if found.email != "" {
+		user.Email = found.email
+	}
+	if found.alias != "" {
+		user.Alias = found.alias
+	}
+	user.LastLoginTime = time.Now()
+	user.UserRoles = syncLDAPRoles(user.UserRoles, l.config.GroupMappings, found.groups, func(mapping model.LDAPGroupMapping) []string {
+		return mapping.PlatformRoles
+	})
+	if user.CreateTime.IsZero() {
+		if err := l.Store.Add(ctx, user); err != nil {
+			log.Logger.Errorf("failed to save the user from the LDAP: %s", err.Error())
+			return nil, err
+		}
+	} else if err := l.Store.Put(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := l.syncProjectRoles(ctx, user.Name, found.groups); err != nil {
+		log.Logger.Errorf("failed to sync the project roles of the LDAP user %s: %s", pkgUtils.Sanitize(user.Name), err.Error())
+	}
+	return convertUserBase(user), nil
+}
+
+// syncProjectRoles sync the roles of the projects in the group mappings, the roles not in any mapping are kept
+func (l *ldapHandlerImpl) syncProjectRoles(ctx context.Context, username string, groups []string) error {
+	var projects []string
+	for _, mapping := range l.config.GroupMappings {
+		for _, project := range mapping.Projects {
+			if !pkgUtils.StringsContain(projects, project.Name) {
+				projects = append(projects, project.Name)
+			}
+		}
+	}
+	for _, project := range projects {
+		if err := l.Store.Get(ctx, &model.Project{Name: project}); err != nil {
+			if errors.Is(err, datastore.ErrRecordNotExist) {
+				log.Logger.Warnf("the project %s in the LDAP group mappings is not exist", project)
+				continue
+			}
+			return err
+		}
+		projectRoles := func(mapping model.LDAPGroupMapping) []string {
+			var roles []string
+			for _, ref := range mapping.Projects {
+				if ref.Name == project {
+					roles = append(roles, ref.Roles...)
+				}
+			}
+			return roles
+		}
+		projectUser := &model.ProjectUser{Username: username, ProjectName: project}
+		exist := true
+		if err := l.Store.Get(ctx, projectUser); err != nil {
+			if !errors.Is(err, datastore.ErrRecordNotExist) {
+				return err
+			}
+			exist = false
+		}
+		var roles []string
+		for _, role := range syncLDAPRoles(projectUser.UserRoles, l.config.GroupMappings, groups, projectRoles) {
+			if err := l.Store.Get(ctx, &model.Role{Name: role, Project: project}); err != nil {
+				log.Logger.Warnf("the role %s of the project %s in the LDAP group mappings is not exist", role, project)
+				continue
+			}
+			roles = append(roles, role)
+		}
+		switch {
+		case len(roles) == 0 && exist:
+			if err := l.Store.Delete(ctx, projectUser); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+				return err
+			}
+		case len(roles) > 0 && exist:
+			projectUser.UserRoles = roles
+			if err := l.Store.Put(ctx, projectUser); err != nil {
+				return err
+			}
+		case len(roles) > 0:
+			projectUser.UserRoles = roles
+			if err := l.Store.Add(ctx, projectUser); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncLDAPRoles removes the roles managed by the group mappings from the current roles, and adds the roles mapped from
+// the groups of the user. The roles that are not managed by the group mappings are kept.
+func syncLDAPRoles(current []string, mappings []model.LDAPGroupMapping, groups []string, rolesOf func(mapping model.LDAPGroupMapping) []string) []string {
+	var managed, granted []string
+	for _, mapping := range mappings {
+		roles := rolesOf(mapping)
+		managed = append(managed, roles...)
+		if pkgUtils.StringsContain(groups, mapping.Group) {
+			granted = append(granted, roles...)
+		}
+	}
+	var roles []string
+	for _, role := range current {
+		if !pkgUtils.StringsContain(managed, role) && !pkgUtils.StringsContain(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	for _, role := range granted {
+		if !pkgUtils.StringsContain(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// searchLDAPUser finds the user by the username, verifies the password by binding as the user and searches the groups
+func searchLDAPUser(config *model.LDAPConfig, username, password string) (*ldapUser, error) {
+	conn, err := dialLDAP(config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	emailAttr := defaultString(config.UserSearch.EmailAttr, "mail")
+	nameAttr := defaultString(config.UserSearch.NameAttr, "cn")
+	attributes := []string{config.UserSearch.Username, emailAttr, nameAttr}
+	userAttr := defaultString(config.GroupSearch.UserAttr, ldapUserAttrDN)
+	if userAttr != ldapUserAttrDN {
+		attributes = append(attributes, userAttr)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		config.UserSearch.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(ldapTimeout.Seconds()), false,
+		andLDAPFilter(config.UserSearch.Filter, fmt.Sprintf("(%s=%s)", config.UserSearch.Username, ldap.EscapeFilter(username))),
+		attributes, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the LDAP user: %w", err)
+	}
+	switch len(result.Entries) {
+	case 0:
+		return nil, bcode.ErrUsernameNotExist
+	case 1:
+	default:
+		return nil, fmt.Errorf("there are multiple LDAP users matched with the username %s", pkgUtils.Sanitize(username))
+	}
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, bcode.ErrUserInconsistentPassword
+		}
+		return nil, fmt.Errorf("failed to bind as the LDAP user: %w", err)
+	}
+	user := &ldapUser{
+		dn:    entry.DN,
+		name:  strings.ToLower(entry.GetAttributeValue(config.UserSearch.Username)),
+		email: entry.GetAttributeValue(emailAttr),
+		alias: entry.GetAttributeValue(nameAttr),
+	}
+	if user.name == "" {
+		user.name = strings.ToLower(username)
+	}
+	if config.GroupSearch.BaseDN == "" {
+		return user, nil
+	}
+
+	// the groups are searched with the bind DN, the user may not have the permission to search them
+	if config.BindDN != "" {
+		if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind as the LDAP bind DN: %w", err)
+		}
+	}
+	member := entry.DN
+	if userAttr != ldapUserAttrDN {
+		member = entry.GetAttributeValue(userAttr)
+	}
+	groupNameAttr := defaultString(config.GroupSearch.NameAttr, "cn")
+	result, err = conn.Search(ldap.NewSearchRequest(
+		config.GroupSearch.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(ldapTimeout.Seconds()), false,
+		andLDAPFilter(config.GroupSearch.Filter, fmt.Sprintf("(%s=%s)", defaultString(config.GroupSearch.GroupAttr, "member"), ldap.EscapeFilter(member))),
+		[]string{groupNameAttr}, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the LDAP groups: %w", err)
+	}
+	for _, group := range result.Entries {
+		if name := group.GetAttributeValue(groupNameAttr); name != "" {
+			user.groups = append(user.groups, name)
+		}
+	}
+	return user, nil
+}
+
+// checkLDAPConfig checks the required fields and the bind DN, so that the users are not locked out by a wrong config
+func checkLDAPConfig(config *model.LDAPConfig) error {
+	if config == nil || config.URL == "" || config.UserSearch.BaseDN == "" || config.UserSearch.Username == "" {
+		return bcode.ErrInvalidLDAPConfig
+	}
+	conn, err := dialLDAP(config)
+	if err != nil {
+		return err
+	}
+	conn.Close()
+	return nil
+}
+
+// dialLDAP connects to the LDAP server and binds as the bind DN
+func dialLDAP(config *model.LDAPConfig) (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{
+		// #nosec G402 it is configured by the administrator for the self-signed certificates
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	conn, err := ldap.DialURL(config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: ldapTimeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the LDAP server: %w", err)
+	}
+	conn.SetTimeout(ldapTimeout)
+	if config.StartTLS {
+		if u, err := url.Parse(config.URL); err == nil {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start the TLS of the LDAP connection: %w", err)
+		}
+	}
+	if config.BindDN != "" {
+		if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to bind as the LDAP bind DN: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func andLDAPFilter(extra, filter string) string {
+	if extra == "" {
+		return filter
+	}
+	if !strings.HasPrefix(extra, "(") {
+		extra = "(" + extra + ")"
+	}
+	return fmt.Sprintf("(&%s%s)", extra, filter)
+}
+
+func defaultString(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
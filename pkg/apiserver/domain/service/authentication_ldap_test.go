@@ -0,0 +1,293 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+// fakeLDAPServer is a minimal LDAP server which supports the bind and the search with the and, equality and present filters
+type fakeLDAPServer struct {
+	listener  net.Listener
+	mutex     sync.Mutex
+	passwords map[string]string
+	entries   []*ldap.Entry
+}
+
+func newFakeLDAPServer(t *testing.T) *fakeLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := &fakeLDAPServer{listener: listener, passwords: map[string]string{}}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeLDAPServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *fakeLDAPServer) setEntry(dn, password string, attributes map[string][]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if password != "" {
+		s.passwords[dn] = password
+	}
+	for i, entry := range s.entries {
+		if entry.DN == dn {
+			s.entries[i] = ldap.NewEntry(dn, attributes)
+			return
+		}
+	}
+	s.entries = append(s.entries, ldap.NewEntry(dn, attributes))
+}
+
+func (s *fakeLDAPServer) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn, password := op.Children[1].Data.String(), op.Children[2].Data.String()
+			s.mutex.Lock()
+			expected, ok := s.passwords[dn]
+			s.mutex.Unlock()
+			code := uint16(ldap.LDAPResultSuccess)
+			if !ok || expected != password {
+				code = ldap.LDAPResultInvalidCredentials
+			}
+			_, err = conn.Write(ldapResponse(messageID, ldap.ApplicationBindResponse, code).Bytes())
+		case ldap.ApplicationSearchRequest:
+			baseDN, filter := strings.ToLower(op.Children[0].Data.String()), op.Children[6]
+			s.mutex.Lock()
+			for _, entry := range s.entries {
+				if strings.HasSuffix(strings.ToLower(entry.DN), baseDN) && matchLDAPFilter(filter, entry) {
+					if _, err = conn.Write(ldapSearchEntry(messageID, entry).Bytes()); err != nil {
+						break
+					}
+				}
+			}
+			s.mutex.Unlock()
+			if err == nil {
+				_, err = conn.Write(ldapResponse(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess).Bytes())
+			}
+		default:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func matchLDAPFilter(filter *ber.Packet, entry *ldap.Entry) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchLDAPFilter(child, entry) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterEqualityMatch:
+		value := filter.Children[1].Data.String()
+		for _, v := range entry.GetAttributeValues(filter.Children[0].Data.String()) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+	case ldap.FilterPresent:
+		return len(entry.GetAttributeValues(filter.Data.String())) > 0
+	}
+	return false
+}
+
+func ldapMessage(messageID int64, op *ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	packet.AppendChild(op)
+	return packet
+}
+
+func ldapResponse(messageID int64, tag ber.Tag, code uint16) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return ldapMessage(messageID, op)
+}
+
+func ldapSearchEntry(messageID int64, entry *ldap.Entry) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "Object Name"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, attr := range entry.Attributes {
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attr.Name, "Type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range attr.Values {
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(values)
+		attributes.AppendChild(attribute)
+	}
+	op.AppendChild(attributes)
+	return ldapMessage(messageID, op)
+}
+
+func TestLDAPLogin(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeLDAPServer(t)
+	const (
+		adminDN = "cn=admin,dc=example,dc=org"
+		aliceDN = "uid=alice,ou=people,dc=example,dc=org"
+		bobDN   = "uid=bob,ou=people,dc=example,dc=org"
+		daveDN  = "uid=dave,ou=people,dc=example,dc=org"
+	)
+	server.setEntry(adminDN, "admin-secret", map[string][]string{"cn": {"admin"}})
+	server.setEntry(aliceDN, "alice-secret", map[string][]string{
+		"objectClass": {"person"}, "uid": {"Alice"}, "mail": {"alice@example.org"}, "cn": {"Alice Liddell"},
+	})
+	server.setEntry(bobDN, "bob-secret", map[string][]string{"objectClass": {"person"}, "uid": {"bob"}})
+	server.setEntry(daveDN, "dave-secret", map[string][]string{"objectClass": {"person"}, "uid": {"dave"}})
+	setGroup := func(name string, members ...string) {
+		server.setEntry(fmt.Sprintf("cn=%s,ou=groups,dc=example,dc=org", name), "", map[string][]string{
+			"objectClass": {"groupOfNames"}, "cn": {name}, "member": members,
+		})
+	}
+	setGroup("vela-admins", aliceDN)
+	setGroup("developers", aliceDN, bobDN)
+
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NoError(t, err)
+	assert.NoError(t, ds.BatchAdd(ctx, []datastore.Entity{
+		&model.Project{Name: "demo"},
+		&model.Role{Name: "app-developer", Project: "demo"},
+	}))
+	config := &model.LDAPConfig{
+		URL:          server.url(),
+		BindDN:       adminDN,
+		BindPassword: "admin-secret",
+		UserSearch:   model.LDAPUserSearch{BaseDN: "ou=people,dc=example,dc=org", Filter: "objectClass=person", Username: "uid"},
+		GroupSearch:  model.LDAPGroupSearch{BaseDN: "ou=groups,dc=example,dc=org", Filter: "(objectClass=groupOfNames)"},
+		GroupMappings: []model.LDAPGroupMapping{
+			{Group: "vela-admins", PlatformRoles: []string{"admin"}},
+			{Group: "developers", Projects: []model.ProjectRef{{Name: "demo", Roles: []string{"app-developer", "not-exist"}}}},
+		},
+	}
+	assert.NoError(t, checkLDAPConfig(config))
+	assert.Error(t, checkLDAPConfig(&model.LDAPConfig{URL: config.URL, BindDN: adminDN, BindPassword: "wrong", UserSearch: config.UserSearch}))
+	assert.Equal(t, bcode.ErrInvalidLDAPConfig, checkLDAPConfig(&model.LDAPConfig{URL: config.URL}))
+
+	authService := &authenticationServiceImpl{Store: ds}
+	login := func(username, password string) (*apisv1.UserBase, error) {
+		handler, err := authService.newLDAPHandler(config, apisv1.LoginRequest{Username: username, Password: password})
+		if err != nil {
+			return nil, err
+		}
+		return handler.login(ctx)
+	}
+	_, err = login("alice", "")
+	assert.Equal(t, bcode.ErrInvalidLoginRequest, err)
+	_, err = login("alice", "wrong")
+	assert.Equal(t, bcode.ErrUserInconsistentPassword, err)
+	_, err = login("carol", "carol-secret")
+	assert.Equal(t, bcode.ErrUsernameNotExist, err)
+
+	user, err := login("alice", "alice-secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	assert.Equal(t, "alice@example.org", user.Email)
+	assert.Equal(t, "Alice Liddell", user.Alias)
+	assert.Equal(t, model.UserSourceLDAP, user.Source)
+	stored := &model.User{Name: "alice"}
+	assert.NoError(t, ds.Get(ctx, stored))
+	assert.Equal(t, model.UserSourceLDAP, stored.Source)
+	assert.Equal(t, []string{"admin"}, stored.UserRoles)
+	projectUser := &model.ProjectUser{Username: "alice", ProjectName: "demo"}
+	assert.NoError(t, ds.Get(ctx, projectUser))
+	assert.Equal(t, []string{"app-developer"}, projectUser.UserRoles)
+
+	// the roles not managed by the group mappings are kept after the groups change
+	stored.UserRoles = append(stored.UserRoles, "auditor")
+	assert.NoError(t, ds.Put(ctx, stored))
+	setGroup("vela-admins", bobDN)
+	_, err = login("alice", "alice-secret")
+	assert.NoError(t, err)
+	stored = &model.User{Name: "alice"}
+	assert.NoError(t, ds.Get(ctx, stored))
+	assert.Equal(t, []string{"auditor"}, stored.UserRoles)
+
+	setGroup("developers", bobDN)
+	_, err = login("alice", "alice-secret")
+	assert.NoError(t, err)
+	assert.Equal(t, datastore.ErrRecordNotExist, ds.Get(ctx, &model.ProjectUser{Username: "alice", ProjectName: "demo"}))
+
+	// the LDAP identity can not log into the local user with the same name
+	assert.NoError(t, ds.Add(ctx, &model.User{Name: "dave", Password: "hash", UserRoles: []string{"admin"}}))
+	_, err = login("dave", "dave-secret")
+	assert.Equal(t, bcode.ErrUserSourceConflict, err)
+	dave := &model.User{Name: "dave"}
+	assert.NoError(t, ds.Get(ctx, dave))
+	assert.Equal(t, model.UserSourceLocal, dave.GetSource())
+	assert.True(t, dave.LastLoginTime.IsZero())
+
+	// the service accounts can not log in with the LDAP
+	assert.NoError(t, ds.Add(ctx, &model.User{Name: "bob", ServiceAccountProject: "demo"}))
+	_, err = login("bob", "bob-secret")
+	assert.Equal(t, bcode.ErrUsernameNotExist, err)
+}
+
+func TestSyncLDAPRoles(t *testing.T) {
+	mappings := []model.LDAPGroupMapping{
+		{Group: "admins", PlatformRoles: []string{"admin"}},
+		{Group: "ops", PlatformRoles: []string{"admin", "operator"}},
+	}
+	platformRoles := func(mapping model.LDAPGroupMapping) []string { return mapping.PlatformRoles }
+	assert.Equal(t, []string{"custom", "admin", "operator"}, syncLDAPRoles([]string{"custom", "admin"}, mappings, []string{"ops"}, platformRoles))
+	assert.Equal(t, []string{"custom"}, syncLDAPRoles([]string{"custom", "operator"}, mappings, []string{"other"}, platformRoles))
+	assert.Nil(t, syncLDAPRoles(nil, mappings, nil, platformRoles))
+}
@@ -40,6 +40,7 @@ import (
 
 	kubevelatypes "github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/repository"
 	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
@@ -383,7 +384,16 @@ func (c *cloudShellServiceImpl) managePrivilegesForProjectRead(ctx context.Conte
 	}
 	var authPDs []auth.PrivilegeDescription
 	for _, t := range targets.Targets {
-		authPDs = append(authPDs, &auth.ScopedPrivilege{Cluster: t.Cluster.ClusterName, Namespace: t.Cluster.Namespace, ReadOnly: readOnly})
+		clusterTargets, err := repository.GetTargetClusters(ctx, c.KubeClient, &model.Target{
+			Cluster:      (*model.ClusterTarget)(t.Cluster),
+			ClusterGroup: (*model.ClusterGroupTarget)(t.ClusterGroup),
+		})
+		if err != nil {
+			log.Logger.Infof("failed to get the clusters of the target %s :%s", t.Name, err.Error())
+		}
+		for _, clusterTarget := range clusterTargets {
+			authPDs = append(authPDs, &auth.ScopedPrivilege{Cluster: clusterTarget.ClusterName, Namespace: clusterTarget.Namespace, ReadOnly: readOnly})
+		}
 	}
 	envs, err := c.EnvService.ListEnvs(ctx, 0, 0, apisv1.ListEnvOptions{Project: projectName})
 	if err != nil {
@@ -64,6 +64,12 @@ type ClusterService interface {
 	GetCloudClusterCreationStatus(context.Context, string, string) (*apis.CreateCloudClusterResponse, error)
 	ListCloudClusterCreation(context.Context, string) (*apis.ListCloudClusterCreationResponse, error)
 	DeleteCloudClusterCreation(context.Context, string, string) (*apis.CreateCloudClusterResponse, error)
+
+	ListClusterGroups(context.Context) (*apis.ListClusterGroupResponse, error)
+	CreateClusterGroup(context.Context, apis.CreateClusterGroupRequest) (*apis.ClusterGroupBase, error)
+	GetClusterGroup(context.Context, string) (*apis.ClusterGroupBase, error)
+	UpdateClusterGroup(context.Context, string, apis.UpdateClusterGroupRequest) (*apis.ClusterGroupBase, error)
+	DeleteClusterGroup(context.Context, string) (*apis.ClusterGroupBase, error)
 	Init(ctx context.Context) error
 }
 
@@ -486,12 +492,25 @@ func (c *clusterServiceImpl) getClusterResourceInfoFromK8s(ctx context.Context,
 	return clusterResourceInfo, nil
 }
 
-func (c *clusterServiceImpl) ListCloudClusters(ctx context.Context, provider string, req apis.AccessKeyRequest, pageNumber int, pageSize int) (*apis.ListCloudClusterResponse, error) {
-	p, err := cloudprovider.GetClusterProvider(provider, req.AccessKeyID, req.AccessKeySecret, c.K8sClient)
+// getCloudClusterProvider get the cluster provider with the access key in the request. The local providers are not
+// available, as the requests must not choose the host paths or namespaces read by the apiserver.
+func (c *clusterServiceImpl) getCloudClusterProvider(provider string, accessKeyID string, accessKeySecret string) (cloudprovider.CloudClusterProvider, error) {
+	if cloudprovider.IsLocalClusterProvider(provider) {
+		return nil, bcode.ErrInvalidCloudClusterProvider
+	}
+	p, err := cloudprovider.GetClusterProvider(provider, accessKeyID, accessKeySecret, c.K8sClient)
 	if err != nil {
 		log.Logger.Errorf("failed to get cluster provider: %s", err.Error())
 		return nil, bcode.ErrInvalidCloudClusterProvider
 	}
+	return p, nil
+}
+
+func (c *clusterServiceImpl) ListCloudClusters(ctx context.Context, provider string, req apis.AccessKeyRequest, pageNumber int, pageSize int) (*apis.ListCloudClusterResponse, error) {
+	p, err := c.getCloudClusterProvider(provider, req.AccessKeyID, req.AccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
 	clusters, total, err := p.ListCloudClusters(pageNumber, pageSize)
 	if err != nil {
 		if p.IsInvalidKey(err) {
@@ -511,10 +530,9 @@ func (c *clusterServiceImpl) ListCloudClusters(ctx context.Context, provider str
 }
 
 func (c *clusterServiceImpl) ConnectCloudCluster(ctx context.Context, provider string, req apis.ConnectCloudClusterRequest) (*apis.ClusterBase, error) {
-	p, err := cloudprovider.GetClusterProvider(provider, req.AccessKeyID, req.AccessKeySecret, c.K8sClient)
+	p, err := c.getCloudClusterProvider(provider, req.AccessKeyID, req.AccessKeySecret)
 	if err != nil {
-		log.Logger.Errorf("failed to get cluster provider: %s", err.Error())
-		return nil, bcode.ErrInvalidCloudClusterProvider
+		return nil, err
 	}
 	kubeConfig, err := p.GetClusterKubeConfig(req.ClusterID)
 	if err != nil {
@@ -541,16 +559,18 @@ func (c *clusterServiceImpl) ConnectCloudCluster(ctx context.Context, provider s
 }
 
 func (c *clusterServiceImpl) CreateCloudCluster(ctx context.Context, provider string, req apis.CreateCloudClusterRequest) (*apis.CreateCloudClusterResponse, error) {
-	p, err := cloudprovider.GetClusterProvider(provider, req.AccessKeyID, req.AccessKeySecret, c.K8sClient)
+	p, err := c.getCloudClusterProvider(provider, req.AccessKeyID, req.AccessKeySecret)
 	if err != nil {
-		log.Logger.Errorf("failed to get cluster provider: %s", err.Error())
-		return nil, bcode.ErrInvalidCloudClusterProvider
+		return nil, err
 	}
 	_, err = p.CreateCloudCluster(ctx, req.Name, req.Zone, req.WorkerNumber, req.CPUCoresPerWorker, req.MemoryPerWorker)
 	if err != nil {
 		if kerrors.IsAlreadyExists(err) {
 			return nil, bcode.ErrCloudClusterAlreadyExists
 		}
+		if errors.Is(err, cloudprovider.ErrClusterCreationNotSupported) {
+			return nil, bcode.ErrCloudClusterCreationNotSupported
+		}
 		log.Logger.Errorf("failed to bootstrap terraform configuration: %s", err.Error())
 		return nil, bcode.ErrBootstrapTerraformConfiguration
 	}
@@ -633,6 +653,92 @@ func (c *clusterServiceImpl) DeleteCloudClusterCreation(ctx context.Context, pro
 	return resp, err
 }
 
+func (c *clusterServiceImpl) newClusterGroupBase(ctx context.Context, group *multicluster.ClusterGroup) (*apis.ClusterGroupBase, error) {
+	members, err := group.GetClusters(ctx, c.K8sClient)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []string{}
+	}
+	return &apis.ClusterGroupBase{
+		Name:                 group.Name,
+		Clusters:             group.Clusters,
+		ClusterLabelSelector: group.ClusterLabelSelector,
+		Members:              members,
+	}, nil
+}
+
+func convertClusterGroupError(err error) error {
+	switch {
+	case errors.Is(err, multicluster.ErrClusterGroupNotExists):
+		return bcode.ErrClusterGroupNotExist
+	case errors.Is(err, multicluster.ErrClusterGroupExists):
+		return bcode.ErrClusterGroupExist
+	default:
+		return err
+	}
+}
+
+func (c *clusterServiceImpl) ListClusterGroups(ctx context.Context) (*apis.ListClusterGroupResponse, error) {
+	groups, err := multicluster.ListClusterGroups(ctx, c.K8sClient)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apis.ListClusterGroupResponse{Groups: []apis.ClusterGroupBase{}}
+	for i := range groups {
+		base, err := c.newClusterGroupBase(ctx, &groups[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Groups = append(resp.Groups, *base)
+	}
+	return resp, nil
+}
+
+func (c *clusterServiceImpl) CreateClusterGroup(ctx context.Context, req apis.CreateClusterGroupRequest) (*apis.ClusterGroupBase, error) {
+	group := &multicluster.ClusterGroup{Name: req.Name, Clusters: req.Clusters, ClusterLabelSelector: req.ClusterLabelSelector}
+	if err := group.Validate(ctx, c.K8sClient); err != nil {
+		log.Logger.Infof("invalid cluster group %s: %s", req.Name, err.Error())
+		return nil, bcode.ErrInvalidClusterGroup
+	}
+	if err := multicluster.CreateClusterGroup(ctx, c.K8sClient, group); err != nil {
+		return nil, convertClusterGroupError(err)
+	}
+	return c.newClusterGroupBase(ctx, group)
+}
+
+func (c *clusterServiceImpl) GetClusterGroup(ctx context.Context, groupName string) (*apis.ClusterGroupBase, error) {
+	group, err := multicluster.GetClusterGroup(ctx, c.K8sClient, groupName)
+	if err != nil {
+		return nil, convertClusterGroupError(err)
+	}
+	return c.newClusterGroupBase(ctx, group)
+}
+
+func (c *clusterServiceImpl) UpdateClusterGroup(ctx context.Context, groupName string, req apis.UpdateClusterGroupRequest) (*apis.ClusterGroupBase, error) {
+	group := &multicluster.ClusterGroup{Name: groupName, Clusters: req.Clusters, ClusterLabelSelector: req.ClusterLabelSelector}
+	if err := group.Validate(ctx, c.K8sClient); err != nil {
+		log.Logger.Infof("invalid cluster group %s: %s", groupName, err.Error())
+		return nil, bcode.ErrInvalidClusterGroup
+	}
+	if err := multicluster.UpdateClusterGroup(ctx, c.K8sClient, group); err != nil {
+		return nil, convertClusterGroupError(err)
+	}
+	return c.newClusterGroupBase(ctx, group)
+}
+
+func (c *clusterServiceImpl) DeleteClusterGroup(ctx context.Context, groupName string) (*apis.ClusterGroupBase, error) {
+	group, err := multicluster.GetClusterGroup(ctx, c.K8sClient, groupName)
+	if err != nil {
+		return nil, convertClusterGroupError(err)
+	}
+	if err = multicluster.DeleteClusterGroup(ctx, c.K8sClient, groupName); err != nil {
+		return nil, convertClusterGroupError(err)
+	}
+	return &apis.ClusterGroupBase{Name: group.Name, Clusters: group.Clusters, ClusterLabelSelector: group.ClusterLabelSelector, Members: []string{}}, nil
+}
+
 func newClusterBaseFromCluster(cluster *model.Cluster) *apis.ClusterBase {
 	return &apis.ClusterBase{
 		Name:        cluster.Name,
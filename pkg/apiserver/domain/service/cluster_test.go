@@ -31,8 +31,10 @@ import (
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
 	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/cloudprovider"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 	pkgutil "github.com/oam-dev/kubevela/pkg/utils"
 )
@@ -127,6 +129,20 @@ var _ = Describe("Test cluster service function", func() {
 		Expect(err).Should(Succeed())
 		Expect(len(resp.Clusters)).Should(Equal(0))
 	})
+
+	It("Test local cloud cluster providers are not available", func() {
+		service := clusterServiceImpl{
+			Store:     ds,
+			caches:    cache,
+			K8sClient: k8sClient,
+		}
+		_, err := service.ListCloudClusters(ctx, cloudprovider.ProviderKubeConfig, apis.AccessKeyRequest{AccessKeyID: "/etc"}, 0, 0)
+		Expect(err).Should(Equal(bcode.ErrInvalidCloudClusterProvider))
+		_, err = service.ConnectCloudCluster(ctx, cloudprovider.ProviderClusterAPI, apis.ConnectCloudClusterRequest{AccessKeyID: "kube-system", ClusterID: "kube-system/admin"})
+		Expect(err).Should(Equal(bcode.ErrInvalidCloudClusterProvider))
+		_, err = service.CreateCloudCluster(ctx, cloudprovider.ProviderKind, apis.CreateCloudClusterRequest{})
+		Expect(err).Should(Equal(bcode.ErrInvalidCloudClusterProvider))
+	})
 })
 
 //type fakePrismClusterClient struct {
@@ -210,6 +210,9 @@ func (p *envServiceImpl) UpdateEnv(ctx context.Context, name string, req apisv1.
 		log.Logger.Errorf("check if env name exists failure %s", err.Error())
 		return nil, bcode.ErrEnvNotExisted
 	}
+	if err := checkResourceVersion(env, req.ResourceVersion); err != nil {
+		return nil, err
+	}
 	if req.Alias != "" {
 		env.Alias = req.Alias
 	}
@@ -335,13 +338,14 @@ func (p *envServiceImpl) checkEnvTarget(ctx context.Context, project string, env
 
 func convertEnvModel2Base(env *model.Env, targets []*model.Target) *apisv1.Env {
 	data := apisv1.Env{
-		Name:        env.Name,
-		Alias:       env.Alias,
-		Description: env.Description,
-		Project:     apisv1.NameAlias{Name: env.Project},
-		Namespace:   env.Namespace,
-		CreateTime:  env.CreateTime,
-		UpdateTime:  env.UpdateTime,
+		Name:            env.Name,
+		Alias:           env.Alias,
+		Description:     env.Description,
+		Project:         apisv1.NameAlias{Name: env.Project},
+		Namespace:       env.Namespace,
+		CreateTime:      env.CreateTime,
+		UpdateTime:      env.UpdateTime,
+		ResourceVersion: env.ResourceVersion,
 	}
 	for _, dt := range env.Targets {
 		for _, tg := range targets {
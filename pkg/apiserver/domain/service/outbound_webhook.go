@@ -0,0 +1,531 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
+)
+
+const (
+	// WebhookSignatureHeader is the header of the HMAC-SHA256 signature of the payload, the value is sha256=<hex digest>
+	WebhookSignatureHeader = "X-Vela-Signature-256"
+	// WebhookEventHeader is the header of the event type
+	WebhookEventHeader = "X-Vela-Event"
+	// WebhookDeliveryHeader is the header of the delivery ID, it is not changed when the delivery is retried
+	WebhookDeliveryHeader = "X-Vela-Delivery"
+
+	// webhookDeliveryMaxAttempts the delivery is failed after the attempts
+	webhookDeliveryMaxAttempts = 6
+	// webhookDeliveryBackoff the delay of the first retry, it is doubled for each retry
+	webhookDeliveryBackoff    = 30 * time.Second
+	webhookDeliveryMaxBackoff = time.Hour
+	webhookDeliveryTimeout    = 10 * time.Second
+	// webhookDeliveryCleanBatchSize the number of the deliveries deleted in one round
+	webhookDeliveryCleanBatchSize = 100
+	// webhookDeliveryConcurrency the number of the webhooks sent at the same time
+	webhookDeliveryConcurrency = 10
+)
+
+// OutboundWebhookService the outbound webhook service, the events are saved as the deliveries and sent by the worker
+type OutboundWebhookService interface {
+	CreateOutboundWebhook(ctx context.Context, projectName string, req apisv1.CreateOutboundWebhookRequest) (*apisv1.CreateOutboundWebhookResponse, error)
+	UpdateOutboundWebhook(ctx context.Context, projectName, name string, req apisv1.UpdateOutboundWebhookRequest) (*apisv1.OutboundWebhookBase, error)
+	ListOutboundWebhooks(ctx context.Context, projectName string) (*apisv1.ListOutboundWebhookResponse, error)
+	DeleteOutboundWebhook(ctx context.Context, projectName, name string) error
+	ListWebhookDeliveries(ctx context.Context, projectName, name string, page, pageSize int) (*apisv1.ListWebhookDeliveryResponse, error)
+	// PublishEvent creates the deliveries for the webhooks subscribing the event, the event without the project is
+	// published to the webhooks of all projects
+	PublishEvent(ctx context.Context, event apisv1.WebhookEvent) error
+	// DeliverWebhooks sends the pending deliveries which reach the next attempt time, return the number of the attempts
+	DeliverWebhooks(ctx context.Context) (int, error)
+	// CleanWebhookDeliveries deletes the finished deliveries created before the given time
+	CleanWebhookDeliveries(ctx context.Context, before time.Time) (int, error)
+}
+
+type outboundWebhookServiceImpl struct {
+	Store  datastore.DataStore `inject:"datastore"`
+	client *http.Client
+	// allowPrivateTarget allows the webhooks in the private networks, it is only used by the tests
+	allowPrivateTarget bool
+}
+
+// NewOutboundWebhookService new outbound webhook service
+func NewOutboundWebhookService() OutboundWebhookService {
+	return &outboundWebhookServiceImpl{client: newWebhookHTTPClient(false)}
+}
+
+// newWebhookHTTPClient returns the client which does not follow the redirects, and refuses to connect to the private
+// addresses unless they are allowed. The addresses are checked after the host is resolved, so that a public domain
+// could not be resolved to a private address.
+func newWebhookHTTPClient(allowPrivateTarget bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookDeliveryTimeout}
+	if !allowPrivateTarget {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateWebhookTarget(ip) {
+				return fmt.Errorf("the webhook address %s is not allowed", host)
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: webhookDeliveryTimeout,
+		// the proxy is not used, the destination could not be checked through it
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookDeliveryTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// CreateOutboundWebhook create an outbound webhook, the secret is only returned in the response
+func (o *outboundWebhookServiceImpl) CreateOutboundWebhook(ctx context.Context, projectName string, req apisv1.CreateOutboundWebhookRequest) (*apisv1.CreateOutboundWebhookResponse, error) {
+	if err := o.checkOutboundWebhook(req.URL, req.Events); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(b)
+	}
+	webhook := &model.OutboundWebhook{
+		Name:        req.Name,
+		Project:     projectName,
+		Description: req.Description,
+		URL:         req.URL,
+		Events:      req.Events,
+		Secret:      secret,
+	}
+	if err := o.Store.Add(ctx, webhook); err != nil {
+		if errors.Is(err, datastore.ErrRecordExist) {
+			return nil, bcode.ErrOutboundWebhookExist
+		}
+		return nil, err
+	}
+	return &apisv1.CreateOutboundWebhookResponse{OutboundWebhookBase: *convertOutboundWebhookModel(webhook), Secret: secret}, nil
+}
+
+// UpdateOutboundWebhook update an outbound webhook, the secret is kept if it is empty in the request
+func (o *outboundWebhookServiceImpl) UpdateOutboundWebhook(ctx context.Context, projectName, name string, req apisv1.UpdateOutboundWebhookRequest) (*apisv1.OutboundWebhookBase, error) {
+	webhook, err := o.getOutboundWebhook(ctx, projectName, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.checkOutboundWebhook(req.URL, req.Events); err != nil {
+		return nil, err
+	}
+	webhook.Description = req.Description
+	webhook.URL = req.URL
+	webhook.Events = req.Events
+	webhook.Disabled = req.Disabled
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if err := o.Store.Put(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return convertOutboundWebhookModel(webhook), nil
+}
+
+// ListOutboundWebhooks list the outbound webhooks of the project
+func (o *outboundWebhookServiceImpl) ListOutboundWebhooks(ctx context.Context, projectName string) (*apisv1.ListOutboundWebhookResponse, error) {
+	entities, err := o.Store.List(ctx, &model.OutboundWebhook{Project: projectName}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListOutboundWebhookResponse{Webhooks: []*apisv1.OutboundWebhookBase{}}
+	for _, entity := range entities {
+		resp.Webhooks = append(resp.Webhooks, convertOutboundWebhookModel(entity.(*model.OutboundWebhook)))
+	}
+	return resp, nil
+}
+
+// DeleteOutboundWebhook delete an outbound webhook and its deliveries
+func (o *outboundWebhookServiceImpl) DeleteOutboundWebhook(ctx context.Context, projectName, name string) error {
+	webhook, err := o.getOutboundWebhook(ctx, projectName, name)
+	if err != nil {
+		return err
+	}
+	return deleteOutboundWebhook(ctx, o.Store, webhook)
+}
+
+// ListWebhookDeliveries list the deliveries of an outbound webhook, the latest is the first
+func (o *outboundWebhookServiceImpl) ListWebhookDeliveries(ctx context.Context, projectName, name string, page, pageSize int) (*apisv1.ListWebhookDeliveryResponse, error) {
+	if _, err := o.getOutboundWebhook(ctx, projectName, name); err != nil {
+		return nil, err
+	}
+	delivery := &model.WebhookDelivery{Project: projectName, WebhookName: name}
+	entities, err := o.Store.List(ctx, delivery, &datastore.ListOptions{
+		Page:     page,
+		PageSize: pageSize,
+		SortBy:   []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListWebhookDeliveryResponse{Deliveries: []*apisv1.WebhookDeliveryBase{}}
+	for _, entity := range entities {
+		resp.Deliveries = append(resp.Deliveries, convertWebhookDeliveryModel(entity.(*model.WebhookDelivery)))
+	}
+	count, err := o.Store.Count(ctx, delivery, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Total = count
+	return resp, nil
+}
+
+// PublishEvent saves a pending delivery for each webhook subscribing the event
+func (o *outboundWebhookServiceImpl) PublishEvent(ctx context.Context, event apisv1.WebhookEvent) error {
+	if event.ID == "" {
+		event.ID = newWebhookID()
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	entities, err := o.Store.List(ctx, &model.OutboundWebhook{Project: event.Project}, &datastore.ListOptions{})
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var deliveries []datastore.Entity
+	for _, entity := range entities {
+		webhook := entity.(*model.OutboundWebhook)
+		if !webhook.Subscribed(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			ID:              newWebhookID(),
+			Project:         webhook.Project,
+			WebhookName:     webhook.Name,
+			Event:           event.Type,
+			Payload:         string(payload),
+			Status:          model.WebhookDeliveryStatusPending,
+			NextAttemptTime: event.Time,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return o.Store.BatchAdd(ctx, deliveries)
+}
+
+// DeliverWebhooks sends the pending deliveries, the webhooks are sent concurrently and the deliveries of the same
+// webhook are sent in order
+func (o *outboundWebhookServiceImpl) DeliverWebhooks(ctx context.Context) (int, error) {
+	entities, err := o.Store.List(ctx, &model.WebhookDelivery{Status: model.WebhookDeliveryStatusPending}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderAscending}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var webhooks []string
+	pending := map[string][]*model.WebhookDelivery{}
+	now := time.Now()
+	for _, entity := range entities {
+		delivery := entity.(*model.WebhookDelivery)
+		if delivery.NextAttemptTime.After(now) {
+			continue
+		}
+		key := delivery.Project + "/" + delivery.WebhookName
+		if _, exist := pending[key]; !exist {
+			webhooks = append(webhooks, key)
+		}
+		pending[key] = append(pending[key], delivery)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		attempts int
+		firstErr error
+	)
+	limit := make(chan struct{}, webhookDeliveryConcurrency)
+	for _, key := range webhooks {
+		deliveries := pending[key]
+		limit <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			n, err := o.deliverWebhook(ctx, deliveries)
+			mutex.Lock()
+			defer mutex.Unlock()
+			attempts += n
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+	return attempts, firstErr
+}
+
+// deliverWebhook sends the deliveries of the same webhook one by one, return the number of the attempts
+func (o *outboundWebhookServiceImpl) deliverWebhook(ctx context.Context, deliveries []*model.WebhookDelivery) (int, error) {
+	attempts := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return attempts, ctx.Err()
+		}
+		webhook, err := o.getOutboundWebhook(ctx, delivery.Project, delivery.WebhookName)
+		switch {
+		case errors.Is(err, bcode.ErrOutboundWebhookNotExist):
+			delivery.Status = model.WebhookDeliveryStatusFailed
+			delivery.Error = "the webhook is deleted"
+		case err != nil:
+			return attempts, err
+		case webhook.Disabled:
+			delivery.Status = model.WebhookDeliveryStatusFailed
+			delivery.Error = "the webhook is disabled"
+		default:
+			attempts++
+			o.deliver(ctx, webhook, delivery)
+		}
+		if err := o.Store.Put(ctx, delivery); err != nil {
+			log.Logger.Errorf("failed to save the webhook delivery %s: %s", delivery.ID, err.Error())
+		}
+	}
+	return attempts, nil
+}
+
+// deliver sends the payload and updates the status of the delivery, the failed delivery is retried with backoff
+func (o *outboundWebhookServiceImpl) deliver(ctx context.Context, webhook *model.OutboundWebhook, delivery *model.WebhookDelivery) {
+	delivery.Attempts++
+	delivery.StatusCode = 0
+	delivery.Error = ""
+	err := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader([]byte(delivery.Payload)))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "KubeVela-Webhook")
+		req.Header.Set(WebhookEventHeader, delivery.Event)
+		req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, []byte(delivery.Payload)))
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+		delivery.StatusCode = resp.StatusCode
+		// the redirects are not followed, they are failed as the other responses
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("the webhook responds with the status code %d", resp.StatusCode)
+		}
+		return nil
+	}()
+	if err == nil {
+		delivery.Status = model.WebhookDeliveryStatusSucceeded
+		return
+	}
+	delivery.Error = err.Error()
+	if delivery.Attempts >= webhookDeliveryMaxAttempts {
+		delivery.Status = model.WebhookDeliveryStatusFailed
+		log.Logger.Warnf("failed to deliver the event %s to the webhook %s/%s: %s", delivery.Event, webhook.Project, webhook.Name, err.Error())
+		return
+	}
+	backoff := webhookDeliveryBackoff << (delivery.Attempts - 1)
+	if backoff > webhookDeliveryMaxBackoff {
+		backoff = webhookDeliveryMaxBackoff
+	}
+	delivery.NextAttemptTime = time.Now().Add(backoff)
+}
+
+// CleanWebhookDeliveries delete the finished deliveries created before the given time, the pending ones are kept
+func (o *outboundWebhookServiceImpl) CleanWebhookDeliveries(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	for {
+		entities, err := o.Store.List(ctx, &model.WebhookDelivery{}, &datastore.ListOptions{
+			Page:     1,
+			PageSize: webhookDeliveryCleanBatchSize,
+			SortBy:   []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderAscending}},
+			FilterOptions: datastore.FilterOptions{In: []datastore.InQueryOption{{
+				Key:    "status",
+				Values: []string{model.WebhookDeliveryStatusSucceeded, model.WebhookDeliveryStatusFailed},
+			}}},
+		})
+		if err != nil {
+			return deleted, err
+		}
+		for _, entity := range entities {
+			delivery := entity.(*model.WebhookDelivery)
+			if !delivery.CreateTime.Before(before) {
+				return deleted, nil
+			}
+			if err := o.Store.Delete(ctx, delivery); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(entities) < webhookDeliveryCleanBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+func (o *outboundWebhookServiceImpl) getOutboundWebhook(ctx context.Context, projectName, name string) (*model.OutboundWebhook, error) {
+	webhook := &model.OutboundWebhook{Project: projectName, Name: name}
+	if err := o.Store.Get(ctx, webhook); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrOutboundWebhookNotExist
+		}
+		return nil, err
+	}
+	// the primary key joins the project and the name, make sure the webhook is not from another project
+	if webhook.Project != projectName {
+		return nil, bcode.ErrOutboundWebhookNotExist
+	}
+	return webhook, nil
+}
+
+// SignWebhookPayload returns the value of the signature header, the receivers should compute it with the secret
+// and compare it with the header
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// publishWebhookEvent publishes the event by the service if it is injected. The failure is only logged, the
+// operation that triggers the event should not fail because of it.
+func publishWebhookEvent(ctx context.Context, s OutboundWebhookService, event apisv1.WebhookEvent) {
+	if s == nil {
+		return
+	}
+	if err := s.PublishEvent(ctx, event); err != nil {
+		log.Logger.Errorf("failed to publish the webhook event %s: %s", event.Type, err.Error())
+	}
+}
+
+// deleteOutboundWebhook deletes the webhook and its deliveries
+func deleteOutboundWebhook(ctx context.Context, store datastore.DataStore, webhook *model.OutboundWebhook) error {
+	deliveries, err := store.List(ctx, &model.WebhookDelivery{Project: webhook.Project, WebhookName: webhook.Name}, &datastore.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := store.Delete(ctx, delivery); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+			return err
+		}
+	}
+	if err := store.Delete(ctx, webhook); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (o *outboundWebhookServiceImpl) checkOutboundWebhook(webhookURL string, events []string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return bcode.ErrInvalidOutboundWebhookURL
+	}
+	// the domains are checked again when they are resolved in the delivery
+	if !o.allowPrivateTarget {
+		host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		if ip := net.ParseIP(host); (ip != nil && isPrivateWebhookTarget(ip)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return bcode.ErrInvalidOutboundWebhookURL
+		}
+	}
+	for _, event := range events {
+		if !pkgUtils.StringsContain(model.WebhookEvents, event) {
+			return bcode.ErrInvalidWebhookEvent
+		}
+	}
+	return nil
+}
+
+// isPrivateWebhookTarget returns whether the address is in the loopback, private, link-local or unspecified networks,
+// such as the cloud metadata service 169.254.169.254
+func isPrivateWebhookTarget(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
+
+func newWebhookID() string {
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), pkgUtils.RandomString(8))
+}
+
+func convertOutboundWebhookModel(webhook *model.OutboundWebhook) *apisv1.OutboundWebhookBase {
+	return &apisv1.OutboundWebhookBase{
+		Name:        webhook.Name,
+		Project:     webhook.Project,
+		Description: webhook.Description,
+		URL:         webhook.URL,
+		Events:      webhook.Events,
+		Disabled:    webhook.Disabled,
+		CreateTime:  webhook.CreateTime,
+		UpdateTime:  webhook.UpdateTime,
+	}
+}
+
+func convertWebhookDeliveryModel(delivery *model.WebhookDelivery) *apisv1.WebhookDeliveryBase {
+	return &apisv1.WebhookDeliveryBase{
+		ID:              delivery.ID,
+		Event:           delivery.Event,
+		Payload:         delivery.Payload,
+		Status:          delivery.Status,
+		Attempts:        delivery.Attempts,
+		NextAttemptTime: delivery.NextAttemptTime,
+		StatusCode:      delivery.StatusCode,
+		Error:           delivery.Error,
+		CreateTime:      delivery.CreateTime,
+		UpdateTime:      delivery.UpdateTime,
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+type webhookReceiver struct {
+	mutex    sync.Mutex
+	status   int
+	secret   string
+	received []apisv1.WebhookEvent
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	body, _ := ioutil.ReadAll(req.Body)
+	if req.Header.Get(WebhookSignatureHeader) != SignWebhookPayload(r.secret, body) || req.Header.Get(WebhookDeliveryHeader) == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var event apisv1.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.Type != req.Header.Get(WebhookEventHeader) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.status != http.StatusOK {
+		w.WriteHeader(r.status)
+		return
+	}
+	r.received = append(r.received, event)
+}
+
+func (r *webhookReceiver) events() []apisv1.WebhookEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.received
+}
+
+func TestOutboundWebhookService(t *testing.T) {
+	ctx := context.Background()
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NoError(t, err)
+	receiver := &webhookReceiver{status: http.StatusOK, secret: "s3cret"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	webhookService := NewOutboundWebhookService().(*outboundWebhookServiceImpl)
+	webhookService.Store = ds
+
+	for _, u := range []string{"ftp://example.com", server.URL, "http://localhost:8080", "http://169.254.169.254/latest/meta-data", "http://10.0.0.1", "http://[::1]:80"} {
+		_, err = webhookService.CreateOutboundWebhook(ctx, "demo", apisv1.CreateOutboundWebhookRequest{Name: "ci", URL: u, Events: []string{model.WebhookEventWorkflowFailed}})
+		assert.Equal(t, bcode.ErrInvalidOutboundWebhookURL, err, u)
+	}
+	// the test server listens on the loopback address
+	webhookService.allowPrivateTarget = true
+	webhookService.client = newWebhookHTTPClient(true)
+	_, err = webhookService.CreateOutboundWebhook(ctx, "demo", apisv1.CreateOutboundWebhookRequest{Name: "ci", URL: server.URL, Events: []string{"app.created"}})
+	assert.Equal(t, bcode.ErrInvalidWebhookEvent, err)
+
+	created, err := webhookService.CreateOutboundWebhook(ctx, "demo", apisv1.CreateOutboundWebhookRequest{
+		Name: "ci", URL: server.URL, Events: []string{model.WebhookEventWorkflowFailed, model.WebhookEventAddonEnabled},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, created.Secret, 64)
+	_, err = webhookService.CreateOutboundWebhook(ctx, "demo", apisv1.CreateOutboundWebhookRequest{Name: "ci", URL: server.URL, Events: []string{model.WebhookEventWorkflowFailed}})
+	assert.Equal(t, bcode.ErrOutboundWebhookExist, err)
+	_, err = webhookService.CreateOutboundWebhook(ctx, "other", apisv1.CreateOutboundWebhookRequest{
+		Name: "chat", URL: server.URL, Events: []string{model.WebhookEventAddonEnabled}, Secret: "s3cret",
+	})
+	assert.NoError(t, err)
+	_, err = webhookService.UpdateOutboundWebhook(ctx, "demo", "ci", apisv1.UpdateOutboundWebhookRequest{
+		URL: server.URL, Events: created.Events, Secret: "s3cret",
+	})
+	assert.NoError(t, err)
+	webhooks, err := webhookService.ListOutboundWebhooks(ctx, "demo")
+	assert.NoError(t, err)
+	assert.Len(t, webhooks.Webhooks, 1)
+	// the webhook of another project with the same primary key could not be accessed
+	_, err = webhookService.CreateOutboundWebhook(ctx, "other", apisv1.CreateOutboundWebhookRequest{
+		Name: "team-ci", URL: server.URL, Events: []string{model.WebhookEventWorkflowFailed},
+	})
+	assert.NoError(t, err)
+	_, err = webhookService.ListWebhookDeliveries(ctx, "other-team", "ci", 1, 10)
+	assert.Equal(t, bcode.ErrOutboundWebhookNotExist, err)
+	assert.Equal(t, bcode.ErrOutboundWebhookNotExist, webhookService.DeleteOutboundWebhook(ctx, "other-team", "ci"))
+
+	// the events of the project are only sent to the webhooks of the project, the addon events are sent to all projects
+	assert.NoError(t, webhookService.PublishEvent(ctx, apisv1.WebhookEvent{Type: model.WebhookEventWorkflowFailed, Project: "demo", Application: "web"}))
+	assert.NoError(t, webhookService.PublishEvent(ctx, apisv1.WebhookEvent{Type: model.WebhookEventApplicationDeployed, Project: "demo", Application: "web"}))
+	assert.NoError(t, webhookService.PublishEvent(ctx, apisv1.WebhookEvent{Type: model.WebhookEventAddonEnabled, Addon: "fluxcd"}))
+	attempts, err := webhookService.DeliverWebhooks(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, receiver.events(), 3)
+	deliveries, err := webhookService.ListWebhookDeliveries(ctx, "demo", "ci", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deliveries.Total)
+	for _, delivery := range deliveries.Deliveries {
+		assert.Equal(t, model.WebhookDeliveryStatusSucceeded, delivery.Status)
+		assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	}
+
+	// the failed delivery is retried with backoff until the max attempts
+	receiver.mutex.Lock()
+	receiver.status = http.StatusInternalServerError
+	receiver.mutex.Unlock()
+	assert.NoError(t, webhookService.PublishEvent(ctx, apisv1.WebhookEvent{Type: model.WebhookEventWorkflowFailed, Project: "demo"}))
+	attempts, err = webhookService.DeliverWebhooks(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+	attempts, err = webhookService.DeliverWebhooks(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, attempts)
+	deliveries, err = webhookService.ListWebhookDeliveries(ctx, "demo", "ci", 1, 1)
+	assert.NoError(t, err)
+	failed := deliveries.Deliveries[0]
+	assert.Equal(t, model.WebhookDeliveryStatusPending, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, failed.StatusCode)
+	assert.True(t, failed.NextAttemptTime.After(time.Now().Add(webhookDeliveryBackoff/2)))
+
+	delivery := &model.WebhookDelivery{ID: failed.ID}
+	assert.NoError(t, ds.Get(ctx, delivery))
+	delivery.Attempts = webhookDeliveryMaxAttempts - 1
+	delivery.NextAttemptTime = time.Now()
+	assert.NoError(t, ds.Put(ctx, delivery))
+	_, err = webhookService.DeliverWebhooks(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, ds.Get(ctx, delivery))
+	assert.Equal(t, model.WebhookDeliveryStatusFailed, delivery.Status)
+
+	deleted, err := webhookService.CleanWebhookDeliveries(ctx, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, deleted)
+
+	assert.NoError(t, webhookService.PublishEvent(ctx, apisv1.WebhookEvent{Type: model.WebhookEventWorkflowFailed, Project: "demo"}))
+	assert.NoError(t, webhookService.DeleteOutboundWebhook(ctx, "demo", "ci"))
+	count, err := ds.Count(ctx, &model.WebhookDelivery{Project: "demo"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	_, err = webhookService.ListWebhookDeliveries(ctx, "demo", "ci", 1, 10)
+	assert.Equal(t, bcode.ErrOutboundWebhookNotExist, err)
+}
+
+type recordingWebhookService struct {
+	OutboundWebhookService
+	events []apisv1.WebhookEvent
+}
+
+func (r *recordingWebhookService) PublishEvent(ctx context.Context, event apisv1.WebhookEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestPublishWorkflowEvents(t *testing.T) {
+	ctx := context.Background()
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NoError(t, err)
+	assert.NoError(t, ds.Add(ctx, &model.Application{Name: "web", Project: "demo"}))
+	recorder := &recordingWebhookService{}
+	workflowService := &workflowServiceImpl{Store: ds, OutboundWebhookService: recorder}
+
+	record := &model.WorkflowRecord{AppPrimaryKey: "web", WorkflowName: "workflow-prod", Name: "record-v2", Status: model.RevisionStatusRunning, Steps: []model.WorkflowStepStatus{
+		{Name: "deploy-test", Type: "deploy", Phase: common.WorkflowStepPhaseSucceeded},
+		{Name: "approve", Type: "suspend", Phase: common.WorkflowStepPhaseRunning},
+	}}
+	workflowService.publishWorkflowEvents(ctx, record, model.RevisionStatusRunning, map[string]common.WorkflowStepPhase{"deploy-test": common.WorkflowStepPhaseRunning})
+	assert.Len(t, recorder.events, 1)
+	assert.Equal(t, model.WebhookEventWorkflowSuspended, recorder.events[0].Type)
+	assert.Equal(t, "demo", recorder.events[0].Project)
+	assert.Equal(t, "approve", recorder.events[0].Step)
+
+	record.Steps[1].Phase = common.WorkflowStepPhaseSucceeded
+	record.Status = model.RevisionStatusComplete
+	workflowService.publishWorkflowEvents(ctx, record, model.RevisionStatusRunning, map[string]common.WorkflowStepPhase{"approve": common.WorkflowStepPhaseRunning})
+	assert.Len(t, recorder.events, 2)
+	assert.Equal(t, model.WebhookEventApplicationDeployed, recorder.events[1].Type)
+
+	// the completed workflow with a failed step is not deployed
+	record.Steps[1].Phase = common.WorkflowStepPhaseFailed
+	record.Steps[1].Message = "rejected"
+	workflowService.publishWorkflowEvents(ctx, record, model.RevisionStatusRunning, map[string]common.WorkflowStepPhase{"approve": common.WorkflowStepPhaseRunning})
+	assert.Len(t, recorder.events, 3)
+	assert.Equal(t, model.WebhookEventWorkflowFailed, recorder.events[2].Type)
+	assert.Equal(t, "rejected", recorder.events[2].Message)
+}
+
+func TestDeliverWebhooksConcurrently(t *testing.T) {
+	ctx := context.Background()
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NoError(t, err)
+	// each endpoint waits for the other one, the deliveries time out if they are sent one by one
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	waitOther := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+	})
+	first, second := httptest.NewServer(waitOther), httptest.NewServer(waitOther)
+	defer first.Close()
+	defer second.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(first.URL, http.StatusFound))
+	defer redirect.Close()
+
+	webhookService := &outboundWebhookServiceImpl{Store: ds, client: newWebhookHTTPClient(true), allowPrivateTarget: true}
+	for name, u := range map[string]string{"first": first.URL, "second": second.URL, "redirect": redirect.URL} {
+		_, err = webhookService.CreateOutboundWebhook(ctx, "demo", apisv1.CreateOutboundWebhookRequest{Name: name, URL: u, Events: []string{model.WebhookEventWorkflowFailed}})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, webhookService.PublishEvent(ctx, apisv1.WebhookEvent{Type: model.WebhookEventWorkflowFailed, Project: "demo"}))
+	attempts, err := webhookService.DeliverWebhooks(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	for name, status := range map[string]string{"first": model.WebhookDeliveryStatusSucceeded, "second": model.WebhookDeliveryStatusSucceeded, "redirect": model.WebhookDeliveryStatusPending} {
+		deliveries, err := webhookService.ListWebhookDeliveries(ctx, "demo", name, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, status, deliveries.Deliveries[0].Status, name)
+	}
+	// the redirect is not followed
+	deliveries, err := webhookService.ListWebhookDeliveries(ctx, "demo", "redirect", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, deliveries.Deliveries[0].StatusCode)
+
+	// the private addresses are refused when they are resolved
+	resp, err := newWebhookHTTPClient(false).Get(first.URL)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	assert.Error(t, err)
+}
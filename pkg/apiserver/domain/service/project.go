@@ -24,11 +24,13 @@ import (
 
 	terraformtypes "github.com/oam-dev/terraform-controller/api/types"
 	terraformapi "github.com/oam-dev/terraform-controller/api/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
@@ -37,6 +39,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
 )
 
 // ProjectService project manage service.
@@ -54,6 +57,7 @@ type ProjectService interface {
 	UpdateProjectUser(ctx context.Context, projectName string, userName string, req apisv1.UpdateProjectUserRequest) (*apisv1.ProjectUserBase, error)
 	Init(ctx context.Context) error
 	GetConfigs(ctx context.Context, projectName, configType string) ([]*apisv1.Config, error)
+	ListProjectQuotas(ctx context.Context, projectName string) (*apisv1.ListProjectQuotasResponse, error)
 }
 
 type projectServiceImpl struct {
@@ -271,6 +275,26 @@ func (p *projectServiceImpl) DeleteProject(ctx context.Context, name string) err
 		return bcode.ErrProjectDenyDeleteByEnvironment
 	}
 
+	serviceAccounts, err := p.Store.List(ctx, &model.User{ServiceAccountProject: name}, &datastore.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, entity := range serviceAccounts {
+		if err := deleteServiceAccount(ctx, p.Store, entity.(*model.User)); err != nil {
+			return err
+		}
+	}
+
+	webhooks, err := p.Store.List(ctx, &model.OutboundWebhook{Project: name}, &datastore.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, entity := range webhooks {
+		if err := deleteOutboundWebhook(ctx, p.Store, entity.(*model.OutboundWebhook)); err != nil {
+			return err
+		}
+	}
+
 	users, _ := p.ListProjectUser(ctx, name, 0, 0)
 	for _, user := range users.Users {
 		err := p.DeleteProjectUser(ctx, name, user.UserName)
@@ -350,6 +374,9 @@ func (p *projectServiceImpl) UpdateProject(ctx context.Context, projectName stri
 	if err != nil {
 		return nil, err
 	}
+	if err := checkResourceVersion(project, req.ResourceVersion); err != nil {
+		return nil, err
+	}
 	project.Alias = req.Alias
 	project.Description = req.Description
 	var user = &model.User{Name: req.Owner}
@@ -598,15 +625,54 @@ func (p *projectServiceImpl) GetConfigs(ctx context.Context, projectName, config
 	return configs, nil
 }
 
+// ListProjectQuotas list the quotas restricting the applications in the project, including the quotas for single
+// application in the project, with the current usage
+func (p *projectServiceImpl) ListProjectQuotas(ctx context.Context, projectName string) (*apisv1.ListProjectQuotasResponse, error) {
+	quotas := &v1alpha1.ApplicationQuotaList{}
+	if err := p.K8sClient.List(ctx, quotas); err != nil {
+		if meta.IsNoMatchError(err) {
+			return &apisv1.ListProjectQuotasResponse{Quotas: []*apisv1.ProjectQuota{}}, nil
+		}
+		return nil, err
+	}
+	var res = []*apisv1.ProjectQuota{}
+	for _, quota := range quotas.Items {
+		if quota.Spec.Project != projectName {
+			if quota.Spec.Project != "" || quota.Spec.Application == nil {
+				continue
+			}
+			app := &v1beta1.Application{}
+			if err := p.K8sClient.Get(ctx, client.ObjectKey{Namespace: quota.Spec.Application.Namespace, Name: quota.Spec.Application.Name}, app); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if app.Labels[oam.LabelProject] != projectName {
+				continue
+			}
+		}
+		res = append(res, &apisv1.ProjectQuota{
+			Name:         quota.Name,
+			Application:  quota.Spec.Application,
+			Hard:         quota.Spec.Hard,
+			Used:         quota.Status.Used,
+			Applications: quota.Status.Applications,
+		})
+	}
+	return &apisv1.ListProjectQuotasResponse{Quotas: res}, nil
+}
+
 // ConvertProjectModel2Base convert project model to base struct
 func ConvertProjectModel2Base(project *model.Project, owner *model.User) *apisv1.ProjectBase {
 	base := &apisv1.ProjectBase{
-		Name:        project.Name,
-		Description: project.Description,
-		Alias:       project.Alias,
-		CreateTime:  project.CreateTime,
-		UpdateTime:  project.UpdateTime,
-		Owner:       apisv1.NameAlias{Name: project.Owner},
+		Name:            project.Name,
+		Description:     project.Description,
+		Alias:           project.Alias,
+		CreateTime:      project.CreateTime,
+		UpdateTime:      project.UpdateTime,
+		ResourceVersion: project.ResourceVersion,
+		Owner:           apisv1.NameAlias{Name: project.Owner},
 	}
 	if owner != nil && owner.Name == project.Owner {
 		base.Owner = apisv1.NameAlias{Name: owner.Name, Alias: owner.Alias}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	velatypes "github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
@@ -297,4 +298,51 @@ var _ = Describe("Test project service functions", func() {
 		Expect(err).Should(BeNil())
 		Expect(roles.Total).Should(BeEquivalentTo(0))
 	})
+
+	It("Test list project quotas function", func() {
+		app := &v1beta1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quota-app",
+				Namespace: "default",
+				Labels:    map[string]string{oam.LabelProject: "quota-project"},
+			},
+			Spec: v1beta1.ApplicationSpec{
+				Components: []common.ApplicationComponent{{
+					Type: "aaa",
+				}},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), app)).Should(BeNil())
+		projectQuota := &v1alpha1.ApplicationQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "project-quota"},
+			Spec: v1alpha1.ApplicationQuotaSpec{
+				Project: "quota-project",
+				Hard:    v1alpha1.ApplicationQuotaHard{Objects: map[string]int64{"Deployment": 10}},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), projectQuota)).Should(BeNil())
+		appQuota := &v1alpha1.ApplicationQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-quota"},
+			Spec: v1alpha1.ApplicationQuotaSpec{
+				Application: &v1alpha1.ApplicationQuotaTarget{Name: "quota-app", Namespace: "default"},
+				Hard:        v1alpha1.ApplicationQuotaHard{Objects: map[string]int64{"Service": 1}},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), appQuota)).Should(BeNil())
+		otherQuota := &v1alpha1.ApplicationQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-quota"},
+			Spec: v1alpha1.ApplicationQuotaSpec{
+				Project: "other-project",
+				Hard:    v1alpha1.ApplicationQuotaHard{Objects: map[string]int64{"Deployment": 1}},
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), otherQuota)).Should(BeNil())
+
+		quotas, err := projectService.ListProjectQuotas(context.TODO(), "quota-project")
+		Expect(err).Should(BeNil())
+		Expect(len(quotas.Quotas)).Should(Equal(2))
+		quotas, err = projectService.ListProjectQuotas(context.TODO(), "no-quota-project")
+		Expect(err).Should(BeNil())
+		Expect(len(quotas.Quotas)).Should(Equal(0))
+	})
 })
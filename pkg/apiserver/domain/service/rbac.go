@@ -53,6 +53,7 @@ var defaultProjectPermissionTemplate = []*model.PermissionTemplate{
 		Resources: []string{
 			"project:{projectName}",
 			"project:{projectName}/config:*",
+			"project:{projectName}/quota:*",
 			"project:{projectName}/role:*",
 			"project:{projectName}/projectUser:*",
 			"project:{projectName}/permission:*",
@@ -82,7 +83,7 @@ var defaultProjectPermissionTemplate = []*model.PermissionTemplate{
 	{
 		Name:      "role-management",
 		Alias:     "Role Management",
-		Resources: []string{"project:{projectName}/role:*", "project:{projectName}/projectUser:*", "project:{projectName}/permission:*"},
+		Resources: []string{"project:{projectName}/role:*", "project:{projectName}/projectUser:*", "project:{projectName}/permission:*", "project:{projectName}/serviceAccount:*/*", "project:{projectName}/outboundWebhook:*/*"},
 		Actions:   []string{"*"},
 		Effect:    "Allow",
 		Scope:     "project",
@@ -230,6 +231,21 @@ var ResourceMaps = map[string]resourceMetadata{
 			},
 			"applicationTemplate": {},
 			"config":              {},
+			"quota":               {},
+			"serviceAccount": {
+				pathName: "serviceAccountName",
+				subResources: map[string]resourceMetadata{
+					"token": {
+						pathName: "tokenID",
+					},
+				},
+			},
+			"outboundWebhook": {
+				pathName: "webhookName",
+				subResources: map[string]resourceMetadata{
+					"delivery": {},
+				},
+			},
 		},
 		pathName: "projectName",
 	},
@@ -256,6 +272,7 @@ var ResourceMaps = map[string]resourceMetadata{
 		pathName: "permissionName",
 	},
 	"systemSetting": {},
+	"auditLog":      {},
 	"definition": {
 		pathName: "definitionName",
 	},
@@ -546,6 +563,22 @@ func (p *rbacServiceImpl) listPermPolices(ctx context.Context, projectName strin
 	return perms, nil
 }
 
+// getScopePermissions get the platform permissions and the permissions of the project in the scopes of the API token
+func (p *rbacServiceImpl) getScopePermissions(ctx context.Context, token *model.APIToken, projectName string) ([]*model.Permission, error) {
+	perms, err := p.listPermPolices(ctx, "", token.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if projectName == "" {
+		return perms, nil
+	}
+	projectPerms, err := p.listPermPolices(ctx, projectName, token.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	return append(perms, projectPerms...), nil
+}
+
 func (p *rbacServiceImpl) CheckPerm(resource string, actions ...string) func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
 	registerResourceAction(resource, actions...)
 	f := func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
@@ -614,6 +647,19 @@ func (p *rbacServiceImpl) CheckPerm(resource string, actions ...string) func(req
 			bcode.ReturnError(req, res, bcode.ErrForbidden)
 			return
 		}
+		// the API token could only use the permissions in its scopes
+		if token, ok := req.Request.Context().Value(&apisv1.CtxKeyAPIToken).(*model.APIToken); ok && len(token.Scopes) > 0 {
+			scopes, err := p.getScopePermissions(req.Request.Context(), token, projectName)
+			if err != nil {
+				log.Logger.Errorf("get the scope permissions of the API token failure %s, token is %s", err.Error(), token.ID)
+				bcode.ReturnError(req, res, bcode.ErrForbidden)
+				return
+			}
+			if !ra.Match(scopes) {
+				bcode.ReturnError(req, res, bcode.ErrForbidden)
+				return
+			}
+		}
 		apiserverutils.SetUsernameAndProjectInRequestContext(req, userName, projectName)
 		chain.ProcessFilter(req, res)
 	}
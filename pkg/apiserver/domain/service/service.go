@@ -46,11 +46,16 @@ func InitServiceBean(c config.Config) []interface{} {
 	configService := NewConfigService()
 	applicationService := NewApplicationService()
 	webhookService := NewWebhookService()
+	auditService := NewAuditService(c.AuditLog)
+	apiTokenService := NewAPITokenService()
+	applicationEventService := NewApplicationEventService()
+	outboundWebhookService := NewOutboundWebhookService()
 	needInitData = []DataInit{clusterService, userService, rbacService, projectService, targetService, systemInfoService}
 	return []interface{}{
 		clusterService, rbacService, projectService, envService, targetService, workflowService, oamApplicationService,
 		velaQLService, definitionService, addonService, envBindingService, systemInfoService, helmService, userService,
 		authenticationService, configService, applicationService, webhookService, NewImageService(), NewCloudShellService(),
+		auditService, apiTokenService, applicationEventService, outboundWebhookService,
 	}
 }
 
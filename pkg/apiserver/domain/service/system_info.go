@@ -116,6 +116,19 @@ func (u systemInfoServiceImpl) UpdateSystemInfo(ctx context.Context, sysInfo v1.
 		StatisticInfo:               info.StatisticInfo,
 		DexUserDefaultProjects:      sysInfo.DexUserDefaultProjects,
 		DexUserDefaultPlatformRoles: info.DexUserDefaultPlatformRoles,
+		LDAP:                        info.LDAP,
+	}
+	if sysInfo.LDAP != nil {
+		modifiedInfo.LDAP = sysInfo.LDAP
+		// the bind password is not returned, keep the saved one if it is not changed
+		if sysInfo.LDAP.BindPassword == "" && info.LDAP != nil && info.LDAP.BindDN == sysInfo.LDAP.BindDN {
+			modifiedInfo.LDAP.BindPassword = info.LDAP.BindPassword
+		}
+	}
+	if sysInfo.LoginType == model.LoginTypeLDAP {
+		if err := checkLDAPConfig(modifiedInfo.LDAP); err != nil {
+			return nil, err
+		}
 	}
 
 	if sysInfo.LoginType == model.LoginTypeDex {
@@ -151,6 +164,7 @@ func (u systemInfoServiceImpl) UpdateSystemInfo(ctx context.Context, sysInfo v1.
 			LoginType:        modifiedInfo.LoginType,
 			// always use the initial createTime as system's installTime
 			InstallTime: info.CreateTime,
+			LDAP:        hideLDAPBindPassword(modifiedInfo.LDAP),
 		},
 		SystemVersion: v1.SystemVersion{VelaVersion: version.VelaVersion, GitVersion: version.GitRevision},
 	}, nil
@@ -174,5 +188,15 @@ func convertInfoToBase(info *model.SystemInfo) v1.SystemInfo {
 		InstallTime:                 info.CreateTime,
 		DexUserDefaultProjects:      info.DexUserDefaultProjects,
 		DexUserDefaultPlatformRoles: info.DexUserDefaultPlatformRoles,
+		LDAP:                        hideLDAPBindPassword(info.LDAP),
+	}
+}
+
+func hideLDAPBindPassword(config *model.LDAPConfig) *model.LDAPConfig {
+	if config == nil {
+		return nil
 	}
+	hidden := *config
+	hidden.BindPassword = ""
+	return &hidden
 }
@@ -120,9 +120,15 @@ func (dt *targetServiceImpl) DeleteTarget(ctx context.Context, targetName string
 	if err != nil {
 		return err
 	}
-	if err = repository.DeleteTargetNamespace(ctx, dt.K8sClient, ddt.Cluster.ClusterName, ddt.Cluster.Namespace, targetName); err != nil {
+	clusterTargets, err := repository.GetTargetClusters(ctx, dt.K8sClient, ddt)
+	if err != nil && !errors.Is(err, bcode.ErrClusterGroupNotExist) {
 		return err
 	}
+	for _, clusterTarget := range clusterTargets {
+		if err = repository.DeleteTargetNamespace(ctx, dt.K8sClient, clusterTarget.ClusterName, clusterTarget.Namespace, targetName); err != nil {
+			return err
+		}
+	}
 	if err = managePrivilegesForTarget(ctx, dt.K8sClient, ddt, true); err != nil {
 		return err
 	}
@@ -145,11 +151,23 @@ func (dt *targetServiceImpl) CreateTarget(ctx context.Context, req apisv1.Create
 		return nil, bcode.ErrProjectIsNotExist
 	}
 	target := convertCreateReqToTargetModel(req)
-	if req.Cluster == nil {
+	if req.Cluster != nil && req.ClusterGroup != nil {
+		return nil, bcode.ErrTargetInvalidWithClusterAndClusterGroup
+	}
+	if req.Cluster == nil && req.ClusterGroup == nil {
 		req.Cluster = &apisv1.ClusterTarget{ClusterName: multicluster.ClusterLocalName, Namespace: req.Name}
 	}
-	if err := repository.CreateTargetNamespace(ctx, dt.K8sClient, req.Cluster.ClusterName, req.Cluster.Namespace, req.Name); err != nil {
-		return nil, err
+	clusterTargets := []*model.ClusterTarget{(*model.ClusterTarget)(req.Cluster)}
+	if req.ClusterGroup != nil {
+		var err error
+		if clusterTargets, err = repository.GetTargetClusters(ctx, dt.K8sClient, &target); err != nil {
+			return nil, err
+		}
+	}
+	for _, clusterTarget := range clusterTargets {
+		if err := repository.CreateTargetNamespace(ctx, dt.K8sClient, clusterTarget.ClusterName, clusterTarget.Namespace, req.Name); err != nil {
+			return nil, err
+		}
 	}
 	if err := managePrivilegesForTarget(ctx, dt.K8sClient, &target, false); err != nil {
 		return nil, err
@@ -162,6 +180,9 @@ func (dt *targetServiceImpl) CreateTarget(ctx context.Context, req apisv1.Create
 }
 
 func (dt *targetServiceImpl) UpdateTarget(ctx context.Context, target *model.Target, req apisv1.UpdateTargetRequest) (*apisv1.DetailTargetResponse, error) {
+	if err := checkResourceVersion(target, req.ResourceVersion); err != nil {
+		return nil, err
+	}
 	targetModel := convertUpdateReqToTargetModel(target, req)
 	if err := dt.Store.Put(ctx, targetModel); err != nil {
 		return nil, err
@@ -200,12 +221,13 @@ func convertUpdateReqToTargetModel(target *model.Target, req apisv1.UpdateTarget
 
 func convertCreateReqToTargetModel(req apisv1.CreateTargetRequest) model.Target {
 	target := model.Target{
-		Name:        req.Name,
-		Alias:       req.Alias,
-		Description: req.Description,
-		Cluster:     (*model.ClusterTarget)(req.Cluster),
-		Variable:    req.Variable,
-		Project:     req.Project,
+		Name:         req.Name,
+		Alias:        req.Alias,
+		Description:  req.Description,
+		Cluster:      (*model.ClusterTarget)(req.Cluster),
+		ClusterGroup: (*model.ClusterGroupTarget)(req.ClusterGroup),
+		Variable:     req.Variable,
+		Project:      req.Project,
 	}
 	return target
 }
@@ -214,14 +236,16 @@ func (dt *targetServiceImpl) convertFromTargetModel(ctx context.Context, target
 	var appNum int64 = 0
 	// TODO: query app num in target
 	targetBase := &apisv1.TargetBase{
-		Name:        target.Name,
-		Alias:       target.Alias,
-		Description: target.Description,
-		Cluster:     (*apisv1.ClusterTarget)(target.Cluster),
-		Variable:    target.Variable,
-		CreateTime:  target.CreateTime,
-		UpdateTime:  target.UpdateTime,
-		AppNum:      appNum,
+		Name:            target.Name,
+		Alias:           target.Alias,
+		Description:     target.Description,
+		Cluster:         (*apisv1.ClusterTarget)(target.Cluster),
+		ClusterGroup:    (*apisv1.ClusterGroupTarget)(target.ClusterGroup),
+		Variable:        target.Variable,
+		CreateTime:      target.CreateTime,
+		UpdateTime:      target.UpdateTime,
+		ResourceVersion: target.ResourceVersion,
+		AppNum:          appNum,
 	}
 	if target.Project != "" {
 		var project = model.Project{
@@ -246,17 +270,27 @@ func (dt *targetServiceImpl) convertFromTargetModel(ctx context.Context, target
 
 // managePrivilegesForTarget grant or revoke privileges for target
 func managePrivilegesForTarget(ctx context.Context, cli client.Client, target *model.Target, revoke bool) error {
-	if target.Cluster == nil {
+	clusterTargets, err := repository.GetTargetClusters(ctx, cli, target)
+	if err != nil {
+		if revoke && errors.Is(err, bcode.ErrClusterGroupNotExist) {
+			return nil
+		}
+		return err
+	}
+	if len(clusterTargets) == 0 {
 		return nil
 	}
-	p := &auth.ScopedPrivilege{Cluster: target.Cluster.ClusterName, Namespace: target.Cluster.Namespace}
+	var pds []auth.PrivilegeDescription
+	for _, clusterTarget := range clusterTargets {
+		pds = append(pds, &auth.ScopedPrivilege{Cluster: clusterTarget.ClusterName, Namespace: clusterTarget.Namespace})
+	}
 	identity := &auth.Identity{Groups: []string{utils.KubeVelaProjectGroupPrefix + target.Project}}
 	writer := &bytes.Buffer{}
 	f, msg := auth.GrantPrivileges, "GrantPrivileges"
 	if revoke {
 		f, msg = auth.RevokePrivileges, "RevokePrivileges"
 	}
-	if err := f(ctx, cli, []auth.PrivilegeDescription{p}, identity, writer); err != nil {
+	if err := f(ctx, cli, pds, identity, writer); err != nil {
 		return err
 	}
 	log.Logger.Debugf("%s: %s", msg, writer.String())
@@ -80,6 +80,7 @@ func (u *userServiceImpl) Init(ctx context.Context) error {
 				Alias:     model.DefaultAdminUserAlias,
 				Password:  encrypted,
 				UserRoles: []string{"admin"},
+				Source:    model.UserSourceLocal,
 			}); err != nil {
 				return err
 			}
@@ -154,6 +155,10 @@ func (u *userServiceImpl) DeleteUser(ctx context.Context, username string) error
 		log.Logger.Errorf("failed to delete user %s %v", pkgUtils.Sanitize(username), err.Error())
 		return err
 	}
+	if err := deleteAPITokens(ctx, u.Store, username); err != nil {
+		log.Logger.Errorf("failed to revoke the API tokens of user %s %v", pkgUtils.Sanitize(username), err.Error())
+		return err
+	}
 	return nil
 }
 
@@ -179,6 +184,7 @@ func (u *userServiceImpl) CreateUser(ctx context.Context, req apisv1.CreateUserR
 		UserRoles: req.Roles,
 		Password:  hash,
 		Disabled:  false,
+		Source:    model.UserSourceLocal,
 	}
 	if err := u.Store.Add(ctx, user); err != nil {
 		return nil, err
@@ -393,6 +399,7 @@ func convertUserBase(user *model.User) *apisv1.UserBase {
 		CreateTime:    user.CreateTime,
 		LastLoginTime: user.LastLoginTime,
 		Disabled:      user.Disabled,
+		Source:        user.GetSource(),
 	}
 }
 
@@ -20,6 +20,9 @@ import (
 	"fmt"
 
 	"encoding/json"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
 )
 
 // guaranteePolicyExist check the slice whether contain the target policy, if not put it in.
@@ -74,3 +77,12 @@ func extractPolicyListAndProperty(property string) ([]string, map[string]interfa
 	}
 	return res, content, nil
 }
+
+// checkResourceVersion check the resource version in the update request is the same as the stored entity,
+// an empty version means the caller does not care about the concurrent modification.
+func checkResourceVersion(entity datastore.Entity, resourceVersion int64) error {
+	if resourceVersion != 0 && resourceVersion != entity.GetResourceVersion() {
+		return bcode.ErrConflict
+	}
+	return nil
+}
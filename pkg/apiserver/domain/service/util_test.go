@@ -17,9 +17,17 @@ limitations under the License.
 package service
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
 
 	"gotest.tools/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
 )
 
 func TestGuaranteePolicyNotExist(t *testing.T) {
@@ -220,3 +228,29 @@ func TestExtractPolicyListAndProperty(t *testing.T) {
 		assert.DeepEqual(t, properties, testCase.res.properties)
 	}
 }
+
+func TestCheckResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NilError(t, err)
+	assert.NilError(t, ds.Add(ctx, &model.Project{Name: "demo"}))
+	projectService := &projectServiceImpl{Store: ds}
+
+	base, err := projectService.DetailProject(ctx, "demo")
+	assert.NilError(t, err)
+	assert.Equal(t, base.ResourceVersion, int64(1))
+
+	updated, err := projectService.UpdateProject(ctx, "demo", apisv1.UpdateProjectRequest{Alias: "first", ResourceVersion: base.ResourceVersion})
+	assert.NilError(t, err)
+	assert.Equal(t, updated.ResourceVersion, int64(2))
+
+	// the update based on a stale version must be rejected
+	_, err = projectService.UpdateProject(ctx, "demo", apisv1.UpdateProjectRequest{Alias: "second", ResourceVersion: base.ResourceVersion})
+	assert.Equal(t, err, bcode.ErrConflict)
+
+	// the update without a version is not checked
+	updated, err = projectService.UpdateProject(ctx, "demo", apisv1.UpdateProjectRequest{Alias: "third"})
+	assert.NilError(t, err)
+	assert.Equal(t, updated.Alias, "third")
+	assert.Equal(t, updated.ResourceVersion, int64(3))
+}
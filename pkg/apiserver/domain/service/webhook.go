@@ -59,6 +59,9 @@ func registerHandlers() {
 	new(dockerHubHandlerImpl).install()
 	new(harborHandlerImpl).install()
 	new(jfrogHandlerImpl).install()
+	(&gitHandlerImpl{provider: model.PayloadTypeGitHub}).install()
+	(&gitHandlerImpl{provider: model.PayloadTypeGitLab}).install()
+	(&gitHandlerImpl{provider: model.PayloadTypeGitea}).install()
 }
 
 type webhookHandler interface {
@@ -162,6 +165,11 @@ func (c *webhookServiceImpl) HandleApplicationWebhook(ctx context.Context, token
 		if err != nil {
 			return nil, err
 		}
+	case model.PayloadTypeGitHub, model.PayloadTypeGitLab, model.PayloadTypeGitea:
+		handler, err = c.newGitHandler(req, webhookTrigger.PayloadType)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, bcode.ErrInvalidWebhookPayloadType
 	}
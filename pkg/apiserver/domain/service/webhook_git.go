@@ -0,0 +1,392 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/emicklei/go-restful/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+const (
+	gitRefBranchPrefix = "refs/heads/"
+	gitRefTagPrefix    = "refs/tags/"
+	gitZeroSHA         = "0000000000000000000000000000000000000000"
+)
+
+// gitProviderHeaders are the request headers of the git providers
+var gitProviderHeaders = map[string]struct {
+	event     string
+	signature string
+	push      []string
+}{
+	model.PayloadTypeGitHub: {event: "X-GitHub-Event", signature: "X-Hub-Signature-256", push: []string{"push"}},
+	model.PayloadTypeGitLab: {event: "X-Gitlab-Event", signature: "X-Gitlab-Token", push: []string{"Push Hook", "Tag Push Hook"}},
+	model.PayloadTypeGitea:  {event: "X-Gitea-Event", signature: "X-Gitea-Signature", push: []string{"push"}},
+}
+
+// gitPushEvent is the push event shared by the git providers, the properties of the git rules are rendered with it
+type gitPushEvent struct {
+	Provider   string
+	Ref        string
+	Branch     string
+	Tag        string
+	SHA        string
+	ShortSHA   string
+	Repository string
+	Author     string
+	Message    string
+	Pusher     string
+	// Payload is the raw request body, it allows the templates to use any field of the payload
+	Payload map[string]interface{}
+}
+
+type gitHandlerImpl struct {
+	provider  string
+	event     string
+	signature string
+	body      []byte
+	req       apisv1.HandleApplicationTriggerGitPushRequest
+	w         *webhookServiceImpl
+}
+
+func (c *webhookServiceImpl) newGitHandler(req *restful.Request, provider string) (webhookHandler, error) {
+	body, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		return nil, bcode.ErrInvalidWebhookPayloadBody
+	}
+	var pushReq apisv1.HandleApplicationTriggerGitPushRequest
+	if err := json.Unmarshal(body, &pushReq); err != nil {
+		return nil, bcode.ErrInvalidWebhookPayloadBody
+	}
+	if err := json.Unmarshal(body, &pushReq.Payload); err != nil {
+		return nil, bcode.ErrInvalidWebhookPayloadBody
+	}
+	headers := gitProviderHeaders[provider]
+	return &gitHandlerImpl{
+		provider:  provider,
+		event:     req.HeaderParameter(headers.event),
+		signature: req.HeaderParameter(headers.signature),
+		body:      body,
+		req:       pushReq,
+		w:         c,
+	}, nil
+}
+
+func (c *gitHandlerImpl) install() {
+	WebhookHandlers = append(WebhookHandlers, c.provider)
+}
+
+func (c *gitHandlerImpl) handle(ctx context.Context, trigger *model.ApplicationTrigger, app *model.Application) (interface{}, error) {
+	if !verifyGitSignature(c.provider, trigger.Secret, c.signature, c.body) {
+		return nil, bcode.ErrInvalidWebhookSignature
+	}
+	if !isGitPushEvent(c.provider, c.event) {
+		return &apisv1.ApplicationGitWebhookResponse{
+			State:       "skipped",
+			Description: fmt.Sprintf("ignore the %s event %q", c.provider, c.event),
+		}, nil
+	}
+	event := newGitPushEvent(c.provider, c.req)
+	if event.SHA == "" || event.SHA == gitZeroSHA || c.req.Deleted {
+		return &apisv1.ApplicationGitWebhookResponse{
+			State:       "skipped",
+			Description: fmt.Sprintf("ignore the deletion of %s", event.Ref),
+		}, nil
+	}
+	rule := matchGitTriggerRule(trigger.GitRules, event)
+	if rule == nil {
+		log.Logger.Debugf("no git rule of the trigger %s matches %s", trigger.Name, event.Ref)
+		return &apisv1.ApplicationGitWebhookResponse{
+			State:       "skipped",
+			Description: fmt.Sprintf("no git rule matches %s", event.Ref),
+		}, nil
+	}
+
+	workflowName, err := c.w.getGitRuleWorkflow(ctx, trigger, app, rule)
+	if err != nil {
+		return nil, err
+	}
+	if len(rule.Properties) > 0 {
+		patch, err := renderGitRuleProperties(rule.Properties, rule.TypedProperties, event)
+		if err != nil {
+			return nil, err
+		}
+		compTrigger := *trigger
+		if rule.ComponentName != "" {
+			compTrigger.ComponentName = rule.ComponentName
+		}
+		component, err := getComponent(ctx, c.w.Store, &compTrigger)
+		if err != nil {
+			if errors.Is(err, datastore.ErrRecordNotExist) {
+				return nil, bcode.ErrApplicationComponentNotExist
+			}
+			return nil, err
+		}
+		if err := c.w.patchComponentProperties(ctx, component, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	ref := event.Branch
+	if ref == "" {
+		ref = event.Tag
+	}
+	return c.w.ApplicationService.Deploy(ctx, app, apisv1.ApplicationDeployRequest{
+		WorkflowName: workflowName,
+		Note:         "triggered by webhook " + c.provider,
+		TriggerType:  apisv1.TriggerTypeWebhook,
+		Force:        true,
+		CodeInfo: &model.CodeInfo{
+			Commit: event.SHA,
+			Branch: ref,
+			User:   event.Pusher,
+		},
+	})
+}
+
+// getGitRuleWorkflow returns the workflow of the rule, the workflow of the rule env or the workflow of the trigger
+func (c *webhookServiceImpl) getGitRuleWorkflow(ctx context.Context, trigger *model.ApplicationTrigger, app *model.Application, rule *model.GitTriggerRule) (string, error) {
+	if rule.WorkflowName != "" {
+		return rule.WorkflowName, nil
+	}
+	if rule.EnvName == "" {
+		return trigger.WorkflowName, nil
+	}
+	workflows, err := c.Store.List(ctx, &model.Workflow{AppPrimaryKey: app.PrimaryKey(), EnvName: rule.EnvName}, &datastore.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	if len(workflows) == 0 {
+		return "", bcode.ErrWorkflowNotExist
+	}
+	return workflows[0].(*model.Workflow).Name, nil
+}
+
+// verifyGitSignature checks the signature of the request body, gitlab sends the secret as the token directly
+func verifyGitSignature(provider, secret, signature string, body []byte) bool {
+	if secret == "" {
+		return true
+	}
+	if provider == model.PayloadTypeGitLab {
+		return subtle.ConstantTimeCompare([]byte(secret), []byte(signature)) == 1
+	}
+	if provider == model.PayloadTypeGitHub {
+		if !strings.HasPrefix(signature, "sha256=") {
+			return false
+		}
+		signature = strings.TrimPrefix(signature, "sha256=")
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func isGitPushEvent(provider, event string) bool {
+	for _, push := range gitProviderHeaders[provider].push {
+		if event == push {
+			return true
+		}
+	}
+	return false
+}
+
+func newGitPushEvent(provider string, req apisv1.HandleApplicationTriggerGitPushRequest) *gitPushEvent {
+	event := &gitPushEvent{
+		Provider:   provider,
+		Ref:        req.Ref,
+		SHA:        req.After,
+		Repository: req.Repository.FullName,
+		Payload:    req.Payload,
+	}
+	switch {
+	case strings.HasPrefix(req.Ref, gitRefBranchPrefix):
+		event.Branch = strings.TrimPrefix(req.Ref, gitRefBranchPrefix)
+	case strings.HasPrefix(req.Ref, gitRefTagPrefix):
+		event.Tag = strings.TrimPrefix(req.Ref, gitRefTagPrefix)
+	}
+	if req.CheckoutSHA != "" {
+		event.SHA = req.CheckoutSHA
+	}
+	event.ShortSHA = event.SHA
+	if len(event.ShortSHA) > 7 {
+		event.ShortSHA = event.ShortSHA[:7]
+	}
+	if req.Project != nil && req.Project.PathWithNamespace != "" {
+		event.Repository = req.Project.PathWithNamespace
+	}
+	if event.Repository == "" {
+		event.Repository = req.Repository.Name
+	}
+	commit := req.HeadCommit
+	for i := range req.Commits {
+		if req.Commits[i].ID == event.SHA {
+			commit = &req.Commits[i]
+		}
+	}
+	if commit != nil {
+		event.Message = commit.Message
+		event.Author = gitUserName(commit.Author)
+	}
+	event.Pusher = gitUserName(req.Pusher)
+	if event.Pusher == "" {
+		event.Pusher = req.UserName
+	}
+	return event
+}
+
+func gitUserName(user apisv1.GitWebhookUser) string {
+	for _, name := range []string{user.Login, user.Username, user.Name} {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// matchGitTriggerRule returns the first rule matching the pushed ref, every push matches if there is no rule
+func matchGitTriggerRule(rules []model.GitTriggerRule, event *gitPushEvent) *model.GitTriggerRule {
+	if len(rules) == 0 {
+		return &model.GitTriggerRule{}
+	}
+	for i, rule := range rules {
+		if rule.Branch != "" && event.Branch != "" {
+			if matched, _ := path.Match(rule.Branch, event.Branch); matched {
+				return &rules[i]
+			}
+		}
+		if rule.Tag != "" && event.Tag != "" {
+			if matched, _ := path.Match(rule.Tag, event.Tag); matched {
+				return &rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// renderGitRuleProperties renders the property templates to the patch of the component properties, only the values
+// of the typed properties are decoded and the others are kept as strings
+func renderGitRuleProperties(properties map[string]string, typedProperties []string, event *gitPushEvent) (*runtime.RawExtension, error) {
+	typed := map[string]bool{}
+	for _, key := range typedProperties {
+		typed[key] = true
+	}
+	patch := map[string]interface{}{}
+	for key, value := range properties {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, bcode.ErrInvalidGitTriggerRule
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, event); err != nil {
+			log.Logger.Warnf("failed to render the property %s of the git rule: %s", key, err.Error())
+			return nil, bcode.ErrInvalidWebhookPayloadBody
+		}
+		fields := strings.Split(key, ".")
+		current := patch
+		for _, field := range fields[:len(fields)-1] {
+			next, ok := current[field].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[field] = next
+			}
+			current = next
+		}
+		if typed[key] {
+			current[fields[len(fields)-1]] = decodeGitRuleProperty(buf.String())
+		} else {
+			current[fields[len(fields)-1]] = buf.String()
+		}
+	}
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
+// decodeGitRuleProperty decodes the rendered value as JSON or YAML, so that the numbers, the booleans, the lists and the
+// objects could be set. The value is kept as a string if it could not be decoded.
+func decodeGitRuleProperty(value string) interface{} {
+	if strings.TrimSpace(value) == "" {
+		return value
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+		return decoded
+	}
+	if err := yaml.Unmarshal([]byte(value), &decoded); err == nil && decoded != nil {
+		return decoded
+	}
+	return value
+}
+
+// validateGitTriggerRules checks the git rules when creating the trigger
+func validateGitTriggerRules(payloadType string, rules []model.GitTriggerRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	if _, ok := gitProviderHeaders[payloadType]; !ok {
+		return bcode.ErrInvalidGitTriggerRule
+	}
+	for _, rule := range rules {
+		if (rule.Branch == "") == (rule.Tag == "") {
+			return bcode.ErrInvalidGitTriggerRule
+		}
+		if _, err := path.Match(rule.Branch+rule.Tag, ""); err != nil {
+			return bcode.ErrInvalidGitTriggerRule
+		}
+		for key, value := range rule.Properties {
+			if key == "" || strings.HasPrefix(key, ".") || strings.HasSuffix(key, ".") || strings.Contains(key, "..") {
+				return bcode.ErrInvalidGitTriggerRule
+			}
+			if _, err := template.New(key).Parse(value); err != nil {
+				return bcode.ErrInvalidGitTriggerRule
+			}
+		}
+		for _, key := range rule.TypedProperties {
+			if _, ok := rule.Properties[key]; !ok {
+				return bcode.ErrInvalidGitTriggerRule
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+type stubDeployApplicationService struct {
+	ApplicationService
+	deployed []apisv1.ApplicationDeployRequest
+}
+
+func (s *stubDeployApplicationService) Deploy(ctx context.Context, app *model.Application, req apisv1.ApplicationDeployRequest) (*apisv1.ApplicationDeployResponse, error) {
+	s.deployed = append(s.deployed, req)
+	return &apisv1.ApplicationDeployResponse{}, nil
+}
+
+func newGitWebhookRequest(t *testing.T, headers map[string]string, body []byte) *restful.Request {
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return restful.NewRequest(req)
+}
+
+func signGitPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyGitSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	signature := signGitPayload("s3cret", body)
+
+	assert.True(t, verifyGitSignature(model.PayloadTypeGitHub, "", "", body))
+	assert.True(t, verifyGitSignature(model.PayloadTypeGitHub, "s3cret", "sha256="+signature, body))
+	assert.False(t, verifyGitSignature(model.PayloadTypeGitHub, "s3cret", signature, body))
+	assert.False(t, verifyGitSignature(model.PayloadTypeGitHub, "other", "sha256="+signature, body))
+	assert.True(t, verifyGitSignature(model.PayloadTypeGitea, "s3cret", signature, body))
+	assert.False(t, verifyGitSignature(model.PayloadTypeGitea, "s3cret", "not-hex", body))
+	assert.True(t, verifyGitSignature(model.PayloadTypeGitLab, "s3cret", "s3cret", body))
+	assert.False(t, verifyGitSignature(model.PayloadTypeGitLab, "s3cret", "", body))
+}
+
+func TestMatchGitTriggerRule(t *testing.T) {
+	rules := []model.GitTriggerRule{
+		{Branch: "main", EnvName: "staging"},
+		{Branch: "release/*", WorkflowName: "release"},
+		{Tag: "v*", EnvName: "prod"},
+	}
+	assert.Equal(t, "staging", matchGitTriggerRule(rules, &gitPushEvent{Branch: "main"}).EnvName)
+	assert.Equal(t, "release", matchGitTriggerRule(rules, &gitPushEvent{Branch: "release/1.0"}).WorkflowName)
+	assert.Equal(t, "prod", matchGitTriggerRule(rules, &gitPushEvent{Tag: "v1.2.0"}).EnvName)
+	assert.Nil(t, matchGitTriggerRule(rules, &gitPushEvent{Branch: "v1.2.0"}))
+	assert.Nil(t, matchGitTriggerRule(rules, &gitPushEvent{Branch: "feature/a"}))
+	assert.NotNil(t, matchGitTriggerRule(nil, &gitPushEvent{Branch: "feature/a"}))
+}
+
+func TestRenderGitRuleProperties(t *testing.T) {
+	event := &gitPushEvent{
+		Branch:   "main",
+		SHA:      "4f2e1c0a9b8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f",
+		ShortSHA: "4f2e1c0",
+		Payload:  map[string]interface{}{"repository": map[string]interface{}{"name": "demo"}},
+	}
+	patch, err := renderGitRuleProperties(map[string]string{
+		"image":          "registry.example.com/{{ .Payload.repository.name }}:{{ .ShortSHA }}",
+		"env.GIT_BRANCH": "{{ .Branch }}",
+		"env.GIT_COMMIT": "{{ .SHA }}",
+		"env.GIT_REF":    `"{{ .Branch }}"`,
+		"replicas":       "{{ len .Branch }}",
+		"debug":          `{{ eq .Branch "main" }}`,
+		"ports":          "[80, 443]",
+		"labels":         "repo: {{ .Payload.repository.name }}\nbranch: {{ .Branch }}",
+		"args":           "",
+		"version":        "1.10",
+		"build":          "1234567",
+		"feature":        "on",
+	}, []string{"env.GIT_REF", "replicas", "debug", "ports", "labels", "args"}, event)
+	assert.NoError(t, err)
+	var properties map[string]interface{}
+	assert.NoError(t, json.Unmarshal(patch.Raw, &properties))
+	assert.Equal(t, map[string]interface{}{
+		"image": "registry.example.com/demo:4f2e1c0",
+		"env": map[string]interface{}{
+			"GIT_BRANCH": "main",
+			"GIT_COMMIT": "4f2e1c0a9b8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f",
+			"GIT_REF":    "main",
+		},
+		"replicas": float64(4),
+		"debug":    true,
+		"ports":    []interface{}{float64(80), float64(443)},
+		"labels":   map[string]interface{}{"repo": "demo", "branch": "main"},
+		"args":     "",
+		"version":  "1.10",
+		"build":    "1234567",
+		"feature":  "on",
+	}, properties)
+
+	_, err = renderGitRuleProperties(map[string]string{"image": "{{ .Payload.missing.field }}"}, nil, event)
+	assert.Equal(t, bcode.ErrInvalidWebhookPayloadBody, err)
+}
+
+func TestValidateGitTriggerRules(t *testing.T) {
+	assert.NoError(t, validateGitTriggerRules(model.PayloadTypeCustom, nil))
+	assert.NoError(t, validateGitTriggerRules(model.PayloadTypeGitHub, []model.GitTriggerRule{
+		{Branch: "main", Properties: map[string]string{"image": "app:{{ .ShortSHA }}"}},
+		{Tag: "v*"},
+	}))
+	for _, rules := range [][]model.GitTriggerRule{
+		{{Branch: "main", Tag: "v*"}},
+		{{}},
+		{{Branch: "[main"}},
+		{{Branch: "main", Properties: map[string]string{"image": "{{ .ShortSHA"}}},
+		{{Branch: "main", Properties: map[string]string{"env..A": "a"}}},
+		{{Branch: "main", Properties: map[string]string{"image": "a"}, TypedProperties: []string{"replicas"}}},
+	} {
+		assert.Equal(t, bcode.ErrInvalidGitTriggerRule, validateGitTriggerRules(model.PayloadTypeGitLab, rules))
+	}
+	assert.Equal(t, bcode.ErrInvalidGitTriggerRule, validateGitTriggerRules(model.PayloadTypeCustom, []model.GitTriggerRule{{Branch: "main"}}))
+}
+
+func TestHandleGitWebhook(t *testing.T) {
+	ctx := context.Background()
+	ds, err := sqldb.New(ctx, datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	assert.NoError(t, err)
+	appService := &stubDeployApplicationService{}
+	webhookService := &webhookServiceImpl{Store: ds, ApplicationService: appService}
+
+	assert.NoError(t, ds.Add(ctx, &model.Application{Name: "demo", Project: "default"}))
+	assert.NoError(t, ds.Add(ctx, &model.ApplicationComponent{
+		AppPrimaryKey: "demo",
+		Name:          "web",
+		Type:          "webservice",
+		Properties:    &model.JSONStruct{"image": "demo:latest", "port": float64(80)},
+	}))
+	assert.NoError(t, ds.Add(ctx, &model.Workflow{AppPrimaryKey: "demo", Name: "workflow-prod", EnvName: "prod"}))
+	for _, trigger := range []*model.ApplicationTrigger{
+		{AppPrimaryKey: "demo", Name: "github", Token: "github-token", Type: "webhook", PayloadType: model.PayloadTypeGitHub,
+			WorkflowName: "workflow-default", Secret: "s3cret",
+			GitRules: []model.GitTriggerRule{{Branch: "main", ComponentName: "web", Properties: map[string]string{"image": "demo:{{ .ShortSHA }}"}}}},
+		{AppPrimaryKey: "demo", Name: "gitlab", Token: "gitlab-token", Type: "webhook", PayloadType: model.PayloadTypeGitLab,
+			WorkflowName: "workflow-default", Secret: "s3cret",
+			GitRules: []model.GitTriggerRule{{Tag: "v*", EnvName: "prod", Properties: map[string]string{"image": "demo:{{ .Tag }}"}}}},
+		{AppPrimaryKey: "demo", Name: "gitea", Token: "gitea-token", Type: "webhook", PayloadType: model.PayloadTypeGitea,
+			WorkflowName: "workflow-default"},
+	} {
+		assert.NoError(t, ds.Add(ctx, trigger))
+	}
+	registerHandlers()
+
+	githubBody := []byte(`{"ref":"refs/heads/main","before":"0000000000000000000000000000000000000000",
+"after":"4f2e1c0a9b8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f","repository":{"name":"demo","full_name":"org/demo"},
+"head_commit":{"id":"4f2e1c0a9b8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f","message":"fix","author":{"name":"Jane","username":"jane"}},
+"pusher":{"name":"jane"}}`)
+	_, err = webhookService.HandleApplicationWebhook(ctx, "github-token", newGitWebhookRequest(t, map[string]string{
+		"X-GitHub-Event":      "push",
+		"X-Hub-Signature-256": "sha256=" + signGitPayload("other", githubBody),
+	}, githubBody))
+	assert.Equal(t, bcode.ErrInvalidWebhookSignature, err)
+
+	_, err = webhookService.HandleApplicationWebhook(ctx, "github-token", newGitWebhookRequest(t, map[string]string{
+		"X-GitHub-Event":      "push",
+		"X-Hub-Signature-256": "sha256=" + signGitPayload("s3cret", githubBody),
+	}, githubBody))
+	assert.NoError(t, err)
+	assert.Len(t, appService.deployed, 1)
+	assert.Equal(t, "workflow-default", appService.deployed[0].WorkflowName)
+	assert.Equal(t, &model.CodeInfo{Commit: "4f2e1c0a9b8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f", Branch: "main", User: "jane"}, appService.deployed[0].CodeInfo)
+	component := &model.ApplicationComponent{AppPrimaryKey: "demo", Name: "web"}
+	assert.NoError(t, ds.Get(ctx, component))
+	assert.Equal(t, "demo:4f2e1c0", (*component.Properties)["image"])
+	assert.Equal(t, float64(80), (*component.Properties)["port"])
+
+	pingBody := []byte(`{"zen":"Keep it logically awesome."}`)
+	resp, err := webhookService.HandleApplicationWebhook(ctx, "github-token", newGitWebhookRequest(t, map[string]string{
+		"X-GitHub-Event":      "ping",
+		"X-Hub-Signature-256": "sha256=" + signGitPayload("s3cret", pingBody),
+	}, pingBody))
+	assert.NoError(t, err)
+	assert.Equal(t, "skipped", resp.(*apisv1.ApplicationGitWebhookResponse).State)
+
+	gitlabBody := []byte(`{"object_kind":"tag_push","ref":"refs/tags/v1.2.0","before":"0000000000000000000000000000000000000000",
+"after":"82b3d5ae55f7080f1e6022629cdb57bfae7cccc7","checkout_sha":"82b3d5ae55f7080f1e6022629cdb57bfae7cccc7",
+"user_name":"John","project":{"name":"demo","path_with_namespace":"group/demo"},"repository":{"name":"demo"},"commits":[]}`)
+	_, err = webhookService.HandleApplicationWebhook(ctx, "gitlab-token", newGitWebhookRequest(t, map[string]string{
+		"X-Gitlab-Event": "Tag Push Hook",
+		"X-Gitlab-Token": "s3cret",
+	}, gitlabBody))
+	assert.NoError(t, err)
+	assert.Len(t, appService.deployed, 2)
+	assert.Equal(t, "workflow-prod", appService.deployed[1].WorkflowName)
+	assert.Equal(t, &model.CodeInfo{Commit: "82b3d5ae55f7080f1e6022629cdb57bfae7cccc7", Branch: "v1.2.0", User: "John"}, appService.deployed[1].CodeInfo)
+	assert.NoError(t, ds.Get(ctx, component))
+	assert.Equal(t, "demo:v1.2.0", (*component.Properties)["image"])
+
+	branchBody := []byte(`{"ref":"refs/heads/main","after":"82b3d5ae55f7080f1e6022629cdb57bfae7cccc7"}`)
+	resp, err = webhookService.HandleApplicationWebhook(ctx, "gitlab-token", newGitWebhookRequest(t, map[string]string{
+		"X-Gitlab-Event": "Push Hook",
+		"X-Gitlab-Token": "s3cret",
+	}, branchBody))
+	assert.NoError(t, err)
+	assert.Equal(t, "skipped", resp.(*apisv1.ApplicationGitWebhookResponse).State)
+	assert.Len(t, appService.deployed, 2)
+
+	giteaBody := []byte(`{"ref":"refs/heads/dev","before":"82b3d5ae55f7080f1e6022629cdb57bfae7cccc7",
+"after":"0000000000000000000000000000000000000000","repository":{"full_name":"org/demo"}}`)
+	resp, err = webhookService.HandleApplicationWebhook(ctx, "gitea-token", newGitWebhookRequest(t, map[string]string{
+		"X-Gitea-Event": "push",
+	}, giteaBody))
+	assert.NoError(t, err)
+	assert.Equal(t, "skipped", resp.(*apisv1.ApplicationGitWebhookResponse).State)
+
+	giteaBody = []byte(`{"ref":"refs/heads/dev","after":"9c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d","pusher":{"login":"bob"}}`)
+	_, err = webhookService.HandleApplicationWebhook(ctx, "gitea-token", newGitWebhookRequest(t, map[string]string{
+		"X-Gitea-Event": "push",
+	}, giteaBody))
+	assert.NoError(t, err)
+	assert.Len(t, appService.deployed, 3)
+	assert.Equal(t, "workflow-default", appService.deployed[2].WorkflowName)
+	assert.Equal(t, "bob", appService.deployed[2].CodeInfo.User)
+}
@@ -77,6 +77,8 @@ type workflowServiceImpl struct {
 	Apply             apply.Applicator    `inject:"apply"`
 	EnvService        EnvService          `inject:""`
 	EnvBindingService EnvBindingService   `inject:""`
+	// OutboundWebhookService publishes the events of the workflow records
+	OutboundWebhookService OutboundWebhookService `inject:""`
 }
 
 // DeleteWorkflow delete application workflow
@@ -195,6 +197,9 @@ func (w *workflowServiceImpl) CreateOrUpdateWorkflow(ctx context.Context, app *m
 }
 
 func (w *workflowServiceImpl) UpdateWorkflow(ctx context.Context, workflow *model.Workflow, req apisv1.UpdateWorkflowRequest) (*apisv1.DetailWorkflowResponse, error) {
+	if err := checkResourceVersion(workflow, req.ResourceVersion); err != nil {
+		return nil, err
+	}
 	modeSteps, err := assembler.CreateWorkflowStepModel(req.Steps)
 	if err != nil {
 		return nil, err
@@ -481,6 +486,11 @@ func (w *workflowServiceImpl) syncWorkflowStatus(ctx context.Context, appPrimary
 	}
 
 	if app.Status.Workflow != nil {
+		oldStatus := record.Status
+		oldPhases := make(map[string]common.WorkflowStepPhase, len(record.Steps))
+		for _, step := range record.Steps {
+			oldPhases[step.Name] = step.Phase
+		}
 		status := app.Status.Workflow
 		summaryStatus := model.RevisionStatusRunning
 		switch {
@@ -514,6 +524,7 @@ func (w *workflowServiceImpl) syncWorkflowStatus(ctx context.Context, appPrimary
 		if err := w.Store.Put(ctx, revision); err != nil {
 			return err
 		}
+		w.publishWorkflowEvents(ctx, record, oldStatus, oldPhases)
 	}
 
 	if record.Finished == "true" {
@@ -523,6 +534,50 @@ func (w *workflowServiceImpl) syncWorkflowStatus(ctx context.Context, appPrimary
 	return nil
 }
 
+// publishWorkflowEvents publishes the events by comparing the synced record with the status before syncing
+func (w *workflowServiceImpl) publishWorkflowEvents(ctx context.Context, record *model.WorkflowRecord, oldStatus string, oldPhases map[string]common.WorkflowStepPhase) {
+	if w.OutboundWebhookService == nil {
+		return
+	}
+	app := &model.Application{Name: record.AppPrimaryKey}
+	if err := w.Store.Get(ctx, app); err != nil {
+		log.Logger.Errorf("failed to get the application of the workflow record %s: %s", record.Name, err.Error())
+		return
+	}
+	newEvent := func(eventType string, step *model.WorkflowStepStatus) apisv1.WebhookEvent {
+		event := apisv1.WebhookEvent{
+			Type:        eventType,
+			Project:     app.Project,
+			Application: app.Name,
+			Workflow:    record.WorkflowName,
+			Record:      record.Name,
+		}
+		if step != nil {
+			event.Step = step.Name
+			event.Message = step.Message
+		}
+		return event
+	}
+	failed := false
+	for i, step := range record.Steps {
+		if step.Phase == common.WorkflowStepPhaseFailed {
+			failed = true
+		}
+		if step.Phase == oldPhases[step.Name] {
+			continue
+		}
+		switch {
+		case step.Phase == common.WorkflowStepPhaseFailed:
+			publishWebhookEvent(ctx, w.OutboundWebhookService, newEvent(model.WebhookEventWorkflowFailed, &record.Steps[i]))
+		case step.Phase == common.WorkflowStepPhaseRunning && step.Type == wfTypes.WorkflowStepTypeSuspend:
+			publishWebhookEvent(ctx, w.OutboundWebhookService, newEvent(model.WebhookEventWorkflowSuspended, &record.Steps[i]))
+		}
+	}
+	if record.Status == model.RevisionStatusComplete && oldStatus != model.RevisionStatusComplete && !failed {
+		publishWebhookEvent(ctx, w.OutboundWebhookService, newEvent(model.WebhookEventApplicationDeployed, nil))
+	}
+}
+
 func (w *workflowServiceImpl) CreateWorkflowRecord(ctx context.Context, appModel *model.Application, app *v1beta1.Application, workflow *model.Workflow) error {
 	if app.Annotations == nil {
 		return fmt.Errorf("empty annotations in application")
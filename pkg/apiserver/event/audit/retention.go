@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+// RetentionCleaner deletes the audit logs that are older than the retention
+type RetentionCleaner struct {
+	Retention    time.Duration
+	Interval     time.Duration
+	AuditService service.AuditService `inject:""`
+}
+
+// Start cleaning the expired audit logs
+func (r *RetentionCleaner) Start(ctx context.Context, errorChan chan error) {
+	if r.Retention <= 0 {
+		log.Logger.Infof("the audit log retention is not set, the audit logs are kept forever")
+		return
+	}
+	log.Logger.Infof("audit log cleaning worker started")
+	defer log.Logger.Infof("audit log cleaning worker closed")
+	t := time.NewTicker(r.Interval)
+	defer t.Stop()
+	for {
+		r.clean(ctx)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *RetentionCleaner) clean(ctx context.Context) {
+	deleted, err := r.AuditService.CleanAuditLogs(ctx, time.Now().Add(-r.Retention))
+	if err != nil {
+		log.Logger.Errorf("failed to clean the audit logs: %s", err.Error())
+	}
+	if deleted > 0 {
+		log.Logger.Infof("%d expired audit logs are deleted", deleted)
+	}
+}
@@ -18,16 +18,22 @@ package event
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/config"
+	"github.com/oam-dev/kubevela/pkg/apiserver/event/audit"
 	"github.com/oam-dev/kubevela/pkg/apiserver/event/collect"
 	"github.com/oam-dev/kubevela/pkg/apiserver/event/sync"
+	"github.com/oam-dev/kubevela/pkg/apiserver/event/webhook"
 )
 
 var workers []Worker
 
+// replicaWorkers run on every replica instead of only the leader
+var replicaWorkers []Worker
+
 // Worker handle events through rotation training, listener and crontab.
 type Worker interface {
 	Start(ctx context.Context, errChan chan error)
@@ -42,8 +48,18 @@ func InitEvent(cfg config.Config) []interface{} {
 		Queue: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
 	}
 	collect := &collect.InfoCalculateCronJob{}
-	workers = append(workers, workflow, application, collect)
-	return []interface{}{workflow, application, collect}
+	auditLog := &audit.RetentionCleaner{
+		Retention: cfg.AuditLog.Retention,
+		Interval:  time.Hour,
+	}
+	webhookDelivery := &webhook.DeliveryWorker{
+		Interval:  10 * time.Second,
+		Retention: 30 * 24 * time.Hour,
+	}
+	applicationEvent := &sync.ApplicationEventWatcher{}
+	workers = append(workers, workflow, application, collect, auditLog, webhookDelivery)
+	replicaWorkers = append(replicaWorkers, applicationEvent)
+	return []interface{}{workflow, application, collect, auditLog, webhookDelivery, applicationEvent}
 }
 
 // StartEventWorker start all event worker
@@ -52,3 +68,10 @@ func StartEventWorker(ctx context.Context, errChan chan error) {
 		go workers[i].Start(ctx, errChan)
 	}
 }
+
+// StartReplicaWorker start the event workers that run on every replica
+func StartReplicaWorker(ctx context.Context, errChan chan error) {
+	for i := range replicaWorkers {
+		go replicaWorkers[i].Start(ctx, errChan)
+	}
+}
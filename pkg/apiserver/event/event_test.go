@@ -26,5 +26,6 @@ import (
 
 func TestInitEvent(t *testing.T) {
 	InitEvent(config.Config{})
-	assert.Equal(t, len(workers), 3)
+	assert.Equal(t, len(workers), 5)
+	assert.Equal(t, len(replicaWorkers), 1)
 }
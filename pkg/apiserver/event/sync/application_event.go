@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicInformer "k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+// ApplicationEventWatcher watches the applications and feeds the status changes to the subscribers of the application
+// events. Unlike the sync workers, it runs on every replica, because the subscribers connect to any of them.
+type ApplicationEventWatcher struct {
+	KubeConfig              *rest.Config                    `inject:"kubeConfig"`
+	ApplicationEventService service.ApplicationEventService `inject:""`
+}
+
+// Start runs the informer of the applications until the context is done
+func (a *ApplicationEventWatcher) Start(ctx context.Context, errorChan chan error) {
+	dynamicClient, err := dynamic.NewForConfig(a.KubeConfig)
+	if err != nil {
+		errorChan <- err
+		return
+	}
+	factory := dynamicInformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 0, v1.NamespaceAll, nil)
+	informer := factory.ForResource(v1beta1.SchemeGroupVersion.WithResource("applications")).Informer()
+	informer.AddEventHandler(a.handlers())
+	log.Logger.Info("app event watching started")
+	informer.Run(ctx.Done())
+}
+
+func (a *ApplicationEventWatcher) handlers() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if app := convertToApplication(obj); app != nil {
+				a.ApplicationEventService.Notify(nil, app)
+			}
+		},
+		UpdateFunc: func(oldObj, obj interface{}) {
+			old, app := convertToApplication(oldObj), convertToApplication(obj)
+			if old != nil && app != nil {
+				a.ApplicationEventService.Notify(old, app)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if app := convertToApplication(obj); app != nil {
+				a.ApplicationEventService.Notify(app, nil)
+			}
+		},
+	}
+}
+
+// convertToApplication converts the object watched by the dynamic informer to the application
+func convertToApplication(obj interface{}) *v1beta1.Application {
+	switch o := obj.(type) {
+	case *v1beta1.Application:
+		return o
+	case *unstructured.Unstructured:
+		app := &v1beta1.Application{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(o.UnstructuredContent(), app); err != nil {
+			log.Logger.Errorf("decode the application failure %s", err.Error())
+			return nil
+		}
+		return app
+	default:
+		return nil
+	}
+}
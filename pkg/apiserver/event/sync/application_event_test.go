@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+)
+
+type fakeApplicationEventService struct {
+	service.ApplicationEventService
+	notified [][2]*v1beta1.Application
+}
+
+func (f *fakeApplicationEventService) Notify(old, new *v1beta1.Application) {
+	f.notified = append(f.notified, [2]*v1beta1.Application{old, new})
+}
+
+func TestApplicationEventWatcherHandlers(t *testing.T) {
+	events := &fakeApplicationEventService{}
+	handlers := (&ApplicationEventWatcher{ApplicationEventService: events}).handlers()
+	newApp := func(phase common.ApplicationPhase) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": v1beta1.SchemeGroupVersion.String(),
+			"kind":       v1beta1.ApplicationKind,
+			"metadata":   map[string]interface{}{"name": "demo", "namespace": "default"},
+			"status":     map[string]interface{}{"status": string(phase)},
+		}}
+	}
+
+	handlers.OnAdd(newApp(common.ApplicationRendering))
+	handlers.OnUpdate(newApp(common.ApplicationRendering), newApp(common.ApplicationRunning))
+	handlers.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/demo", Obj: newApp(common.ApplicationRunning)})
+	handlers.OnAdd("not an application")
+
+	require.Len(t, events.notified, 3)
+	require.Nil(t, events.notified[0][0])
+	require.Equal(t, "demo", events.notified[0][1].Name)
+	require.Equal(t, common.ApplicationRendering, events.notified[1][0].Status.Phase)
+	require.Equal(t, common.ApplicationRunning, events.notified[1][1].Status.Phase)
+	require.Equal(t, "default", events.notified[2][0].Namespace)
+	require.Nil(t, events.notified[2][1])
+}
@@ -137,13 +137,16 @@ func FromCRTargets(ctx context.Context, cli client.Client, targetApp *v1beta1.Ap
 	existTarget := make(map[string]*model.Target)
 	for i := range existTargets {
 		t := existTargets[i].(*model.Target)
+		if t.Cluster == nil {
+			continue
+		}
 		existTarget[fmt.Sprintf("%s-%s", t.Cluster.ClusterName, t.Cluster.Namespace)] = t
 	}
 	var targets []*model.Target
 	var targetNames = map[string]string{}
 	nc := make(map[string]struct{})
 	// read the target from the topology policies
-	placements, err := policy.GetPlacementsFromTopologyPolicies(ctx, cli, targetApp.Namespace, targetApp.Spec.Policies, true)
+	placements, err := policy.GetPlacementsFromTopologyPolicies(policy.ContextWithScheduledClusters(ctx, targetApp), cli, targetApp.Namespace, targetApp.Spec.Policies, true)
 	if err != nil {
 		log.Logger.Errorf("fail to get placements from topology policies %s", err.Error())
 		return targets, targetNames
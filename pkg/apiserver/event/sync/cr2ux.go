@@ -98,40 +98,48 @@ func (c *CR2UX) AddOrUpdate(ctx context.Context, targetApp *v1beta1.Application)
 		log.Logger.Errorf("Store Env Metadata to data store err %v", err)
 		return err
 	}
-	if err = StoreEnvBinding(ctx, dsApp.Eb, ds); err != nil {
+	// the metadata of the application is stored in one transaction, the services above can't be called in it as they
+	// don't join the transaction
+	if err = ds.Transaction(ctx, func(tx datastore.DataStore) error {
+		return storeAppMetadata(ctx, dsApp, tx)
+	}); err != nil {
+		return err
+	}
+
+	// update cache
+	c.syncCache(dsApp.AppMeta.PrimaryKey(), targetApp.Generation, int64(len(dsApp.Targets)))
+	return nil
+}
+
+func storeAppMetadata(ctx context.Context, dsApp *model.DataStoreApp, ds datastore.DataStore) error {
+	if err := StoreEnvBinding(ctx, dsApp.Eb, ds); err != nil {
 		log.Logger.Errorf("Store EnvBinding Metadata to data store err %v", err)
 		return err
 	}
-	if err = StoreComponents(ctx, dsApp.AppMeta.Name, dsApp.Comps, ds); err != nil {
+	if err := StoreComponents(ctx, dsApp.AppMeta.Name, dsApp.Comps, ds); err != nil {
 		log.Logger.Errorf("Store Components Metadata to data store err %v", err)
 		return err
 	}
-	if err = StorePolicy(ctx, dsApp.AppMeta.Name, dsApp.Policies, ds); err != nil {
+	if err := StorePolicy(ctx, dsApp.AppMeta.Name, dsApp.Policies, ds); err != nil {
 		log.Logger.Errorf("Store Policy Metadata to data store err %v", err)
 		return err
 	}
-	if err = StoreWorkflow(ctx, dsApp, ds); err != nil {
+	if err := StoreWorkflow(ctx, dsApp, ds); err != nil {
 		log.Logger.Errorf("Store Workflow Metadata to data store err %v", err)
 		return err
 	}
-
-	if err = StoreApplicationRevision(ctx, dsApp, ds); err != nil {
+	if err := StoreApplicationRevision(ctx, dsApp, ds); err != nil {
 		log.Logger.Errorf("Store application revision to data store err %v", err)
 		return err
 	}
-
-	if err = StoreWorkflowRecord(ctx, dsApp, ds); err != nil {
+	if err := StoreWorkflowRecord(ctx, dsApp, ds); err != nil {
 		log.Logger.Errorf("Store Workflow Record to data store err %v", err)
 		return err
 	}
-
-	if err = StoreAppMeta(ctx, dsApp, ds); err != nil {
+	if err := StoreAppMeta(ctx, dsApp, ds); err != nil {
 		log.Logger.Errorf("Store App Metadata to data store err %v", err)
 		return err
 	}
-
-	// update cache
-	c.syncCache(dsApp.AppMeta.PrimaryKey(), targetApp.Generation, int64(len(dsApp.Targets)))
 	return nil
 }
 
@@ -113,9 +113,14 @@ func StoreEnvBinding(ctx context.Context, eb *model.EnvBinding, ds datastore.Dat
 	return ds.Add(ctx, eb)
 }
 
-// StoreComponents will sync application components from CR to datastore
+// StoreComponents will sync application components from CR to datastore, the components are changed in one transaction
 func StoreComponents(ctx context.Context, appPrimaryKey string, expComps []*model.ApplicationComponent, ds datastore.DataStore) error {
+	return ds.Transaction(ctx, func(tx datastore.DataStore) error {
+		return storeComponents(ctx, appPrimaryKey, expComps, tx)
+	})
+}
 
+func storeComponents(ctx context.Context, appPrimaryKey string, expComps []*model.ApplicationComponent, ds datastore.DataStore) error {
 	// list the existing components in datastore
 	originComps, err := ds.List(ctx, &model.ApplicationComponent{AppPrimaryKey: appPrimaryKey}, &datastore.ListOptions{})
 	if err != nil {
@@ -172,8 +177,14 @@ func StoreComponents(ctx context.Context, appPrimaryKey string, expComps []*mode
 	return nil
 }
 
-// StorePolicy will add/update/delete policies, we don't delete ref policy
+// StorePolicy will add/update/delete policies in one transaction, we don't delete ref policy
 func StorePolicy(ctx context.Context, appPrimaryKey string, expPolicies []*model.ApplicationPolicy, ds datastore.DataStore) error {
+	return ds.Transaction(ctx, func(tx datastore.DataStore) error {
+		return storePolicy(ctx, appPrimaryKey, expPolicies, tx)
+	})
+}
+
+func storePolicy(ctx context.Context, appPrimaryKey string, expPolicies []*model.ApplicationPolicy, ds datastore.DataStore) error {
 	// list the existing policies for this app in datastore
 	originPolicies, err := ds.List(ctx, &model.ApplicationPolicy{AppPrimaryKey: appPrimaryKey}, &datastore.ListOptions{})
 	if err != nil {
@@ -294,12 +305,13 @@ func StoreTargets(ctx context.Context, dsApp *model.DataStoreApp, ds datastore.D
 			return err
 		}
 		_, err = targetService.CreateTarget(ctx, v1.CreateTargetRequest{
-			Name:        t.Name,
-			Alias:       t.Alias,
-			Project:     t.Project,
-			Description: t.Description,
-			Cluster:     (*v1.ClusterTarget)(t.Cluster),
-			Variable:    t.Variable,
+			Name:         t.Name,
+			Alias:        t.Alias,
+			Project:      t.Project,
+			Description:  t.Description,
+			Cluster:      (*v1.ClusterTarget)(t.Cluster),
+			ClusterGroup: (*v1.ClusterGroupTarget)(t.ClusterGroup),
+			Variable:     t.Variable,
 		})
 		if err != nil && !errors.Is(err, bcode.ErrTargetExist) {
 			return err
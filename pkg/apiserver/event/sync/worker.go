@@ -92,8 +92,7 @@ func (a *ApplicationSync) Start(ctx context.Context, errorChan chan error) {
 		}
 	}()
 
-	addOrUpdateHandler := func(obj interface{}) {
-		app := getApp(obj)
+	addOrUpdateHandler := func(app *v1beta1.Application) {
 		if app.DeletionTimestamp == nil {
 			a.Queue.Add(app)
 			log.Logger.Infof("watched update/add app event, namespace: %s, name: %s", app.Namespace, app.Name)
@@ -102,10 +101,10 @@ func (a *ApplicationSync) Start(ctx context.Context, errorChan chan error) {
 
 	handlers := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			addOrUpdateHandler(obj)
+			addOrUpdateHandler(getApp(obj))
 		},
 		UpdateFunc: func(oldObj, obj interface{}) {
-			addOrUpdateHandler(obj)
+			addOrUpdateHandler(getApp(obj))
 		},
 		DeleteFunc: func(obj interface{}) {
 			app := getApp(obj)
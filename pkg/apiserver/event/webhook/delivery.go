@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+// DeliveryWorker sends the pending deliveries of the outbound webhooks and deletes the expired ones
+type DeliveryWorker struct {
+	Interval time.Duration
+	// Retention the finished deliveries older than it are deleted
+	Retention              time.Duration
+	OutboundWebhookService service.OutboundWebhookService `inject:""`
+}
+
+// Start delivering the outbound webhooks
+func (d *DeliveryWorker) Start(ctx context.Context, errorChan chan error) {
+	log.Logger.Infof("webhook delivery worker started")
+	defer log.Logger.Infof("webhook delivery worker closed")
+	t := time.NewTicker(d.Interval)
+	defer t.Stop()
+	lastClean := time.Time{}
+	for {
+		select {
+		case <-t.C:
+			d.deliver(ctx)
+			if time.Since(lastClean) > time.Hour {
+				d.clean(ctx)
+				lastClean = time.Now()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *DeliveryWorker) deliver(ctx context.Context) {
+	if _, err := d.OutboundWebhookService.DeliverWebhooks(ctx); err != nil {
+		log.Logger.Errorf("failed to deliver the outbound webhooks: %s", err.Error())
+	}
+}
+
+func (d *DeliveryWorker) clean(ctx context.Context) {
+	if d.Retention <= 0 {
+		return
+	}
+	deleted, err := d.OutboundWebhookService.CleanWebhookDeliveries(ctx, time.Now().Add(-d.Retention))
+	if err != nil {
+		log.Logger.Errorf("failed to clean the webhook deliveries: %s", err.Error())
+	}
+	if deleted > 0 {
+		log.Logger.Infof("%d expired webhook deliveries are deleted", deleted)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	"github.com/oam-dev/kubevela/version"
+)
+
+// SchemaVersion is the version of the entity models in the archive, it should be increased when the models are changed
+// incompatibly or new tables are added.
+const SchemaVersion = 6
+
+const (
+	// metadataFile is the first file in the archive, which is checked before importing any table
+	metadataFile = "metadata.json"
+	// tablesDir contains one file for each table, every line of the file is an entity in JSON
+	tablesDir = "tables"
+)
+
+// Metadata describes the archive
+type Metadata struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	VelaVersion   string    `json:"velaVersion"`
+	DatastoreType string    `json:"datastoreType,omitempty"`
+	CreateTime    time.Time `json:"createTime"`
+	// Tables records the number of the entities in each table
+	Tables map[string]int `json:"tables"`
+}
+
+// ImportOptions the options of importing the archive
+type ImportOptions struct {
+	// Idempotent overwrites the existing entities instead of failing, so that the import can be retried
+	Idempotent bool
+}
+
+// ImportResult the result of importing the archive
+type ImportResult struct {
+	Metadata *Metadata
+	Created  int
+	Updated  int
+}
+
+// registeredEntities returns the entities of all the registered tables, sorted by the table name
+func registeredEntities() (map[string]datastore.Entity, []string, error) {
+	entities := map[string]datastore.Entity{}
+	var tables []string
+	for table, m := range model.GetRegisterModels() {
+		entity, ok := m.(datastore.Entity)
+		if !ok {
+			return nil, nil, fmt.Errorf("the model of table %s is not a datastore entity", table)
+		}
+		entities[table] = entity
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return entities, tables, nil
+}
+
+func tableFile(table string) string {
+	return path.Join(tablesDir, table+".jsonl")
+}
+
+// Export writes all the entities of the registered tables in the datastore to a gzipped tar archive. The datastore is
+// not locked while exporting, so stop the apiserver for a consistent backup.
+func Export(ctx context.Context, ds datastore.DataStore, datastoreType string, w io.Writer) (*Metadata, error) {
+	entities, tables, err := registeredEntities()
+	if err != nil {
+		return nil, err
+	}
+	metadata := &Metadata{
+		SchemaVersion: SchemaVersion,
+		VelaVersion:   version.VelaVersion,
+		DatastoreType: datastoreType,
+		CreateTime:    time.Now(),
+		Tables:        map[string]int{},
+	}
+	// the entities are read before writing, as the metadata with the counts is the first file of the archive
+	contents := map[string][]byte{}
+	for _, table := range tables {
+		list, err := ds.List(ctx, entities[table], nil)
+		if err != nil {
+			return nil, fmt.Errorf("list the entities of table %s failure %w", table, err)
+		}
+		var buf bytes.Buffer
+		for _, entity := range list {
+			data, err := json.Marshal(entity)
+			if err != nil {
+				return nil, fmt.Errorf("encode the entity %s of table %s failure %w", entity.PrimaryKey(), table, err)
+			}
+			buf.Write(data)
+			buf.WriteByte('\n')
+		}
+		contents[table] = buf.Bytes()
+		metadata.Tables[table] = len(list)
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	metadataContent, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFile(tw, metadataFile, metadataContent, metadata.CreateTime); err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if err := writeFile(tw, tableFile(table), contents[table], metadata.CreateTime); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+	}); err != nil {
+		return fmt.Errorf("write the header of %s failure %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("write %s failure %w", name, err)
+	}
+	return nil
+}
+
+// Import reads the archive and adds the entities to the datastore. The schema version of the archive is checked before
+// importing, the archives created by the newer apiserver are rejected. The create time and update time of the entities
+// are kept, while the resource versions are restarted.
+func Import(ctx context.Context, ds datastore.DataStore, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	entities, _, err := registeredEntities()
+	if err != nil {
+		return nil, err
+	}
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("the archive is not gzipped %w", err)
+	}
+	defer func() {
+		if err := gr.Close(); err != nil {
+			log.Logger.Warnf("close the archive failure %s", err.Error())
+		}
+	}()
+	tr := tar.NewReader(gr)
+
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("read the archive failure %w", err)
+	}
+	if header.Name != metadataFile {
+		return nil, fmt.Errorf("the first file of the archive should be %s, got %s", metadataFile, header.Name)
+	}
+	metadata := &Metadata{}
+	if err := json.NewDecoder(tr).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("decode the metadata of the archive failure %w", err)
+	}
+	if err := checkMetadata(metadata, entities); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Metadata: metadata}
+	imported := map[string]int{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("read the archive failure %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || path.Dir(header.Name) != tablesDir {
+			continue
+		}
+		table := strings.TrimSuffix(path.Base(header.Name), ".jsonl")
+		entity, ok := entities[table]
+		if !ok {
+			return result, fmt.Errorf("the table %s in the archive is not supported", table)
+		}
+		count, err := importTable(ctx, ds, entity, tr, opts, result)
+		imported[table] = count
+		if err != nil {
+			return result, fmt.Errorf("import table %s failure %w", table, err)
+		}
+	}
+	for table, count := range metadata.Tables {
+		if imported[table] != count {
+			return result, fmt.Errorf("the archive is incomplete, table %s should have %d entities but got %d", table, count, imported[table])
+		}
+	}
+	return result, nil
+}
+
+func checkMetadata(metadata *Metadata, entities map[string]datastore.Entity) error {
+	if metadata.SchemaVersion <= 0 {
+		return fmt.Errorf("the schema version of the archive is missing")
+	}
+	if metadata.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("the schema version %d of the archive created by KubeVela %s is newer than the supported version %d, please upgrade the apiserver",
+			metadata.SchemaVersion, metadata.VelaVersion, SchemaVersion)
+	}
+	for table := range metadata.Tables {
+		if _, ok := entities[table]; !ok {
+			return fmt.Errorf("the table %s in the archive is not supported", table)
+		}
+	}
+	return nil
+}
+
+// importTable adds the entities in the JSON lines to the datastore, it returns the number of the imported entities
+func importTable(ctx context.Context, ds datastore.DataStore, entity datastore.Entity, r io.Reader, opts ImportOptions, result *ImportResult) (int, error) {
+	reader := bufio.NewReader(r)
+	var count int
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			item, newErr := datastore.NewEntity(entity)
+			if newErr != nil {
+				return count, newErr
+			}
+			if err := json.Unmarshal(line, item); err != nil {
+				return count, fmt.Errorf("decode the entity failure %w", err)
+			}
+			if err := importEntity(ctx, ds, &restoredEntity{Entity: item}, opts, result); err != nil {
+				return count, fmt.Errorf("import the entity %s failure %w", item.PrimaryKey(), err)
+			}
+			count++
+		}
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
+
+func importEntity(ctx context.Context, ds datastore.DataStore, entity *restoredEntity, opts ImportOptions, result *ImportResult) error {
+	err := ds.Add(ctx, entity)
+	if err == nil {
+		result.Created++
+		return nil
+	}
+	if !errors.Is(err, datastore.ErrRecordExist) || !opts.Idempotent {
+		return err
+	}
+	// the resource version is reset, to overwrite the existing one unconditionally
+	entity.SetResourceVersion(0)
+	if err := ds.Put(ctx, entity); err != nil {
+		return err
+	}
+	result.Updated++
+	return nil
+}
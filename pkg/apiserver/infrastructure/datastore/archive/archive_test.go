@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
+)
+
+func newSQLiteDataStore(t *testing.T) datastore.DataStore {
+	ds, err := sqldb.New(context.Background(), datastore.Config{Type: sqldb.TypeSQLite, URL: filepath.Join(t.TempDir(), "kubevela.db")})
+	require.NoError(t, err)
+	return ds
+}
+
+func TestExportAndImport(t *testing.T) {
+	ctx := context.Background()
+	source := newSQLiteDataStore(t)
+	require.NoError(t, source.BatchAdd(ctx, []datastore.Entity{
+		&model.Project{Name: "default", Owner: "admin"},
+		&model.Application{Name: "app-1", Project: "default", Description: "first"},
+		&model.Application{Name: "app-2", Project: "default", Description: "second"},
+		&model.ApplicationComponent{AppPrimaryKey: "app-1", Name: "comp", Type: "webservice"},
+	}))
+	origin := &model.Application{Name: "app-1"}
+	require.NoError(t, source.Get(ctx, origin))
+
+	var buf bytes.Buffer
+	metadata, err := Export(ctx, source, sqldb.TypeSQLite, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, metadata.SchemaVersion)
+	assert.Equal(t, 2, metadata.Tables[(&model.Application{}).TableName()])
+	assert.Equal(t, 1, metadata.Tables[(&model.Project{}).TableName()])
+	assert.Equal(t, 0, metadata.Tables[(&model.Cluster{}).TableName()])
+	archiveData := buf.Bytes()
+
+	target := newSQLiteDataStore(t)
+	result, err := Import(ctx, target, bytes.NewReader(archiveData), ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Created)
+	assert.Equal(t, 0, result.Updated)
+	assert.Equal(t, sqldb.TypeSQLite, result.Metadata.DatastoreType)
+
+	app := &model.Application{Name: "app-1"}
+	require.NoError(t, target.Get(ctx, app))
+	assert.Equal(t, "first", app.Description)
+	assert.Equal(t, "default", app.Project)
+	// the time of the entity is kept
+	assert.True(t, origin.CreateTime.Equal(app.CreateTime))
+	assert.True(t, origin.UpdateTime.Equal(app.UpdateTime))
+	count, err := target.Count(ctx, &model.Application{Project: "default"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	comp := &model.ApplicationComponent{AppPrimaryKey: "app-1", Name: "comp"}
+	require.NoError(t, target.Get(ctx, comp))
+	assert.Equal(t, "webservice", comp.Type)
+
+	// the existing entities fail the import without the idempotent mode
+	_, err = Import(ctx, target, bytes.NewReader(archiveData), ImportOptions{})
+	assert.True(t, errors.Is(err, datastore.ErrRecordExist))
+
+	app.Description = "changed"
+	require.NoError(t, target.Put(ctx, app))
+	result, err = Import(ctx, target, bytes.NewReader(archiveData), ImportOptions{Idempotent: true})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 4, result.Updated)
+	app = &model.Application{Name: "app-1"}
+	require.NoError(t, target.Get(ctx, app))
+	assert.Equal(t, "first", app.Description)
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	ctx := context.Background()
+	build := func(metadata *Metadata, files map[string]string) []byte {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+		content, err := json.Marshal(metadata)
+		require.NoError(t, err)
+		require.NoError(t, writeFile(tw, metadataFile, content, time.Now()))
+		for name, content := range files {
+			require.NoError(t, writeFile(tw, name, []byte(content), time.Now()))
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gw.Close())
+		return buf.Bytes()
+	}
+	table := (&model.Project{}).TableName()
+
+	testCases := map[string]struct {
+		archive []byte
+		err     string
+	}{
+		"not gzipped": {
+			archive: []byte("not an archive"),
+			err:     "the archive is not gzipped",
+		},
+		"newer schema": {
+			archive: build(&Metadata{SchemaVersion: SchemaVersion + 1, VelaVersion: "v9.9.9"}, nil),
+			err:     "is newer than the supported version",
+		},
+		"missing schema": {
+			archive: build(&Metadata{}, nil),
+			err:     "the schema version of the archive is missing",
+		},
+		"unknown table": {
+			archive: build(&Metadata{SchemaVersion: SchemaVersion, Tables: map[string]int{"vela_unknown": 1}}, nil),
+			err:     "the table vela_unknown in the archive is not supported",
+		},
+		"incomplete": {
+			archive: build(&Metadata{SchemaVersion: SchemaVersion, Tables: map[string]int{table: 2}},
+				map[string]string{tableFile(table): `{"name":"default"}` + "\n"}),
+			err: "the archive is incomplete",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Import(ctx, newSQLiteDataStore(t), bytes.NewReader(tc.archive), ImportOptions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+)
+
+// restoredEntity keeps the create time and update time of the entity read from the archive, which are set to the
+// current time by the datastore otherwise. The entity is encoded as the wrapped one.
+type restoredEntity struct {
+	datastore.Entity
+}
+
+// SetCreateTime keeps the create time in the archive
+func (r *restoredEntity) SetCreateTime(time.Time) {}
+
+// SetUpdateTime keeps the update time in the archive
+func (r *restoredEntity) SetUpdateTime(time.Time) {}
+
+// MarshalJSON encodes the wrapped entity
+func (r *restoredEntity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Entity)
+}
+
+// UnmarshalJSON decodes to the wrapped entity
+func (r *restoredEntity) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, r.Entity)
+}
+
+// MarshalBSON encodes the wrapped entity
+func (r *restoredEntity) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(r.Entity)
+}
+
+// UnmarshalBSON decodes to the wrapped entity
+func (r *restoredEntity) UnmarshalBSON(data []byte) error {
+	return bson.Unmarshal(data, r.Entity)
+}
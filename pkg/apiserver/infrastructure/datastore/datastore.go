@@ -44,6 +44,9 @@ var (
 
 	// ErrEntityInvalid Error that entity is invalid
 	ErrEntityInvalid = NewDBError(fmt.Errorf("entity is invalid"))
+
+	// ErrRecordConflict Error that entity has been modified since it was read
+	ErrRecordConflict = NewDBError(fmt.Errorf("data record has been modified"))
 )
 
 // DBError datastore error
@@ -71,6 +74,8 @@ type Config struct {
 type Entity interface {
 	SetCreateTime(time time.Time)
 	SetUpdateTime(time time.Time)
+	GetResourceVersion() int64
+	SetResourceVersion(version int64)
 	PrimaryKey() string
 	TableName() string
 	ShortTableName() string
@@ -147,6 +152,8 @@ type DataStore interface {
 	BatchAdd(ctx context.Context, entities []Entity) error
 
 	// Put will update entity to database, Name() and TableName() can't return zero value.
+	// If the resource version of the entity is not zero, the entity is updated only when the stored one has the same
+	// version, otherwise ErrRecordConflict is returned. The resource version is increased after updating.
 	Put(ctx context.Context, entity Entity) error
 
 	// Delete entity from database, Name() and TableName() can't return zero value.
@@ -163,4 +170,8 @@ type DataStore interface {
 
 	// IsExist Name() and TableName() can't return zero value.
 	IsExist(ctx context.Context, entity Entity) (bool, error)
+
+	// Transaction runs the function with a datastore in which the changes are committed together, all the changes are
+	// rolled back if the function returns error.
+	Transaction(ctx context.Context, fn func(tx DataStore) error) error
 }
@@ -105,22 +105,31 @@ func (m *kubeapi) generateConfigMap(entity datastore.Entity) *corev1.ConfigMap {
 
 // Add add data model
 func (m *kubeapi) Add(ctx context.Context, entity datastore.Entity) error {
+	_, err := m.add(ctx, entity)
+	return err
+}
+
+// add creates the ConfigMap of the entity and returns the created one
+func (m *kubeapi) add(ctx context.Context, entity datastore.Entity) (*corev1.ConfigMap, error) {
 	if entity.PrimaryKey() == "" {
-		return datastore.ErrPrimaryEmpty
+		return nil, datastore.ErrPrimaryEmpty
 	}
 	if entity.TableName() == "" {
-		return datastore.ErrTableNameEmpty
+		return nil, datastore.ErrTableNameEmpty
 	}
 	entity.SetCreateTime(time.Now())
 	entity.SetUpdateTime(time.Now())
+	version := entity.GetResourceVersion()
+	entity.SetResourceVersion(1)
 	configMap := m.generateConfigMap(entity)
 	if err := m.kubeClient.Create(ctx, configMap); err != nil {
+		entity.SetResourceVersion(version)
 		if apierrors.IsAlreadyExists(err) {
-			return datastore.ErrRecordExist
+			return nil, datastore.ErrRecordExist
 		}
-		return datastore.NewDBError(err)
+		return nil, datastore.NewDBError(err)
 	}
-	return nil
+	return configMap, nil
 }
 
 // BatchAdd batch add entity, this operation has some atomicity.
@@ -169,11 +178,17 @@ func (m *kubeapi) Get(ctx context.Context, entity datastore.Entity) error {
 
 // Put update data model
 func (m *kubeapi) Put(ctx context.Context, entity datastore.Entity) error {
+	_, _, err := m.put(ctx, entity)
+	return err
+}
+
+// put updates the data model and returns the ConfigMaps before and after updating
+func (m *kubeapi) put(ctx context.Context, entity datastore.Entity) (*corev1.ConfigMap, *corev1.ConfigMap, error) {
 	if entity.PrimaryKey() == "" {
-		return datastore.ErrPrimaryEmpty
+		return nil, nil, datastore.ErrPrimaryEmpty
 	}
 	if entity.TableName() == "" {
-		return datastore.ErrTableNameEmpty
+		return nil, nil, datastore.ErrTableNameEmpty
 	}
 	// update labels
 	labels := entity.Index()
@@ -189,20 +204,36 @@ func (m *kubeapi) Put(ctx context.Context, entity datastore.Entity) error {
 	var configMap corev1.ConfigMap
 	if err := m.kubeClient.Get(ctx, types.NamespacedName{Namespace: m.namespace, Name: generateName(entity)}, &configMap); err != nil {
 		if apierrors.IsNotFound(err) {
-			return datastore.ErrRecordNotExist
+			return nil, nil, datastore.ErrRecordNotExist
 		}
-		return datastore.NewDBError(err)
+		return nil, nil, datastore.NewDBError(err)
 	}
+	origin := configMap.DeepCopy()
+	version := entity.GetResourceVersion()
+	storedVersion := gjson.GetBytes(configMap.BinaryData["data"], "resourceVersion").Int()
+	if version != 0 && version != storedVersion {
+		return nil, nil, datastore.ErrRecordConflict
+	}
+	entity.SetResourceVersion(storedVersion + 1)
 	data, err := json.Marshal(entity)
 	if err != nil {
-		return datastore.NewDBError(err)
+		entity.SetResourceVersion(version)
+		return nil, nil, datastore.NewDBError(err)
 	}
 	configMap.BinaryData["data"] = data
 	configMap.Labels = labels
+	// the ConfigMap is updated with the resource version read before, so the concurrent updates are conflicted
 	if err := m.kubeClient.Update(ctx, &configMap); err != nil {
-		return datastore.NewDBError(err)
+		entity.SetResourceVersion(version)
+		if apierrors.IsConflict(err) {
+			return nil, nil, datastore.ErrRecordConflict
+		}
+		if apierrors.IsNotFound(err) {
+			return nil, nil, datastore.ErrRecordNotExist
+		}
+		return nil, nil, datastore.NewDBError(err)
 	}
-	return nil
+	return origin, &configMap, nil
 }
 
 // IsExist determine whether data exists.
@@ -72,6 +72,110 @@ var _ = Describe("Test kubeapi datastore driver", func() {
 		err := kubeStore.Put(context.TODO(), &model.Application{Name: "kubevela-app", Description: "this is demo"})
 		Expect(err).ToNot(HaveOccurred())
 	})
+	It("Test put function with resource version", func() {
+		project := &model.Project{Name: "version-project", Description: "default"}
+		err := kubeStore.Add(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ResourceVersion).Should(Equal(int64(1)))
+
+		stale := &model.Project{Name: "version-project"}
+		err = kubeStore.Get(context.TODO(), stale)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stale.ResourceVersion).Should(Equal(int64(1)))
+
+		project.Description = "updated"
+		err = kubeStore.Put(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ResourceVersion).Should(Equal(int64(2)))
+
+		// the entity read before the updating is conflicted
+		stale.Description = "stale"
+		err = kubeStore.Put(context.TODO(), stale)
+		equal := cmp.Equal(err, datastore.ErrRecordConflict, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+		Expect(stale.ResourceVersion).Should(Equal(int64(1)))
+
+		// the entity without version is updated unconditionally
+		err = kubeStore.Put(context.TODO(), &model.Project{Name: "version-project", Description: "overwrite"})
+		Expect(err).ToNot(HaveOccurred())
+		latest := &model.Project{Name: "version-project"}
+		err = kubeStore.Get(context.TODO(), latest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(latest.Description).Should(Equal("overwrite"))
+		Expect(latest.ResourceVersion).Should(Equal(int64(3)))
+
+		err = kubeStore.Delete(context.TODO(), latest)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Test transaction function", func() {
+		err := kubeStore.Add(context.TODO(), &model.Project{Name: "tx-project", Description: "default"})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = kubeStore.Transaction(context.TODO(), func(tx datastore.DataStore) error {
+			if err := tx.Add(context.TODO(), &model.Project{Name: "tx-project-2"}); err != nil {
+				return err
+			}
+			return tx.Put(context.TODO(), &model.Project{Name: "tx-project", Description: "committed"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+		project := &model.Project{Name: "tx-project"}
+		err = kubeStore.Get(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.Description).Should(Equal("committed"))
+		exist, err := kubeStore.IsExist(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exist).Should(BeTrue())
+
+		// all the changes are rolled back if any error occurs
+		err = kubeStore.Transaction(context.TODO(), func(tx datastore.DataStore) error {
+			if err := tx.Add(context.TODO(), &model.Project{Name: "tx-project-3"}); err != nil {
+				return err
+			}
+			if err := tx.Delete(context.TODO(), &model.Project{Name: "tx-project-2"}); err != nil {
+				return err
+			}
+			if err := tx.Put(context.TODO(), &model.Project{Name: "tx-project", Description: "rollback"}); err != nil {
+				return err
+			}
+			return tx.Put(context.TODO(), &model.Project{Name: "tx-project-4"})
+		})
+		equal := cmp.Equal(err, datastore.ErrRecordNotExist, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+		project = &model.Project{Name: "tx-project"}
+		err = kubeStore.Get(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.Description).Should(Equal("committed"))
+		exist, err = kubeStore.IsExist(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exist).Should(BeTrue())
+		exist, err = kubeStore.IsExist(context.TODO(), &model.Project{Name: "tx-project-3"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exist).Should(BeFalse())
+
+		// the changes updated by others are not rolled back
+		err = kubeStore.Transaction(context.TODO(), func(tx datastore.DataStore) error {
+			if err := tx.Put(context.TODO(), &model.Project{Name: "tx-project", Description: "rollback"}); err != nil {
+				return err
+			}
+			if err := kubeStore.Put(context.TODO(), &model.Project{Name: "tx-project", Description: "concurrent"}); err != nil {
+				return err
+			}
+			return datastore.ErrRecordNotExist
+		})
+		equal = cmp.Equal(err, datastore.ErrRecordConflict, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+		project = &model.Project{Name: "tx-project"}
+		err = kubeStore.Get(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.Description).Should(Equal("concurrent"))
+
+		err = kubeStore.Delete(context.TODO(), &model.Project{Name: "tx-project"})
+		Expect(err).ToNot(HaveOccurred())
+		err = kubeStore.Delete(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("Test index", func() {
 		var app = model.Application{
 			Name: "test",
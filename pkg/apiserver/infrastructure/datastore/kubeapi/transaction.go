@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+// Transaction runs the function in a transaction. The Kubernetes API server does not support transactions, so the
+// changes are applied immediately and compensated in the reverse order if the function returns error. The changes
+// modified by others after the transaction are not compensated, and ErrRecordConflict is returned in that case.
+func (m *kubeapi) Transaction(ctx context.Context, fn func(tx datastore.DataStore) error) error {
+	tx := &kubeapiTx{kubeapi: m}
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.rollback(); rollbackErr != nil {
+			log.Logger.Errorf("the transaction failure %s is not rolled back completely", err.Error())
+			return rollbackErr
+		}
+		return err
+	}
+	return nil
+}
+
+// kubeapiTx records the compensations of the changes made in the transaction
+type kubeapiTx struct {
+	*kubeapi
+	compensations []func(ctx context.Context) error
+}
+
+// Add add data model, the ConfigMap is deleted when rolling back
+func (t *kubeapiTx) Add(ctx context.Context, entity datastore.Entity) error {
+	added, err := t.kubeapi.add(ctx, entity)
+	if err != nil {
+		return err
+	}
+	t.compensations = append(t.compensations, func(ctx context.Context) error {
+		return t.deleteConfigMap(ctx, added)
+	})
+	return nil
+}
+
+// BatchAdd batch add entity, the added entities are deleted when rolling back
+func (t *kubeapiTx) BatchAdd(ctx context.Context, entities []datastore.Entity) error {
+	for _, entity := range entities {
+		if err := t.Add(ctx, entity); err != nil {
+			return datastore.NewDBError(fmt.Errorf("save entities occur error, %w", err))
+		}
+	}
+	return nil
+}
+
+// Put update data model, the origin data is restored when rolling back
+func (t *kubeapiTx) Put(ctx context.Context, entity datastore.Entity) error {
+	origin, updated, err := t.kubeapi.put(ctx, entity)
+	if err != nil {
+		return err
+	}
+	t.compensations = append(t.compensations, func(ctx context.Context) error {
+		return t.restoreConfigMap(ctx, origin, updated)
+	})
+	return nil
+}
+
+// Delete delete data, the deleted ConfigMap is recreated when rolling back
+func (t *kubeapiTx) Delete(ctx context.Context, entity datastore.Entity) error {
+	if entity.PrimaryKey() == "" {
+		return datastore.ErrPrimaryEmpty
+	}
+	if entity.TableName() == "" {
+		return datastore.ErrTableNameEmpty
+	}
+	var origin corev1.ConfigMap
+	if err := t.kubeClient.Get(ctx, types.NamespacedName{Namespace: t.namespace, Name: generateName(entity)}, &origin); err != nil {
+		if apierrors.IsNotFound(err) {
+			return datastore.ErrRecordNotExist
+		}
+		return datastore.NewDBError(err)
+	}
+	if err := t.kubeapi.Delete(ctx, entity); err != nil {
+		return err
+	}
+	t.compensations = append(t.compensations, func(ctx context.Context) error {
+		return t.recreateConfigMap(ctx, &origin)
+	})
+	return nil
+}
+
+// Transaction the nested transaction joins the current one
+func (t *kubeapiTx) Transaction(ctx context.Context, fn func(tx datastore.DataStore) error) error {
+	return fn(t)
+}
+
+// rollback runs the compensations in the reverse order, the first conflict is returned after all the compensations
+func (t *kubeapiTx) rollback() error {
+	// the compensations should be finished even if the context of the transaction is canceled
+	ctx := context.Background()
+	var conflict error
+	for i := len(t.compensations) - 1; i >= 0; i-- {
+		if err := t.compensations[i](ctx); err != nil {
+			log.Logger.Errorf("rollback the change of the transaction failure %s", err.Error())
+			if conflict == nil && errors.Is(err, datastore.ErrRecordConflict) {
+				conflict = err
+			}
+		}
+	}
+	return conflict
+}
+
+// deleteConfigMap deletes the ConfigMap added by the transaction, unless it's modified by others
+func (m *kubeapi) deleteConfigMap(ctx context.Context, added *corev1.ConfigMap) error {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: added.Name, Namespace: added.Namespace}}
+	if err := m.kubeClient.Delete(ctx, configMap, client.Preconditions{UID: &added.UID, ResourceVersion: &added.ResourceVersion}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if apierrors.IsConflict(err) {
+			return datastore.ErrRecordConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// restoreConfigMap restores the data and labels of the ConfigMap updated by the transaction, unless it's modified
+// by others
+func (m *kubeapi) restoreConfigMap(ctx context.Context, origin *corev1.ConfigMap, updated *corev1.ConfigMap) error {
+	configMap := updated.DeepCopy()
+	configMap.Labels = origin.Labels
+	configMap.BinaryData = origin.BinaryData
+	if err := m.kubeClient.Update(ctx, configMap); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return datastore.ErrRecordConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// recreateConfigMap recreates the ConfigMap deleted by the transaction, unless it's created by others
+func (m *kubeapi) recreateConfigMap(ctx context.Context, origin *corev1.ConfigMap) error {
+	err := m.kubeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      origin.Name,
+			Namespace: origin.Namespace,
+			Labels:    origin.Labels,
+		},
+		BinaryData: origin.BinaryData,
+	})
+	if apierrors.IsAlreadyExists(err) {
+		return datastore.ErrRecordConflict
+	}
+	return err
+}
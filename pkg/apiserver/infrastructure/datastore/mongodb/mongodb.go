@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"cuelang.org/go/pkg/strings"
@@ -35,11 +36,25 @@ import (
 type mongodb struct {
 	client   *mongo.Client
 	database string
+
+	mu sync.Mutex
+	// transactionSupported caches whether the server supports the transaction, nil means not detected yet
+	transactionSupported *bool
 }
 
 // PrimaryKey primary key
 const PrimaryKey = "_name"
 
+// ResourceVersionKey the key of the resource version in the document
+const ResourceVersionKey = "basemodel.resourceversion"
+
+// versionDocument is used to read the resource version of the document
+type versionDocument struct {
+	BaseModel struct {
+		ResourceVersion int64 `bson:"resourceversion"`
+	} `bson:"basemodel"`
+}
+
 // New new mongodb datastore instance
 func New(ctx context.Context, cfg datastore.Config) (datastore.DataStore, error) {
 	if !strings.HasPrefix(cfg.URL, "mongodb://") {
@@ -70,14 +85,18 @@ func (m *mongodb) Add(ctx context.Context, entity datastore.Entity) error {
 	if err := m.Get(ctx, entity); err == nil {
 		return datastore.ErrRecordExist
 	}
+	version := entity.GetResourceVersion()
+	entity.SetResourceVersion(1)
 	model, err := convertToMap(entity)
 	if err != nil {
+		entity.SetResourceVersion(version)
 		return datastore.ErrEntityInvalid
 	}
 	model[PrimaryKey] = entity.PrimaryKey()
 	collection := m.client.Database(m.database).Collection(entity.TableName())
 	_, err = collection.InsertOne(ctx, model)
 	if err != nil {
+		entity.SetResourceVersion(version)
 		return datastore.NewDBError(err)
 	}
 	return nil
@@ -134,13 +153,43 @@ func (m *mongodb) Put(ctx context.Context, entity datastore.Entity) error {
 	}
 	entity.SetUpdateTime(time.Now())
 	collection := m.client.Database(m.database).Collection(entity.TableName())
-	_, err := collection.UpdateOne(ctx, makeNameFilter(entity.PrimaryKey()), makeEntityUpdate(entity))
+	filter := makeNameFilter(entity.PrimaryKey())
+	version := entity.GetResourceVersion()
+	newVersion := version + 1
+	if version == 0 {
+		// the entity without version is updated unconditionally, base on the stored version
+		var stored versionDocument
+		opts := options.FindOne().SetProjection(bson.D{{Key: ResourceVersionKey, Value: 1}})
+		if err := collection.FindOne(ctx, filter, opts).Decode(&stored); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return datastore.ErrRecordNotExist
+			}
+			return datastore.NewDBError(err)
+		}
+		newVersion = stored.BaseModel.ResourceVersion + 1
+	} else {
+		filter = append(filter, bson.E{Key: ResourceVersionKey, Value: version})
+	}
+	entity.SetResourceVersion(newVersion)
+	res, err := collection.UpdateOne(ctx, filter, makeEntityUpdate(entity))
 	if err != nil {
+		entity.SetResourceVersion(version)
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return datastore.ErrRecordNotExist
 		}
 		return datastore.NewDBError(err)
 	}
+	if res.MatchedCount == 0 {
+		entity.SetResourceVersion(version)
+		exist, err := m.IsExist(ctx, entity)
+		if err != nil {
+			return err
+		}
+		if exist {
+			return datastore.ErrRecordConflict
+		}
+		return datastore.ErrRecordNotExist
+	}
 	return nil
 }
 
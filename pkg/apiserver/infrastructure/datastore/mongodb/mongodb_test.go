@@ -104,6 +104,67 @@ var _ = Describe("Test mongodb datastore driver", func() {
 		err := mongodbDriver.Put(context.TODO(), &model.Application{Name: "kubevela-app", Description: "this is demo"})
 		Expect(err).ToNot(HaveOccurred())
 	})
+	It("Test put function with resource version", func() {
+		project := &model.Project{Name: "version-project", Description: "default"}
+		err := mongodbDriver.Add(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ResourceVersion).Should(Equal(int64(1)))
+
+		stale := &model.Project{Name: "version-project"}
+		err = mongodbDriver.Get(context.TODO(), stale)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stale.ResourceVersion).Should(Equal(int64(1)))
+
+		project.Description = "updated"
+		err = mongodbDriver.Put(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ResourceVersion).Should(Equal(int64(2)))
+
+		// the entity read before the updating is conflicted
+		stale.Description = "stale"
+		err = mongodbDriver.Put(context.TODO(), stale)
+		equal := cmp.Equal(err, datastore.ErrRecordConflict, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+		Expect(stale.ResourceVersion).Should(Equal(int64(1)))
+
+		// the entity without version is updated unconditionally
+		err = mongodbDriver.Put(context.TODO(), &model.Project{Name: "version-project", Description: "overwrite"})
+		Expect(err).ToNot(HaveOccurred())
+		latest := &model.Project{Name: "version-project"}
+		err = mongodbDriver.Get(context.TODO(), latest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(latest.Description).Should(Equal("overwrite"))
+		Expect(latest.ResourceVersion).Should(Equal(int64(3)))
+
+		err = mongodbDriver.Delete(context.TODO(), latest)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Test transaction function", func() {
+		err := mongodbDriver.Add(context.TODO(), &model.Project{Name: "tx-project", Description: "default"})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = mongodbDriver.Transaction(context.TODO(), func(tx datastore.DataStore) error {
+			if err := tx.Add(context.TODO(), &model.Project{Name: "tx-project-2"}); err != nil {
+				return err
+			}
+			return tx.Put(context.TODO(), &model.Project{Name: "tx-project", Description: "committed"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+		project := &model.Project{Name: "tx-project"}
+		err = mongodbDriver.Get(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.Description).Should(Equal("committed"))
+		exist, err := mongodbDriver.IsExist(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exist).Should(BeTrue())
+
+		err = mongodbDriver.Delete(context.TODO(), &model.Project{Name: "tx-project"})
+		Expect(err).ToNot(HaveOccurred())
+		err = mongodbDriver.Delete(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("Test list function", func() {
 		var app model.Application
 		list, err := mongodbDriver.List(context.TODO(), &app, &datastore.ListOptions{Page: -1})
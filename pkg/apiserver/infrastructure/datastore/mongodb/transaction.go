@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+// Transaction runs the function in a multi-document transaction. The transaction is only supported by the replica set
+// and the sharded cluster, the function runs without the transaction for the standalone server.
+func (m *mongodb) Transaction(ctx context.Context, fn func(tx datastore.DataStore) error) error {
+	supported, err := m.isTransactionSupported(ctx)
+	if err != nil {
+		return datastore.NewDBError(err)
+	}
+	if !supported {
+		return fn(m)
+	}
+	session, err := m.client.StartSession()
+	if err != nil {
+		return datastore.NewDBError(err)
+	}
+	defer session.EndSession(context.Background())
+	if err := session.StartTransaction(); err != nil {
+		return datastore.NewDBError(err)
+	}
+	if err := fn(&mongodbTx{mongodb: m, session: session}); err != nil {
+		if err := session.AbortTransaction(context.Background()); err != nil {
+			log.Logger.Errorf("abort the transaction failure %s", err.Error())
+		}
+		return err
+	}
+	if err := session.CommitTransaction(ctx); err != nil {
+		return datastore.NewDBError(err)
+	}
+	return nil
+}
+
+// isTransactionSupported detects whether the server is a replica set member or mongos
+func (m *mongodb) isTransactionSupported(ctx context.Context) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.transactionSupported != nil {
+		return *m.transactionSupported, nil
+	}
+	var result struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := m.client.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result); err != nil {
+		return false, err
+	}
+	supported := result.SetName != "" || result.Msg == "isdbgrid"
+	if !supported {
+		log.Logger.Warnf("the mongodb server is standalone, the transactions of the datastore are not atomic")
+	}
+	m.transactionSupported = &supported
+	return supported, nil
+}
+
+// mongodbTx runs the operations with the session of the transaction
+type mongodbTx struct {
+	*mongodb
+	session mongo.Session
+}
+
+func (t *mongodbTx) sessionContext(ctx context.Context) context.Context {
+	return mongo.NewSessionContext(ctx, t.session)
+}
+
+// Add add data model in the transaction
+func (t *mongodbTx) Add(ctx context.Context, entity datastore.Entity) error {
+	return t.mongodb.Add(t.sessionContext(ctx), entity)
+}
+
+// BatchAdd batch add entity in the transaction
+func (t *mongodbTx) BatchAdd(ctx context.Context, entities []datastore.Entity) error {
+	return t.mongodb.BatchAdd(t.sessionContext(ctx), entities)
+}
+
+// Put update data model in the transaction
+func (t *mongodbTx) Put(ctx context.Context, entity datastore.Entity) error {
+	return t.mongodb.Put(t.sessionContext(ctx), entity)
+}
+
+// Delete delete data in the transaction
+func (t *mongodbTx) Delete(ctx context.Context, entity datastore.Entity) error {
+	return t.mongodb.Delete(t.sessionContext(ctx), entity)
+}
+
+// Get get data model in the transaction
+func (t *mongodbTx) Get(ctx context.Context, entity datastore.Entity) error {
+	return t.mongodb.Get(t.sessionContext(ctx), entity)
+}
+
+// List list entity in the transaction
+func (t *mongodbTx) List(ctx context.Context, query datastore.Entity, op *datastore.ListOptions) ([]datastore.Entity, error) {
+	return t.mongodb.List(t.sessionContext(ctx), query, op)
+}
+
+// Count counts entities in the transaction
+func (t *mongodbTx) Count(ctx context.Context, entity datastore.Entity, filterOptions *datastore.FilterOptions) (int64, error) {
+	return t.mongodb.Count(t.sessionContext(ctx), entity, filterOptions)
+}
+
+// IsExist determine whether data exists in the transaction
+func (t *mongodbTx) IsExist(ctx context.Context, entity datastore.Entity) (bool, error) {
+	return t.mongodb.IsExist(t.sessionContext(ctx), entity)
+}
+
+// Transaction the nested transaction joins the current one
+func (t *mongodbTx) Transaction(ctx context.Context, fn func(tx datastore.DataStore) error) error {
+	return fn(t)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqldb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	// register the database drivers
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+)
+
+const (
+	// TypePostgres the datastore type of PostgreSQL
+	TypePostgres = "postgres"
+	// TypeMySQL the datastore type of MySQL
+	TypeMySQL = "mysql"
+	// TypeSQLite the datastore type of SQLite, the driver is only available when built with CGO
+	TypeSQLite = "sqlite"
+)
+
+const (
+	columnPrimaryKey = "_name"
+	columnData       = "_data"
+	columnCreateTime = "_create_time"
+	columnUpdateTime = "_update_time"
+	columnVersion    = "_version"
+)
+
+// dialect describes the differences of the SQL syntax between the databases
+type dialect struct {
+	driverName string
+	dataType   string
+	quoteChar  string
+	bindVar    func(i int) string
+	// jsonExtract returns the expression that extracts the value as text in the given path of the json column
+	jsonExtract func(column string, path []string) string
+}
+
+var dialects = map[string]*dialect{
+	TypePostgres: {
+		driverName: "postgres",
+		dataType:   "TEXT",
+		quoteChar:  `"`,
+		bindVar:    func(i int) string { return fmt.Sprintf("$%d", i) },
+		jsonExtract: func(column string, path []string) string {
+			return fmt.Sprintf("(%s::jsonb #>> '{%s}')", column, strings.Join(path, ","))
+		},
+	},
+	TypeMySQL: {
+		driverName: "mysql",
+		dataType:   "LONGTEXT",
+		quoteChar:  "`",
+		bindVar:    func(i int) string { return "?" },
+		jsonExtract: func(column string, path []string) string {
+			return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, '$.%s'))", column, strings.Join(path, "."))
+		},
+	},
+	TypeSQLite: {
+		driverName: "sqlite3",
+		dataType:   "TEXT",
+		quoteChar:  `"`,
+		bindVar:    func(i int) string { return "?" },
+		jsonExtract: func(column string, path []string) string {
+			return fmt.Sprintf("json_extract(%s, '$.%s')", column, strings.Join(path, "."))
+		},
+	},
+}
+
+func (d *dialect) quote(identifier string) string {
+	return d.quoteChar + identifier + d.quoteChar
+}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// indexColumnName returns the column name of the index key, the dots are not allowed in the column name
+func indexColumnName(key string) (string, error) {
+	if !identifierRegexp.MatchString(key) {
+		return "", datastore.ErrIndexInvalid
+	}
+	return strings.ToLower(strings.ReplaceAll(key, ".", "_")), nil
+}
+
+// indexName returns the name of the database index created for the index column, which is limited to 64 characters in
+// MySQL and 63 characters in PostgreSQL
+func indexName(table, column string) string {
+	name := fmt.Sprintf("%s_%s_idx", table, column)
+	if len(name) > 63 {
+		name = name[len(name)-63:]
+	}
+	return name
+}
+
+// statement builds the SQL statement with the bind variables of the dialect
+type statement struct {
+	dialect *dialect
+	sb      strings.Builder
+	args    []interface{}
+}
+
+func (s *statement) write(format string, a ...interface{}) *statement {
+	_, _ = fmt.Fprintf(&s.sb, format, a...)
+	return s
+}
+
+// arg adds the argument and returns the bind variable of it
+func (s *statement) arg(value interface{}) string {
+	s.args = append(s.args, value)
+	return s.dialect.bindVar(len(s.args))
+}
+
+func (s *statement) String() string {
+	return s.sb.String()
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// where builds the conditions of the index and the filter options, the columns are the existing columns in the table
+func (s *statement) where(index map[string]string, op *datastore.FilterOptions, columns map[string]bool) error {
+	var conditions []string
+	for key, value := range index {
+		column, err := indexColumnName(key)
+		if err != nil {
+			return err
+		}
+		if !columns[column] {
+			conditions = append(conditions, "1 = 0")
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("%s = %s", s.dialect.quote(column), s.arg(value)))
+	}
+	if op != nil {
+		for _, query := range op.Queries {
+			if !identifierRegexp.MatchString(query.Key) {
+				return datastore.ErrIndexInvalid
+			}
+			expr := s.dialect.jsonExtract(s.dialect.quote(columnData), strings.Split(query.Key, "."))
+			conditions = append(conditions, fmt.Sprintf("%s LIKE %s ESCAPE '!'", expr, s.arg("%"+likeEscaper.Replace(query.Query)+"%")))
+		}
+		for _, in := range op.In {
+			column, err := indexColumnName(in.Key)
+			if err != nil {
+				return err
+			}
+			if !columns[column] || len(in.Values) == 0 {
+				conditions = append(conditions, "1 = 0")
+				continue
+			}
+			var vars []string
+			for _, value := range in.Values {
+				vars = append(vars, s.arg(value))
+			}
+			conditions = append(conditions, fmt.Sprintf("%s IN (%s)", s.dialect.quote(column), strings.Join(vars, ", ")))
+		}
+		for _, notExist := range op.IsNotExist {
+			column, err := indexColumnName(notExist.Key)
+			if err != nil {
+				return err
+			}
+			if !columns[column] {
+				continue
+			}
+			conditions = append(conditions, fmt.Sprintf("(%s IS NULL OR %s = '')", s.dialect.quote(column), s.dialect.quote(column)))
+		}
+	}
+	if len(conditions) > 0 {
+		s.write(" WHERE %s", strings.Join(conditions, " AND "))
+	}
+	return nil
+}
+
+// orderBy builds the sorting of the list, the create time and update time are stored in the dedicated columns
+func (s *statement) orderBy(sortBy []datastore.SortOption, columns map[string]bool) error {
+	var orders []string
+	for _, op := range sortBy {
+		var expr string
+		switch op.Key {
+		case "createTime":
+			expr = s.dialect.quote(columnCreateTime)
+		case "updateTime":
+			expr = s.dialect.quote(columnUpdateTime)
+		default:
+			column, err := indexColumnName(op.Key)
+			if err != nil {
+				return err
+			}
+			if columns[column] {
+				expr = s.dialect.quote(column)
+			} else {
+				expr = s.dialect.jsonExtract(s.dialect.quote(columnData), strings.Split(op.Key, "."))
+			}
+		}
+		if op.Order == datastore.SortOrderDescending {
+			expr += " DESC"
+		}
+		orders = append(orders, expr)
+	}
+	// sort by the primary key at last to make the paging stable
+	orders = append(orders, s.dialect.quote(columnPrimaryKey))
+	s.write(" ORDER BY %s", strings.Join(orders, ", "))
+	return nil
+}
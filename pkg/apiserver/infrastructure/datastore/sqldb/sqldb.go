@@ -0,0 +1,535 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+// executor is implemented by both the database and the transaction
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type sqldb struct {
+	db *sql.DB
+	// exec is the database or the transaction that the statements are executed in
+	exec    executor
+	tx      *sql.Tx
+	dialect *dialect
+	schema  *schema
+}
+
+// schema caches the columns of the tables, it's shared by the transactions
+type schema struct {
+	mu      sync.Mutex
+	columns map[string]map[string]bool
+}
+
+func (s *schema) get(table string) (map[string]bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	columns, ok := s.columns[table]
+	if !ok {
+		return nil, false
+	}
+	copied := make(map[string]bool, len(columns))
+	for column := range columns {
+		copied[column] = true
+	}
+	return copied, true
+}
+
+func (s *schema) set(table string, columns map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := make(map[string]bool, len(columns))
+	for column := range columns {
+		copied[column] = true
+	}
+	s.columns[table] = copied
+}
+
+// reset clears the cache, the schema changes may be rolled back with the transaction
+func (s *schema) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.columns = map[string]map[string]bool{}
+}
+
+// New new sql datastore instance, the type of the config decides the database driver, and the url is the data source
+// name of the driver. The tables are created for each entity on demand, and the indexes of the entities are stored as
+// the indexed columns.
+func New(ctx context.Context, cfg datastore.Config) (datastore.DataStore, error) {
+	d, ok := dialects[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sql datastore type %s", cfg.Type)
+	}
+	if cfg.Type == TypeSQLite && !sqliteSupported {
+		return nil, fmt.Errorf("the sqlite datastore requires the apiserver to be built with CGO_ENABLED=1, use postgres or mysql instead")
+	}
+	db, err := sql.Open(d.driverName, cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Type == TypeSQLite {
+		// sqlite does not support concurrent writes, and the in-memory database is not shared between connections
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("connect to the database failure %w", err)
+	}
+	return &sqldb{
+		db:      db,
+		exec:    db,
+		dialect: d,
+		schema:  &schema{columns: map[string]map[string]bool{}},
+	}, nil
+}
+
+func validateEntity(entity datastore.Entity) error {
+	if entity.PrimaryKey() == "" {
+		return datastore.ErrPrimaryEmpty
+	}
+	if entity.TableName() == "" {
+		return datastore.ErrTableNameEmpty
+	}
+	return nil
+}
+
+// prepareTable creates the table if not exists and adds the columns for the given index keys, the existing columns of
+// the table are returned
+func (m *sqldb) prepareTable(ctx context.Context, table string, index map[string]string) (map[string]bool, error) {
+	if !identifierRegexp.MatchString(table) {
+		return nil, datastore.ErrTableNameEmpty
+	}
+	// the lock is not held when executing the statements, which may wait for the connection used by a transaction
+	columns, ok := m.schema.get(table)
+	if !ok {
+		createTable := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s VARCHAR(255) NOT NULL PRIMARY KEY, %s %s, %s BIGINT, %s BIGINT, %s BIGINT NOT NULL DEFAULT 0)",
+			m.dialect.quote(table), m.dialect.quote(columnPrimaryKey), m.dialect.quote(columnData), m.dialect.dataType,
+			m.dialect.quote(columnCreateTime), m.dialect.quote(columnUpdateTime), m.dialect.quote(columnVersion))
+		if _, err := m.exec.ExecContext(ctx, createTable); err != nil {
+			return nil, datastore.NewDBError(fmt.Errorf("create table %s failure %w", table, err))
+		}
+		var err error
+		if columns, err = m.loadColumns(ctx, table); err != nil {
+			return nil, err
+		}
+		// the tables created before the version column was introduced
+		if !columns[columnVersion] {
+			addColumn := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s BIGINT NOT NULL DEFAULT 0", m.dialect.quote(table), m.dialect.quote(columnVersion))
+			if _, err := m.exec.ExecContext(ctx, addColumn); err != nil {
+				return nil, datastore.NewDBError(fmt.Errorf("add column %s to table %s failure %w", columnVersion, table, err))
+			}
+			columns[columnVersion] = true
+		}
+		m.schema.set(table, columns)
+	}
+	for key := range index {
+		column, err := indexColumnName(key)
+		if err != nil {
+			return nil, err
+		}
+		if columns[column] {
+			continue
+		}
+		addColumn := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s VARCHAR(512)", m.dialect.quote(table), m.dialect.quote(column))
+		if _, err := m.exec.ExecContext(ctx, addColumn); err != nil {
+			// the column may be added by other instances
+			if columns, err = m.loadColumns(ctx, table); err != nil {
+				return nil, err
+			}
+			m.schema.set(table, columns)
+			if columns[column] {
+				continue
+			}
+			return nil, datastore.NewDBError(fmt.Errorf("add column %s to table %s failure %w", column, table, err))
+		}
+		createIndex := fmt.Sprintf("CREATE INDEX %s ON %s (%s)", m.dialect.quote(indexName(table, column)), m.dialect.quote(table), m.dialect.quote(column))
+		if _, err := m.exec.ExecContext(ctx, createIndex); err != nil {
+			log.Logger.Warnf("create index for column %s in table %s failure %s", column, table, err.Error())
+		}
+		columns[column] = true
+		m.schema.set(table, columns)
+	}
+	return columns, nil
+}
+
+// loadColumns reads the columns of the table from the database
+func (m *sqldb) loadColumns(ctx context.Context, table string) (map[string]bool, error) {
+	rows, err := m.exec.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", m.dialect.quote(table)))
+	if err != nil {
+		return nil, datastore.NewDBError(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Logger.Warnf("close rows failure %s", err.Error())
+		}
+	}()
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, datastore.NewDBError(err)
+	}
+	columns := map[string]bool{}
+	for _, name := range names {
+		columns[strings.ToLower(name)] = true
+	}
+	return columns, nil
+}
+
+func (m *sqldb) isExist(ctx context.Context, exec executor, entity datastore.Entity) (bool, error) {
+	stmt := &statement{dialect: m.dialect}
+	stmt.write("SELECT COUNT(*) FROM %s WHERE %s = %s", m.dialect.quote(entity.TableName()), m.dialect.quote(columnPrimaryKey), stmt.arg(entity.PrimaryKey()))
+	var count int64
+	if err := exec.QueryRowContext(ctx, stmt.String(), stmt.args...).Scan(&count); err != nil {
+		return false, datastore.NewDBError(err)
+	}
+	return count > 0, nil
+}
+
+// add inserts the entity, the table should be prepared before
+func (m *sqldb) add(ctx context.Context, exec executor, entity datastore.Entity) error {
+	now := time.Now()
+	entity.SetCreateTime(now)
+	entity.SetUpdateTime(now)
+	index := entity.Index()
+	if exist, err := m.isExist(ctx, exec, entity); err != nil {
+		return err
+	} else if exist {
+		return datastore.ErrRecordExist
+	}
+	version := entity.GetResourceVersion()
+	entity.SetResourceVersion(1)
+	data, err := json.Marshal(entity)
+	if err != nil {
+		entity.SetResourceVersion(version)
+		return datastore.ErrEntityInvalid
+	}
+	stmt := &statement{dialect: m.dialect}
+	columns := []string{m.dialect.quote(columnPrimaryKey), m.dialect.quote(columnData), m.dialect.quote(columnCreateTime), m.dialect.quote(columnUpdateTime), m.dialect.quote(columnVersion)}
+	vars := []string{stmt.arg(entity.PrimaryKey()), stmt.arg(string(data)), stmt.arg(now.UnixNano()), stmt.arg(now.UnixNano()), stmt.arg(int64(1))}
+	for _, key := range sortedKeys(index) {
+		column, _ := indexColumnName(key)
+		columns = append(columns, m.dialect.quote(column))
+		vars = append(vars, stmt.arg(index[key]))
+	}
+	stmt.write("INSERT INTO %s (%s) VALUES (%s)", m.dialect.quote(entity.TableName()), strings.Join(columns, ", "), strings.Join(vars, ", "))
+	if _, err := exec.ExecContext(ctx, stmt.String(), stmt.args...); err != nil {
+		entity.SetResourceVersion(version)
+		if exist, _ := m.isExist(ctx, exec, entity); exist {
+			return datastore.ErrRecordExist
+		}
+		return datastore.NewDBError(err)
+	}
+	return nil
+}
+
+// Add add data model
+func (m *sqldb) Add(ctx context.Context, entity datastore.Entity) error {
+	if err := validateEntity(entity); err != nil {
+		return err
+	}
+	if _, err := m.prepareTable(ctx, entity.TableName(), entity.Index()); err != nil {
+		return err
+	}
+	return m.add(ctx, m.exec, entity)
+}
+
+// BatchAdd batch add entity, the entities are added in one transaction.
+func (m *sqldb) BatchAdd(ctx context.Context, entities []datastore.Entity) error {
+	// the tables are prepared out of the transaction, as the DDL statements commit the transaction implicitly in MySQL
+	for _, entity := range entities {
+		if err := validateEntity(entity); err != nil {
+			return datastore.NewDBError(fmt.Errorf("save entities occur error, %w", err))
+		}
+		if _, err := m.prepareTable(ctx, entity.TableName(), entity.Index()); err != nil {
+			return datastore.NewDBError(fmt.Errorf("save entities occur error, %w", err))
+		}
+	}
+	return m.Transaction(ctx, func(tx datastore.DataStore) error {
+		for _, entity := range entities {
+			if err := m.add(ctx, tx.(*sqldb).exec, entity); err != nil {
+				return datastore.NewDBError(fmt.Errorf("save entities occur error, %w", err))
+			}
+		}
+		return nil
+	})
+}
+
+// Get get data model
+func (m *sqldb) Get(ctx context.Context, entity datastore.Entity) error {
+	if err := validateEntity(entity); err != nil {
+		return err
+	}
+	if _, err := m.prepareTable(ctx, entity.TableName(), nil); err != nil {
+		return err
+	}
+	stmt := &statement{dialect: m.dialect}
+	stmt.write("SELECT %s FROM %s WHERE %s = %s", m.dialect.quote(columnData), m.dialect.quote(entity.TableName()), m.dialect.quote(columnPrimaryKey), stmt.arg(entity.PrimaryKey()))
+	var data string
+	if err := m.exec.QueryRowContext(ctx, stmt.String(), stmt.args...).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return datastore.ErrRecordNotExist
+		}
+		return datastore.NewDBError(err)
+	}
+	if err := json.Unmarshal([]byte(data), entity); err != nil {
+		return datastore.NewDBError(err)
+	}
+	return nil
+}
+
+// Put update data model
+func (m *sqldb) Put(ctx context.Context, entity datastore.Entity) error {
+	if err := validateEntity(entity); err != nil {
+		return err
+	}
+	now := time.Now()
+	entity.SetUpdateTime(now)
+	index := entity.Index()
+	columns, err := m.prepareTable(ctx, entity.TableName(), index)
+	if err != nil {
+		return err
+	}
+	version := entity.GetResourceVersion()
+	expectVersion := version
+	if version == 0 {
+		// the entity without version is updated unconditionally, base on the stored version
+		if expectVersion, err = m.getVersion(ctx, entity); err != nil {
+			return err
+		}
+	}
+	entity.SetResourceVersion(expectVersion + 1)
+	data, err := json.Marshal(entity)
+	if err != nil {
+		entity.SetResourceVersion(version)
+		return datastore.ErrEntityInvalid
+	}
+	stmt := &statement{dialect: m.dialect}
+	sets := []string{
+		fmt.Sprintf("%s = %s", m.dialect.quote(columnData), stmt.arg(string(data))),
+		fmt.Sprintf("%s = %s", m.dialect.quote(columnUpdateTime), stmt.arg(now.UnixNano())),
+		fmt.Sprintf("%s = %s", m.dialect.quote(columnVersion), stmt.arg(expectVersion+1)),
+	}
+	indexColumns := map[string]string{}
+	for key, value := range index {
+		column, _ := indexColumnName(key)
+		indexColumns[column] = value
+	}
+	var columnNames []string
+	for column := range columns {
+		if !strings.HasPrefix(column, "_") {
+			columnNames = append(columnNames, column)
+		}
+	}
+	sort.Strings(columnNames)
+	// the index keys removed from the entity are cleared
+	for _, column := range columnNames {
+		var value interface{}
+		if v, ok := indexColumns[column]; ok {
+			value = v
+		}
+		sets = append(sets, fmt.Sprintf("%s = %s", m.dialect.quote(column), stmt.arg(value)))
+	}
+	stmt.write("UPDATE %s SET %s WHERE %s = %s AND %s = %s", m.dialect.quote(entity.TableName()), strings.Join(sets, ", "),
+		m.dialect.quote(columnPrimaryKey), stmt.arg(entity.PrimaryKey()), m.dialect.quote(columnVersion), stmt.arg(expectVersion))
+	res, err := m.exec.ExecContext(ctx, stmt.String(), stmt.args...)
+	if err != nil {
+		entity.SetResourceVersion(version)
+		return datastore.NewDBError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		entity.SetResourceVersion(version)
+		exist, err := m.isExist(ctx, m.exec, entity)
+		if err != nil {
+			return err
+		}
+		if exist {
+			return datastore.ErrRecordConflict
+		}
+		return datastore.ErrRecordNotExist
+	}
+	return nil
+}
+
+// getVersion reads the stored resource version of the entity
+func (m *sqldb) getVersion(ctx context.Context, entity datastore.Entity) (int64, error) {
+	stmt := &statement{dialect: m.dialect}
+	stmt.write("SELECT %s FROM %s WHERE %s = %s", m.dialect.quote(columnVersion), m.dialect.quote(entity.TableName()), m.dialect.quote(columnPrimaryKey), stmt.arg(entity.PrimaryKey()))
+	var version int64
+	if err := m.exec.QueryRowContext(ctx, stmt.String(), stmt.args...).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, datastore.ErrRecordNotExist
+		}
+		return 0, datastore.NewDBError(err)
+	}
+	return version, nil
+}
+
+// IsExist determine whether data exists.
+func (m *sqldb) IsExist(ctx context.Context, entity datastore.Entity) (bool, error) {
+	if err := validateEntity(entity); err != nil {
+		return false, err
+	}
+	if _, err := m.prepareTable(ctx, entity.TableName(), nil); err != nil {
+		return false, err
+	}
+	return m.isExist(ctx, m.exec, entity)
+}
+
+// Delete delete data
+func (m *sqldb) Delete(ctx context.Context, entity datastore.Entity) error {
+	if err := validateEntity(entity); err != nil {
+		return err
+	}
+	if _, err := m.prepareTable(ctx, entity.TableName(), nil); err != nil {
+		return err
+	}
+	stmt := &statement{dialect: m.dialect}
+	stmt.write("DELETE FROM %s WHERE %s = %s", m.dialect.quote(entity.TableName()), m.dialect.quote(columnPrimaryKey), stmt.arg(entity.PrimaryKey()))
+	res, err := m.exec.ExecContext(ctx, stmt.String(), stmt.args...)
+	if err != nil {
+		return datastore.NewDBError(err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return datastore.ErrRecordNotExist
+	}
+	return nil
+}
+
+// List list entity function
+func (m *sqldb) List(ctx context.Context, entity datastore.Entity, op *datastore.ListOptions) ([]datastore.Entity, error) {
+	if entity.TableName() == "" {
+		return nil, datastore.ErrTableNameEmpty
+	}
+	columns, err := m.prepareTable(ctx, entity.TableName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &statement{dialect: m.dialect}
+	stmt.write("SELECT %s FROM %s", m.dialect.quote(columnData), m.dialect.quote(entity.TableName()))
+	var filterOptions *datastore.FilterOptions
+	var sortBy []datastore.SortOption
+	if op != nil {
+		filterOptions, sortBy = &op.FilterOptions, op.SortBy
+	}
+	if err := stmt.where(entity.Index(), filterOptions, columns); err != nil {
+		return nil, err
+	}
+	if err := stmt.orderBy(sortBy, columns); err != nil {
+		return nil, err
+	}
+	if op != nil && op.PageSize > 0 && op.Page > 0 {
+		stmt.write(" LIMIT %s OFFSET %s", stmt.arg(op.PageSize), stmt.arg(op.PageSize*(op.Page-1)))
+	}
+	rows, err := m.exec.QueryContext(ctx, stmt.String(), stmt.args...)
+	if err != nil {
+		return nil, datastore.NewDBError(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Logger.Warnf("close rows failure %s", err.Error())
+		}
+	}()
+	var list []datastore.Entity
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, datastore.NewDBError(err)
+		}
+		item, err := datastore.NewEntity(entity)
+		if err != nil {
+			return nil, datastore.NewDBError(err)
+		}
+		if err := json.Unmarshal([]byte(data), item); err != nil {
+			return nil, datastore.NewDBError(fmt.Errorf("decode entity failure %w", err))
+		}
+		list = append(list, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, datastore.NewDBError(err)
+	}
+	return list, nil
+}
+
+// Count counts entities
+func (m *sqldb) Count(ctx context.Context, entity datastore.Entity, filterOptions *datastore.FilterOptions) (int64, error) {
+	if entity.TableName() == "" {
+		return 0, datastore.ErrTableNameEmpty
+	}
+	columns, err := m.prepareTable(ctx, entity.TableName(), nil)
+	if err != nil {
+		return 0, err
+	}
+	stmt := &statement{dialect: m.dialect}
+	stmt.write("SELECT COUNT(*) FROM %s", m.dialect.quote(entity.TableName()))
+	if err := stmt.where(entity.Index(), filterOptions, columns); err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := m.exec.QueryRowContext(ctx, stmt.String(), stmt.args...).Scan(&count); err != nil {
+		return 0, datastore.NewDBError(err)
+	}
+	return count, nil
+}
+
+// Transaction runs the function in a database transaction, the nested transaction joins the current one. Note that
+// the tables and columns are created in the transaction if missing, which commits the transaction implicitly in MySQL.
+func (m *sqldb) Transaction(ctx context.Context, fn func(tx datastore.DataStore) error) error {
+	if m.tx != nil {
+		return fn(m)
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return datastore.NewDBError(err)
+	}
+	if err := fn(&sqldb{db: m.db, exec: tx, tx: tx, dialect: m.dialect, schema: m.schema}); err != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Logger.Errorf("rollback the transaction failure %s", err.Error())
+		}
+		m.schema.reset()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		m.schema.reset()
+		return datastore.NewDBError(err)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
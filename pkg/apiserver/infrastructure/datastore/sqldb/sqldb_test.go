@@ -0,0 +1,463 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqldb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
+)
+
+var sqlDriver datastore.DataStore
+var testDir string
+var _ = BeforeSuite(func() {
+	By("bootstrapping sqlite test environment")
+	var err error
+	testDir, err = os.MkdirTemp("", "kubevela-sqldb")
+	Expect(err).ToNot(HaveOccurred())
+	_, err = New(context.TODO(), datastore.Config{Type: "unknown"})
+	Expect(err).Should(HaveOccurred())
+
+	sqlDriver, err = New(context.TODO(), datastore.Config{
+		Type: TypeSQLite,
+		URL:  filepath.Join(testDir, "kubevela.db"),
+	})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(sqlDriver).ToNot(BeNil())
+	By("create sqlite driver success")
+})
+
+var _ = AfterSuite(func() {
+	Expect(os.RemoveAll(testDir)).Should(Succeed())
+})
+
+var _ = Describe("Test sql datastore driver", func() {
+
+	It("Test add function", func() {
+		err := sqlDriver.Add(context.TODO(), &model.Application{Name: "kubevela-app", Description: "default"})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = sqlDriver.Add(context.TODO(), &model.Application{Name: "kubevela-app", Description: "default"})
+		equal := cmp.Equal(err, datastore.ErrRecordExist, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+
+		err = sqlDriver.Add(context.TODO(), &model.Application{Description: "default"})
+		equal = cmp.Equal(err, datastore.ErrPrimaryEmpty, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+	})
+
+	It("Test batch add function", func() {
+		var datas = []datastore.Entity{
+			&model.Application{Name: "kubevela-app-2", Description: "this is demo 2"},
+			&model.Application{Name: "kubevela-app-3", Description: "this is demo 3"},
+			&model.Application{Name: "kubevela-app-4", Project: "test-project", Description: "this is demo 4"},
+			&model.Workflow{Name: "kubevela-app-workflow", AppPrimaryKey: "kubevela-app-2", Description: "this is workflow"},
+			&model.ApplicationTrigger{Name: "kubevela-app-trigger", AppPrimaryKey: "kubevela-app-2", Token: "token-test", Description: "this is demo 4"},
+		}
+		err := sqlDriver.BatchAdd(context.TODO(), datas)
+		Expect(err).ToNot(HaveOccurred())
+
+		var datas2 = []datastore.Entity{
+			&model.Application{Name: "can-delete", Description: "this is demo can-delete"},
+			&model.Application{Name: "kubevela-app-2", Description: "this is demo 2"},
+		}
+		err = sqlDriver.BatchAdd(context.TODO(), datas2)
+		Expect(err).Should(HaveOccurred())
+		Expect(strings.Contains(err.Error(), "save entities occur error")).Should(BeTrue())
+		// the transaction is rolled back
+		exist, err := sqlDriver.IsExist(context.TODO(), &model.Application{Name: "can-delete"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(exist).Should(BeFalse())
+	})
+
+	It("Test get function", func() {
+		app := &model.Application{Name: "kubevela-app"}
+		err := sqlDriver.Get(context.TODO(), app)
+		Expect(err).Should(BeNil())
+		diff := cmp.Diff(app.Description, "default")
+		Expect(diff).Should(BeEmpty())
+		Expect(app.CreateTime.IsZero()).Should(BeFalse())
+
+		workflow := &model.Workflow{Name: "kubevela-app-workflow", AppPrimaryKey: "kubevela-app-2"}
+		err = sqlDriver.Get(context.TODO(), workflow)
+		Expect(err).Should(BeNil())
+		diff = cmp.Diff(workflow.Description, "this is workflow")
+		Expect(diff).Should(BeEmpty())
+
+		err = sqlDriver.Get(context.TODO(), &model.Cluster{Name: "not-exist"})
+		equal := cmp.Equal(err, datastore.ErrRecordNotExist, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+	})
+
+	It("Test put function", func() {
+		err := sqlDriver.Put(context.TODO(), &model.Application{Name: "kubevela-app", Project: "moved-project", Description: "this is demo"})
+		Expect(err).ToNot(HaveOccurred())
+		list, err := sqlDriver.List(context.TODO(), &model.Application{Project: "moved-project"}, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(list)).Should(Equal(1))
+		Expect(list[0].(*model.Application).Description).Should(Equal("this is demo"))
+
+		// the removed index is cleared
+		err = sqlDriver.Put(context.TODO(), &model.Application{Name: "kubevela-app", Description: "this is demo"})
+		Expect(err).ToNot(HaveOccurred())
+		count, err := sqlDriver.Count(context.TODO(), &model.Application{Project: "moved-project"}, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(count).Should(Equal(int64(0)))
+
+		err = sqlDriver.Put(context.TODO(), &model.Application{Name: "kubevela-app-5"})
+		equal := cmp.Equal(err, datastore.ErrRecordNotExist, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+	})
+
+	It("Test put function with resource version", func() {
+		project := &model.Project{Name: "version-project", Description: "default"}
+		err := sqlDriver.Add(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ResourceVersion).Should(Equal(int64(1)))
+
+		stale := &model.Project{Name: "version-project"}
+		err = sqlDriver.Get(context.TODO(), stale)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stale.ResourceVersion).Should(Equal(int64(1)))
+
+		project.Description = "updated"
+		err = sqlDriver.Put(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.ResourceVersion).Should(Equal(int64(2)))
+
+		// the entity read before the updating is conflicted
+		stale.Description = "stale"
+		err = sqlDriver.Put(context.TODO(), stale)
+		equal := cmp.Equal(err, datastore.ErrRecordConflict, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+		Expect(stale.ResourceVersion).Should(Equal(int64(1)))
+
+		// the entity without version is updated unconditionally
+		err = sqlDriver.Put(context.TODO(), &model.Project{Name: "version-project", Description: "overwrite"})
+		Expect(err).ToNot(HaveOccurred())
+		latest := &model.Project{Name: "version-project"}
+		err = sqlDriver.Get(context.TODO(), latest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(latest.Description).Should(Equal("overwrite"))
+		Expect(latest.ResourceVersion).Should(Equal(int64(3)))
+
+		err = sqlDriver.Delete(context.TODO(), latest)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Test transaction function", func() {
+		err := sqlDriver.Add(context.TODO(), &model.Project{Name: "tx-project", Description: "default"})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = sqlDriver.Transaction(context.TODO(), func(tx datastore.DataStore) error {
+			if err := tx.Add(context.TODO(), &model.Project{Name: "tx-project-2"}); err != nil {
+				return err
+			}
+			return tx.Put(context.TODO(), &model.Project{Name: "tx-project", Description: "committed"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+		project := &model.Project{Name: "tx-project"}
+		err = sqlDriver.Get(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.Description).Should(Equal("committed"))
+		exist, err := sqlDriver.IsExist(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exist).Should(BeTrue())
+
+		// all the changes are rolled back if any error occurs
+		err = sqlDriver.Transaction(context.TODO(), func(tx datastore.DataStore) error {
+			if err := tx.Add(context.TODO(), &model.Project{Name: "tx-project-3"}); err != nil {
+				return err
+			}
+			if err := tx.Delete(context.TODO(), &model.Project{Name: "tx-project-2"}); err != nil {
+				return err
+			}
+			if err := tx.Put(context.TODO(), &model.Project{Name: "tx-project", Description: "rollback"}); err != nil {
+				return err
+			}
+			return tx.Put(context.TODO(), &model.Project{Name: "tx-project-4"})
+		})
+		equal := cmp.Equal(err, datastore.ErrRecordNotExist, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+		project = &model.Project{Name: "tx-project"}
+		err = sqlDriver.Get(context.TODO(), project)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(project.Description).Should(Equal("committed"))
+		exist, err = sqlDriver.IsExist(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exist).Should(BeTrue())
+		exist, err = sqlDriver.IsExist(context.TODO(), &model.Project{Name: "tx-project-3"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exist).Should(BeFalse())
+
+		err = sqlDriver.Delete(context.TODO(), &model.Project{Name: "tx-project"})
+		Expect(err).ToNot(HaveOccurred())
+		err = sqlDriver.Delete(context.TODO(), &model.Project{Name: "tx-project-2"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Test list function", func() {
+		var app model.Application
+		list, err := sqlDriver.List(context.TODO(), &app, &datastore.ListOptions{Page: -1})
+		Expect(err).ShouldNot(HaveOccurred())
+		diff := cmp.Diff(len(list), 4)
+		Expect(diff).Should(BeEmpty())
+
+		list, err = sqlDriver.List(context.TODO(), &app, &datastore.ListOptions{Page: 2, PageSize: 2})
+		Expect(err).ShouldNot(HaveOccurred())
+		diff = cmp.Diff(len(list), 2)
+		Expect(diff).Should(BeEmpty())
+
+		list, err = sqlDriver.List(context.TODO(), &app, &datastore.ListOptions{Page: 3, PageSize: 2})
+		Expect(err).ShouldNot(HaveOccurred())
+		diff = cmp.Diff(len(list), 0)
+		Expect(diff).Should(BeEmpty())
+
+		list, err = sqlDriver.List(context.TODO(), &app, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		diff = cmp.Diff(len(list), 4)
+		Expect(diff).Should(BeEmpty())
+
+		var workflow = model.Workflow{
+			AppPrimaryKey: "kubevela-app-2",
+		}
+		list, err = sqlDriver.List(context.TODO(), &workflow, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		diff = cmp.Diff(len(list), 1)
+		Expect(diff).Should(BeEmpty())
+
+		list, err = sqlDriver.List(context.TODO(), &app, &datastore.ListOptions{FilterOptions: datastore.FilterOptions{In: []datastore.InQueryOption{
+			{
+				Key:    "name",
+				Values: []string{"kubevela-app-3", "kubevela-app-2"},
+			},
+		}}})
+		Expect(err).ShouldNot(HaveOccurred())
+		diff = cmp.Diff(len(list), 2)
+		Expect(diff).Should(BeEmpty())
+
+		list, err = sqlDriver.List(context.TODO(), &app, &datastore.ListOptions{FilterOptions: datastore.FilterOptions{IsNotExist: []datastore.IsNotExistQueryOption{
+			{
+				Key: "project",
+			},
+		}}})
+		Expect(err).ShouldNot(HaveOccurred())
+		diff = cmp.Diff(len(list), 3)
+		Expect(diff).Should(BeEmpty())
+
+		list, err = sqlDriver.List(context.TODO(), &app, &datastore.ListOptions{FilterOptions: datastore.FilterOptions{In: []datastore.InQueryOption{
+			{
+				Key:    "not-a-key",
+				Values: []string{"value"},
+			},
+		}}})
+		Expect(err).Should(HaveOccurred())
+		Expect(list).Should(BeNil())
+	})
+
+	It("Test list clusters with sort and fuzzy query", func() {
+		clusters, err := sqlDriver.List(context.TODO(), &model.Cluster{}, nil)
+		Expect(err).Should(Succeed())
+		for _, cluster := range clusters {
+			Expect(sqlDriver.Delete(context.TODO(), cluster)).Should(Succeed())
+		}
+		for _, name := range []string{"first", "second", "third"} {
+			Expect(sqlDriver.Add(context.TODO(), &model.Cluster{Name: name, Alias: name + "_100%"})).Should(Succeed())
+			time.Sleep(time.Millisecond * 10)
+		}
+		entities, err := sqlDriver.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+			SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderAscending}}})
+		Expect(err).Should(Succeed())
+		Expect(len(entities)).Should(Equal(3))
+		for i, name := range []string{"first", "second", "third"} {
+			Expect(entities[i].(*model.Cluster).Name).Should(Equal(name))
+		}
+		entities, err = sqlDriver.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+			SortBy:   []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+			Page:     1,
+			PageSize: 2,
+		})
+		Expect(err).Should(Succeed())
+		Expect(len(entities)).Should(Equal(2))
+		for i, name := range []string{"third", "second"} {
+			Expect(entities[i].(*model.Cluster).Name).Should(Equal(name))
+		}
+		entities, err = sqlDriver.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+			SortBy:   []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+			Page:     2,
+			PageSize: 2,
+		})
+		Expect(err).Should(Succeed())
+		Expect(len(entities)).Should(Equal(1))
+		for i, name := range []string{"first"} {
+			Expect(entities[i].(*model.Cluster).Name).Should(Equal(name))
+		}
+		entities, err = sqlDriver.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+			SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+			FilterOptions: datastore.FilterOptions{
+				Queries: []datastore.FuzzyQueryOption{{Key: "name", Query: "ir"}},
+			},
+		})
+		Expect(err).Should(Succeed())
+		Expect(len(entities)).Should(Equal(2))
+		for i, name := range []string{"third", "first"} {
+			Expect(entities[i].(*model.Cluster).Name).Should(Equal(name))
+		}
+		entities, err = sqlDriver.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+			SortBy: []datastore.SortOption{{Key: "name", Order: datastore.SortOrderDescending}},
+			FilterOptions: datastore.FilterOptions{
+				Queries: []datastore.FuzzyQueryOption{{Key: "alias", Query: "d_100%"}},
+			},
+		})
+		Expect(err).Should(Succeed())
+		Expect(len(entities)).Should(Equal(2))
+		for i, name := range []string{"third", "second"} {
+			Expect(entities[i].(*model.Cluster).Name).Should(Equal(name))
+		}
+	})
+
+	It("Test count function", func() {
+		var app model.Application
+		count, err := sqlDriver.Count(context.TODO(), &app, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(count).Should(Equal(int64(4)))
+
+		count, err = sqlDriver.Count(context.TODO(), &model.Cluster{}, &datastore.FilterOptions{
+			Queries: []datastore.FuzzyQueryOption{{Key: "name", Query: "ir"}},
+		})
+		Expect(err).Should(Succeed())
+		Expect(count).Should(Equal(int64(2)))
+
+		count, err = sqlDriver.Count(context.TODO(), &app, &datastore.FilterOptions{In: []datastore.InQueryOption{
+			{
+				Key:    "name",
+				Values: []string{"kubevela-app-3", "kubevela-app-2"},
+			},
+		}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(count).Should(Equal(int64(2)))
+
+		count, err = sqlDriver.Count(context.TODO(), &app, &datastore.FilterOptions{IsNotExist: []datastore.IsNotExistQueryOption{
+			{
+				Key: "project",
+			},
+		}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(count).Should(Equal(int64(3)))
+
+		app.Name = "kubevela-app-3"
+		count, err = sqlDriver.Count(context.TODO(), &app, &datastore.FilterOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(count).Should(Equal(int64(1)))
+
+		count, err = sqlDriver.Count(context.TODO(), &model.Workflow{EnvName: "not-indexed-yet"}, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(count).Should(Equal(int64(0)))
+	})
+
+	It("Test isExist function", func() {
+		var app model.Application
+		app.Name = "kubevela-app-3"
+		exist, err := sqlDriver.IsExist(context.TODO(), &app)
+		Expect(err).ShouldNot(HaveOccurred())
+		diff := cmp.Diff(exist, true)
+		Expect(diff).Should(BeEmpty())
+
+		app.Name = "kubevela-app-5"
+		notexist, err := sqlDriver.IsExist(context.TODO(), &app)
+		Expect(err).ShouldNot(HaveOccurred())
+		diff = cmp.Diff(notexist, false)
+		Expect(diff).Should(BeEmpty())
+	})
+
+	It("Test delete function", func() {
+		var app model.Application
+		app.Name = "kubevela-app"
+		err := sqlDriver.Delete(context.TODO(), &app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		app.Name = "kubevela-app-2"
+		err = sqlDriver.Delete(context.TODO(), &app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		app.Name = "kubevela-app-3"
+		err = sqlDriver.Delete(context.TODO(), &app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		app.Name = "kubevela-app-4"
+		err = sqlDriver.Delete(context.TODO(), &app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		app.Name = "kubevela-app-4"
+		err = sqlDriver.Delete(context.TODO(), &app)
+		equal := cmp.Equal(err, datastore.ErrRecordNotExist, cmpopts.EquateErrors())
+		Expect(equal).Should(BeTrue())
+
+		workflow := model.Workflow{Name: "kubevela-app-workflow", AppPrimaryKey: "kubevela-app-2", Description: "this is workflow"}
+		err = sqlDriver.Delete(context.TODO(), &workflow)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		trigger := model.ApplicationTrigger{Name: "kubevela-app-trigger", AppPrimaryKey: "kubevela-app-2", Token: "token-test", Description: "this is demo 4"}
+		err = sqlDriver.Delete(context.TODO(), &trigger)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("Test sql statement of dialects", func() {
+	It("Test build statement for postgres", func() {
+		stmt := &statement{dialect: dialects[TypePostgres]}
+		stmt.write("SELECT * FROM t")
+		Expect(stmt.where(map[string]string{"principal.type": "user"}, &datastore.FilterOptions{
+			Queries:    []datastore.FuzzyQueryOption{{Key: "alias", Query: "a%"}},
+			In:         []datastore.InQueryOption{{Key: "project", Values: []string{"p1", "p2"}}},
+			IsNotExist: []datastore.IsNotExistQueryOption{{Key: "owner"}},
+		}, map[string]bool{"principal_type": true, "project": true, "owner": true})).Should(Succeed())
+		Expect(stmt.orderBy([]datastore.SortOption{{Key: "updateTime", Order: datastore.SortOrderDescending}}, nil)).Should(Succeed())
+		Expect(stmt.String()).Should(Equal(`SELECT * FROM t WHERE "principal_type" = $1 AND ("_data"::jsonb #>> '{alias}') LIKE $2 ESCAPE '!' ` +
+			`AND "project" IN ($3, $4) AND ("owner" IS NULL OR "owner" = '') ORDER BY "_update_time" DESC, "_name"`))
+		Expect(stmt.args).Should(Equal([]interface{}{"user", "%a!%%", "p1", "p2"}))
+	})
+
+	It("Test build statement for mysql", func() {
+		stmt := &statement{dialect: dialects[TypeMySQL]}
+		stmt.write("SELECT * FROM t")
+		Expect(stmt.where(nil, &datastore.FilterOptions{
+			Queries: []datastore.FuzzyQueryOption{{Key: "spec.alias", Query: "a"}},
+		}, nil)).Should(Succeed())
+		Expect(stmt.orderBy([]datastore.SortOption{{Key: "name", Order: datastore.SortOrderAscending}}, nil)).Should(Succeed())
+		Expect(stmt.String()).Should(Equal("SELECT * FROM t WHERE JSON_UNQUOTE(JSON_EXTRACT(`_data`, '$.spec.alias')) LIKE ? ESCAPE '!' " +
+			"ORDER BY JSON_UNQUOTE(JSON_EXTRACT(`_data`, '$.name')), `_name`"))
+	})
+
+	It("Test invalid keys", func() {
+		stmt := &statement{dialect: dialects[TypeSQLite]}
+		Expect(stmt.where(nil, &datastore.FilterOptions{
+			Queries: []datastore.FuzzyQueryOption{{Key: "name') OR ('1", Query: "a"}},
+		}, nil)).Should(MatchError(datastore.ErrIndexInvalid))
+		Expect(stmt.orderBy([]datastore.SortOption{{Key: "name;"}}, nil)).Should(MatchError(datastore.ErrIndexInvalid))
+	})
+})
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+type apiTokenAPIInterface struct {
+	APITokenService service.APITokenService `inject:""`
+}
+
+// NewAPITokenAPIInterface is the APIInterface of the API tokens of the login user
+func NewAPITokenAPIInterface() Interface {
+	return &apiTokenAPIInterface{}
+}
+
+func (a *apiTokenAPIInterface) GetWebServiceRoute() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(versionPrefix+"/tokens").
+		Consumes(restful.MIME_XML, restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML).
+		Doc("api for the API tokens of the login user")
+
+	tags := []string{"apiToken"}
+
+	ws.Route(ws.GET("/").To(a.listAPITokens).
+		Doc("list the API tokens of the login user").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Returns(200, "OK", apis.ListAPITokenResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ListAPITokenResponse{}))
+
+	ws.Route(ws.POST("/").To(a.createAPIToken).
+		Doc("create an API token for the login user, the token is only returned once").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Reads(apis.CreateAPITokenRequest{}).
+		Returns(200, "OK", apis.CreateAPITokenResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.CreateAPITokenResponse{}))
+
+	ws.Route(ws.DELETE("/{tokenID}").To(a.deleteAPIToken).
+		Doc("revoke an API token of the login user").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("tokenID", "identifier of the API token").DataType("string")).
+		Returns(200, "OK", apis.EmptyResponse{}).
+		Returns(404, "Not Found", bcode.Bcode{}).
+		Writes(apis.EmptyResponse{}))
+
+	ws.Filter(authCheckFilter)
+	return ws
+}
+
+func (a *apiTokenAPIInterface) listAPITokens(req *restful.Request, res *restful.Response) {
+	userName, _ := req.Request.Context().Value(&apis.CtxKeyUser).(string)
+	resp, err := a.APITokenService.ListAPITokens(req.Request.Context(), userName)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (a *apiTokenAPIInterface) createAPIToken(req *restful.Request, res *restful.Response) {
+	// the API token could not be used to create the long-lived tokens of the user
+	if _, ok := req.Request.Context().Value(&apis.CtxKeyAPIToken).(*model.APIToken); ok {
+		bcode.ReturnError(req, res, bcode.ErrAPITokenNotAllowed)
+		return
+	}
+	var createReq apis.CreateAPITokenRequest
+	if err := req.ReadEntity(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	userName, _ := req.Request.Context().Value(&apis.CtxKeyUser).(string)
+	resp, err := a.APITokenService.CreateAPIToken(req.Request.Context(), userName, createReq)
+	if err != nil {
+		log.Logger.Errorf("create API token failure %s", err.Error())
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (a *apiTokenAPIInterface) deleteAPIToken(req *restful.Request, res *restful.Response) {
+	userName, _ := req.Request.Context().Value(&apis.CtxKeyUser).(string)
+	if err := a.APITokenService.DeleteAPIToken(req.Request.Context(), userName, req.PathParameter("tokenID")); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(apis.EmptyResponse{}); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"gotest.tools/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+type fakeAPITokenService struct {
+	service.APITokenService
+}
+
+func (f *fakeAPITokenService) Authenticate(ctx context.Context, token string) (*model.APIToken, error) {
+	if token != service.APITokenPrefix+"id_secret" {
+		return nil, bcode.ErrAPITokenInvalid
+	}
+	return &model.APIToken{ID: "id", Username: "ci"}, nil
+}
+
+func TestAPITokenFilter(t *testing.T) {
+	authentication := &authenticationAPIInterface{APITokenService: &fakeAPITokenService{}}
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/envs").Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/").To(func(req *restful.Request, res *restful.Response) {
+		userName, _ := req.Request.Context().Value(&apis.CtxKeyUser).(string)
+		_, _ = res.Write([]byte(userName))
+	}))
+	ws.Filter(authCheckFilter)
+	container := restful.NewContainer()
+	container.Filter(authentication.Filter)
+	container.Add(ws)
+
+	for _, c := range []struct {
+		token  string
+		status int
+		body   string
+	}{
+		{token: service.APITokenPrefix + "id_secret", status: http.StatusOK, body: "ci"},
+		{token: service.APITokenPrefix + "id_wrong", status: http.StatusUnauthorized},
+		{token: "not-a-jwt", status: http.StatusForbidden},
+		{status: http.StatusUnauthorized},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/envs", nil)
+		req.Header.Set("Accept", restful.MIME_JSON)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		res := httptest.NewRecorder()
+		container.ServeHTTP(res, req)
+		assert.Equal(t, res.Code, c.status)
+		if c.body != "" {
+			assert.Equal(t, res.Body.String(), c.body)
+		}
+	}
+}
@@ -18,7 +18,11 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
 
@@ -37,8 +41,13 @@ type applicationAPIInterface struct {
 	RbacService        service.RBACService        `inject:""`
 	ApplicationService service.ApplicationService `inject:""`
 	EnvBindingService  service.EnvBindingService  `inject:""`
+	// ApplicationEventService streams the status changes of the applications
+	ApplicationEventService service.ApplicationEventService `inject:""`
 }
 
+// eventStreamHeartbeat is the interval of the comment lines sent to keep the event stream alive
+const eventStreamHeartbeat = 30 * time.Second
+
 // NewApplicationAPIInterface new application manage APIInterface
 func NewApplicationAPIInterface() Interface {
 	return &applicationAPIInterface{}
@@ -409,6 +418,19 @@ func (c *applicationAPIInterface) GetWebServiceRoute() *restful.WebService {
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.ApplicationStatusResponse{}))
 
+	ws.Route(ws.GET("/{appName}/envs/{envName}/events").To(c.watchApplicationStatus).
+		Doc("watch the status changes of the application by the server-sent events").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Produces(utils.MIMEEventStream, restful.MIME_JSON).
+		Filter(c.RbacService.CheckPerm("envBinding", "detail")).
+		Filter(c.appCheckFilter).
+		Filter(c.envCheckFilter).
+		Param(ws.PathParameter("appName", "identifier of the application ").DataType("string")).
+		Param(ws.PathParameter("envName", "identifier of the application envbinding").DataType("string")).
+		Returns(200, "OK", apis.ApplicationEvent{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ApplicationEvent{}))
+
 	ws.Route(ws.POST("/{appName}/envs/{envName}/recycle").To(c.recycleApplicationEnv).
 		Doc("get application status").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -1116,6 +1138,57 @@ func (c *applicationAPIInterface) deleteApplicationEnv(req *restful.Request, res
 	}
 }
 
+// watchApplicationStatus streams the status changes until the client disconnects, a comment line is sent periodically
+// to keep the connection alive through the proxies
+func (c *applicationAPIInterface) watchApplicationStatus(req *restful.Request, res *restful.Response) {
+	flusher, ok := res.ResponseWriter.(http.Flusher)
+	if !ok {
+		bcode.ReturnError(req, res, fmt.Errorf("the streaming is not supported, the request must accept %s", utils.MIMEEventStream))
+		return
+	}
+	ctx := req.Request.Context()
+	app := ctx.Value(&apis.CtxKeyApplication).(*model.Application)
+	events, cancel, err := c.ApplicationEventService.Subscribe(ctx, app, req.PathParameter("envName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	defer cancel()
+
+	res.Header().Set("Content-Type", utils.MIMEEventStream)
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Logger.Errorf("failed to encode the application event: %s", err.Error())
+				continue
+			}
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
 func (c *applicationAPIInterface) appCheckFilter(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
 	app, err := c.ApplicationService.GetApplication(req.Request.Context(), req.PathParameter("appName"))
 	if err != nil {
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"gotest.tools/assert"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
+)
+
+type fakeApplicationEventService struct {
+	service.ApplicationEventService
+	events chan apis.ApplicationEvent
+}
+
+func (f *fakeApplicationEventService) Subscribe(ctx context.Context, app *model.Application, envName string) (<-chan apis.ApplicationEvent, func(), error) {
+	return f.events, func() {}, nil
+}
+
+func TestWatchApplicationStatus(t *testing.T) {
+	events := make(chan apis.ApplicationEvent, 2)
+	events <- apis.ApplicationEvent{Type: apis.ApplicationEventStatus, Status: &common.AppStatus{Phase: common.ApplicationRunning}}
+	events <- apis.ApplicationEvent{Type: apis.ApplicationEventPhase, Phase: common.ApplicationRunningWorkflow}
+	close(events)
+	application := &applicationAPIInterface{ApplicationEventService: &fakeApplicationEventService{events: events}}
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/applications").Produces(utils.MIMEEventStream, restful.MIME_JSON)
+	ws.Route(ws.GET("/{appName}/envs/{envName}/events").Filter(func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+		ctx := context.WithValue(req.Request.Context(), &apis.CtxKeyApplication, &model.Application{Name: req.PathParameter("appName")})
+		req.Request = req.Request.WithContext(ctx)
+		chain.ProcessFilter(req, res)
+	}).To(application.watchApplicationStatus))
+	container := restful.NewContainer()
+	container.Add(ws)
+	server := httptest.NewServer(container)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/applications/web/envs/prod/events", nil)
+	assert.NilError(t, err)
+	req.Header.Set("Accept", utils.MIMEEventStream)
+	res, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer func() { _ = res.Body.Close() }()
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, res.Header.Get("Content-Type"), utils.MIMEEventStream)
+
+	var received []apis.ApplicationEvent
+	scanner := bufio.NewScanner(res.Body)
+	eventType := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var event apis.ApplicationEvent
+			assert.NilError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+			assert.Equal(t, event.Type, eventType)
+			received = append(received, event)
+		}
+	}
+	assert.Equal(t, len(received), 2)
+	assert.Equal(t, received[0].Status.Phase, common.ApplicationRunning)
+	assert.Equal(t, received[1].Phase, common.ApplicationRunningWorkflow)
+}
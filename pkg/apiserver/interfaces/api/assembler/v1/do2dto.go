@@ -40,6 +40,12 @@ func ConvertEnvBindingModelToBase(envBinding *model.EnvBinding, env *model.Env,
 					Namespace:   dt.Cluster.Namespace,
 				}
 			}
+			if dt.ClusterGroup != nil {
+				ebt.ClusterGroup = &apisv1.ClusterGroupTarget{
+					GroupName: dt.ClusterGroup.GroupName,
+					Namespace: dt.ClusterGroup.Namespace,
+				}
+			}
 			envBindingTargets = append(envBindingTargets, ebt)
 		}
 	}
@@ -60,15 +66,16 @@ func ConvertEnvBindingModelToBase(envBinding *model.EnvBinding, env *model.Env,
 // ConvertAppModelToBase assemble the Application model to DTO
 func ConvertAppModelToBase(app *model.Application, projects []*apisv1.ProjectBase) *apisv1.ApplicationBase {
 	appBase := &apisv1.ApplicationBase{
-		Name:        app.Name,
-		Alias:       app.Alias,
-		CreateTime:  app.CreateTime,
-		UpdateTime:  app.UpdateTime,
-		Description: app.Description,
-		Icon:        app.Icon,
-		Labels:      app.Labels,
-		Project:     &apisv1.ProjectBase{Name: app.Project},
-		ReadOnly:    app.IsReadOnly(),
+		Name:            app.Name,
+		Alias:           app.Alias,
+		CreateTime:      app.CreateTime,
+		UpdateTime:      app.UpdateTime,
+		ResourceVersion: app.ResourceVersion,
+		Description:     app.Description,
+		Icon:            app.Icon,
+		Labels:          app.Labels,
+		Project:         &apisv1.ProjectBase{Name: app.Project},
+		ReadOnly:        app.IsReadOnly(),
 	}
 
 	for _, project := range projects {
@@ -85,19 +92,20 @@ func ConvertComponentModelToBase(componentModel *model.ApplicationComponent) *ap
 		return nil
 	}
 	return &apisv1.ComponentBase{
-		Name:          componentModel.Name,
-		Alias:         componentModel.Alias,
-		Description:   componentModel.Description,
-		Labels:        componentModel.Labels,
-		ComponentType: componentModel.Type,
-		Icon:          componentModel.Icon,
-		DependsOn:     componentModel.DependsOn,
-		Inputs:        componentModel.Inputs,
-		Outputs:       componentModel.Outputs,
-		Creator:       componentModel.Creator,
-		Main:          componentModel.Main,
-		CreateTime:    componentModel.CreateTime,
-		UpdateTime:    componentModel.UpdateTime,
+		Name:            componentModel.Name,
+		Alias:           componentModel.Alias,
+		Description:     componentModel.Description,
+		Labels:          componentModel.Labels,
+		ComponentType:   componentModel.Type,
+		Icon:            componentModel.Icon,
+		DependsOn:       componentModel.DependsOn,
+		Inputs:          componentModel.Inputs,
+		Outputs:         componentModel.Outputs,
+		Creator:         componentModel.Creator,
+		Main:            componentModel.Main,
+		CreateTime:      componentModel.CreateTime,
+		UpdateTime:      componentModel.UpdateTime,
+		ResourceVersion: componentModel.ResourceVersion,
 		Traits: func() (traits []*apisv1.ApplicationTrait) {
 			for _, trait := range componentModel.Traits {
 				traits = append(traits, &apisv1.ApplicationTrait{
@@ -174,29 +182,31 @@ func ConvertWorkflowBase(workflow *model.Workflow) apisv1.WorkflowBase {
 		steps = append(steps, ConvertFromWorkflowStepModel(step))
 	}
 	return apisv1.WorkflowBase{
-		Name:        workflow.Name,
-		Alias:       workflow.Alias,
-		Description: workflow.Description,
-		Default:     convertBool(workflow.Default),
-		EnvName:     workflow.EnvName,
-		CreateTime:  workflow.CreateTime,
-		UpdateTime:  workflow.UpdateTime,
-		Steps:       steps,
+		Name:            workflow.Name,
+		Alias:           workflow.Alias,
+		Description:     workflow.Description,
+		Default:         convertBool(workflow.Default),
+		EnvName:         workflow.EnvName,
+		CreateTime:      workflow.CreateTime,
+		UpdateTime:      workflow.UpdateTime,
+		ResourceVersion: workflow.ResourceVersion,
+		Steps:           steps,
 	}
 }
 
 // ConvertPolicyModelToBase assemble the ApplicationPolicy model to DTO
 func ConvertPolicyModelToBase(policy *model.ApplicationPolicy) *apisv1.PolicyBase {
 	pb := &apisv1.PolicyBase{
-		Name:        policy.Name,
-		Alias:       policy.Alias,
-		Type:        policy.Type,
-		Properties:  policy.Properties,
-		Description: policy.Description,
-		Creator:     policy.Creator,
-		CreateTime:  policy.CreateTime,
-		UpdateTime:  policy.UpdateTime,
-		EnvName:     policy.EnvName,
+		Name:            policy.Name,
+		Alias:           policy.Alias,
+		Type:            policy.Type,
+		Properties:      policy.Properties,
+		Description:     policy.Description,
+		Creator:         policy.Creator,
+		CreateTime:      policy.CreateTime,
+		UpdateTime:      policy.UpdateTime,
+		ResourceVersion: policy.ResourceVersion,
+		EnvName:         policy.EnvName,
 	}
 	return pb
 }
@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
+)
+
+// auditActions the actions of the mutating http methods
+var auditActions = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "update",
+	http.MethodDelete: "delete",
+}
+
+// auditSensitiveBody is the route metadata key marking the request body carries passwords, the digest of the body is
+// not recorded in the audit log as the unsalted digest of a weak password can be guessed
+const auditSensitiveBody = "auditSensitiveBody"
+
+// auditMaskedPathParameters the path parameters carrying secrets, they are masked in the path of the audit log
+var auditMaskedPathParameters = map[string]bool{
+	"token": true,
+}
+
+type auditAPIInterface struct {
+	AuditService service.AuditService `inject:""`
+	RbacService  service.RBACService  `inject:""`
+}
+
+// NewAuditAPIInterface is the APIInterface of the audit log
+func NewAuditAPIInterface() Interface {
+	return &auditAPIInterface{}
+}
+
+func (a *auditAPIInterface) GetWebServiceRoute() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(versionPrefix+"/audit_logs").
+		Consumes(restful.MIME_XML, restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML).
+		Doc("api for audit log manage")
+
+	tags := []string{"auditLog"}
+
+	ws.Route(ws.GET("/").To(a.listAuditLogs).
+		Doc("list the audit logs, the latest is the first").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(a.RbacService.CheckPerm("auditLog", "list")).
+		Param(ws.QueryParameter("page", "query the page number").DataType("integer")).
+		Param(ws.QueryParameter("pageSize", "query the page size number").DataType("integer")).
+		Param(ws.QueryParameter("username", "filter the audit logs by the username").DataType("string")).
+		Param(ws.QueryParameter("project", "filter the audit logs by the project").DataType("string")).
+		Param(ws.QueryParameter("action", "filter the audit logs by the action, support create, update and delete").DataType("string")).
+		Param(ws.QueryParameter("method", "filter the audit logs by the http method").DataType("string")).
+		Returns(200, "OK", apis.ListAuditLogResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ListAuditLogResponse{}))
+
+	ws.Filter(authCheckFilter)
+	return ws
+}
+
+func (a *auditAPIInterface) listAuditLogs(req *restful.Request, res *restful.Response) {
+	page, pageSize, err := utils.ExtractPagingParams(req, minPageSize, maxPageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	resp, err := a.AuditService.ListAuditLogs(req.Request.Context(), page, pageSize, apis.ListAuditLogOptions{
+		Username: req.QueryParameter("username"),
+		Project:  req.QueryParameter("project"),
+		Action:   req.QueryParameter("action"),
+		Method:   req.QueryParameter("method"),
+	})
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+// Filter records the audit log of the mutating requests, it should be added to the container
+// so that the requests of all web services are audited.
+func (a *auditAPIInterface) Filter(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+	action, mutating := auditActions[req.Request.Method]
+	if !mutating || req.SelectedRoute() == nil {
+		chain.ProcessFilter(req, res)
+		return
+	}
+	var digest string
+	if sensitive, _ := req.SelectedRoute().Metadata()[auditSensitiveBody].(bool); !sensitive {
+		var err error
+		if digest, err = digestRequestBody(req.Request); err != nil {
+			bcode.ReturnError(req, res, err)
+			return
+		}
+	}
+	recorder := &statusRecorder{ResponseWriter: res.ResponseWriter}
+	res.ResponseWriter = recorder
+	chain.ProcessFilter(req, res)
+
+	auditLog := newAuditLog(req, action, digest, recorder.StatusCode())
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := a.AuditService.RecordAuditLog(ctx, auditLog); err != nil {
+			log.Logger.Errorf("failed to record the audit log of %s %s: %s", auditLog.Method, pkgUtils.Sanitize(auditLog.Path), err.Error())
+		}
+	}()
+}
+
+// newAuditLog builds the audit log after the request is handled, the username and the project
+// are set in the request context by the authentication and the permission check filters.
+func newAuditLog(req *restful.Request, action, digest string, statusCode int) *model.AuditLog {
+	ctx := req.Request.Context()
+	username, _ := utils.UsernameFrom(ctx)
+	if username == "" {
+		switch user := ctx.Value(&apis.CtxKeyUser).(type) {
+		case string:
+			username = user
+		case *model.User:
+			username = user.Name
+		}
+	}
+	project, _ := utils.ProjectFrom(ctx)
+	if project == "" {
+		project = req.PathParameter("projectName")
+	}
+	route := req.SelectedRoute()
+	return &model.AuditLog{
+		Username:   username,
+		Project:    project,
+		Action:     action,
+		Operation:  route.Operation(),
+		Resource:   route.Path(),
+		Path:       maskPathParameters(route.Path(), req.Request.URL.Path),
+		Method:     req.Request.Method,
+		BodyDigest: digest,
+		StatusCode: statusCode,
+		ClientIP:   utils.ClientIP(req.Request),
+	}
+}
+
+// maskPathParameters replaces the secret path parameters in the request path by matching it with the route path
+func maskPathParameters(routePath, requestPath string) string {
+	routeSegments := strings.Split(strings.Trim(routePath, "/"), "/")
+	requestSegments := strings.Split(strings.Trim(requestPath, "/"), "/")
+	for i, segment := range routeSegments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		name := strings.SplitN(strings.Trim(segment, "{}"), ":", 2)[0]
+		if !auditMaskedPathParameters[name] {
+			continue
+		}
+		// the route with the secret path parameter in the wildcard is recorded without the actual path
+		if i >= len(requestSegments) || len(routeSegments) != len(requestSegments) {
+			return routePath
+		}
+		requestSegments[i] = "***"
+	}
+	return "/" + strings.Join(requestSegments, "/")
+}
+
+// digestRequestBody returns the sha256 digest of the request body and restores the body for the handlers
+func digestRequestBody(r *http.Request) (string, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) == 0 {
+		return "", nil
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// statusRecorder records the status code of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it to the response writer
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if s.status == 0 {
+		s.status = statusCode
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write writes the data to the response writer, the status code is 200 if it is not written before
+func (s *statusRecorder) Write(data []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(data)
+}
+
+// StatusCode returns the status code of the response
+func (s *statusRecorder) StatusCode() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"gotest.tools/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
+)
+
+type fakeAuditService struct {
+	records chan *model.AuditLog
+}
+
+func (f *fakeAuditService) RecordAuditLog(ctx context.Context, auditLog *model.AuditLog) error {
+	f.records <- auditLog
+	return nil
+}
+
+func (f *fakeAuditService) ListAuditLogs(ctx context.Context, page, pageSize int, options apis.ListAuditLogOptions) (*apis.ListAuditLogResponse, error) {
+	return &apis.ListAuditLogResponse{}, nil
+}
+
+func (f *fakeAuditService) CleanAuditLogs(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestAuditFilter(t *testing.T) {
+	auditService := &fakeAuditService{records: make(chan *model.AuditLog, 1)}
+	audit := &auditAPIInterface{AuditService: auditService}
+
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/projects")
+	ws.Route(ws.PUT("/{projectName}").To(func(req *restful.Request, res *restful.Response) {
+		body, err := io.ReadAll(req.Request.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, string(body), `{"alias":"Default"}`)
+		res.WriteHeader(http.StatusAccepted)
+	}).Operation("updateProject").Filter(func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+		utils.SetUsernameAndProjectInRequestContext(req, "admin", req.PathParameter("projectName"))
+		chain.ProcessFilter(req, res)
+	}))
+	ws.Route(ws.GET("/{projectName}").To(func(req *restful.Request, res *restful.Response) {
+		res.WriteHeader(http.StatusOK)
+	}))
+	ws.Route(ws.POST("/{projectName}/webhook/{token}").To(func(req *restful.Request, res *restful.Response) {
+		res.WriteHeader(http.StatusOK)
+	}).Metadata(auditSensitiveBody, true))
+	container := restful.NewContainer()
+	container.Filter(audit.Filter)
+	container.Add(ws)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/default", nil)
+	container.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/projects/default", strings.NewReader(`{"alias":"Default"}`))
+	req.Header.Set("Content-Type", restful.MIME_JSON)
+	req.RemoteAddr = "10.0.0.1:12345"
+	res := httptest.NewRecorder()
+	container.ServeHTTP(res, req)
+	assert.Equal(t, res.Code, http.StatusAccepted)
+
+	select {
+	case auditLog := <-auditService.records:
+		assert.Equal(t, auditLog.Username, "admin")
+		assert.Equal(t, auditLog.Project, "default")
+		assert.Equal(t, auditLog.Action, "update")
+		assert.Equal(t, auditLog.Method, http.MethodPut)
+		assert.Equal(t, auditLog.Operation, "updateProject")
+		assert.Equal(t, auditLog.Resource, "/api/v1/projects/{projectName}")
+		assert.Equal(t, auditLog.Path, "/api/v1/projects/default")
+		assert.Equal(t, auditLog.BodyDigest, "ce01c5e2b4776ffdf1891c9bf424abac0f760de34c04ce5466cbcc580854cbf2")
+		assert.Equal(t, auditLog.StatusCode, http.StatusAccepted)
+		assert.Equal(t, auditLog.ClientIP, "10.0.0.1")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the audit log is not recorded")
+	}
+	assert.Equal(t, len(auditService.records), 0)
+
+	// the secret path parameters are masked and the sensitive body is not digested
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/projects/default/webhook/secret-token", strings.NewReader(`{"password":"password"}`))
+	req.Header.Set("Content-Type", restful.MIME_JSON)
+	container.ServeHTTP(httptest.NewRecorder(), req)
+	select {
+	case auditLog := <-auditService.records:
+		assert.Equal(t, auditLog.Resource, "/api/v1/projects/{projectName}/webhook/{token}")
+		assert.Equal(t, auditLog.Path, "/api/v1/projects/default/webhook/***")
+		assert.Equal(t, auditLog.BodyDigest, "")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the audit log is not recorded")
+	}
+}
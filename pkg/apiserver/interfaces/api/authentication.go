@@ -23,14 +23,17 @@ import (
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 
+	"github.com/oam-dev/kubevela/pkg/apiserver/domain/model"
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
 	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
 )
 
 type authenticationAPIInterface struct {
 	AuthenticationService service.AuthenticationService `inject:""`
 	UserService           service.UserService           `inject:""`
+	APITokenService       service.APITokenService       `inject:""`
 }
 
 // NewAuthenticationAPIInterface is the APIInterface of authentication
@@ -50,6 +53,7 @@ func (c *authenticationAPIInterface) GetWebServiceRoute() *restful.WebService {
 	ws.Route(ws.POST("/login").To(c.login).
 		Doc("handle login request").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Metadata(auditSensitiveBody, true).
 		Reads(apis.LoginRequest{}).
 		Returns(200, "", apis.LoginResponse{}).
 		Returns(400, "", bcode.Bcode{}).
@@ -87,25 +91,15 @@ func (c *authenticationAPIInterface) GetWebServiceRoute() *restful.WebService {
 }
 
 func authCheckFilter(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
-	// support getting the token from the cookie
-	var tokenValue string
-	tokenHeader := req.HeaderParameter("Authorization")
-	if tokenHeader != "" {
-		splitted := strings.Split(tokenHeader, " ")
-		if len(splitted) != 2 {
-			bcode.ReturnError(req, res, bcode.ErrNotAuthorized)
-			return
-		}
-		tokenValue = splitted[1]
+	// the request has been authenticated with an API token by the container filter
+	if _, ok := req.Request.Context().Value(&apis.CtxKeyAPIToken).(*model.APIToken); ok {
+		chain.ProcessFilter(req, res)
+		return
 	}
+	tokenValue := utils.TokenFromRequest(req)
 	if tokenValue == "" {
-		if strings.HasPrefix(req.Request.URL.Path, "/view") {
-			tokenValue = req.QueryParameter("token")
-		}
-		if tokenValue == "" {
-			bcode.ReturnError(req, res, bcode.ErrNotAuthorized)
-			return
-		}
+		bcode.ReturnError(req, res, bcode.ErrNotAuthorized)
+		return
 	}
 
 	token, err := service.ParseToken(tokenValue)
@@ -123,6 +117,25 @@ func authCheckFilter(req *restful.Request, res *restful.Response, chain *restful
 	chain.ProcessFilter(req, res)
 }
 
+// Filter authenticates the requests carrying the API tokens, it should be added to the container so that
+// the API tokens are accepted by all web services. The other requests are authenticated by authCheckFilter.
+func (c *authenticationAPIInterface) Filter(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+	tokenValue := utils.TokenFromRequest(req)
+	if !strings.HasPrefix(tokenValue, service.APITokenPrefix) {
+		chain.ProcessFilter(req, res)
+		return
+	}
+	token, err := c.APITokenService.Authenticate(req.Request.Context(), tokenValue)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	ctx := context.WithValue(req.Request.Context(), &apis.CtxKeyUser, token.Username)
+	ctx = context.WithValue(ctx, &apis.CtxKeyAPIToken, token)
+	req.Request = req.Request.WithContext(ctx)
+	chain.ProcessFilter(req, res)
+}
+
 func (c *authenticationAPIInterface) login(req *restful.Request, res *restful.Response) {
 	var loginReq apis.LoginRequest
 	if err := req.ReadEntity(&loginReq); err != nil {
@@ -166,6 +166,51 @@ func (c *ClusterAPIInterface) GetWebServiceRoute() *restful.WebService {
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.CreateCloudClusterResponse{}))
 
+	ws.Route(ws.GET("/cluster_groups").To(c.listClusterGroups).
+		Doc("list cluster groups").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(c.RbacService.CheckPerm("cluster", "list")).
+		Returns(200, "OK", apis.ListClusterGroupResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ListClusterGroupResponse{}))
+
+	ws.Route(ws.POST("/cluster_groups").To(c.createClusterGroup).
+		Doc("create cluster group").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(c.RbacService.CheckPerm("cluster", "create")).
+		Reads(apis.CreateClusterGroupRequest{}).
+		Returns(200, "OK", apis.ClusterGroupBase{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ClusterGroupBase{}))
+
+	ws.Route(ws.GET("/cluster_groups/{groupName}").To(c.getClusterGroup).
+		Doc("detail cluster group").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(c.RbacService.CheckPerm("cluster", "detail")).
+		Param(ws.PathParameter("groupName", "identifier of the cluster group").DataType("string")).
+		Returns(200, "OK", apis.ClusterGroupBase{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ClusterGroupBase{}))
+
+	ws.Route(ws.PUT("/cluster_groups/{groupName}").To(c.updateClusterGroup).
+		Doc("update the members of cluster group").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(c.RbacService.CheckPerm("cluster", "update")).
+		Param(ws.PathParameter("groupName", "identifier of the cluster group").DataType("string")).
+		Reads(apis.UpdateClusterGroupRequest{}).
+		Returns(200, "OK", apis.ClusterGroupBase{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ClusterGroupBase{}))
+
+	ws.Route(ws.DELETE("/cluster_groups/{groupName}").To(c.deleteClusterGroup).
+		Doc("delete cluster group").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(c.RbacService.CheckPerm("cluster", "delete")).
+		Param(ws.PathParameter("groupName", "identifier of the cluster group").DataType("string")).
+		Returns(200, "OK", apis.ClusterGroupBase{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ClusterGroupBase{}))
+
 	ws.Filter(authCheckFilter)
 	return ws
 }
@@ -447,3 +492,87 @@ func (c *ClusterAPIInterface) deleteCloudClusterCreation(req *restful.Request, r
 		return
 	}
 }
+
+func (c *ClusterAPIInterface) listClusterGroups(req *restful.Request, res *restful.Response) {
+	resp, err := c.ClusterService.ListClusterGroups(req.Request.Context())
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (c *ClusterAPIInterface) createClusterGroup(req *restful.Request, res *restful.Response) {
+	// Verify the validity of parameters
+	var createReq apis.CreateClusterGroupRequest
+	if err := req.ReadEntity(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+
+	// Call the domain layer code
+	resp, err := c.ClusterService.CreateClusterGroup(req.Request.Context(), createReq)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+
+	// Write back response data
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (c *ClusterAPIInterface) getClusterGroup(req *restful.Request, res *restful.Response) {
+	resp, err := c.ClusterService.GetClusterGroup(req.Request.Context(), req.PathParameter("groupName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (c *ClusterAPIInterface) updateClusterGroup(req *restful.Request, res *restful.Response) {
+	// Verify the validity of parameters
+	var updateReq apis.UpdateClusterGroupRequest
+	if err := req.ReadEntity(&updateReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+
+	// Call the domain layer code
+	resp, err := c.ClusterService.UpdateClusterGroup(req.Request.Context(), req.PathParameter("groupName"), updateReq)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+
+	// Write back response data
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (c *ClusterAPIInterface) deleteClusterGroup(req *restful.Request, res *restful.Response) {
+	resp, err := c.ClusterService.DeleteClusterGroup(req.Request.Context(), req.PathParameter("groupName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
@@ -25,6 +25,7 @@ import (
 	registryv1 "github.com/google/go-containerregistry/pkg/v1"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/addon"
@@ -48,6 +49,8 @@ var (
 	CtxKeyUser = "user"
 	// CtxKeyToken request context key of request token
 	CtxKeyToken = "token"
+	// CtxKeyAPIToken request context key of the API token, it is set if the request is authenticated by an API token
+	CtxKeyAPIToken = "api-token"
 )
 
 // AddonPhase defines the phase of an addon
@@ -315,6 +318,32 @@ type ListCloudClusterCreationResponse struct {
 	Creations []CreateCloudClusterResponse `json:"creations"`
 }
 
+// CreateClusterGroupRequest request parameters to create a cluster group
+type CreateClusterGroupRequest struct {
+	Name                 string            `json:"name" validate:"checkname"`
+	Clusters             []string          `json:"clusters,omitempty" optional:"true"`
+	ClusterLabelSelector map[string]string `json:"clusterLabelSelector,omitempty" optional:"true"`
+}
+
+// UpdateClusterGroupRequest request parameters to update the members of a cluster group
+type UpdateClusterGroupRequest struct {
+	Clusters             []string          `json:"clusters,omitempty" optional:"true"`
+	ClusterLabelSelector map[string]string `json:"clusterLabelSelector,omitempty" optional:"true"`
+}
+
+// ClusterGroupBase cluster group base model, the members are the clusters currently in the group
+type ClusterGroupBase struct {
+	Name                 string            `json:"name"`
+	Clusters             []string          `json:"clusters,omitempty"`
+	ClusterLabelSelector map[string]string `json:"clusterLabelSelector,omitempty"`
+	Members              []string          `json:"members"`
+}
+
+// ListClusterGroupResponse list cluster groups
+type ListClusterGroupResponse struct {
+	Groups []ClusterGroupBase `json:"groups"`
+}
+
 // ClusterBase cluster base model
 type ClusterBase struct {
 	Name        string            `json:"name"`
@@ -349,15 +378,16 @@ type EnvBindingList []*EnvBinding
 
 // ApplicationBase application base model
 type ApplicationBase struct {
-	Name        string            `json:"name"`
-	Alias       string            `json:"alias"`
-	Project     *ProjectBase      `json:"project"`
-	Description string            `json:"description"`
-	CreateTime  time.Time         `json:"createTime"`
-	UpdateTime  time.Time         `json:"updateTime"`
-	Icon        string            `json:"icon"`
-	Labels      map[string]string `json:"labels,omitempty"`
-	ReadOnly    bool              `json:"readOnly,omitempty"`
+	Name            string            `json:"name"`
+	Alias           string            `json:"alias"`
+	Project         *ProjectBase      `json:"project"`
+	Description     string            `json:"description"`
+	CreateTime      time.Time         `json:"createTime"`
+	UpdateTime      time.Time         `json:"updateTime"`
+	ResourceVersion int64             `json:"resourceVersion"`
+	Icon            string            `json:"icon"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	ReadOnly        bool              `json:"readOnly,omitempty"`
 }
 
 // AppCompareResponse application compare result
@@ -418,6 +448,30 @@ type ApplicationStatusResponse struct {
 	Status  *common.AppStatus `json:"status"`
 }
 
+const (
+	// ApplicationEventStatus is the first event of the stream, it contains the current status of the application
+	ApplicationEventStatus = "status"
+	// ApplicationEventPhase means the phase of the application is changed
+	ApplicationEventPhase = "phase"
+	// ApplicationEventWorkflowStep means the phase of a workflow step is changed
+	ApplicationEventWorkflowStep = "workflowStep"
+	// ApplicationEventComponentHealth means the health of a component is changed
+	ApplicationEventComponentHealth = "componentHealth"
+	// ApplicationEventDeleted means the application is deleted from the environment
+	ApplicationEventDeleted = "deleted"
+)
+
+// ApplicationEvent the status change of the application in an environment, it is streamed as a server-sent event
+type ApplicationEvent struct {
+	Type          string                             `json:"type"`
+	Time          time.Time                          `json:"time"`
+	Status        *common.AppStatus                  `json:"status,omitempty"`
+	Phase         common.ApplicationPhase            `json:"phase,omitempty"`
+	PreviousPhase common.ApplicationPhase            `json:"previousPhase,omitempty"`
+	WorkflowStep  *common.StepStatus                 `json:"workflowStep,omitempty"`
+	Component     *common.ApplicationComponentStatus `json:"component,omitempty"`
+}
+
 // ApplicationStatisticsResponse application statistics response body
 type ApplicationStatisticsResponse struct {
 	EnvCount      int64 `json:"envCount"`
@@ -454,6 +508,8 @@ type UpdateApplicationRequest struct {
 	Description string            `json:"description" optional:"true"`
 	Icon        string            `json:"icon" optional:"true"`
 	Labels      map[string]string `json:"labels,omitempty"`
+	// ResourceVersion is the version of the resource this update is based on, the update is rejected if it has changed
+	ResourceVersion int64 `json:"resourceVersion,omitempty" optional:"true"`
 }
 
 // CreateApplicationTriggerRequest create application trigger
@@ -465,6 +521,9 @@ type CreateApplicationTriggerRequest struct {
 	Type          string `json:"type" validate:"oneof=webhook"`
 	PayloadType   string `json:"payloadType" validate:"checkpayloadtype"`
 	ComponentName string `json:"componentName,omitempty" optional:"true"`
+	// Secret validates the signature of the github, gitlab and gitea webhooks
+	Secret   string                 `json:"secret,omitempty" optional:"true"`
+	GitRules []model.GitTriggerRule `json:"gitRules,omitempty" optional:"true"`
 }
 
 // ApplicationTriggerBase application trigger base model
@@ -479,6 +538,10 @@ type ApplicationTriggerBase struct {
 	ComponentName string    `json:"componentName,omitempty"`
 	CreateTime    time.Time `json:"createTime"`
 	UpdateTime    time.Time `json:"updateTime"`
+
+	// HasSecret means the signature of the webhook requests is validated
+	HasSecret bool                   `json:"hasSecret,omitempty"`
+	GitRules  []model.GitTriggerRule `json:"gitRules,omitempty"`
 }
 
 // ListApplicationTriggerResponse list application triggers response body
@@ -581,6 +644,52 @@ type DockerHubRepository struct {
 	Status          string `json:"status"`
 }
 
+// HandleApplicationTriggerGitPushRequest is the push event of the github, gitlab and gitea webhooks
+type HandleApplicationTriggerGitPushRequest struct {
+	Ref    string `json:"ref"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+	// CheckoutSHA is only set by gitlab
+	CheckoutSHA string `json:"checkout_sha,omitempty"`
+	// Deleted is only set by github
+	Deleted    bool                   `json:"deleted,omitempty"`
+	Repository GitWebhookRepository   `json:"repository"`
+	Project    *GitWebhookRepository  `json:"project,omitempty"`
+	HeadCommit *GitWebhookCommit      `json:"head_commit,omitempty"`
+	Commits    []GitWebhookCommit     `json:"commits,omitempty"`
+	Pusher     GitWebhookUser         `json:"pusher"`
+	UserName   string                 `json:"user_name,omitempty"`
+	Payload    map[string]interface{} `json:"-"`
+}
+
+// GitWebhookRepository is the repository of the git push event
+type GitWebhookRepository struct {
+	Name              string `json:"name"`
+	FullName          string `json:"full_name,omitempty"`
+	PathWithNamespace string `json:"path_with_namespace,omitempty"`
+}
+
+// GitWebhookCommit is the commit of the git push event
+type GitWebhookCommit struct {
+	ID      string         `json:"id"`
+	Message string         `json:"message"`
+	Author  GitWebhookUser `json:"author"`
+}
+
+// GitWebhookUser is the user of the git push event
+type GitWebhookUser struct {
+	Name     string `json:"name,omitempty"`
+	Login    string `json:"login,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// ApplicationGitWebhookResponse is the response of the git webhooks which do not deploy the application
+type ApplicationGitWebhookResponse struct {
+	State       string `json:"state"`
+	Description string `json:"description,omitempty"`
+}
+
 // HandleApplicationTriggerJFrogRequest application trigger JFrog webhook request
 type HandleApplicationTriggerJFrogRequest struct {
 	Domain    string           `json:"domain"`
@@ -608,7 +717,8 @@ type EnvBinding struct {
 // EnvBindingTarget the target struct in the envbinding base struct
 type EnvBindingTarget struct {
 	NameAlias
-	Cluster *ClusterTarget `json:"cluster,omitempty"`
+	Cluster      *ClusterTarget      `json:"cluster,omitempty"`
+	ClusterGroup *ClusterGroupTarget `json:"clusterGroup,omitempty"`
 }
 
 // EnvBindingBase application env binding
@@ -658,21 +768,22 @@ type ApplicationResourceInfo struct {
 
 // ComponentBase component  base model
 type ComponentBase struct {
-	Name          string                        `json:"name"`
-	Alias         string                        `json:"alias"`
-	Description   string                        `json:"description"`
-	Labels        map[string]string             `json:"labels,omitempty"`
-	ComponentType string                        `json:"componentType"`
-	Main          bool                          `json:"main"`
-	Icon          string                        `json:"icon,omitempty"`
-	DependsOn     []string                      `json:"dependsOn"`
-	Creator       string                        `json:"creator,omitempty"`
-	CreateTime    time.Time                     `json:"createTime"`
-	UpdateTime    time.Time                     `json:"updateTime"`
-	Inputs        common.StepInputs             `json:"inputs,omitempty"`
-	Outputs       common.StepOutputs            `json:"outputs,omitempty"`
-	Traits        []*ApplicationTrait           `json:"traits"`
-	WorkloadType  common.WorkloadTypeDescriptor `json:"workloadType,omitempty"`
+	Name            string                        `json:"name"`
+	Alias           string                        `json:"alias"`
+	Description     string                        `json:"description"`
+	Labels          map[string]string             `json:"labels,omitempty"`
+	ComponentType   string                        `json:"componentType"`
+	Main            bool                          `json:"main"`
+	Icon            string                        `json:"icon,omitempty"`
+	DependsOn       []string                      `json:"dependsOn"`
+	Creator         string                        `json:"creator,omitempty"`
+	CreateTime      time.Time                     `json:"createTime"`
+	UpdateTime      time.Time                     `json:"updateTime"`
+	ResourceVersion int64                         `json:"resourceVersion"`
+	Inputs          common.StepInputs             `json:"inputs,omitempty"`
+	Outputs         common.StepOutputs            `json:"outputs,omitempty"`
+	Traits          []*ApplicationTrait           `json:"traits"`
+	WorkloadType    common.WorkloadTypeDescriptor `json:"workloadType,omitempty"`
 }
 
 // ComponentListResponse list component
@@ -703,6 +814,8 @@ type UpdateApplicationComponentRequest struct {
 	Labels      *map[string]string `json:"labels,omitempty"`
 	Properties  *string            `json:"properties,omitempty"`
 	DependsOn   *[]string          `json:"dependsOn" optional:"true"`
+	// ResourceVersion is the version of the resource this update is based on, the update is rejected if it has changed
+	ResourceVersion int64 `json:"resourceVersion,omitempty" optional:"true"`
 }
 
 // DetailComponentResponse detail component response body
@@ -748,12 +861,13 @@ type ListProjectResponse struct {
 
 // ProjectBase project base model
 type ProjectBase struct {
-	Name        string    `json:"name"`
-	Alias       string    `json:"alias"`
-	Description string    `json:"description"`
-	CreateTime  time.Time `json:"createTime"`
-	UpdateTime  time.Time `json:"updateTime"`
-	Owner       NameAlias `json:"owner,omitempty"`
+	Name            string    `json:"name"`
+	Alias           string    `json:"alias"`
+	Description     string    `json:"description"`
+	CreateTime      time.Time `json:"createTime"`
+	UpdateTime      time.Time `json:"updateTime"`
+	ResourceVersion int64     `json:"resourceVersion"`
+	Owner           NameAlias `json:"owner,omitempty"`
 }
 
 // CreateProjectRequest create project request body
@@ -769,6 +883,8 @@ type UpdateProjectRequest struct {
 	Alias       string `json:"alias" validate:"checkalias" optional:"true"`
 	Description string `json:"description" optional:"true"`
 	Owner       string `json:"owner" optional:"true"`
+	// ResourceVersion is the version of the resource this update is based on, the update is rejected if it has changed
+	ResourceVersion int64 `json:"resourceVersion,omitempty" optional:"true"`
 }
 
 // Env models the data of env in API
@@ -786,8 +902,9 @@ type Env struct {
 	// In one project, a delivery target can only belong to one env.
 	Targets []NameAlias `json:"targets,omitempty"  optional:"true"`
 
-	CreateTime time.Time `json:"createTime"`
-	UpdateTime time.Time `json:"updateTime"`
+	CreateTime      time.Time `json:"createTime"`
+	UpdateTime      time.Time `json:"updateTime"`
+	ResourceVersion int64     `json:"resourceVersion"`
 }
 
 // ListEnvOptions list envs by query options
@@ -827,6 +944,8 @@ type UpdateEnvRequest struct {
 	// Targets defines the name of delivery target that belongs to this env
 	// In one project, a delivery target can only belong to one env.
 	Targets []string `json:"targets,omitempty"  optional:"true"`
+	// ResourceVersion is the version of the resource this update is based on, the update is rejected if it has changed
+	ResourceVersion int64 `json:"resourceVersion,omitempty" optional:"true"`
 }
 
 // ListDefinitionResponse list definition response model
@@ -905,6 +1024,8 @@ type UpdatePolicyRequest struct {
 
 	// Bind this policy to workflow
 	WorkflowPolicyBindings []WorkflowPolicyBinding `json:"workflowPolicyBind"`
+	// ResourceVersion is the version of the resource this update is based on, the update is rejected if it has changed
+	ResourceVersion int64 `json:"resourceVersion,omitempty" optional:"true"`
 }
 
 // PolicyBase application policy base info
@@ -916,10 +1037,11 @@ type PolicyBase struct {
 	Description string `json:"description"`
 	Creator     string `json:"creator"`
 	// Properties json data
-	Properties *model.JSONStruct `json:"properties"`
-	CreateTime time.Time         `json:"createTime"`
-	UpdateTime time.Time         `json:"updateTime"`
-	EnvName    string            `json:"envName"`
+	Properties      *model.JSONStruct `json:"properties"`
+	CreateTime      time.Time         `json:"createTime"`
+	UpdateTime      time.Time         `json:"updateTime"`
+	ResourceVersion int64             `json:"resourceVersion"`
+	EnvName         string            `json:"envName"`
 }
 
 // DetailPolicyResponse app policy detail model
@@ -962,6 +1084,8 @@ type UpdateWorkflowRequest struct {
 	Description string         `json:"description" optional:"true"`
 	Steps       []WorkflowStep `json:"steps,omitempty"`
 	Default     *bool          `json:"default"`
+	// ResourceVersion is the version of the resource this update is based on, the update is rejected if it has changed
+	ResourceVersion int64 `json:"resourceVersion,omitempty" optional:"true"`
 }
 
 // WorkflowStep workflow step config
@@ -989,15 +1113,16 @@ type ListWorkflowResponse struct {
 
 // WorkflowBase workflow base model
 type WorkflowBase struct {
-	Name        string         `json:"name"`
-	Alias       string         `json:"alias"`
-	Description string         `json:"description"`
-	Enable      bool           `json:"enable"`
-	Default     bool           `json:"default"`
-	EnvName     string         `json:"envName"`
-	CreateTime  time.Time      `json:"createTime"`
-	UpdateTime  time.Time      `json:"updateTime"`
-	Steps       []WorkflowStep `json:"steps,omitempty"`
+	Name            string         `json:"name"`
+	Alias           string         `json:"alias"`
+	Description     string         `json:"description"`
+	Enable          bool           `json:"enable"`
+	Default         bool           `json:"default"`
+	EnvName         string         `json:"envName"`
+	CreateTime      time.Time      `json:"createTime"`
+	UpdateTime      time.Time      `json:"updateTime"`
+	ResourceVersion int64          `json:"resourceVersion"`
+	Steps           []WorkflowStep `json:"steps,omitempty"`
 }
 
 // ListWorkflowRecordsResponse list workflow execution record
@@ -1110,12 +1235,13 @@ type ApplicationTrait struct {
 
 // CreateTargetRequest  create delivery target request body
 type CreateTargetRequest struct {
-	Name        string                 `json:"name" validate:"checkname"`
-	Alias       string                 `json:"alias,omitempty" validate:"checkalias" optional:"true"`
-	Project     string                 `json:"project" validate:"checkname"`
-	Description string                 `json:"description,omitempty" optional:"true"`
-	Cluster     *ClusterTarget         `json:"cluster,omitempty"`
-	Variable    map[string]interface{} `json:"variable,omitempty"`
+	Name         string                 `json:"name" validate:"checkname"`
+	Alias        string                 `json:"alias,omitempty" validate:"checkalias" optional:"true"`
+	Project      string                 `json:"project" validate:"checkname"`
+	Description  string                 `json:"description,omitempty" optional:"true"`
+	Cluster      *ClusterTarget         `json:"cluster,omitempty"`
+	ClusterGroup *ClusterGroupTarget    `json:"clusterGroup,omitempty"`
+	Variable     map[string]interface{} `json:"variable,omitempty"`
 }
 
 // UpdateTargetRequest only support full quantity update
@@ -1123,6 +1249,8 @@ type UpdateTargetRequest struct {
 	Alias       string                 `json:"alias,omitempty" validate:"checkalias" optional:"true"`
 	Description string                 `json:"description,omitempty" optional:"true"`
 	Variable    map[string]interface{} `json:"variable,omitempty"`
+	// ResourceVersion is the version of the resource this update is based on, the update is rejected if it has changed
+	ResourceVersion int64 `json:"resourceVersion,omitempty" optional:"true"`
 }
 
 // ClusterTarget kubernetes delivery target
@@ -1131,6 +1259,12 @@ type ClusterTarget struct {
 	Namespace   string `json:"namespace" optional:"true"`
 }
 
+// ClusterGroupTarget the clusters in one cluster group delivery target
+type ClusterGroupTarget struct {
+	GroupName string `json:"groupName" validate:"checkname"`
+	Namespace string `json:"namespace" optional:"true"`
+}
+
 // DetailTargetResponse detail Target response
 type DetailTargetResponse struct {
 	TargetBase
@@ -1144,16 +1278,18 @@ type ListTargetResponse struct {
 
 // TargetBase Target base model
 type TargetBase struct {
-	Name         string                 `json:"name"`
-	Alias        string                 `json:"alias,omitempty" validate:"checkalias" optional:"true"`
-	Description  string                 `json:"description,omitempty" optional:"true"`
-	Cluster      *ClusterTarget         `json:"cluster,omitempty"`
-	ClusterAlias string                 `json:"clusterAlias,omitempty"`
-	Variable     map[string]interface{} `json:"variable,omitempty"`
-	CreateTime   time.Time              `json:"createTime"`
-	UpdateTime   time.Time              `json:"updateTime"`
-	AppNum       int64                  `json:"appNum,omitempty"`
-	Project      NameAlias              `json:"project"`
+	Name            string                 `json:"name"`
+	Alias           string                 `json:"alias,omitempty" validate:"checkalias" optional:"true"`
+	Description     string                 `json:"description,omitempty" optional:"true"`
+	Cluster         *ClusterTarget         `json:"cluster,omitempty"`
+	ClusterGroup    *ClusterGroupTarget    `json:"clusterGroup,omitempty"`
+	ClusterAlias    string                 `json:"clusterAlias,omitempty"`
+	Variable        map[string]interface{} `json:"variable,omitempty"`
+	CreateTime      time.Time              `json:"createTime"`
+	UpdateTime      time.Time              `json:"updateTime"`
+	ResourceVersion int64                  `json:"resourceVersion"`
+	AppNum          int64                  `json:"appNum,omitempty"`
+	Project         NameAlias              `json:"project"`
 }
 
 // ApplicationRevisionBase application revision base spec
@@ -1196,10 +1332,12 @@ type SystemInfoResponse struct {
 type SystemInfo struct {
 	PlatformID                  string             `json:"platformID"`
 	EnableCollection            bool               `json:"enableCollection"`
-	LoginType                   string             `json:"loginType" validate:"oneof=dex local"`
+	LoginType                   string             `json:"loginType" validate:"oneof=dex local ldap"`
 	InstallTime                 time.Time          `json:"installTime,omitempty"`
 	DexUserDefaultProjects      []model.ProjectRef `json:"dexUserDefaultProjects,omitempty"`
 	DexUserDefaultPlatformRoles []string           `json:"dexUserDefaultPlatformRoles,omitempty"`
+	// LDAP the config of the LDAP login, the bind password is never returned
+	LDAP *model.LDAPConfig `json:"ldap,omitempty"`
 }
 
 // StatisticInfo generated by cronJob running in backend
@@ -1220,6 +1358,8 @@ type SystemInfoRequest struct {
 	LoginType              string             `json:"loginType"`
 	VelaAddress            string             `json:"velaAddress,omitempty"`
 	DexUserDefaultProjects []model.ProjectRef `json:"dexUserDefaultProjects,omitempty"`
+	// LDAP the config of the LDAP login, the saved bind password is kept if it is empty
+	LDAP *model.LDAPConfig `json:"ldap,omitempty"`
 }
 
 // SystemVersion contains KubeVela version
@@ -1288,6 +1428,20 @@ type ListProjectUsersResponse struct {
 	Total int64              `json:"total"`
 }
 
+// ProjectQuota the quota restricting the applications in the project and the current usage
+type ProjectQuota struct {
+	Name         string                           `json:"name"`
+	Application  *v1alpha1.ApplicationQuotaTarget `json:"application,omitempty"`
+	Hard         v1alpha1.ApplicationQuotaHard    `json:"hard"`
+	Used         v1alpha1.ApplicationQuotaUsage   `json:"used"`
+	Applications []v1alpha1.ApplicationUsage      `json:"applications,omitempty"`
+}
+
+// ListProjectQuotasResponse the response body that list quotas of a project
+type ListProjectQuotasResponse struct {
+	Quotas []*ProjectQuota `json:"quotas"`
+}
+
 // CreateUserRequest create user request
 type CreateUserRequest struct {
 	Name     string   `json:"name" validate:"checkname"`
@@ -1319,6 +1473,8 @@ type UserBase struct {
 	Email         string    `json:"email"`
 	Alias         string    `json:"alias,omitempty"`
 	Disabled      bool      `json:"disabled"`
+	// Source is where the user comes from, one of local, dex and ldap
+	Source string `json:"source,omitempty"`
 }
 
 // ListUserOptions list user options
@@ -1458,3 +1614,165 @@ type CloudShellPrepareResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 }
+
+// AuditLogBase the audit log of a mutating operation
+type AuditLogBase struct {
+	ID         string    `json:"id"`
+	Username   string    `json:"username"`
+	Project    string    `json:"project,omitempty"`
+	Action     string    `json:"action"`
+	Operation  string    `json:"operation,omitempty"`
+	Resource   string    `json:"resource"`
+	Path       string    `json:"path"`
+	Method     string    `json:"method"`
+	BodyDigest string    `json:"bodyDigest,omitempty"`
+	StatusCode int       `json:"statusCode"`
+	ClientIP   string    `json:"clientIP,omitempty"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// ListAuditLogOptions list audit log options
+type ListAuditLogOptions struct {
+	Username string `json:"username"`
+	Project  string `json:"project"`
+	Action   string `json:"action"`
+	Method   string `json:"method"`
+}
+
+// ListAuditLogResponse the response of listing the audit logs
+type ListAuditLogResponse struct {
+	AuditLogs []*AuditLogBase `json:"auditLogs"`
+	Total     int64           `json:"total"`
+}
+
+// CreateAPITokenRequest the request body of creating an API token
+type CreateAPITokenRequest struct {
+	Name string `json:"name" validate:"checkname"`
+	// Scopes are the names of the permissions that the token could use, the token could use all permissions
+	// of the owner if it is empty
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpireTime the token never expires if it is empty
+	ExpireTime *time.Time `json:"expireTime,omitempty"`
+}
+
+// APITokenBase the API token info, the token is only returned when it is created
+type APITokenBase struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Username     string     `json:"username"`
+	Project      string     `json:"project,omitempty"`
+	Scopes       []string   `json:"scopes,omitempty"`
+	ExpireTime   *time.Time `json:"expireTime,omitempty"`
+	LastUsedTime *time.Time `json:"lastUsedTime,omitempty"`
+	CreateTime   time.Time  `json:"createTime"`
+}
+
+// CreateAPITokenResponse the response of creating an API token
+type CreateAPITokenResponse struct {
+	APITokenBase
+	Token string `json:"token"`
+}
+
+// ListAPITokenResponse the response of listing the API tokens
+type ListAPITokenResponse struct {
+	Tokens []*APITokenBase `json:"tokens"`
+}
+
+// CreateServiceAccountRequest the request body of creating a service account
+type CreateServiceAccountRequest struct {
+	Name  string `json:"name" validate:"checkname"`
+	Alias string `json:"alias,omitempty" validate:"checkalias"`
+	// UserRoles the project level roles of the service account
+	UserRoles []string `json:"userRoles"`
+}
+
+// ServiceAccountBase the service account info
+type ServiceAccountBase struct {
+	Name       string    `json:"name"`
+	Alias      string    `json:"alias,omitempty"`
+	Project    string    `json:"project"`
+	UserRoles  []string  `json:"userRoles"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// ListServiceAccountResponse the response of listing the service accounts
+type ListServiceAccountResponse struct {
+	ServiceAccounts []*ServiceAccountBase `json:"serviceAccounts"`
+}
+
+// CreateOutboundWebhookRequest the request body of creating an outbound webhook
+type CreateOutboundWebhookRequest struct {
+	Name        string   `json:"name" validate:"checkname"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url" validate:"required"`
+	Events      []string `json:"events" validate:"required,min=1"`
+	// Secret is the key of the HMAC signature, a random secret is generated if it is empty
+	Secret string `json:"secret,omitempty"`
+}
+
+// UpdateOutboundWebhookRequest the request body of updating an outbound webhook
+type UpdateOutboundWebhookRequest struct {
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url" validate:"required"`
+	Events      []string `json:"events" validate:"required,min=1"`
+	// Secret the secret is not changed if it is empty
+	Secret   string `json:"secret,omitempty"`
+	Disabled bool   `json:"disabled"`
+}
+
+// OutboundWebhookBase the outbound webhook info, the secret is only returned when it is created
+type OutboundWebhookBase struct {
+	Name        string    `json:"name"`
+	Project     string    `json:"project"`
+	Description string    `json:"description,omitempty"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Disabled    bool      `json:"disabled"`
+	CreateTime  time.Time `json:"createTime"`
+	UpdateTime  time.Time `json:"updateTime"`
+}
+
+// CreateOutboundWebhookResponse the response of creating an outbound webhook
+type CreateOutboundWebhookResponse struct {
+	OutboundWebhookBase
+	Secret string `json:"secret"`
+}
+
+// ListOutboundWebhookResponse the response of listing the outbound webhooks
+type ListOutboundWebhookResponse struct {
+	Webhooks []*OutboundWebhookBase `json:"webhooks"`
+}
+
+// WebhookDeliveryBase the delivery of an event to an outbound webhook
+type WebhookDeliveryBase struct {
+	ID              string    `json:"id"`
+	Event           string    `json:"event"`
+	Payload         string    `json:"payload"`
+	Status          string    `json:"status"`
+	Attempts        int       `json:"attempts"`
+	NextAttemptTime time.Time `json:"nextAttemptTime,omitempty"`
+	StatusCode      int       `json:"statusCode,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	CreateTime      time.Time `json:"createTime"`
+	UpdateTime      time.Time `json:"updateTime"`
+}
+
+// ListWebhookDeliveryResponse the response of listing the deliveries of an outbound webhook
+type ListWebhookDeliveryResponse struct {
+	Deliveries []*WebhookDeliveryBase `json:"deliveries"`
+	Total      int64                  `json:"total"`
+}
+
+// WebhookEvent the payload sent to the outbound webhooks
+type WebhookEvent struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Project     string    `json:"project,omitempty"`
+	Time        time.Time `json:"time"`
+	Application string    `json:"application,omitempty"`
+	Workflow    string    `json:"workflow,omitempty"`
+	Record      string    `json:"record,omitempty"`
+	Step        string    `json:"step,omitempty"`
+	Addon       string    `json:"addon,omitempty"`
+	Message     string    `json:"message,omitempty"`
+}
@@ -35,6 +35,11 @@ type Interface interface {
 	GetWebServiceRoute() *restful.WebService
 }
 
+// FilterInterface the API that provides a filter for the requests of all web services
+type FilterInterface interface {
+	Filter(req *restful.Request, res *restful.Response, chain *restful.FilterChain)
+}
+
 var registeredAPIInterface []Interface
 
 // RegisterAPIInterface register APIInterface
@@ -85,11 +90,15 @@ func InitAPIBean() []interface{} {
 	// Authentication
 	RegisterAPIInterface(NewAuthenticationAPIInterface())
 	RegisterAPIInterface(NewUserAPIInterface())
+	RegisterAPIInterface(NewAPITokenAPIInterface())
 	RegisterAPIInterface(NewSystemInfoAPIInterface())
 	RegisterAPIInterface(NewCloudShellView())
 
 	// RBAC
 	RegisterAPIInterface(NewRBACAPIInterface())
+
+	// Audit
+	RegisterAPIInterface(NewAuditAPIInterface())
 	var beans []interface{}
 	for i := range registeredAPIInterface {
 		beans = append(beans, registeredAPIInterface[i])
@@ -28,9 +28,12 @@ import (
 )
 
 type projectAPIInterface struct {
-	RbacService    service.RBACService    `inject:""`
-	ProjectService service.ProjectService `inject:""`
-	TargetService  service.TargetService  `inject:""`
+	RbacService     service.RBACService     `inject:""`
+	ProjectService  service.ProjectService  `inject:""`
+	TargetService   service.TargetService   `inject:""`
+	APITokenService service.APITokenService `inject:""`
+	// OutboundWebhookService manages the outbound webhooks of the projects
+	OutboundWebhookService service.OutboundWebhookService `inject:""`
 }
 
 // NewProjectAPIInterface new project APIInterface
@@ -132,6 +135,110 @@ func (n *projectAPIInterface) GetWebServiceRoute() *restful.WebService {
 		Returns(200, "OK", apis.EmptyResponse{}).
 		Writes(apis.EmptyResponse{}))
 
+	ws.Route(ws.GET("/{projectName}/service_accounts").To(n.listServiceAccounts).
+		Doc("list all service accounts of a project").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/serviceAccount", "list")).
+		Returns(200, "OK", apis.ListServiceAccountResponse{}).
+		Writes(apis.ListServiceAccountResponse{}))
+
+	ws.Route(ws.POST("/{projectName}/service_accounts").To(n.createServiceAccount).
+		Doc("create a service account in a project").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/serviceAccount", "create")).
+		Reads(apis.CreateServiceAccountRequest{}).
+		Returns(200, "OK", apis.ServiceAccountBase{}).
+		Writes(apis.ServiceAccountBase{}))
+
+	ws.Route(ws.DELETE("/{projectName}/service_accounts/{serviceAccountName}").To(n.deleteServiceAccount).
+		Doc("delete a service account and revoke all its API tokens").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Param(ws.PathParameter("serviceAccountName", "identifier of the service account").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/serviceAccount", "delete")).
+		Returns(200, "OK", apis.EmptyResponse{}).
+		Writes(apis.EmptyResponse{}))
+
+	ws.Route(ws.GET("/{projectName}/service_accounts/{serviceAccountName}/tokens").To(n.listServiceAccountTokens).
+		Doc("list the API tokens of a service account").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Param(ws.PathParameter("serviceAccountName", "identifier of the service account").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/serviceAccount/token", "list")).
+		Returns(200, "OK", apis.ListAPITokenResponse{}).
+		Writes(apis.ListAPITokenResponse{}))
+
+	ws.Route(ws.POST("/{projectName}/service_accounts/{serviceAccountName}/tokens").To(n.createServiceAccountToken).
+		Doc("create an API token for a service account, the token is only returned once").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Param(ws.PathParameter("serviceAccountName", "identifier of the service account").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/serviceAccount/token", "create")).
+		Reads(apis.CreateAPITokenRequest{}).
+		Returns(200, "OK", apis.CreateAPITokenResponse{}).
+		Writes(apis.CreateAPITokenResponse{}))
+
+	ws.Route(ws.DELETE("/{projectName}/service_accounts/{serviceAccountName}/tokens/{tokenID}").To(n.deleteServiceAccountToken).
+		Doc("revoke an API token of a service account").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Param(ws.PathParameter("serviceAccountName", "identifier of the service account").DataType("string")).
+		Param(ws.PathParameter("tokenID", "identifier of the API token").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/serviceAccount/token", "delete")).
+		Returns(200, "OK", apis.EmptyResponse{}).
+		Writes(apis.EmptyResponse{}))
+
+	ws.Route(ws.GET("/{projectName}/outbound_webhooks").To(n.listOutboundWebhooks).
+		Doc("list the outbound webhooks of a project").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/outboundWebhook", "list")).
+		Returns(200, "OK", apis.ListOutboundWebhookResponse{}).
+		Writes(apis.ListOutboundWebhookResponse{}))
+
+	ws.Route(ws.POST("/{projectName}/outbound_webhooks").To(n.createOutboundWebhook).
+		Doc("create an outbound webhook, the secret is only returned once").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/outboundWebhook", "create")).
+		Reads(apis.CreateOutboundWebhookRequest{}).
+		Returns(200, "OK", apis.CreateOutboundWebhookResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.CreateOutboundWebhookResponse{}))
+
+	ws.Route(ws.PUT("/{projectName}/outbound_webhooks/{webhookName}").To(n.updateOutboundWebhook).
+		Doc("update an outbound webhook").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Param(ws.PathParameter("webhookName", "identifier of the outbound webhook").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/outboundWebhook", "update")).
+		Reads(apis.UpdateOutboundWebhookRequest{}).
+		Returns(200, "OK", apis.OutboundWebhookBase{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.OutboundWebhookBase{}))
+
+	ws.Route(ws.DELETE("/{projectName}/outbound_webhooks/{webhookName}").To(n.deleteOutboundWebhook).
+		Doc("delete an outbound webhook and its deliveries").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Param(ws.PathParameter("webhookName", "identifier of the outbound webhook").DataType("string")).
+		Filter(n.RbacService.CheckPerm("project/outboundWebhook", "delete")).
+		Returns(200, "OK", apis.EmptyResponse{}).
+		Writes(apis.EmptyResponse{}))
+
+	ws.Route(ws.GET("/{projectName}/outbound_webhooks/{webhookName}/deliveries").To(n.listWebhookDeliveries).
+		Doc("list the deliveries of an outbound webhook").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Param(ws.PathParameter("webhookName", "identifier of the outbound webhook").DataType("string")).
+		Param(ws.QueryParameter("page", "query the page number").DataType("integer")).
+		Param(ws.QueryParameter("pageSize", "query the page size number").DataType("integer")).
+		Filter(n.RbacService.CheckPerm("project/outboundWebhook/delivery", "list")).
+		Returns(200, "OK", apis.ListWebhookDeliveryResponse{}).
+		Writes(apis.ListWebhookDeliveryResponse{}))
+
 	ws.Route(ws.GET("/{projectName}/roles").To(n.listProjectRoles).
 		Doc("list all project level roles").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -203,6 +310,15 @@ func (n *projectAPIInterface) GetWebServiceRoute() *restful.WebService {
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes([]*apis.Config{}))
 
+	ws.Route(ws.GET("/{projectName}/quotas").To(n.listProjectQuotas).
+		Doc("list the quotas of a project with the current usage").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(n.RbacService.CheckPerm("project/quota", "list")).
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Returns(200, "OK", apis.ListProjectQuotasResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ListProjectQuotasResponse{}))
+
 	ws.Filter(authCheckFilter)
 	return ws
 }
@@ -589,3 +705,211 @@ func (n *projectAPIInterface) getConfigs(req *restful.Request, res *restful.Resp
 		return
 	}
 }
+
+func (n *projectAPIInterface) listProjectQuotas(req *restful.Request, res *restful.Response) {
+	quotas, err := n.ProjectService.ListProjectQuotas(req.Request.Context(), req.PathParameter("projectName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(quotas); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) listServiceAccounts(req *restful.Request, res *restful.Response) {
+	serviceAccounts, err := n.APITokenService.ListServiceAccounts(req.Request.Context(), req.PathParameter("projectName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(serviceAccounts); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) createServiceAccount(req *restful.Request, res *restful.Response) {
+	var createReq apis.CreateServiceAccountRequest
+	if err := req.ReadEntity(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if len(createReq.UserRoles) == 0 {
+		bcode.ReturnError(req, res, bcode.ErrProjectRoleCheckFailure)
+		return
+	}
+	serviceAccount, err := n.APITokenService.CreateServiceAccount(req.Request.Context(), req.PathParameter("projectName"), createReq)
+	if err != nil {
+		log.Logger.Errorf("create service account failure %s", err.Error())
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(serviceAccount); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) deleteServiceAccount(req *restful.Request, res *restful.Response) {
+	err := n.APITokenService.DeleteServiceAccount(req.Request.Context(), req.PathParameter("projectName"), req.PathParameter("serviceAccountName"))
+	if err != nil {
+		log.Logger.Errorf("delete service account failure %s", err.Error())
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(apis.EmptyResponse{}); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) listServiceAccountTokens(req *restful.Request, res *restful.Response) {
+	serviceAccount, err := n.APITokenService.GetServiceAccount(req.Request.Context(), req.PathParameter("projectName"), req.PathParameter("serviceAccountName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	tokens, err := n.APITokenService.ListAPITokens(req.Request.Context(), serviceAccount.Name)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(tokens); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) createServiceAccountToken(req *restful.Request, res *restful.Response) {
+	var createReq apis.CreateAPITokenRequest
+	if err := req.ReadEntity(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	serviceAccount, err := n.APITokenService.GetServiceAccount(req.Request.Context(), req.PathParameter("projectName"), req.PathParameter("serviceAccountName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	token, err := n.APITokenService.CreateAPIToken(req.Request.Context(), serviceAccount.Name, createReq)
+	if err != nil {
+		log.Logger.Errorf("create service account token failure %s", err.Error())
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(token); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) deleteServiceAccountToken(req *restful.Request, res *restful.Response) {
+	serviceAccount, err := n.APITokenService.GetServiceAccount(req.Request.Context(), req.PathParameter("projectName"), req.PathParameter("serviceAccountName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := n.APITokenService.DeleteAPIToken(req.Request.Context(), serviceAccount.Name, req.PathParameter("tokenID")); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(apis.EmptyResponse{}); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) listOutboundWebhooks(req *restful.Request, res *restful.Response) {
+	webhooks, err := n.OutboundWebhookService.ListOutboundWebhooks(req.Request.Context(), req.PathParameter("projectName"))
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(webhooks); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) createOutboundWebhook(req *restful.Request, res *restful.Response) {
+	var createReq apis.CreateOutboundWebhookRequest
+	if err := req.ReadEntity(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	webhook, err := n.OutboundWebhookService.CreateOutboundWebhook(req.Request.Context(), req.PathParameter("projectName"), createReq)
+	if err != nil {
+		log.Logger.Errorf("create outbound webhook failure %s", err.Error())
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(webhook); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) updateOutboundWebhook(req *restful.Request, res *restful.Response) {
+	var updateReq apis.UpdateOutboundWebhookRequest
+	if err := req.ReadEntity(&updateReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&updateReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	webhook, err := n.OutboundWebhookService.UpdateOutboundWebhook(req.Request.Context(), req.PathParameter("projectName"), req.PathParameter("webhookName"), updateReq)
+	if err != nil {
+		log.Logger.Errorf("update outbound webhook failure %s", err.Error())
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(webhook); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) deleteOutboundWebhook(req *restful.Request, res *restful.Response) {
+	if err := n.OutboundWebhookService.DeleteOutboundWebhook(req.Request.Context(), req.PathParameter("projectName"), req.PathParameter("webhookName")); err != nil {
+		log.Logger.Errorf("delete outbound webhook failure %s", err.Error())
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(apis.EmptyResponse{}); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *projectAPIInterface) listWebhookDeliveries(req *restful.Request, res *restful.Response) {
+	page, pageSize, err := utils.ExtractPagingParams(req, minPageSize, maxPageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	deliveries, err := n.OutboundWebhookService.ListWebhookDeliveries(req.Request.Context(), req.PathParameter("projectName"), req.PathParameter("webhookName"), page, pageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(deliveries); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
@@ -65,6 +65,7 @@ func (c *userAPIInterface) GetWebServiceRoute() *restful.WebService {
 		Doc("create a user").
 		Filter(c.RbacService.CheckPerm("user", "create")).
 		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Metadata(auditSensitiveBody, true).
 		Reads(apis.CreateUserRequest{}).
 		Returns(200, "OK", apis.UserBase{}).
 		Returns(400, "Bad Request", bcode.Bcode{}).
@@ -82,6 +83,7 @@ func (c *userAPIInterface) GetWebServiceRoute() *restful.WebService {
 	ws.Route(ws.PUT("/{username}").To(c.updateUser).
 		Doc("update a user's alias or password").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Metadata(auditSensitiveBody, true).
 		Filter(c.RbacService.CheckPerm("user", "update")).
 		Filter(c.userCheckFilter).
 		Returns(200, "OK", apis.UserBase{}).
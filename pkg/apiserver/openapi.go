@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	restfulSpec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/log"
+)
+
+// OpenAPIV3Path is the path serving the OpenAPI 3 document of the apiserver APIs
+const OpenAPIV3Path = "/openapi.json"
+
+// BuildOpenAPIV3 builds the OpenAPI 3 document of the registered web services, it is converted from the swagger document
+func BuildOpenAPIV3(config restfulSpec.Config) (*openapi3.T, error) {
+	raw, err := json.Marshal(restfulSpec.BuildSwagger(config))
+	if err != nil {
+		return nil, err
+	}
+	var swagger openapi2.T
+	if err := json.Unmarshal(raw, &swagger); err != nil {
+		return nil, err
+	}
+	return openapi2conv.ToV3(&swagger)
+}
+
+// newOpenAPIV3Service serves the OpenAPI 3 document, the document is built once at the first request
+func newOpenAPIV3Service(config restfulSpec.Config) *restful.WebService {
+	var once sync.Once
+	var doc []byte
+	var buildErr error
+	ws := new(restful.WebService)
+	ws.Path(OpenAPIV3Path).Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("").To(func(req *restful.Request, res *restful.Response) {
+		once.Do(func() {
+			var spec *openapi3.T
+			spec, buildErr = BuildOpenAPIV3(config)
+			if buildErr == nil {
+				doc, buildErr = json.Marshal(spec)
+			}
+			if buildErr != nil {
+				log.Logger.Errorf("failed to build the openapi v3 document %s", buildErr.Error())
+			}
+		})
+		if buildErr != nil {
+			bcode.ReturnError(req, res, bcode.ErrServer)
+			return
+		}
+		res.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+		res.WriteHeader(http.StatusOK)
+		if _, err := res.Write(doc); err != nil {
+			log.Logger.Errorf("failed to write the openapi v3 document %s", err.Error())
+		}
+	}))
+	return ws
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	restfulSpec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+func TestBuildOpenAPIV3(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/projects").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/{projectName}").To(func(req *restful.Request, res *restful.Response) {}).
+		Operation("detailProject").
+		Param(ws.PathParameter("projectName", "identifier of the project").DataType("string")).
+		Returns(200, "OK", apis.ProjectBase{}).
+		Writes(apis.ProjectBase{}))
+	ws.Route(ws.POST("/").To(func(req *restful.Request, res *restful.Response) {}).
+		Operation("createProject").
+		Reads(apis.CreateProjectRequest{}).
+		Returns(200, "OK", apis.ProjectBase{}).
+		Writes(apis.ProjectBase{}))
+	config := restfulSpec.Config{WebServices: []*restful.WebService{ws}, PostBuildSwaggerObjectHandler: enrichSwaggerObject}
+
+	doc, err := BuildOpenAPIV3(config)
+	assert.NoError(t, err)
+	assert.NoError(t, doc.Validate(context.Background()))
+	assert.Equal(t, "Kubevela api doc", doc.Info.Title)
+	detail := doc.Paths.Find("/api/v1/projects/{projectName}")
+	require.NotNil(t, detail)
+	assert.Equal(t, "detailProject", detail.Get.OperationID)
+	create := doc.Paths.Find("/api/v1/projects")
+	require.NotNil(t, create)
+	assert.NotNil(t, create.Post.RequestBody.Value.Content.Get(restful.MIME_JSON))
+	assert.Contains(t, doc.Components.Schemas, "v1.CreateProjectRequest")
+
+	container := restful.NewContainer()
+	container.Add(newOpenAPIV3Service(config))
+	server := httptest.NewServer(container)
+	defer server.Close()
+	res, err := http.Get(server.URL + OpenAPIV3Path)
+	assert.NoError(t, err)
+	defer func() { _ = res.Body.Close() }()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var served openapi3.T
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&served))
+	assert.Equal(t, "3.0.3", served.OpenAPI)
+	assert.NotNil(t, served.Paths.Find("/api/v1/projects/{projectName}"))
+}
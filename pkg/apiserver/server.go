@@ -38,6 +38,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore"
 	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/kubeapi"
 	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/mongodb"
+	"github.com/oam-dev/kubevela/pkg/apiserver/infrastructure/datastore/sqldb"
 	"github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/container"
@@ -70,6 +71,33 @@ func New(cfg config.Config) (a APIServer) {
 	return s
 }
 
+// NewDataStore creates the datastore instance with the config, the kube config should be set before creating the
+// kubeapi datastore.
+func NewDataStore(ctx context.Context, cfg datastore.Config) (datastore.DataStore, error) {
+	switch cfg.Type {
+	case "mongodb":
+		ds, err := mongodb.New(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("create mongodb datastore instance failure %w", err)
+		}
+		return ds, nil
+	case "kubeapi":
+		ds, err := kubeapi.New(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("create kubeapi datastore instance failure %w", err)
+		}
+		return ds, nil
+	case sqldb.TypePostgres, sqldb.TypeMySQL, sqldb.TypeSQLite:
+		ds, err := sqldb.New(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("create %s datastore instance failure %w", cfg.Type, err)
+		}
+		return ds, nil
+	default:
+		return nil, fmt.Errorf("not support datastore type %s", cfg.Type)
+	}
+}
+
 func (s *restServer) buildIoCContainer() error {
 	// infrastructure
 
@@ -85,20 +113,9 @@ func (s *restServer) buildIoCContainer() error {
 	if err != nil {
 		return err
 	}
-	var ds datastore.DataStore
-	switch s.cfg.Datastore.Type {
-	case "mongodb":
-		ds, err = mongodb.New(context.Background(), s.cfg.Datastore)
-		if err != nil {
-			return fmt.Errorf("create mongodb datastore instance failure %w", err)
-		}
-	case "kubeapi":
-		ds, err = kubeapi.New(context.Background(), s.cfg.Datastore)
-		if err != nil {
-			return fmt.Errorf("create kubeapi datastore instance failure %w", err)
-		}
-	default:
-		return fmt.Errorf("not support datastore type %s", s.cfg.Datastore.Type)
+	ds, err := NewDataStore(context.Background(), s.cfg.Datastore)
+	if err != nil {
+		return err
 	}
 	s.dataStore = ds
 	if err := s.beanContainer.ProvideWithName("datastore", s.dataStore); err != nil {
@@ -151,6 +168,9 @@ func (s *restServer) Run(ctx context.Context, errChan chan error) error {
 
 	s.RegisterAPIRoute()
 
+	// the subscribers of the application events connect to any replica
+	event.StartReplicaWorker(ctx, errChan)
+
 	l, err := s.setupLeaderElection(errChan)
 	if err != nil {
 		return err
@@ -227,6 +247,13 @@ func (s *restServer) RegisterAPIRoute() restfulSpec.Config {
 	// Add request log
 	s.webContainer.Filter(s.requestLog)
 
+	// Add the container filters provided by the APIs, such as the audit log
+	for _, handler := range api.GetRegisteredAPIInterface() {
+		if filter, ok := handler.(api.FilterInterface); ok {
+			s.webContainer.Filter(filter.Filter)
+		}
+	}
+
 	// Register all custom api
 	for _, handler := range api.GetRegisteredAPIInterface() {
 		s.webContainer.Add(handler.GetWebServiceRoute())
@@ -237,11 +264,13 @@ func (s *restServer) RegisterAPIRoute() restfulSpec.Config {
 		APIPath:                       "/apidocs.json",
 		PostBuildSwaggerObjectHandler: enrichSwaggerObject}
 	s.webContainer.Add(restfulSpec.NewOpenAPIService(config))
+	s.webContainer.Add(newOpenAPIV3Service(config))
 	return config
 }
 
 func (s *restServer) requestLog(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
-	if req.HeaderParameter("Upgrade") == "websocket" && req.HeaderParameter("Connection") == "Upgrade" {
+	// the long-lived connections are not captured, the response writer of them must support the streaming
+	if (req.HeaderParameter("Upgrade") == "websocket" && req.HeaderParameter("Connection") == "Upgrade") || utils.AcceptEventStream(req.Request) {
 		chain.ProcessFilter(req, resp)
 		return
 	}
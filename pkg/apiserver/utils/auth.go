@@ -18,6 +18,7 @@ package utils
 
 import (
 	"context"
+	"strings"
 
 	"github.com/emicklei/go-restful/v3"
 	"k8s.io/apiserver/pkg/authentication/user"
@@ -56,6 +57,22 @@ func ContextWithUserInfo(ctx context.Context) context.Context {
 	return request.WithUser(ctx, userInfo)
 }
 
+// TokenFromRequest extract the bearer token from the Authorization header, the token of the view pages could be
+// carried by the query parameter. The JWT tokens and the API tokens are both carried in this way.
+func TokenFromRequest(req *restful.Request) string {
+	if tokenHeader := req.HeaderParameter("Authorization"); tokenHeader != "" {
+		splitted := strings.Split(tokenHeader, " ")
+		if len(splitted) != 2 {
+			return ""
+		}
+		return splitted[1]
+	}
+	if strings.HasPrefix(req.Request.URL.Path, "/view") {
+		return req.QueryParameter("token")
+	}
+	return ""
+}
+
 // SetUsernameAndProjectInRequestContext .
 func SetUsernameAndProjectInRequestContext(req *restful.Request, userName string, projectName string) {
 	ctx := req.Request.Context()
@@ -99,3 +99,9 @@ var ErrApplicationPolicyIsBeingUsed = NewBcode(400, 10026, "the policy is being
 
 // ErrApplicationDryRunFailed means the application configuration does not dry run successfully
 var ErrApplicationDryRunFailed = NewBcode(400, 10027, "The application dry run failed")
+
+// ErrInvalidWebhookSignature means the signature of the webhook request is invalid
+var ErrInvalidWebhookSignature = NewBcode(401, 10028, "Invalid webhook signature")
+
+// ErrInvalidGitTriggerRule means the git rule of the application trigger is invalid
+var ErrInvalidGitTriggerRule = NewBcode(400, 10029, "Invalid git trigger rule")
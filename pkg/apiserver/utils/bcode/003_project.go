@@ -45,3 +45,15 @@ var ErrProjectUserExist = NewBcode(400, 30009, "the user is already exist in thi
 
 // ErrProjectOwnerIsNotExist means the project owner name is invalid
 var ErrProjectOwnerIsNotExist = NewBcode(400, 30010, "the project owner name is invalid")
+
+// ErrOutboundWebhookNotExist means the outbound webhook is not exist
+var ErrOutboundWebhookNotExist = NewBcode(404, 30011, "the outbound webhook is not exist")
+
+// ErrOutboundWebhookExist means the outbound webhook is already exist
+var ErrOutboundWebhookExist = NewBcode(400, 30012, "the outbound webhook is already exist")
+
+// ErrInvalidOutboundWebhookURL means the URL of the outbound webhook is not a valid http or https URL
+var ErrInvalidOutboundWebhookURL = NewBcode(400, 30013, "the URL of the outbound webhook must be a valid http or https URL out of the private networks")
+
+// ErrInvalidWebhookEvent means the event can not be subscribed by the outbound webhooks
+var ErrInvalidWebhookEvent = NewBcode(400, 30014, "the event can not be subscribed by the outbound webhooks")
@@ -60,3 +60,15 @@ var ErrInvalidAccessKeyOrSecretKey = NewBcode(400, 40013, "access key or secret
 
 // ErrClusterCreateNamespaceNoPermission cluster create namespace is forbidden
 var ErrClusterCreateNamespaceNoPermission = NewBcode(401, 40014, "no permission to create namespace in cluster")
+
+// ErrCloudClusterCreationNotSupported the cloud provider can only import existing clusters
+var ErrCloudClusterCreationNotSupported = NewBcode(400, 40015, "the provider does not support creating clusters")
+
+// ErrClusterGroupNotExist cluster group is not exist
+var ErrClusterGroupNotExist = NewBcode(404, 40016, "cluster group is not exist")
+
+// ErrClusterGroupExist cluster group is exist
+var ErrClusterGroupExist = NewBcode(400, 40017, "cluster group is exist")
+
+// ErrInvalidClusterGroup cluster group is invalid, eg: no members or nonexistent clusters
+var ErrInvalidClusterGroup = NewBcode(400, 40018, "cluster group must have existing clusters or cluster label selector")
@@ -30,3 +30,6 @@ var ErrTargetNamespaceAlreadyBound = NewBcode(400, 80004, "the namespace specifi
 
 // ErrTargetInvalidWithEmptyClusterOrNamespace indicates the namespace/cluster of target is empty
 var ErrTargetInvalidWithEmptyClusterOrNamespace = NewBcode(400, 80005, "the namespace or cluster of target should not be empty")
+
+// ErrTargetInvalidWithClusterAndClusterGroup indicates both the cluster and the cluster group of target are set
+var ErrTargetInvalidWithClusterAndClusterGroup = NewBcode(400, 80006, "the cluster and cluster group of target should not be set at the same time")
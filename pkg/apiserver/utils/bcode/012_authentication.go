@@ -39,4 +39,18 @@ var (
 	ErrRefreshTokenExpired = NewBcode(400, 12010, "the refresh token is expired")
 	// ErrNoDexConnector is the error of no dex connector
 	ErrNoDexConnector = NewBcode(400, 12011, "there is no dex connector")
+	// ErrAPITokenInvalid is the error of API token invalid
+	ErrAPITokenInvalid = NewBcode(401, 12012, "the API token is invalid")
+	// ErrAPITokenExpired is the error of API token expired
+	ErrAPITokenExpired = NewBcode(401, 12013, "the API token is expired")
+	// ErrAPITokenNotExist is the error of API token not exist
+	ErrAPITokenNotExist = NewBcode(404, 12014, "the API token is not exist")
+	// ErrAPITokenScopeInvalid is the error of API token scopes invalid
+	ErrAPITokenScopeInvalid = NewBcode(400, 12015, "the scopes of the API token must be the names of the permissions")
+	// ErrAPITokenExpireTimeInvalid is the error of API token expire time invalid
+	ErrAPITokenExpireTimeInvalid = NewBcode(400, 12016, "the expire time of the API token must be in the future")
+	// ErrAPITokenNotAllowed is the error of managing the API tokens with an API token
+	ErrAPITokenNotAllowed = NewBcode(403, 12017, "the API tokens can not be created with an API token")
+	// ErrInvalidLDAPConfig is the error of invalid LDAP config
+	ErrInvalidLDAPConfig = NewBcode(400, 12018, "the LDAP config is invalid, the url, the base DN and the username attribute of the user search are required")
 )
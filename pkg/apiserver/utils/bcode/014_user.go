@@ -37,4 +37,12 @@ var (
 	ErrDexNotFound = NewBcode(200, 14009, "the dex is not found")
 	// ErrEmptyAdminEmail is the error of empty admin email
 	ErrEmptyAdminEmail = NewBcode(400, 14010, "the admin email is empty, please set the admin email before using sso login")
+	// ErrServiceAccountIsExist is the error of service account name is used
+	ErrServiceAccountIsExist = NewBcode(400, 14011, "the name is already used by another user or service account")
+	// ErrServiceAccountNotExist is the error of service account not exist
+	ErrServiceAccountNotExist = NewBcode(404, 14012, "the service account is not exist")
+	// ErrServiceAccountCannotModified is the error of modifying a service account as a user
+	ErrServiceAccountCannotModified = NewBcode(400, 14013, "the service account can only be managed in its project")
+	// ErrUserSourceConflict is the error of logging in a user created by another login source
+	ErrUserSourceConflict = NewBcode(401, 14014, "the user with the same name is not from this login source")
 )
@@ -41,6 +41,9 @@ var ErrForbidden = NewBcode(403, 403, "403 Forbidden")
 // ErrUnauthorized check user auth failure
 var ErrUnauthorized = NewBcode(401, 401, "401 Unauthorized")
 
+// ErrConflict the resource has been modified by others since it was read
+var ErrConflict = NewBcode(409, 409, "The resource has been modified, please refresh and try again.")
+
 // Bcode business error code
 type Bcode struct {
 	HTTPCode     int32 `json:"-"`
@@ -92,6 +95,14 @@ func ReturnError(req *restful.Request, res *restful.Response, err error) {
 		}
 		return
 	}
+
+	if errors.Is(err, datastore.ErrRecordConflict) {
+		if err := res.WriteHeaderAndEntity(int(ErrConflict.HTTPCode), ErrConflict); err != nil {
+			log.Logger.Error("write entity failure %s", err.Error())
+		}
+		return
+	}
+
 	var restfulerr restful.ServiceError
 	if errors.As(err, &restfulerr) {
 		if err := res.WriteHeaderAndEntity(restfulerr.Code, Bcode{HTTPCode: int32(restfulerr.Code), BusinessCode: int32(restfulerr.Code), Message: restfulerr.Message}); err != nil {
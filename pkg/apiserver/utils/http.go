@@ -23,6 +23,14 @@ import (
 	"strings"
 )
 
+// MIMEEventStream is the content type of the server-sent events
+const MIMEEventStream = "text/event-stream"
+
+// AcceptEventStream returns whether the client requests the server-sent events
+func AcceptEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), MIMEEventStream)
+}
+
 // ClientIP get client ip
 func ClientIP(r *http.Request) string {
 	xForwardedFor := r.Header.Get("X-Forwarded-For")
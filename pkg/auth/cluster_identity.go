@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+)
+
+var (
+	// ClusterIdentityCacheTTL the duration to cache the identities configured on the clusters
+	ClusterIdentityCacheTTL = time.Minute
+)
+
+// NewIdentityFromClusterIdentity convert the identity given by the topology policy, the namespace of the service
+// account defaults to the given namespace
+func NewIdentityFromClusterIdentity(clusterIdentity *v1alpha1.ClusterIdentity, namespace string) *Identity {
+	identity := &Identity{
+		User:                    clusterIdentity.User,
+		Groups:                  clusterIdentity.Groups,
+		ServiceAccount:          clusterIdentity.ServiceAccount,
+		ServiceAccountNamespace: clusterIdentity.ServiceAccountNamespace,
+	}
+	if identity.ServiceAccount != "" && identity.ServiceAccountNamespace == "" {
+		identity.ServiceAccountNamespace = namespace
+	}
+	return identity
+}
+
+// NewIdentityFromUserInfo convert the user info into identity, the service account user is parsed into the service
+// account and its namespace
+func NewIdentityFromUserInfo(info user.Info) *Identity {
+	if namespace, name, err := serviceaccount.SplitUsername(info.GetName()); err == nil {
+		return &Identity{ServiceAccount: name, ServiceAccountNamespace: namespace}
+	}
+	return &Identity{User: info.GetName(), Groups: info.GetGroups()}
+}
+
+// getClusterIdentity extract the identity to impersonate from the annotations of the cluster object, nil is returned
+// if no identity is configured
+func getClusterIdentity(o client.Object) *Identity {
+	annots := o.GetAnnotations()
+	if annots == nil {
+		return nil
+	}
+	identity := &Identity{User: annots[types.AnnotationClusterImpersonateUser]}
+	if groups := annots[types.AnnotationClusterImpersonateGroups]; groups != "" {
+		identity.Groups = strings.Split(groups, groupSeparator)
+	}
+	if sa := annots[types.AnnotationClusterImpersonateServiceAccount]; sa != "" {
+		identity.ServiceAccount = sa
+		if idx := strings.Index(sa, "/"); idx >= 0 {
+			identity.ServiceAccountNamespace, identity.ServiceAccount = sa[:idx], sa[idx+1:]
+		}
+	}
+	if identity.User == "" && identity.ServiceAccount == "" {
+		return nil
+	}
+	identity.Regularize()
+	return identity
+}
+
+// GetClusterIdentity returns the identity configured on the cluster for impersonation, nil is returned if the cluster
+// has no identity configured
+func GetClusterIdentity(ctx context.Context, cli client.Client, cluster string) (*Identity, error) {
+	if cluster == "" || cluster == multicluster.ClusterLocalName {
+		return nil, nil
+	}
+	vc, err := multicluster.GetVirtualCluster(ctx, cli, cluster)
+	if err != nil {
+		if errors.Is(err, multicluster.ErrClusterNotExists) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get cluster %s", cluster)
+	}
+	if vc.Object == nil {
+		return nil, nil
+	}
+	return getClusterIdentity(vc.Object), nil
+}
+
+// GetTopologyClusterIdentities extract the identities recorded in the status of the topology policies of application
+func GetTopologyClusterIdentities(app *v1beta1.Application) map[string]*Identity {
+	identities := map[string]*Identity{}
+	for _, policyStatus := range app.Status.PolicyStatus {
+		if policyStatus.Type != v1alpha1.TopologyPolicyType || policyStatus.Status == nil {
+			continue
+		}
+		status := &v1alpha1.TopologyPolicyStatus{}
+		if err := json.Unmarshal(policyStatus.Status.Raw, status); err != nil {
+			continue
+		}
+		for cluster, clusterIdentity := range status.ClusterIdentities {
+			clusterIdentity := clusterIdentity
+			identities[cluster] = NewIdentityFromClusterIdentity(&clusterIdentity, app.Namespace)
+		}
+	}
+	return identities
+}
+
+// GetEffectiveIdentity returns the identity to impersonate while dispatching the resources of application into the
+// placed cluster. The identity given by the topology policy is used first, then the identity configured on the
+// cluster and at last the identity of the application. Nil is returned if the identity of controller is used.
+func GetEffectiveIdentity(ctx context.Context, cli client.Client, app *v1beta1.Application, placement v1alpha1.PlacementDecision) (*Identity, error) {
+	if placement.Identity != nil {
+		return NewIdentityFromClusterIdentity(placement.Identity, app.Namespace), nil
+	}
+	identity, err := GetClusterIdentity(ctx, cli, placement.Cluster)
+	if err != nil || identity != nil {
+		return identity, err
+	}
+	info := GetUserInfoInAnnotation(&app.ObjectMeta)
+	if info.GetName() == "" {
+		return nil, nil
+	}
+	return NewIdentityFromUserInfo(info), nil
+}
+
+// CheckImpersonatePermission check if the requester is allowed to impersonate the identity, through the
+// SubjectAccessReviews of the impersonate verb on the user, groups and service account in the hub cluster
+func CheckImpersonatePermission(ctx context.Context, cli client.Client, requester user.Info, identity *Identity) error {
+	if requester == nil || requester.GetName() == "" {
+		return errors.New("the requester of the application is unknown, enable the AuthenticateApplication feature to impersonate the identity")
+	}
+	var attrs []authorizationv1.ResourceAttributes
+	if identity.User != "" {
+		attrs = append(attrs, authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "users", Name: identity.User})
+	}
+	for _, group := range identity.Groups {
+		attrs = append(attrs, authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "groups", Name: group})
+	}
+	if identity.ServiceAccount != "" {
+		attrs = append(attrs, authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "serviceaccounts", Namespace: identity.ServiceAccountNamespace, Name: identity.ServiceAccount})
+	}
+	// the review is created by the controller itself in the hub cluster
+	ctx = multicluster.ContextInLocalCluster(ContextClearUserInfo(ctx))
+	for i := range attrs {
+		review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attrs[i],
+			User:               requester.GetName(),
+			Groups:             requester.GetGroups(),
+		}}
+		if err := cli.Create(ctx, review); err != nil {
+			return errors.Wrapf(err, "failed to check the permission to impersonate %s %s", attrs[i].Resource, attrs[i].Name)
+		}
+		if !review.Status.Allowed {
+			return errors.Errorf("%s is not allowed to impersonate %s %s", requester.GetName(), attrs[i].Resource, attrs[i].Name)
+		}
+	}
+	return nil
+}
+
+type clusterIdentitiesKey struct{}
+
+func contextWithClusterIdentities(ctx context.Context, identities map[string]*Identity) context.Context {
+	return context.WithValue(ctx, clusterIdentitiesKey{}, identities)
+}
+
+func clusterIdentitiesFrom(ctx context.Context) map[string]*Identity {
+	identities, _ := ctx.Value(clusterIdentitiesKey{}).(map[string]*Identity)
+	return identities
+}
+
+type cachedClusterIdentity struct {
+	identity  *Identity
+	checkTime time.Time
+}
+
+// clusterIdentityCache caches the identities configured on the clusters
+type clusterIdentityCache struct {
+	cli        client.Client
+	mu         sync.Mutex
+	identities map[string]*cachedClusterIdentity
+}
+
+func (c *clusterIdentityCache) get(cluster string) (*Identity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, found := c.identities[cluster]; found && time.Since(cached.checkTime) < ClusterIdentityCacheTTL {
+		return cached.identity, nil
+	}
+	identity, err := GetClusterIdentity(context.Background(), c.cli, cluster)
+	if err != nil {
+		return nil, err
+	}
+	c.identities[cluster] = &cachedClusterIdentity{identity: identity, checkTime: time.Now()}
+	return identity, nil
+}
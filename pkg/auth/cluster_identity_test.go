@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestGetEffectiveIdentity(t *testing.T) {
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster-a",
+			Namespace:   multicluster.ClusterGatewaySecretNamespace,
+			Labels:      map[string]string{clustercommon.LabelKeyClusterCredentialType: "X509Certificate"},
+			Annotations: map[string]string{types.AnnotationClusterImpersonateServiceAccount: "deployer"},
+		},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-b",
+			Namespace: multicluster.ClusterGatewaySecretNamespace,
+			Labels:    map[string]string{clustercommon.LabelKeyClusterCredentialType: "X509Certificate"},
+		},
+	}).Build()
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Namespace: "demo"}}
+	withUser := app.DeepCopy()
+	metav1.SetMetaDataAnnotation(&withUser.ObjectMeta, oam.AnnotationApplicationUsername, "alice")
+	metav1.SetMetaDataAnnotation(&withUser.ObjectMeta, oam.AnnotationApplicationGroup, "dev")
+	testCases := map[string]struct {
+		App       *v1beta1.Application
+		Placement v1alpha1.PlacementDecision
+		Identity  *Identity
+	}{
+		"topology-identity": {
+			App:       withUser,
+			Placement: v1alpha1.PlacementDecision{Cluster: "cluster-a", Identity: &v1alpha1.ClusterIdentity{ServiceAccount: "restricted"}},
+			Identity:  &Identity{ServiceAccount: "restricted", ServiceAccountNamespace: "demo"},
+		},
+		"cluster-identity": {
+			App:       withUser,
+			Placement: v1alpha1.PlacementDecision{Cluster: "cluster-a"},
+			Identity:  &Identity{ServiceAccount: "deployer", ServiceAccountNamespace: "default"},
+		},
+		"application-identity": {
+			App:       withUser,
+			Placement: v1alpha1.PlacementDecision{Cluster: "cluster-b"},
+			Identity:  &Identity{User: "alice", Groups: []string{"dev"}},
+		},
+		"local-without-identity": {
+			App:       app,
+			Placement: v1alpha1.PlacementDecision{Cluster: multicluster.ClusterLocalName},
+		},
+		"cluster-not-exists": {
+			App:       app,
+			Placement: v1alpha1.PlacementDecision{Cluster: "cluster-x"},
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			identity, err := GetEffectiveIdentity(context.Background(), cli, tt.App, tt.Placement)
+			require.NoError(t, err)
+			require.Equal(t, tt.Identity, identity)
+		})
+	}
+}
+
+// reviewClient allows the reviews of the permitted resource names
+type reviewClient struct {
+	client.Client
+	permitted map[string]bool
+	reviews   []*authorizationv1.SubjectAccessReview
+}
+
+func (c *reviewClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if review, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "alice" && c.permitted[attrs.Resource+"/"+attrs.Namespace+"/"+attrs.Name]
+		c.reviews = append(c.reviews, review)
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestCheckImpersonatePermission(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := &reviewClient{
+		Client:    fake.NewClientBuilder().WithScheme(common.Scheme).Build(),
+		permitted: map[string]bool{"serviceaccounts/demo/deployer": true, "users//bob": true, "groups//dev": true},
+	}
+	alice := &user.DefaultInfo{Name: "alice", Groups: []string{"team"}}
+	r.NoError(CheckImpersonatePermission(ctx, cli, alice, &Identity{ServiceAccount: "deployer", ServiceAccountNamespace: "demo"}))
+	r.Equal(1, len(cli.reviews))
+	r.Equal("impersonate", cli.reviews[0].Spec.ResourceAttributes.Verb)
+	r.Equal([]string{"team"}, cli.reviews[0].Spec.Groups)
+	r.NoError(CheckImpersonatePermission(ctx, cli, alice, &Identity{User: "bob", Groups: []string{"dev"}}))
+
+	err := CheckImpersonatePermission(ctx, cli, alice, &Identity{User: "bob", Groups: []string{"dev", "system:masters"}})
+	r.Error(err)
+	r.Contains(err.Error(), "alice is not allowed to impersonate groups system:masters")
+	r.Error(CheckImpersonatePermission(ctx, cli, &user.DefaultInfo{Name: "eve"}, &Identity{User: "bob"}))
+	r.Error(CheckImpersonatePermission(ctx, cli, &user.DefaultInfo{}, &Identity{User: "bob"}))
+	r.Error(CheckImpersonatePermission(ctx, cli, nil, &Identity{User: "bob"}))
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	velatypes "github.com/oam-dev/kubevela/apis/types"
+)
+
+const (
+	// DexConfigSecretName the name of the secret which stores the config of the Dex used by VelaUX
+	DexConfigSecretName = "dex-config"
+	// DexConfigSecretKey the key of the Dex config in the secret
+	DexConfigSecretKey = "config.yaml"
+	// DexKubeLoginClientID the id of the public Dex client used by the kubectl oidc-login plugin
+	DexKubeLoginClientID = "kubelogin"
+	// DexKubeLoginClientName the name of the public Dex client used by the kubectl oidc-login plugin
+	DexKubeLoginClientName = "KubeLogin"
+)
+
+// DexKubeLoginRedirectURIs the redirect uris of the kubectl oidc-login plugin, which listens on the port 8000 or 18000
+var DexKubeLoginRedirectURIs = []string{"http://localhost:8000", "http://localhost:18000"}
+
+// dexConfig the fields of the Dex config used to generate the OIDC kubeconfig
+type dexConfig struct {
+	Issuer        string `json:"issuer"`
+	StaticClients []struct {
+		ID     string `json:"id"`
+		Public bool   `json:"public"`
+	} `json:"staticClients"`
+}
+
+// GetDexKubeLoginOIDCOptions returns the issuer and the public kubelogin client of the Dex configured in VelaUX, which
+// can be used by the kubeconfig of users to authenticate against the Dex
+func GetDexKubeLoginOIDCOptions(ctx context.Context, cli client.Reader) (*KubeConfigGenerateOIDCOptions, error) {
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: velatypes.DefaultKubeVelaNS, Name: DexConfigSecretName}, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the dex config")
+	}
+	config := &dexConfig{}
+	if err := yaml.Unmarshal(secret.Data[DexConfigSecretKey], config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the dex config")
+	}
+	if config.Issuer == "" {
+		return nil, errors.New("no issuer found in the dex config")
+	}
+	for _, staticClient := range config.StaticClients {
+		if staticClient.ID == DexKubeLoginClientID && staticClient.Public {
+			return &KubeConfigGenerateOIDCOptions{IssuerURL: config.Issuer, ClientID: staticClient.ID}, nil
+		}
+	}
+	return nil, errors.Errorf("no public client %s found in the dex config, update the dex config in VelaUX to register it", DexKubeLoginClientID)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestGetDexKubeLoginOIDCOptions(t *testing.T) {
+	testCases := map[string]struct {
+		Config  string
+		Options *KubeConfigGenerateOIDCOptions
+		Error   string
+	}{
+		"public-client": {
+			Config: `issuer: https://velaux.example.com/dex
+staticClients:
+- id: velaux
+  secret: velaux-secret
+  redirectURIs: ["https://velaux.example.com/callback"]
+- id: kubelogin
+  public: true
+  redirectURIs: ["http://localhost:8000"]`,
+			Options: &KubeConfigGenerateOIDCOptions{IssuerURL: "https://velaux.example.com/dex", ClientID: "kubelogin"},
+		},
+		"no-public-client": {
+			Config: `issuer: https://velaux.example.com/dex
+staticClients:
+- id: velaux
+  secret: velaux-secret
+  redirectURIs: ["https://velaux.example.com/callback"]`,
+			Error: "no public client kubelogin found",
+		},
+		"no-issuer": {
+			Config: `staticClients: []`,
+			Error:  "no issuer found",
+		},
+		"no-config": {
+			Error: "failed to get the dex config",
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			builder := fake.NewClientBuilder().WithScheme(common.Scheme)
+			if tt.Config != "" {
+				builder = builder.WithObjects(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: DexConfigSecretName, Namespace: types.DefaultKubeVelaNS},
+					Data:       map[string][]byte{DexConfigSecretKey: []byte(tt.Config)},
+				})
+			}
+			options, err := GetDexKubeLoginOIDCOptions(context.Background(), builder.Build())
+			if tt.Error != "" {
+				r.Error(err)
+				r.Contains(err.Error(), tt.Error)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.Options, options)
+		})
+	}
+}
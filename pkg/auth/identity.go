@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/utils/strings/slices"
 )
 
@@ -49,6 +50,14 @@ func (identity *Identity) String() string {
 	return strings.Join(tokens, " ")
 }
 
+// UserInfo returns the user info to impersonate
+func (identity *Identity) UserInfo() user.Info {
+	if identity.ServiceAccount != "" {
+		return &user.DefaultInfo{Name: serviceaccount.MakeUsername(identity.ServiceAccountNamespace, identity.ServiceAccount)}
+	}
+	return &user.DefaultInfo{Name: identity.User, Groups: identity.Groups}
+}
+
 // Match validate if identity matches rbac subject
 func (identity *Identity) Match(subject rbacv1.Subject) bool {
 	switch subject.Kind {
@@ -95,6 +104,19 @@ func (identity *Identity) Regularize() {
 	}
 }
 
+// AddOIDCPrefix add the prefixes to the user and groups, which are prepended by the apiserver to the claims of the
+// OIDC id token with --oidc-username-prefix and --oidc-groups-prefix
+func (identity *Identity) AddOIDCPrefix(usernamePrefix string, groupsPrefix string) {
+	if identity.User != "" && !strings.HasPrefix(identity.User, usernamePrefix) {
+		identity.User = usernamePrefix + identity.User
+	}
+	for i, group := range identity.Groups {
+		if !strings.HasPrefix(group, groupsPrefix) {
+			identity.Groups[i] = groupsPrefix + group
+		}
+	}
+}
+
 // Validate check if identity is valid
 func (identity *Identity) Validate() error {
 	if identity.User == "" && identity.ServiceAccount == "" {
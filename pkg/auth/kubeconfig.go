@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -50,6 +51,7 @@ import (
 type KubeConfigGenerateOptions struct {
 	X509           *KubeConfigGenerateX509Options
 	ServiceAccount *KubeConfigGenerateServiceAccountOptions
+	OIDC           *KubeConfigGenerateOIDCOptions
 }
 
 // KubeConfigGenerateX509Options options for create X509 based KubeConfig
@@ -66,6 +68,17 @@ type KubeConfigGenerateServiceAccountOptions struct {
 	ServiceAccountNamespace string
 }
 
+// KubeConfigGenerateOIDCOptions options for create OIDC based KubeConfig
+type KubeConfigGenerateOIDCOptions struct {
+	User         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	ExtraScopes  []string
+	// UseAuthProvider use the legacy oidc auth-provider of kubectl instead of the exec credential plugin
+	UseAuthProvider bool
+}
+
 // KubeConfigWithUserGenerateOption option for setting user in KubeConfig
 type KubeConfigWithUserGenerateOption string
 
@@ -99,6 +112,20 @@ func (opt KubeConfigWithServiceAccountGenerateOption) ApplyToOptions(options *Ku
 	}
 }
 
+// KubeConfigWithOIDCGenerateOption option for setting OIDC issuer and client in KubeConfig
+type KubeConfigWithOIDCGenerateOption KubeConfigGenerateOIDCOptions
+
+// ApplyToOptions .
+func (opt KubeConfigWithOIDCGenerateOption) ApplyToOptions(options *KubeConfigGenerateOptions) {
+	oidcOptions := KubeConfigGenerateOIDCOptions(opt)
+	if oidcOptions.User == "" {
+		oidcOptions.User = DefaultOIDCAuthInfoName
+	}
+	options.X509 = nil
+	options.ServiceAccount = nil
+	options.OIDC = &oidcOptions
+}
+
 // KubeConfigWithIdentityGenerateOption option for setting identity in KubeConfig
 type KubeConfigWithIdentityGenerateOption Identity
 
@@ -144,6 +171,16 @@ const (
 	KubeVelaClientGroup = "kubevela:client"
 	// CSRNamePrefix the prefix of the CSR name
 	CSRNamePrefix = "kubevela-csr"
+	// DefaultOIDCAuthInfoName the default name of the user in the generated OIDC KubeConfig
+	DefaultOIDCAuthInfoName = "oidc"
+	// OIDCLoginCommand the kubectl plugin used to retrieve the OIDC id token, see https://github.com/int128/kubelogin
+	OIDCLoginCommand = "kubectl"
+)
+
+var (
+	// DefaultOIDCExtraScopes the default scopes to request besides openid, the groups scope is required to get the
+	// groups of the user from Dex
+	DefaultOIDCExtraScopes = []string{"email", "groups"}
 )
 
 // GenerateKubeConfig generate KubeConfig for users with given options.
@@ -153,8 +190,10 @@ func GenerateKubeConfig(ctx context.Context, cli kubernetes.Interface, cfg *clie
 		return generateX509KubeConfig(ctx, cli, cfg, writer, opts.X509)
 	} else if opts.ServiceAccount != nil {
 		return generateServiceAccountKubeConfig(ctx, cli, cfg, writer, opts.ServiceAccount)
+	} else if opts.OIDC != nil {
+		return generateOIDCKubeConfig(cfg, writer, opts.OIDC)
 	}
-	return nil, errors.New("either x509 or serviceaccount must be set for creating KubeConfig")
+	return nil, errors.New("either x509, serviceaccount or oidc must be set for creating KubeConfig")
 }
 
 func genKubeConfig(cfg *clientcmdapi.Config, authInfo *clientcmdapi.AuthInfo, caData []byte) (*clientcmdapi.Config, error) {
@@ -355,6 +394,51 @@ func generateServiceAccountKubeConfig(ctx context.Context, cli kubernetes.Interf
 	}, secret.Data["ca.crt"])
 }
 
+func generateOIDCKubeConfig(cfg *clientcmdapi.Config, writer io.Writer, opts *KubeConfigGenerateOIDCOptions) (*clientcmdapi.Config, error) {
+	if opts.IssuerURL == "" || opts.ClientID == "" {
+		return nil, errors.New("both issuer url and client id must be set for creating OIDC KubeConfig")
+	}
+	authInfo := &clientcmdapi.AuthInfo{}
+	if opts.UseAuthProvider {
+		config := map[string]string{
+			"idp-issuer-url": opts.IssuerURL,
+			"client-id":      opts.ClientID,
+		}
+		if opts.ClientSecret != "" {
+			config["client-secret"] = opts.ClientSecret
+		}
+		if len(opts.ExtraScopes) > 0 {
+			config["extra-scopes"] = strings.Join(opts.ExtraScopes, ",")
+		}
+		authInfo.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: "oidc", Config: config}
+	} else {
+		args := []string{"oidc-login", "get-token", "--oidc-issuer-url=" + opts.IssuerURL, "--oidc-client-id=" + opts.ClientID}
+		if opts.ClientSecret != "" {
+			args = append(args, "--oidc-client-secret="+opts.ClientSecret)
+		}
+		for _, scope := range opts.ExtraScopes {
+			args = append(args, "--oidc-extra-scope="+scope)
+		}
+		authInfo.Exec = &clientcmdapi.ExecConfig{
+			APIVersion:      "client.authentication.k8s.io/v1beta1",
+			Command:         OIDCLoginCommand,
+			Args:            args,
+			InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+		}
+	}
+	_, _ = fmt.Fprintf(writer, "OIDC credential configured with issuer %s.\n", opts.IssuerURL)
+	exportCfg, err := genKubeConfig(cfg, authInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the credentials of the other users cannot be reused, rename the user in the exported context
+	for _, exportContext := range exportCfg.Contexts {
+		exportContext.AuthInfo = opts.User
+	}
+	exportCfg.AuthInfos = map[string]*clientcmdapi.AuthInfo{opts.User: authInfo}
+	return exportCfg, nil
+}
+
 // ReadIdentityFromKubeConfig extract identity from kubeconfig
 func ReadIdentityFromKubeConfig(kubeconfigPath string) (*Identity, error) {
 	cfg, err := clientcmd.LoadFromFile(kubeconfigPath)
@@ -370,6 +454,9 @@ func ReadIdentityFromKubeConfig(kubeconfigPath string) (*Identity, error) {
 		return nil, fmt.Errorf("cannot find auth-info %s", ctx.AuthInfo)
 	}
 
+	if authInfo.Exec != nil || authInfo.AuthProvider != nil {
+		return nil, fmt.Errorf("cannot recognize identity from the credential plugin in auth-info %s, the user and groups should be set explicitly", ctx.AuthInfo)
+	}
 	identity := &Identity{}
 	token := authInfo.Token
 	if token == "" && authInfo.TokenFile != "" {
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestGenerateOIDCKubeConfig(t *testing.T) {
+	cfg := &clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"kind": {Server: "https://127.0.0.1:6443"}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"admin": {Token: "admin-token"}},
+		Contexts:       map[string]*clientcmdapi.Context{"kind": {Cluster: "kind", AuthInfo: "admin"}},
+		CurrentContext: "kind",
+	}
+	testCases := map[string]struct {
+		Option   KubeConfigWithOIDCGenerateOption
+		User     string
+		AuthInfo *clientcmdapi.AuthInfo
+		Error    string
+	}{
+		"exec": {
+			Option: KubeConfigWithOIDCGenerateOption{IssuerURL: "https://dex.example.com", ClientID: "velaux", ClientSecret: "secret", ExtraScopes: DefaultOIDCExtraScopes},
+			User:   DefaultOIDCAuthInfoName,
+			AuthInfo: &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{
+				APIVersion: "client.authentication.k8s.io/v1beta1",
+				Command:    OIDCLoginCommand,
+				Args: []string{"oidc-login", "get-token", "--oidc-issuer-url=https://dex.example.com", "--oidc-client-id=velaux",
+					"--oidc-client-secret=secret", "--oidc-extra-scope=email", "--oidc-extra-scope=groups"},
+				InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+			}},
+		},
+		"auth-provider": {
+			Option: KubeConfigWithOIDCGenerateOption{User: "alice", IssuerURL: "https://dex.example.com", ClientID: "kubernetes", ExtraScopes: []string{"groups"}, UseAuthProvider: true},
+			User:   "alice",
+			AuthInfo: &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "oidc", Config: map[string]string{
+				"idp-issuer-url": "https://dex.example.com",
+				"client-id":      "kubernetes",
+				"extra-scopes":   "groups",
+			}}},
+		},
+		"no-issuer": {
+			Option: KubeConfigWithOIDCGenerateOption{ClientID: "kubernetes"},
+			Error:  "both issuer url and client id must be set",
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			exportCfg, err := GenerateKubeConfig(context.Background(), nil, cfg, bytes.NewBuffer(nil), tt.Option)
+			if tt.Error != "" {
+				r.Error(err)
+				r.Contains(err.Error(), tt.Error)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.User, exportCfg.Contexts["kind"].AuthInfo)
+			r.Equal(map[string]*clientcmdapi.AuthInfo{tt.User: tt.AuthInfo}, exportCfg.AuthInfos)
+			r.Equal("admin", cfg.Contexts["kind"].AuthInfo)
+
+			file := filepath.Join(t.TempDir(), "kubeconfig")
+			r.NoError(clientcmd.WriteToFile(*exportCfg, file))
+			_, err = ReadIdentityFromKubeConfig(file)
+			r.Error(err)
+			r.Contains(err.Error(), "cannot recognize identity from the credential plugin")
+		})
+	}
+}
@@ -19,12 +19,15 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/transport"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/utils"
 )
 
@@ -35,7 +38,8 @@ const (
 var _ utilnet.RoundTripperWrapper = &impersonatingRoundTripper{}
 
 type impersonatingRoundTripper struct {
-	rt http.RoundTripper
+	rt                http.RoundTripper
+	clusterIdentities *clusterIdentityCache
 }
 
 // NewImpersonatingRoundTripper will add an ImpersonateUser header to a request
@@ -46,11 +50,46 @@ func NewImpersonatingRoundTripper(rt http.RoundTripper) http.RoundTripper {
 	}
 }
 
+// NewClusterImpersonatingRoundTripperGenerator returns the wrapper of impersonating round tripper which maps the
+// user in context to the identity configured for the target cluster, either by the topology policy or on the
+// cluster object. The cluster objects are read by the given client and cached for ClusterIdentityCacheTTL.
+func NewClusterImpersonatingRoundTripperGenerator(cli client.Client) transport.WrapperFunc {
+	cache := &clusterIdentityCache{cli: cli, identities: map[string]*cachedClusterIdentity{}}
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonatingRoundTripper{
+			rt:                rt,
+			clusterIdentities: cache,
+		}
+	}
+}
+
+// getClusterIdentity returns the identity to impersonate in the target cluster of the request
+func (rt *impersonatingRoundTripper) getClusterIdentity(ctx context.Context) (*Identity, error) {
+	cluster := multicluster.ClusterNameInContext(ctx)
+	if cluster == "" {
+		cluster = multicluster.ClusterLocalName
+	}
+	if identity, found := clusterIdentitiesFrom(ctx)[cluster]; found {
+		return identity, nil
+	}
+	if rt.clusterIdentities == nil {
+		return nil, nil
+	}
+	return rt.clusterIdentities.get(cluster)
+}
+
 func (rt *impersonatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	ctx := req.Context()
 	req = req.Clone(ctx)
 	userInfo, exists := request.UserFrom(ctx)
 	if exists && userInfo != nil {
+		identity, err := rt.getClusterIdentity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if identity != nil {
+			userInfo = identity.UserInfo()
+		}
 		if name := userInfo.GetName(); name != "" {
 			req.Header.Set(transport.ImpersonateUserHeader, name)
 			for _, group := range userInfo.GetGroups() {
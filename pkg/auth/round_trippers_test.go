@@ -25,14 +25,24 @@ import (
 
 	"github.com/stretchr/testify/require"
 	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/transport"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+
+	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/features"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
 )
 
 type testRoundTripper struct {
@@ -112,3 +122,68 @@ func TestImpersonatingRoundTripper(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterImpersonatingRoundTripper(t *testing.T) {
+	newClusterSecret := func(name string, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: v1.ObjectMeta{
+			Name:        name,
+			Namespace:   multicluster.ClusterGatewaySecretNamespace,
+			Labels:      map[string]string{clustercommon.LabelKeyClusterCredentialType: "X509Certificate"},
+			Annotations: annotations,
+		}}
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newClusterSecret("cluster-sa", map[string]string{types.AnnotationClusterImpersonateServiceAccount: "vela-system/deployer"}),
+		newClusterSecret("cluster-user", map[string]string{
+			types.AnnotationClusterImpersonateUser:   "alice",
+			types.AnnotationClusterImpersonateGroups: "dev,ops",
+		}),
+		newClusterSecret("cluster-plain", nil),
+	).Build()
+	app := &v1beta1.Application{}
+	app.SetNamespace("demo")
+	v1.SetMetaDataAnnotation(&app.ObjectMeta, oam.AnnotationApplicationServiceAccountName, "default")
+	app.Status.PolicyStatus = []apicommon.PolicyStatus{{
+		Name:   "topology",
+		Type:   v1alpha1.TopologyPolicyType,
+		Status: &runtime.RawExtension{Raw: []byte(`{"clusterIdentities":{"cluster-user":{"serviceAccount":"restricted"}}}`)},
+	}}
+	testSets := map[string]struct {
+		ctx           context.Context
+		expectedUser  string
+		expectedGroup []string
+	}{
+		"cluster identity": {
+			ctx:          ContextWithUserInfo(multicluster.ContextWithClusterName(context.Background(), "cluster-sa"), app),
+			expectedUser: "system:serviceaccount:vela-system:deployer",
+		},
+		"topology identity overrides cluster identity": {
+			ctx:          ContextWithUserInfo(multicluster.ContextWithClusterName(context.Background(), "cluster-user"), app),
+			expectedUser: "system:serviceaccount:demo:restricted",
+		},
+		"cluster user and groups": {
+			ctx:           ContextWithUserInfo(multicluster.ContextWithClusterName(context.Background(), "cluster-user"), &v1beta1.Application{}),
+			expectedUser:  "alice",
+			expectedGroup: []string{"dev", "ops"},
+		},
+		"application identity": {
+			ctx:          ContextWithUserInfo(multicluster.ContextWithClusterName(context.Background(), "cluster-plain"), app),
+			expectedUser: "system:serviceaccount:demo:default",
+		},
+		"without user info": {
+			ctx: multicluster.ContextWithClusterName(context.Background(), "cluster-sa"),
+		},
+	}
+	wrapper := NewClusterImpersonatingRoundTripperGenerator(cli)
+	for name, ts := range testSets {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(ts.ctx)
+			rt := &testRoundTripper{}
+			_, err := wrapper(rt).RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, ts.expectedUser, rt.Request.Header.Get(transport.ImpersonateUserHeader))
+			require.Equal(t, ts.expectedGroup, rt.Request.Header.Values(transport.ImpersonateGroupHeader))
+		})
+	}
+}
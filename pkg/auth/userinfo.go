@@ -40,16 +40,18 @@ const (
 
 // ContextWithUserInfo inject username & group from app annotations into context
 // If serviceAccount is set and username is empty, identity will user the serviceAccount
+// The identities given by the topology policies for clusters are injected as well
 func ContextWithUserInfo(ctx context.Context, app *v1beta1.Application) context.Context {
 	if app == nil {
 		return ctx
 	}
+	ctx = contextWithClusterIdentities(ctx, GetTopologyClusterIdentities(app))
 	return request.WithUser(ctx, GetUserInfoInAnnotation(&app.ObjectMeta))
 }
 
 // ContextClearUserInfo clear user info in context
 func ContextClearUserInfo(ctx context.Context) context.Context {
-	return request.WithUser(ctx, nil)
+	return request.WithUser(contextWithClusterIdentities(ctx, nil), nil)
 }
 
 // SetUserInfoInAnnotation set username and group from userInfo into annotations
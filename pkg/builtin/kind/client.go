@@ -32,6 +32,17 @@ import (
 	"sigs.k8s.io/kind/pkg/fs"
 )
 
+// ListClusters lists the names of the kind clusters
+func ListClusters() ([]string, error) {
+	return cluster.NewProvider(cluster.ProviderWithDocker()).List()
+}
+
+// GetKubeConfig returns the kubeconfig of the kind cluster. The internal kubeconfig uses the address of the control plane
+// container in the docker network, which is reachable from other kind clusters.
+func GetKubeConfig(name string, internal bool) (string, error) {
+	return cluster.NewProvider(cluster.ProviderWithDocker()).KubeConfig(name, internal)
+}
+
 func LoadDockerImage(imageName string) error {
 	return LoadDockerImagesWithFlags([]string{imageName}, "", nil)
 }
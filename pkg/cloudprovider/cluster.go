@@ -18,8 +18,10 @@ package cloudprovider
 
 import (
 	"context"
+	"sort"
 
 	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -37,12 +39,98 @@ type CloudClusterProvider interface {
 	CreateCloudCluster(ctx context.Context, clusterName string, zone string, worker int, cpu int64, mem int64) (string, error)
 }
 
+// ErrClusterCreationNotSupported the cluster provider can only import existing clusters
+var ErrClusterCreationNotSupported = errors.New("creating clusters is not supported by the cluster provider")
+
+// ClusterProviderFactory creates the cluster provider. The meaning of the access key depends on the provider, for
+// example, the kubeconfig provider regards the access key id as the directory of the kubeconfig files.
+type ClusterProviderFactory func(accessKeyID string, accessKeySecret string, k8sClient client.Client) (CloudClusterProvider, error)
+
+var clusterProviderFactories = map[string]ClusterProviderFactory{}
+
+// localClusterProviders the providers which read the file system, the docker or any namespace of the hub cluster with
+// the source given by the caller. They act with the permissions of the caller's environment, so they are only
+// available to the CLI and must not be exposed through the apiserver.
+var localClusterProviders = map[string]bool{
+	ProviderKubeConfig: true,
+	ProviderClusterAPI: true,
+	ProviderKind:       true,
+}
+
+func init() {
+	RegisterClusterProvider(ProviderAliyun, func(accessKeyID string, accessKeySecret string, k8sClient client.Client) (CloudClusterProvider, error) {
+		return NewAliyunCloudProvider(accessKeyID, accessKeySecret, k8sClient)
+	})
+	RegisterClusterProvider(ProviderKubeConfig, func(dir string, _ string, _ client.Client) (CloudClusterProvider, error) {
+		return NewKubeConfigClusterProvider(dir)
+	})
+	RegisterClusterProvider(ProviderClusterAPI, func(namespace string, _ string, k8sClient client.Client) (CloudClusterProvider, error) {
+		return NewClusterAPIClusterProvider(namespace, k8sClient), nil
+	})
+	RegisterClusterProvider(ProviderKind, func(_ string, _ string, _ client.Client) (CloudClusterProvider, error) {
+		return NewKindClusterProvider(), nil
+	})
+}
+
+// RegisterClusterProvider registers the factory of the cluster provider, the existing one with the same name will be
+// overridden
+func RegisterClusterProvider(provider string, factory ClusterProviderFactory) {
+	clusterProviderFactories[provider] = factory
+}
+
+// ListClusterProviders lists the names of the registered cluster providers
+func ListClusterProviders() []string {
+	var providers []string
+	for provider := range clusterProviderFactories {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// IsLocalClusterProvider check if the provider reads local resources with the source given by the caller
+func IsLocalClusterProvider(provider string) bool {
+	return localClusterProviders[provider]
+}
+
 // GetClusterProvider creates interface for getting cloud cluster provider
 func GetClusterProvider(provider string, accessKeyID string, accessKeySecret string, k8sClient client.Client) (CloudClusterProvider, error) {
-	switch provider {
-	case ProviderAliyun:
-		return NewAliyunCloudProvider(accessKeyID, accessKeySecret, k8sClient)
-	default:
+	factory, ok := clusterProviderFactories[provider]
+	if !ok {
 		return nil, errors.Errorf("cluster provider %s is not implemented", provider)
 	}
+	return factory(accessKeyID, accessKeySecret, k8sClient)
+}
+
+// paginateClusters returns the clusters in the page, all the clusters are returned if the page or the page size is
+// not positive
+func paginateClusters(clusters []*CloudCluster, pageNumber int, pageSize int) []*CloudCluster {
+	if pageNumber <= 0 || pageSize <= 0 {
+		return clusters
+	}
+	start := (pageNumber - 1) * pageSize
+	if start >= len(clusters) {
+		return []*CloudCluster{}
+	}
+	end := start + pageSize
+	if end > len(clusters) {
+		end = len(clusters)
+	}
+	return clusters[start:end]
+}
+
+// getKubeConfigServer returns the server of the current context in the kubeconfig
+func getKubeConfigServer(kubeConfig string) string {
+	config, err := clientcmd.Load([]byte(kubeConfig))
+	if err != nil {
+		return ""
+	}
+	ctx, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return ""
+	}
+	if cluster, ok := config.Clusters[ctx.Cluster]; ok {
+		return cluster.Server
+	}
+	return ""
 }
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func newTestKubeConfig(server string, contexts ...string) *clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+	for _, name := range contexts {
+		config.Clusters[name] = &clientcmdapi.Cluster{Server: server + "/" + name}
+		config.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: "token-" + name}
+		config.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name}
+	}
+	if len(contexts) > 0 {
+		config.CurrentContext = contexts[0]
+	}
+	return config
+}
+
+func TestGetClusterProvider(t *testing.T) {
+	r := require.New(t)
+	r.Equal([]string{ProviderAliyun, ProviderClusterAPI, ProviderKind, ProviderKubeConfig}, ListClusterProviders())
+	_, err := GetClusterProvider("unknown", "", "", nil)
+	r.NotNil(err)
+	_, err = GetClusterProvider(ProviderKubeConfig, "", "", nil)
+	r.NotNil(err)
+	p, err := GetClusterProvider(ProviderKind, "", "", nil)
+	r.NoError(err)
+	_, err = p.CreateCloudCluster(context.Background(), "test", "", 0, 0, 0)
+	r.ErrorIs(err, ErrClusterCreationNotSupported)
+	r.False(IsLocalClusterProvider(ProviderAliyun))
+	r.True(IsLocalClusterProvider(ProviderKubeConfig))
+	r.True(IsLocalClusterProvider(ProviderClusterAPI))
+	r.True(IsLocalClusterProvider(ProviderKind))
+}
+
+func TestPaginateClusters(t *testing.T) {
+	r := require.New(t)
+	clusters := []*CloudCluster{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	r.Equal(clusters, paginateClusters(clusters, 0, 2))
+	r.Equal(clusters[:2], paginateClusters(clusters, 1, 2))
+	r.Equal(clusters[2:], paginateClusters(clusters, 2, 2))
+	r.Equal([]*CloudCluster{}, paginateClusters(clusters, 3, 2))
+}
+
+func TestKubeConfigClusterProvider(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	r.NoError(clientcmd.WriteToFile(*newTestKubeConfig("https://a", "dev", "prod"), filepath.Join(dir, "a.kubeconfig")))
+	r.NoError(clientcmd.WriteToFile(*newTestKubeConfig("https://b", "test"), filepath.Join(dir, "b.kubeconfig")))
+	r.NoError(os.WriteFile(filepath.Join(dir, "README.md"), []byte("- invalid"), 0600))
+	r.NoError(os.Mkdir(filepath.Join(dir, "cache"), 0700))
+
+	p, err := NewKubeConfigClusterProvider(dir)
+	r.NoError(err)
+	clusters, total, err := p.ListCloudClusters(0, 0)
+	r.NoError(err)
+	r.Equal(3, total)
+	var ids []string
+	for _, cluster := range clusters {
+		ids = append(ids, cluster.ID)
+	}
+	r.Equal([]string{"a.kubeconfig/dev", "a.kubeconfig/prod", "b.kubeconfig/test"}, ids)
+
+	cluster, err := p.GetClusterInfo("a.kubeconfig/prod")
+	r.NoError(err)
+	r.Equal("prod", cluster.Name)
+	r.Equal("https://a/prod", cluster.APIServerURL)
+	kubeConfig, err := p.GetClusterKubeConfig("a.kubeconfig/prod")
+	r.NoError(err)
+	config, err := clientcmd.Load([]byte(kubeConfig))
+	r.NoError(err)
+	r.Equal("prod", config.CurrentContext)
+	r.Equal(1, len(config.Contexts))
+	r.Equal("token-prod", config.AuthInfos["prod"].Token)
+
+	for _, clusterID := range []string{"a.kubeconfig", "a.kubeconfig/staging", "c.kubeconfig/dev", "../a.kubeconfig/dev"} {
+		_, err = p.GetClusterKubeConfig(clusterID)
+		r.NotNil(err, clusterID)
+	}
+	_, err = NewKubeConfigClusterProvider(filepath.Join(dir, "a.kubeconfig"))
+	r.NotNil(err)
+}
+
+func TestClusterAPIClusterProvider(t *testing.T) {
+	r := require.New(t)
+	newCluster := func(namespace string, name string, phase string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"controlPlaneEndpoint": map[string]interface{}{"host": name + ".example.com", "port": int64(6443)},
+				"infrastructureRef":    map[string]interface{}{"kind": "DockerCluster"},
+			},
+			"status": map[string]interface{}{"phase": phase},
+		}}
+		obj.SetGroupVersionKind(clusterAPIClusterGVK)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(map[string]string{"region": namespace})
+		return obj
+	}
+	kubeConfig, err := clientcmd.Write(*newTestKubeConfig("https://capi", "capi-a"))
+	r.NoError(err)
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newCluster("default", "capi-a", "Provisioned"),
+		newCluster("default", "capi-b", "Provisioning"),
+		newCluster("other", "capi-c", "Provisioned"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "capi-a-kubeconfig"},
+			Data:       map[string][]byte{"value": kubeConfig},
+		},
+	).Build()
+
+	p := NewClusterAPIClusterProvider("default", cli)
+	clusters, total, err := p.ListCloudClusters(1, 1)
+	r.NoError(err)
+	r.Equal(2, total)
+	r.Equal([]*CloudCluster{{
+		Provider:     ProviderClusterAPI,
+		ID:           "default/capi-a",
+		Name:         "capi-a",
+		Type:         "DockerCluster",
+		Labels:       map[string]string{"region": "default"},
+		Status:       "Provisioned",
+		APIServerURL: "https://capi-a.example.com:6443",
+	}}, clusters)
+	_, total, err = NewClusterAPIClusterProvider("", cli).ListCloudClusters(0, 0)
+	r.NoError(err)
+	r.Equal(3, total)
+
+	cluster, err := p.GetClusterInfo("other/capi-c")
+	r.NoError(err)
+	r.Equal("capi-c", cluster.Name)
+	s, err := p.GetClusterKubeConfig("default/capi-a")
+	r.NoError(err)
+	r.Equal(string(kubeConfig), s)
+	_, err = p.GetClusterKubeConfig("default/capi-b")
+	r.NotNil(err)
+	_, err = p.GetClusterInfo("capi-a")
+	r.NotNil(err)
+}
+
+func TestKindClusterProvider(t *testing.T) {
+	r := require.New(t)
+	kubeConfig, err := clientcmd.Write(*newTestKubeConfig("https://kind", "kind-worker"))
+	r.NoError(err)
+	p := &KindClusterProvider{
+		listClusters: func() ([]string, error) {
+			return []string{"kind", "worker"}, nil
+		},
+		getKubeConfig: func(name string, internal bool) (string, error) {
+			r.True(internal)
+			return string(kubeConfig), nil
+		},
+	}
+	clusters, total, err := p.ListCloudClusters(2, 1)
+	r.NoError(err)
+	r.Equal(2, total)
+	r.Equal(1, len(clusters))
+	r.Equal("worker", clusters[0].ID)
+	r.Equal("https://kind/kind-worker", clusters[0].APIServerURL)
+	cluster, err := p.GetClusterInfo("worker")
+	r.NoError(err)
+	r.Equal(ProviderKind, cluster.Provider)
+	s, err := p.GetClusterKubeConfig("worker")
+	r.NoError(err)
+	r.Equal(string(kubeConfig), s)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// clusterAPIKubeConfigSecretSuffix the kubeconfig of the Cluster API cluster is stored in the secret <cluster name>-kubeconfig
+	clusterAPIKubeConfigSecretSuffix = "-kubeconfig"
+	// clusterAPIKubeConfigSecretKey the key of the kubeconfig in the secret
+	clusterAPIKubeConfigSecretKey = "value"
+)
+
+var clusterAPIClusterGVK = schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "Cluster"}
+
+// ClusterAPIClusterProvider imports the clusters provisioned by Cluster API. The Cluster objects and their kubeconfig
+// secrets are read from the management cluster, the id of the cluster is <namespace>/<name>.
+type ClusterAPIClusterProvider struct {
+	k8sClient client.Client
+	namespace string
+}
+
+// NewClusterAPIClusterProvider create the provider for the Cluster API management cluster, clusters in all namespaces
+// will be listed if the namespace is empty
+func NewClusterAPIClusterProvider(namespace string, k8sClient client.Client) *ClusterAPIClusterProvider {
+	return &ClusterAPIClusterProvider{k8sClient: k8sClient, namespace: namespace}
+}
+
+// IsInvalidKey Cluster API provider has no access key
+func (provider *ClusterAPIClusterProvider) IsInvalidKey(err error) bool {
+	return false
+}
+
+func (provider *ClusterAPIClusterProvider) newCloudCluster(obj *unstructured.Unstructured) *CloudCluster {
+	cluster := &CloudCluster{
+		Provider: ProviderClusterAPI,
+		ID:       obj.GetNamespace() + "/" + obj.GetName(),
+		Name:     obj.GetName(),
+		Labels:   obj.GetLabels(),
+	}
+	cluster.Type, _, _ = unstructured.NestedString(obj.Object, "spec", "infrastructureRef", "kind")
+	cluster.Status, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	host, _, _ := unstructured.NestedString(obj.Object, "spec", "controlPlaneEndpoint", "host")
+	port, _, _ := unstructured.NestedInt64(obj.Object, "spec", "controlPlaneEndpoint", "port")
+	if host != "" {
+		cluster.APIServerURL = fmt.Sprintf("https://%s:%d", host, port)
+	}
+	if cluster.Labels == nil {
+		cluster.Labels = map[string]string{}
+	}
+	return cluster
+}
+
+func (provider *ClusterAPIClusterProvider) parseClusterID(clusterID string) (string, string, error) {
+	parts := strings.SplitN(clusterID, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid cluster id %s, it should be <namespace>/<name>", clusterID)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ListCloudClusters list the Cluster objects in the management cluster
+func (provider *ClusterAPIClusterProvider) ListCloudClusters(pageNumber int, pageSize int) ([]*CloudCluster, int, error) {
+	objs := &unstructured.UnstructuredList{}
+	objs.SetGroupVersionKind(clusterAPIClusterGVK.GroupVersion().WithKind(clusterAPIClusterGVK.Kind + "List"))
+	if err := provider.k8sClient.List(context.Background(), objs, client.InNamespace(provider.namespace)); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to list Cluster API clusters")
+	}
+	var clusters []*CloudCluster
+	for i := range objs.Items {
+		clusters = append(clusters, provider.newCloudCluster(&objs.Items[i]))
+	}
+	return paginateClusters(clusters, pageNumber, pageSize), len(clusters), nil
+}
+
+// GetClusterKubeConfig get the kubeconfig from the secret generated by Cluster API
+func (provider *ClusterAPIClusterProvider) GetClusterKubeConfig(clusterID string) (string, error) {
+	namespace, name, err := provider.parseClusterID(clusterID)
+	if err != nil {
+		return "", err
+	}
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: name + clusterAPIKubeConfigSecretSuffix}
+	if err = provider.k8sClient.Get(context.Background(), key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to get the kubeconfig secret of cluster %s", clusterID)
+	}
+	kubeConfig, ok := secret.Data[clusterAPIKubeConfigSecretKey]
+	if !ok {
+		return "", errors.Errorf("kubeconfig not found in secret %s", key.String())
+	}
+	return string(kubeConfig), nil
+}
+
+// GetClusterInfo retrieves cluster info by clusterID
+func (provider *ClusterAPIClusterProvider) GetClusterInfo(clusterID string) (*CloudCluster, error) {
+	namespace, name, err := provider.parseClusterID(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(clusterAPIClusterGVK)
+	if err = provider.k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster API cluster %s", clusterID)
+	}
+	return provider.newCloudCluster(obj), nil
+}
+
+// CreateCloudCluster is not supported by the Cluster API provider, clusters should be created through Cluster API
+func (provider *ClusterAPIClusterProvider) CreateCloudCluster(ctx context.Context, clusterName string, zone string, worker int, cpu int64, mem int64) (string, error) {
+	return "", ErrClusterCreationNotSupported
+}
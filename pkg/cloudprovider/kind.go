@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/pkg/builtin/kind"
+)
+
+// KindClusterProvider imports the kind clusters running in the local docker. The kubeconfig uses the address in the
+// docker network, so that the clusters can be accessed by the hub cluster running in kind as well.
+type KindClusterProvider struct {
+	listClusters  func() ([]string, error)
+	getKubeConfig func(name string, internal bool) (string, error)
+}
+
+// NewKindClusterProvider create the provider for kind clusters
+func NewKindClusterProvider() *KindClusterProvider {
+	return &KindClusterProvider{listClusters: kind.ListClusters, getKubeConfig: kind.GetKubeConfig}
+}
+
+// IsInvalidKey kind provider has no access key
+func (provider *KindClusterProvider) IsInvalidKey(err error) bool {
+	return false
+}
+
+// ListCloudClusters list the kind clusters
+func (provider *KindClusterProvider) ListCloudClusters(pageNumber int, pageSize int) ([]*CloudCluster, int, error) {
+	names, err := provider.listClusters()
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to list kind clusters")
+	}
+	var clusters []*CloudCluster
+	for _, name := range names {
+		clusters = append(clusters, &CloudCluster{Provider: ProviderKind, ID: name, Name: name, Type: ProviderKind, Labels: map[string]string{}})
+	}
+	clusters = paginateClusters(clusters, pageNumber, pageSize)
+	for _, cluster := range clusters {
+		if kubeConfig, err := provider.getKubeConfig(cluster.Name, true); err == nil {
+			cluster.APIServerURL = getKubeConfigServer(kubeConfig)
+		}
+	}
+	return clusters, len(names), nil
+}
+
+// GetClusterKubeConfig get the internal kubeconfig of the kind cluster
+func (provider *KindClusterProvider) GetClusterKubeConfig(clusterID string) (string, error) {
+	kubeConfig, err := provider.getKubeConfig(clusterID, true)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the kubeconfig of kind cluster %s", clusterID)
+	}
+	return kubeConfig, nil
+}
+
+// GetClusterInfo retrieves cluster info by clusterID
+func (provider *KindClusterProvider) GetClusterInfo(clusterID string) (*CloudCluster, error) {
+	kubeConfig, err := provider.GetClusterKubeConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudCluster{
+		Provider:     ProviderKind,
+		ID:           clusterID,
+		Name:         clusterID,
+		Type:         ProviderKind,
+		Labels:       map[string]string{},
+		APIServerURL: getKubeConfigServer(kubeConfig),
+	}, nil
+}
+
+// CreateCloudCluster is not supported by the kind provider, clusters should be created by kind
+func (provider *KindClusterProvider) CreateCloudCluster(ctx context.Context, clusterName string, zone string, worker int, cpu int64, mem int64) (string, error) {
+	return "", ErrClusterCreationNotSupported
+}
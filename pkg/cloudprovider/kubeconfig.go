@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+)
+
+// KubeConfigClusterProvider imports clusters from the kubeconfig files in a directory. Each context in the kubeconfig
+// files is regarded as a cluster, whose id is <file name>/<context name>.
+type KubeConfigClusterProvider struct {
+	dir string
+}
+
+// NewKubeConfigClusterProvider create the provider for the kubeconfig directory
+func NewKubeConfigClusterProvider(dir string) (*KubeConfigClusterProvider, error) {
+	if dir == "" {
+		return nil, errors.New("the directory of kubeconfig files is not specified")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read kubeconfig directory %s", dir)
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("%s is not a directory", dir)
+	}
+	return &KubeConfigClusterProvider{dir: dir}, nil
+}
+
+// IsInvalidKey kubeconfig provider has no access key
+func (provider *KubeConfigClusterProvider) IsInvalidKey(err error) bool {
+	return false
+}
+
+func (provider *KubeConfigClusterProvider) loadKubeConfig(file string) (*clientcmdapi.Config, error) {
+	if file == "" || file == ".." || strings.Contains(file, string(filepath.Separator)) {
+		return nil, errors.Errorf("invalid kubeconfig file name %s", file)
+	}
+	config, err := clientcmd.LoadFromFile(filepath.Join(provider.dir, file))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load kubeconfig %s", file)
+	}
+	return config, nil
+}
+
+func (provider *KubeConfigClusterProvider) newCloudCluster(file string, config *clientcmdapi.Config, contextName string) (*CloudCluster, error) {
+	ctx, ok := config.Contexts[contextName]
+	if !ok {
+		return nil, errors.Errorf("context %s not found in kubeconfig %s", contextName, file)
+	}
+	cluster, ok := config.Clusters[ctx.Cluster]
+	if !ok {
+		return nil, errors.Errorf("cluster %s not found in kubeconfig %s", ctx.Cluster, file)
+	}
+	return &CloudCluster{
+		Provider:     ProviderKubeConfig,
+		ID:           file + "/" + contextName,
+		Name:         contextName,
+		Type:         ProviderKubeConfig,
+		Labels:       map[string]string{},
+		APIServerURL: cluster.Server,
+	}, nil
+}
+
+func (provider *KubeConfigClusterProvider) parseClusterID(clusterID string) (*clientcmdapi.Config, string, string, error) {
+	parts := strings.SplitN(clusterID, "/", 2)
+	if len(parts) != 2 {
+		return nil, "", "", errors.Errorf("invalid cluster id %s, it should be <file name>/<context name>", clusterID)
+	}
+	file, contextName := parts[0], parts[1]
+	config, err := provider.loadKubeConfig(file)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return config, file, contextName, nil
+}
+
+// ListCloudClusters list the contexts in the kubeconfig files, files which are not valid kubeconfig will be skipped
+func (provider *KubeConfigClusterProvider) ListCloudClusters(pageNumber int, pageSize int) ([]*CloudCluster, int, error) {
+	entries, err := os.ReadDir(provider.dir)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to read kubeconfig directory %s", provider.dir)
+	}
+	var clusters []*CloudCluster
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		config, err := provider.loadKubeConfig(entry.Name())
+		if err != nil {
+			klog.Warningf("skip invalid kubeconfig: %v", err)
+			continue
+		}
+		var contextNames []string
+		for contextName := range config.Contexts {
+			contextNames = append(contextNames, contextName)
+		}
+		sort.Strings(contextNames)
+		for _, contextName := range contextNames {
+			cluster, err := provider.newCloudCluster(entry.Name(), config, contextName)
+			if err != nil {
+				klog.Warningf("skip invalid context: %v", err)
+				continue
+			}
+			clusters = append(clusters, cluster)
+		}
+	}
+	return paginateClusters(clusters, pageNumber, pageSize), len(clusters), nil
+}
+
+// GetClusterKubeConfig returns the kubeconfig which only contains the context of the cluster, the referenced files
+// such as certificates are embedded
+func (provider *KubeConfigClusterProvider) GetClusterKubeConfig(clusterID string) (string, error) {
+	config, file, contextName, err := provider.parseClusterID(clusterID)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := config.Contexts[contextName]; !ok {
+		return "", errors.Errorf("context %s not found in kubeconfig %s", contextName, file)
+	}
+	config.CurrentContext = contextName
+	if err = clientcmdapi.MinifyConfig(config); err != nil {
+		return "", errors.Wrapf(err, "failed to minify kubeconfig %s", file)
+	}
+	if err = clientcmd.ResolveLocalPaths(config); err != nil {
+		return "", errors.Wrapf(err, "failed to resolve the paths in kubeconfig %s", file)
+	}
+	if err = clientcmdapi.FlattenConfig(config); err != nil {
+		return "", errors.Wrapf(err, "failed to flatten kubeconfig %s", file)
+	}
+	bs, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to encode kubeconfig")
+	}
+	return string(bs), nil
+}
+
+// GetClusterInfo retrieves cluster info by clusterID
+func (provider *KubeConfigClusterProvider) GetClusterInfo(clusterID string) (*CloudCluster, error) {
+	config, file, contextName, err := provider.parseClusterID(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return provider.newCloudCluster(file, config, contextName)
+}
+
+// CreateCloudCluster is not supported by the kubeconfig provider
+func (provider *KubeConfigClusterProvider) CreateCloudCluster(ctx context.Context, clusterName string, zone string, worker int, cpu int64, mem int64) (string, error) {
+	return "", ErrClusterCreationNotSupported
+}
@@ -19,6 +19,12 @@ package cloudprovider
 const (
 	// ProviderAliyun cloud provider aliyun
 	ProviderAliyun = "aliyun"
+	// ProviderKubeConfig imports clusters from the kubeconfig files in a directory
+	ProviderKubeConfig = "kubeconfig"
+	// ProviderClusterAPI imports clusters provisioned by Cluster API in the management cluster
+	ProviderClusterAPI = "cluster-api"
+	// ProviderKind imports local kind clusters
+	ProviderKind = "kind"
 )
 
 // CloudCluster describes the interface that cloud provider should return
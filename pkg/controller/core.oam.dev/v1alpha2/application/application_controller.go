@@ -51,6 +51,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/features"
 	monitorContext "github.com/oam-dev/kubevela/pkg/monitor/context"
 	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/discoverymapper"
 	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
@@ -69,6 +70,9 @@ const (
 	// baseWorkflowBackoffWaitTime is the time to wait gc check
 	baseGCBackoffWaitTime = 3000 * time.Millisecond
 
+	// baseDependencyBackoffWaitTime is the time to wait dependency applications check
+	baseDependencyBackoffWaitTime = 5000 * time.Millisecond
+
 	// resourceTrackerFinalizer is to delete the resource tracker of the latest app revision.
 	resourceTrackerFinalizer = "app.oam.dev/resource-tracker-finalizer"
 )
@@ -199,6 +203,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	app.Status.SetConditions(condition.ReadyCondition(common.RenderCondition.String()))
 	r.Recorder.Event(app, event.Normal(velatypes.ReasonRendered, velatypes.MessageRendered))
+	dependencyReady, err := r.checkAppDependencies(logCtx, app)
+	if err != nil {
+		logCtx.Error(err, "[check dependency applications]")
+		return r.endWithNegativeCondition(logCtx, app, condition.ErrorCondition(common.DependencyCondition.String(), err), common.ApplicationStarting)
+	}
+	if !dependencyReady && app.Status.Workflow == nil {
+		logCtx.Info("Waiting for dependency applications to be healthy")
+		return r.result(r.patchStatus(logCtx, app, common.ApplicationStarting)).requeue(baseDependencyBackoffWaitTime).ret()
+	}
 	wf := workflow.NewWorkflow(app, r.Client, appFile.WorkflowMode, appFile.Debug, handler.resourceKeeper)
 	workflowState, err := wf.ExecuteSteps(logCtx.Fork("workflow"), handler.currentAppRev, steps)
 	if err != nil {
@@ -211,6 +224,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	handler.addAppliedResource(true, app.Status.AppliedResources...)
 	app.Status.AppliedResources = handler.appliedResources
 	app.Status.Services = handler.services
+	app.Status.Summary = summarizeServices(handler.services)
 	switch workflowState {
 	case common.WorkflowStateInitializing:
 		metrics.WorkflowInitializedCounter.WithLabelValues().Inc()
@@ -263,6 +277,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	var phase = common.ApplicationRunning
 	if !hasHealthCheckPolicy(appFile.PolicyWorkloads) {
 		app.Status.Services = handler.services
+		app.Status.Summary = summarizeServices(handler.services)
 		if !isHealthy(handler.services) {
 			phase = common.ApplicationUnhealthy
 		}
@@ -286,11 +301,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 }
 
 func (r *Reconciler) stateKeep(logCtx monitorContext.Context, handler *AppHandler, app *v1beta1.Application) {
+	detected := app.Status.Drifts
 	if err := handler.resourceKeeper.StateKeep(logCtx); err != nil {
 		logCtx.Error(err, "Failed to run prevent-configuration-drift")
 		r.Recorder.Event(app, event.Warning(velatypes.ReasonFailedStateKeep, err))
 		app.Status.SetConditions(condition.ErrorCondition("StateKeep", err))
 	}
+	for _, drift := range app.Status.Drifts {
+		if !containsDrift(detected, drift) {
+			cluster := drift.Cluster
+			if cluster == "" {
+				cluster = multicluster.ClusterLocalName
+			}
+			r.Recorder.Event(app, event.Warning(velatypes.ReasonDriftDetected,
+				errors.Errorf(velatypes.MessageDriftDetected, drift.Kind, drift.Namespace, drift.Name, cluster)))
+		}
+	}
+}
+
+func containsDrift(drifts []common.ResourceDrift, target common.ResourceDrift) bool {
+	for _, drift := range drifts {
+		if drift.ClusterObjectReference.Equal(target.ClusterObjectReference) {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *Reconciler) gcResourceTrackers(logCtx monitorContext.Context, handler *AppHandler, phase common.ApplicationPhase, gcOutdated bool, isPatch bool) (ctrl.Result, error) {
@@ -374,6 +409,17 @@ func (r *Reconciler) handleFinalizers(ctx monitorContext.Context, app *v1beta1.A
 				metrics.HandleFinalizersDurationHistogram.WithLabelValues("application", "remove").Observe(v)
 			}))
 			defer subCtx.Commit("finish remove finalizers")
+			msg, err := r.checkAppDependents(ctx, app)
+			if err != nil {
+				return r.result(err).end(true)
+			}
+			if msg != "" {
+				subCtx.Info("Deletion blocked by dependent applications")
+				cond := condition.Deleting()
+				cond.Message = msg
+				app.Status.SetConditions(cond)
+				return r.result(r.patchStatus(ctx, app, common.ApplicationDeleting)).requeue(baseDependencyBackoffWaitTime).end(true)
+			}
 			rootRT, currentRT, historyRTs, cvRT, err := resourcetracker.ListApplicationResourceTrackers(ctx, r.Client, app)
 			if err != nil {
 				return r.result(err).end(true)
@@ -383,6 +429,11 @@ func (r *Reconciler) handleFinalizers(ctx monitorContext.Context, app *v1beta1.A
 				return true, result, err
 			}
 			if rootRT == nil && currentRT == nil && len(historyRTs) == 0 && cvRT == nil {
+				if err = resourcekeeper.ReleaseQuotaUsage(ctx, r.Client, app); err != nil {
+					return r.result(err).end(true)
+				}
+				// the drift series of the deleted application will not be updated anymore
+				metrics.ApplicationResourceDriftGauge.DeleteLabelValues(app.Name, app.Namespace)
 				meta.RemoveFinalizer(app, resourceTrackerFinalizer)
 				return r.result(errors.Wrap(r.Client.Update(ctx, app), errUpdateApplicationFinalizer)).end(true)
 			}
@@ -467,6 +518,52 @@ func isHealthy(services []common.ApplicationComponentStatus) bool {
 	return true
 }
 
+// summarizeServices counts the healthy components and clusters in the services of the application
+func summarizeServices(services []common.ApplicationComponentStatus) *common.ApplicationStatusSummary {
+	if len(services) == 0 {
+		return nil
+	}
+	summary := &common.ApplicationStatusSummary{}
+	clusterHealthy := map[string]bool{}
+	var clusters []string
+	for _, service := range services {
+		cluster := service.Cluster
+		if cluster == "" {
+			cluster = multicluster.ClusterLocalName
+		}
+		healthy := isHealthy([]common.ApplicationComponentStatus{service})
+		if _, found := clusterHealthy[cluster]; !found {
+			clusterHealthy[cluster] = true
+			clusters = append(clusters, cluster)
+		}
+		clusterHealthy[cluster] = clusterHealthy[cluster] && healthy
+		summary.Components++
+		idx := -1
+		for i, status := range summary.ClusterStatus {
+			if status.Cluster == cluster && status.Env == service.Env {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			summary.ClusterStatus = append(summary.ClusterStatus, common.ClusterStatusSummary{Cluster: cluster, Env: service.Env})
+			idx = len(summary.ClusterStatus) - 1
+		}
+		summary.ClusterStatus[idx].Components++
+		if healthy {
+			summary.HealthyComponents++
+			summary.ClusterStatus[idx].HealthyComponents++
+		}
+	}
+	summary.Clusters = len(clusters)
+	for _, cluster := range clusters {
+		if clusterHealthy[cluster] {
+			summary.HealthyClusters++
+		}
+	}
+	return summary
+}
+
 // SetupWithManager install to manager
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// If Application Own these two child objects, AC status change will notify application controller and recursively update AC again, and trigger application event again...
@@ -525,6 +622,7 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 					// once the resources is added, the managed fields will also be changed
 					new.Status.AppliedResources = old.Status.AppliedResources
 					new.Status.Services = old.Status.Services
+					new.Status.Summary = old.Status.Summary
 					// the resource version will be changed if the object is changed
 					// ignore this change and let reflect.DeepEqual to compare the rest of the object
 					new.ResourceVersion = old.ResourceVersion
@@ -38,6 +38,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -5747,3 +5748,26 @@ func newMockHTTP() *httptest.Server {
 	ts.Start()
 	return ts
 }
+
+func TestSummarizeServices(t *testing.T) {
+	r := require.New(t)
+	r.Nil(summarizeServices(nil))
+	summary := summarizeServices([]common.ApplicationComponentStatus{
+		{Name: "web", Healthy: true},
+		{Name: "db", Healthy: true},
+		{Name: "web", Cluster: "cluster-a", Env: "prod", Healthy: true},
+		{Name: "db", Cluster: "cluster-a", Env: "prod", Healthy: true, Traits: []common.ApplicationTraitStatus{{Type: "scaler", Healthy: false}}},
+		{Name: "web", Cluster: "cluster-b", Env: "prod", Healthy: false},
+	})
+	r.Equal(&common.ApplicationStatusSummary{
+		Clusters:          3,
+		HealthyClusters:   1,
+		Components:        5,
+		HealthyComponents: 3,
+		ClusterStatus: []common.ClusterStatusSummary{
+			{Cluster: "local", Components: 2, HealthyComponents: 2},
+			{Cluster: "cluster-a", Env: "prod", Components: 2, HealthyComponents: 1},
+			{Cluster: "cluster-b", Env: "prod", Components: 1, HealthyComponents: 0},
+		},
+	}, summary)
+}
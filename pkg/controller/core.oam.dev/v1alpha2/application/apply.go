@@ -258,6 +258,7 @@ func (h *AppHandler) collectHealthStatus(ctx context.Context, wl *appfile.Worklo
 		if err != nil {
 			return nil, false, errors.WithMessagef(err, "app=%s, comp=%s, evaluate workload status message error", appName, wl.Name)
 		}
+		status.Replicas = h.collectWorkloadReplicas(wl, accessor)
 	}
 
 	var traitStatusList []common.ApplicationTraitStatus
@@ -293,6 +294,31 @@ func (h *AppHandler) collectHealthStatus(ctx context.Context, wl *appfile.Worklo
 	return &status, isHealth, nil
 }
 
+// collectWorkloadReplicas reads the desired and ready replicas of the workload, returns nil if the workload has no replicas
+func (h *AppHandler) collectWorkloadReplicas(wl *appfile.Workload, accessor util.NamespaceAccessor) *common.WorkloadReplicas {
+	if wl.SkipApplyWorkload || wl.Ctx == nil {
+		return nil
+	}
+	base, _ := wl.Ctx.Output()
+	if base == nil {
+		return nil
+	}
+	obj, err := base.Unstructured()
+	if err != nil || obj.GetName() == "" {
+		return nil
+	}
+	workload, err := util.GetObjectGivenGVKAndName(wl.Ctx.GetCtx(), h.r.Client, obj.GroupVersionKind(), accessor.For(obj), obj.GetName())
+	if err != nil {
+		return nil
+	}
+	desired, found, err := unstructured.NestedInt64(workload.Object, "spec", "replicas")
+	if err != nil || !found {
+		return nil
+	}
+	ready, _, _ := unstructured.NestedInt64(workload.Object, "status", "readyReplicas")
+	return &common.WorkloadReplicas{Ready: int32(ready), Desired: int32(desired)}
+}
+
 func setStatus(status *common.ApplicationComponentStatus, observedGeneration, generation int64, labels map[string]string,
 	appRevName string, state terraformtypes.ConfigurationState, message string) bool {
 	isLatest := func() bool {
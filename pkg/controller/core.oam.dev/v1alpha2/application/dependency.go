@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/policy"
+)
+
+// checkAppDependencies check the health of the applications that the current application depends on and record the
+// result into the Dependency condition. Return false if any of the dependency applications is not healthy.
+func (r *Reconciler) checkAppDependencies(ctx context.Context, app *v1beta1.Application) (bool, error) {
+	deps, err := policy.GetAppDependencies(app)
+	if err != nil || len(deps) == 0 {
+		return err == nil, err
+	}
+	cycle, err := policy.FindAppDependencyCycle(ctx, r.Client, app)
+	if err != nil {
+		return false, err
+	}
+	if cycle != nil {
+		var names []string
+		for _, dep := range cycle {
+			names = append(names, dep.String())
+		}
+		return false, errors.Errorf("dependency applications form a cycle: %s", strings.Join(names, " -> "))
+	}
+	unhealthy, err := policy.CheckAppDependencies(ctx, r.Client, app)
+	if err != nil {
+		return false, err
+	}
+	if len(unhealthy) == 0 {
+		app.Status.SetConditions(condition.ReadyCondition(common.DependencyCondition.String()))
+		return true, nil
+	}
+	var names []string
+	for _, dep := range unhealthy {
+		names = append(names, dep.String())
+	}
+	app.Status.SetConditions(condition.ErrorCondition(common.DependencyCondition.String(),
+		errors.Errorf("dependency applications are not healthy: %s", strings.Join(names, ", "))))
+	return false, nil
+}
+
+// checkAppDependents return the message for blocking the deletion if there are applications depending on the current
+// application. Empty message will be returned if no application depends on it.
+func (r *Reconciler) checkAppDependents(ctx context.Context, app *v1beta1.Application) (string, error) {
+	dependents, err := policy.ListDependentApplications(ctx, r.Client, app)
+	if err != nil || len(dependents) == 0 {
+		return "", err
+	}
+	var names []string
+	for _, dependent := range dependents {
+		names = append(names, dependent.Namespace+"/"+dependent.Name)
+	}
+	return fmt.Sprintf("Waiting for dependent applications %s to be deleted.", strings.Join(names, ", ")), nil
+}
@@ -79,6 +79,7 @@ func (h *AppHandler) GenerateApplicationSteps(ctx monitorContext.Context,
 		func(comp common.ApplicationComponent) (*appfile.Workload, error) {
 			return appParser.ParseWorkloadFromRevision(comp, appRev)
 		},
+		h.r.Recorder,
 	)
 	terraformProvider.Install(handlerProviders, app, func(comp common.ApplicationComponent) (*appfile.Workload, error) {
 		return appParser.ParseWorkloadFromRevision(comp, appRev)
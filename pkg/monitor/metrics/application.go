@@ -121,6 +121,12 @@ var (
 		Help: "application phase number",
 	}, []string{"phase"})
 
+	// ApplicationResourceDriftGauge report the number of drifted resources in application
+	ApplicationResourceDriftGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "application_resource_drift_number",
+		Help: "application drifted resource number",
+	}, []string{"app_name", "app_namespace"})
+
 	// WorkflowStepPhaseGauge report the number of workflow step state
 	WorkflowStepPhaseGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "workflow_step_phase_number",
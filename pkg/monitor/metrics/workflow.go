@@ -49,6 +49,7 @@ var collectorGroup = []prometheus.Collector{
 	WorkflowInitializedCounter,
 	ApplicationPhaseCounter,
 	WorkflowStepPhaseGauge,
+	ApplicationResourceDriftGauge,
 	ResourceTrackerNumberGauge,
 	ClusterIsConnectedGauge,
 	ClusterWorkerNumberGauge,
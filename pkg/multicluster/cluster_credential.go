@@ -0,0 +1,347 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1alpha1 "github.com/oam-dev/cluster-gateway/pkg/apis/cluster/v1alpha1"
+
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+const (
+	// DefaultClusterServiceAccountName the name of the service account provisioned in the managed cluster
+	DefaultClusterServiceAccountName = "kubevela-cluster-gateway"
+	// DefaultClusterServiceAccountRole the cluster role bound to the service account provisioned in the managed cluster
+	// by default. It aggregates the built-in edit role and the cluster roles labeled with
+	// LabelAggregateToClusterServiceAccountRole, and is created by KubeVela if not exists.
+	DefaultClusterServiceAccountRole = "kubevela-cluster-gateway"
+	// LabelAggregateToClusterServiceAccountRole the label of cluster roles to aggregate into the default cluster role,
+	// which can be used by administrators to grant extra permissions to the provisioned service account
+	LabelAggregateToClusterServiceAccountRole = "cluster.core.oam.dev/aggregate-to-cluster-gateway"
+	// DefaultClusterTokenExpiration the default expiration of the service account token used as cluster credential
+	DefaultClusterTokenExpiration = 24 * time.Hour
+)
+
+// ClusterCredential records the information of the credential used to access the cluster
+type ClusterCredential struct {
+	// ServiceAccount is the namespace/name of the service account provisioned in the managed cluster, empty if the
+	// credential is not provisioned by KubeVela
+	ServiceAccount string
+	// ExpirationTime is the expiration time of the credential, nil if the credential does not expire
+	ExpirationTime *time.Time
+}
+
+// IsRotatable check if the credential is a service account token which can be rotated
+func (c *ClusterCredential) IsRotatable() bool {
+	return c != nil && c.ServiceAccount != ""
+}
+
+// getClusterCredential extract the credential information from the annotations of cluster object
+func getClusterCredential(o client.Object) *ClusterCredential {
+	credential := &ClusterCredential{}
+	annots := o.GetAnnotations()
+	if annots == nil {
+		return credential
+	}
+	credential.ServiceAccount = annots[types.AnnotationClusterServiceAccount]
+	if t, err := time.Parse(time.RFC3339, annots[types.AnnotationClusterCredentialExpiration]); err == nil {
+		credential.ExpirationTime = &t
+	}
+	return credential
+}
+
+// IsExecCredential check if the kubeconfig user uses exec plugins or auth providers, instead of static credentials
+func (clusterConfig *KubeClusterConfig) IsExecCredential() bool {
+	authInfo := clusterConfig.AuthInfo
+	if authInfo == nil || authInfo.Token != "" || len(authInfo.ClientCertificateData) > 0 {
+		return false
+	}
+	return authInfo.Exec != nil || authInfo.AuthProvider != nil
+}
+
+// ProvisionServiceAccountToken create a service account in the managed cluster with the kubeconfig, and replace the
+// credential of the cluster with the token of the service account, the service account is bound to the given cluster
+// role, the default cluster role is used if the role is empty
+func (clusterConfig *KubeClusterConfig) ProvisionServiceAccountToken(ctx context.Context, namespace string, role string, expiration time.Duration) error {
+	restConfig, err := clusterConfig.GetRESTConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	if err = ProvisionServiceAccount(ctx, kubeClient, namespace, DefaultClusterServiceAccountName, role); err != nil {
+		return err
+	}
+	token, expirationTime, err := RequestServiceAccountToken(ctx, kubeClient, namespace, DefaultClusterServiceAccountName, expiration)
+	if err != nil {
+		return err
+	}
+	authInfo := clusterConfig.AuthInfo.DeepCopy()
+	authInfo.Token = token
+	clusterConfig.AuthInfo = authInfo
+	clusterConfig.Credential = &ClusterCredential{
+		ServiceAccount: namespace + "/" + DefaultClusterServiceAccountName,
+		ExpirationTime: &expirationTime,
+	}
+	return nil
+}
+
+// ProvisionServiceAccount ensure the service account and its cluster role binding exist in the managed cluster. The
+// default cluster role is created and used if the role is empty, otherwise the role must exist in the managed cluster.
+func ProvisionServiceAccount(ctx context.Context, kubeClient kubernetes.Interface, namespace string, name string, role string) error {
+	if role == "" || role == DefaultClusterServiceAccountRole {
+		role = DefaultClusterServiceAccountRole
+		if err := provisionDefaultClusterRole(ctx, kubeClient); err != nil {
+			return err
+		}
+	} else if _, err := kubeClient.RbacV1().ClusterRoles().Get(ctx, role, metav1.GetOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to get cluster role %s", role)
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	if _, err := kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create namespace %s", namespace)
+	}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if _, err := kubeClient.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create service account %s/%s", namespace, name)
+	}
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}},
+	}
+	existing, err := kubeClient.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err = kubeClient.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create cluster role binding %s", name)
+		}
+	case err != nil:
+		return errors.Wrapf(err, "failed to get cluster role binding %s", name)
+	case existing.RoleRef.Name != role:
+		// the role of a binding is immutable, recreate the binding to switch the role
+		if err = kubeClient.RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to delete cluster role binding %s", name)
+		}
+		if _, err = kubeClient.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create cluster role binding %s", name)
+		}
+	}
+	return nil
+}
+
+// provisionDefaultClusterRole ensure the default cluster role exists in the managed cluster. It aggregates the
+// built-in edit role for namespaced resources, and a base role which allows managing namespaces. Existing roles are
+// not modified, so administrators can customize them.
+func provisionDefaultClusterRole(ctx context.Context, kubeClient kubernetes.Interface) error {
+	baseRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   DefaultClusterServiceAccountRole + "-base",
+			Labels: map[string]string{LabelAggregateToClusterServiceAccountRole: "true"},
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"namespaces"},
+			Verbs:     []string{"get", "list", "watch", "create"},
+		}},
+	}
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultClusterServiceAccountRole},
+		AggregationRule: &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-edit": "true"}},
+			{MatchLabels: map[string]string{LabelAggregateToClusterServiceAccountRole: "true"}},
+		}},
+	}
+	for _, r := range []*rbacv1.ClusterRole{baseRole, role} {
+		if _, err := kubeClient.RbacV1().ClusterRoles().Create(ctx, r, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create cluster role %s", r.Name)
+		}
+	}
+	return nil
+}
+
+// RequestServiceAccountToken request a token of the service account with the expiration through the TokenRequest API
+func RequestServiceAccountToken(ctx context.Context, kubeClient kubernetes.Interface, namespace string, name string, expiration time.Duration) (string, time.Time, error) {
+	expirationSeconds := int64(expiration.Seconds())
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}
+	resp, err := kubeClient.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return "", time.Time{}, errors.Wrapf(err, "failed to request token for service account %s/%s", namespace, name)
+	}
+	if resp.Status.Token == "" {
+		return "", time.Time{}, errors.Errorf("empty token returned for service account %s/%s", namespace, name)
+	}
+	return resp.Status.Token, resp.Status.ExpirationTimestamp.Time, nil
+}
+
+// SetClusterToken replace the token in the cluster secret and record its expiration time
+func SetClusterToken(ctx context.Context, cli client.Client, vc *VirtualCluster, token string, expirationTime time.Time) error {
+	if _, ok := vc.Object.(*corev1.Secret); !ok || vc.Type != clusterv1alpha1.CredentialTypeServiceAccountToken {
+		return errors.Errorf("cannot set token for cluster %s with credential type %s", vc.Name, vc.Type)
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				types.AnnotationClusterCredentialExpiration: expirationTime.Format(time.RFC3339),
+			},
+		},
+		"data": map[string][]byte{"token": []byte(token)},
+	})
+	if err != nil {
+		return err
+	}
+	if err = cli.Patch(ContextInLocalCluster(ctx), vc.Object, client.RawPatch(apitypes.MergePatchType, patch)); err != nil {
+		return errors.Wrapf(err, "failed to set token for cluster %s", vc.Name)
+	}
+	vc.Credential = &ClusterCredential{ServiceAccount: vc.Credential.ServiceAccount, ExpirationTime: &expirationTime}
+	return nil
+}
+
+// ClusterCredentialRotator request a new token of the service account in the cluster
+type ClusterCredentialRotator func(ctx context.Context, clusterName string, serviceAccount string, expiration time.Duration) (string, time.Time, error)
+
+// ClusterCredentialMgr periodically rotates the service account tokens of clusters before they expire
+type ClusterCredentialMgr struct {
+	kubeClient     client.Client
+	rotator        ClusterCredentialRotator
+	rotationPeriod time.Duration
+	expiration     time.Duration
+}
+
+// NewClusterCredentialMgr will create a cluster credential manager which rotates tokens through cluster-gateway
+func NewClusterCredentialMgr(ctx context.Context, kubeClient client.Client, config *rest.Config, rotationPeriod time.Duration, expiration time.Duration) (*ClusterCredentialMgr, error) {
+	if expiration <= rotationPeriod {
+		return nil, errors.Errorf("the token expiration %s must be longer than the rotation period %s", expiration, rotationPeriod)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	mgr := &ClusterCredentialMgr{
+		kubeClient: kubeClient,
+		rotator: func(ctx context.Context, clusterName string, serviceAccount string, expiration time.Duration) (string, time.Time, error) {
+			namespace, name, err := parseServiceAccount(serviceAccount)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			return RequestServiceAccountToken(ContextWithClusterName(ctx, clusterName), clientset, namespace, name, expiration)
+		},
+		rotationPeriod: rotationPeriod,
+		expiration:     expiration,
+	}
+	go mgr.Start(ctx)
+	return mgr, nil
+}
+
+func parseServiceAccount(serviceAccount string) (string, string, error) {
+	parts := strings.Split(serviceAccount, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid service account %s, should be in the format of namespace/name", serviceAccount)
+	}
+	return parts[0], parts[1], nil
+}
+
+// needRotate check if the credential should be rotated, which is expired or will expire in the next two rotation
+// periods, or has lived more than two thirds of its lifetime
+func (ccm *ClusterCredentialMgr) needRotate(credential *ClusterCredential, now time.Time) bool {
+	if !credential.IsRotatable() {
+		return false
+	}
+	if credential.ExpirationTime == nil {
+		return true
+	}
+	remaining := credential.ExpirationTime.Sub(now)
+	return remaining < 2*ccm.rotationPeriod || remaining < ccm.expiration/3
+}
+
+// Refresh will rotate the tokens of clusters which are about to expire
+func (ccm *ClusterCredentialMgr) Refresh(ctx context.Context) error {
+	clusters, err := FindVirtualClustersByLabels(ctx, ccm.kubeClient, map[string]string{})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range clusters {
+		cluster := &clusters[i]
+		if !ccm.needRotate(cluster.Credential, now) {
+			continue
+		}
+		token, expirationTime, err := ccm.rotator(ctx, cluster.Name, cluster.Credential.ServiceAccount, ccm.expiration)
+		if err != nil {
+			klog.Warningf("failed to rotate token of cluster-(%s): %v", cluster.Name, err)
+			continue
+		}
+		if err = SetClusterToken(ctx, ccm.kubeClient, cluster, token, expirationTime); err != nil {
+			klog.Warningf("failed to update token of cluster-(%s): %v", cluster.Name, err)
+			continue
+		}
+		klog.Infof("token of cluster-(%s) rotated, expires at %s", cluster.Name, expirationTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Start will start polling clusters to rotate their tokens
+func (ccm *ClusterCredentialMgr) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			klog.Warning("Stop cluster credential rotation loop.")
+			return
+		default:
+			if err := ccm.Refresh(ctx); err != nil {
+				klog.Warningf("failed to rotate cluster credentials: %v", err)
+			}
+			time.Sleep(ccm.rotationPeriod)
+		}
+	}
+}
+
+// clusterCredentialAnnotations the annotations to record the credential on cluster secret
+func clusterCredentialAnnotations(credential *ClusterCredential) map[string]string {
+	annots := map[string]string{}
+	if credential == nil {
+		return annots
+	}
+	if credential.ServiceAccount != "" {
+		annots[types.AnnotationClusterServiceAccount] = credential.ServiceAccount
+	}
+	if credential.ExpirationTime != nil {
+		annots[types.AnnotationClusterCredentialExpiration] = credential.ExpirationTime.Format(time.RFC3339)
+	}
+	return annots
+}
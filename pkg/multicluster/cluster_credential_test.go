@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestProvisionServiceAccount(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	expirationTime := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "serviceaccounts", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		tokenRequest := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenRequest)
+		r.Equal(int64(3600), *tokenRequest.Spec.ExpirationSeconds)
+		tokenRequest.Status = authenticationv1.TokenRequestStatus{Token: "token", ExpirationTimestamp: expirationTime}
+		return true, tokenRequest, nil
+	})
+	r.NoError(ProvisionServiceAccount(ctx, kubeClient, types.DefaultKubeVelaNS, DefaultClusterServiceAccountName, ""))
+	// provisioning is idempotent
+	r.NoError(ProvisionServiceAccount(ctx, kubeClient, types.DefaultKubeVelaNS, DefaultClusterServiceAccountName, ""))
+	binding, err := kubeClient.RbacV1().ClusterRoleBindings().Get(ctx, DefaultClusterServiceAccountName, metav1.GetOptions{})
+	r.NoError(err)
+	r.Equal(DefaultClusterServiceAccountRole, binding.RoleRef.Name)
+	r.Equal(types.DefaultKubeVelaNS, binding.Subjects[0].Namespace)
+	role, err := kubeClient.RbacV1().ClusterRoles().Get(ctx, DefaultClusterServiceAccountRole, metav1.GetOptions{})
+	r.NoError(err)
+	r.Equal(2, len(role.AggregationRule.ClusterRoleSelectors))
+	_, err = kubeClient.RbacV1().ClusterRoles().Get(ctx, DefaultClusterServiceAccountRole+"-base", metav1.GetOptions{})
+	r.NoError(err)
+
+	// the configured role must exist
+	r.Error(ProvisionServiceAccount(ctx, kubeClient, types.DefaultKubeVelaNS, DefaultClusterServiceAccountName, "deployer"))
+	_, err = kubeClient.RbacV1().ClusterRoles().Create(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "deployer"}}, metav1.CreateOptions{})
+	r.NoError(err)
+	r.NoError(ProvisionServiceAccount(ctx, kubeClient, types.DefaultKubeVelaNS, DefaultClusterServiceAccountName, "deployer"))
+	binding, err = kubeClient.RbacV1().ClusterRoleBindings().Get(ctx, DefaultClusterServiceAccountName, metav1.GetOptions{})
+	r.NoError(err)
+	r.Equal("deployer", binding.RoleRef.Name)
+
+	token, expiration, err := RequestServiceAccountToken(ctx, kubeClient, types.DefaultKubeVelaNS, DefaultClusterServiceAccountName, time.Hour)
+	r.NoError(err)
+	r.Equal("token", token)
+	r.True(expirationTime.Time.Equal(expiration))
+}
+
+func TestIsExecCredential(t *testing.T) {
+	r := require.New(t)
+	clusterConfig := &KubeClusterConfig{AuthInfo: &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{Command: "aws"}}}
+	r.True(clusterConfig.IsExecCredential())
+	clusterConfig.AuthInfo = &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "gcp"}}
+	r.True(clusterConfig.IsExecCredential())
+	clusterConfig.AuthInfo = &clientcmdapi.AuthInfo{Token: "token"}
+	r.False(clusterConfig.IsExecCredential())
+}
+
+func TestClusterCredentialRefresh(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	newSecret := func(name string, serviceAccount string, expiration time.Duration) *corev1.Secret {
+		secret := FakeSecret(name)
+		secret.Annotations = map[string]string{
+			types.AnnotationClusterCredentialExpiration: time.Now().Add(expiration).Format(time.RFC3339),
+		}
+		if serviceAccount != "" {
+			secret.Annotations[types.AnnotationClusterServiceAccount] = serviceAccount
+		}
+		return secret
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newSecret("expiring-cluster", "vela-system/kubevela-cluster-gateway", 30*time.Minute),
+		newSecret("fresh-cluster", "vela-system/kubevela-cluster-gateway", 23*time.Hour),
+		newSecret("static-cluster", "", time.Minute),
+	).Build()
+	newExpirationTime := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	var rotated []string
+	mgr := &ClusterCredentialMgr{
+		kubeClient: cli,
+		rotator: func(ctx context.Context, clusterName string, serviceAccount string, expiration time.Duration) (string, time.Time, error) {
+			r.Equal("vela-system/kubevela-cluster-gateway", serviceAccount)
+			rotated = append(rotated, clusterName)
+			return "new-token", newExpirationTime, nil
+		},
+		rotationPeriod: 10 * time.Minute,
+		expiration:     24 * time.Hour,
+	}
+	r.NoError(mgr.Refresh(ctx))
+	r.Equal([]string{"expiring-cluster"}, rotated)
+	vc, err := GetVirtualCluster(ctx, cli, "expiring-cluster")
+	r.NoError(err)
+	r.True(vc.Credential.IsRotatable())
+	r.True(newExpirationTime.Equal(*vc.Credential.ExpirationTime))
+	r.Equal("new-token", string(vc.Object.(*corev1.Secret).Data["token"]))
+	vc, err = GetVirtualCluster(ctx, cli, "static-cluster")
+	r.NoError(err)
+	r.False(vc.Credential.IsRotatable())
+
+	_, err = NewClusterCredentialMgr(ctx, cli, nil, time.Hour, time.Minute)
+	r.NotNil(err)
+}
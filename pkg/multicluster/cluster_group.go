@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"encoding/json"
+
+	prismclusterv1alpha1 "github.com/kubevela/prism/pkg/apis/cluster/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+const (
+	clusterGroupConfigMapPrefix         = "cluster-group-"
+	clusterGroupClustersKey             = "clusters"
+	clusterGroupClusterLabelSelectorKey = "clusterLabelSelector"
+)
+
+// ClusterGroup is a named set of clusters, stored as configmap alongside the cluster secrets. The members of the
+// group are the static clusters and the clusters matching the label selector.
+type ClusterGroup struct {
+	Name                 string            `json:"name"`
+	Clusters             []string          `json:"clusters,omitempty"`
+	ClusterLabelSelector map[string]string `json:"clusterLabelSelector,omitempty"`
+}
+
+// NewClusterGroupFromConfigMap extract cluster group from the configmap
+func NewClusterGroupFromConfigMap(cm *corev1.ConfigMap) (*ClusterGroup, error) {
+	name, ok := cm.GetLabels()[types.LabelClusterGroup]
+	if !ok {
+		return nil, errors.Errorf("configmap %s is not a valid cluster group", cm.Name)
+	}
+	group := &ClusterGroup{Name: name}
+	if s, ok := cm.Data[clusterGroupClustersKey]; ok && s != "" {
+		if err := json.Unmarshal([]byte(s), &group.Clusters); err != nil {
+			return nil, errors.Wrapf(err, "invalid clusters in cluster group %s", name)
+		}
+	}
+	if s, ok := cm.Data[clusterGroupClusterLabelSelectorKey]; ok && s != "" {
+		if err := json.Unmarshal([]byte(s), &group.ClusterLabelSelector); err != nil {
+			return nil, errors.Wrapf(err, "invalid cluster label selector in cluster group %s", name)
+		}
+	}
+	return group, nil
+}
+
+func (group *ClusterGroup) setConfigMapData(cm *corev1.ConfigMap) error {
+	clusters, err := json.Marshal(group.Clusters)
+	if err != nil {
+		return err
+	}
+	selector, err := json.Marshal(group.ClusterLabelSelector)
+	if err != nil {
+		return err
+	}
+	cm.Data = map[string]string{
+		clusterGroupClustersKey:             string(clusters),
+		clusterGroupClusterLabelSelectorKey: string(selector),
+	}
+	return nil
+}
+
+// Validate check if the cluster group is valid, the static clusters must exist
+func (group *ClusterGroup) Validate(ctx context.Context, cli client.Client) error {
+	if errs := validation.IsDNS1123Label(group.Name); len(errs) > 0 {
+		return errors.Errorf("invalid cluster group name %s: %v", group.Name, errs)
+	}
+	if len(group.Clusters) == 0 && len(group.ClusterLabelSelector) == 0 {
+		return errors.Errorf("cluster group %s must have clusters or cluster label selector", group.Name)
+	}
+	for _, cluster := range group.Clusters {
+		if _, err := GetVirtualCluster(ctx, cli, cluster); err != nil {
+			return errors.Wrapf(err, "failed to get cluster %s in cluster group %s", cluster, group.Name)
+		}
+	}
+	return nil
+}
+
+// GetClusters returns the members of the cluster group, the static clusters come first
+func (group *ClusterGroup) GetClusters(ctx context.Context, cli client.Client) ([]string, error) {
+	var clusters []string
+	found := map[string]bool{}
+	add := func(cluster string) {
+		if !found[cluster] {
+			found[cluster] = true
+			clusters = append(clusters, cluster)
+		}
+	}
+	for _, cluster := range group.Clusters {
+		add(cluster)
+	}
+	if len(group.ClusterLabelSelector) > 0 {
+		clusterList, err := prismclusterv1alpha1.NewClusterClient(cli).List(ctx, client.MatchingLabels(group.ClusterLabelSelector))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find clusters in cluster group %s", group.Name)
+		}
+		for _, cluster := range clusterList.Items {
+			add(cluster.Name)
+		}
+	}
+	return clusters, nil
+}
+
+func getClusterGroupConfigMap(ctx context.Context, cli client.Client, name string) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: ClusterGatewaySecretNamespace, Name: clusterGroupConfigMapPrefix + name}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrClusterGroupNotExists
+		}
+		return nil, errors.Wrapf(err, "failed to get cluster group %s", name)
+	}
+	if _, ok := cm.GetLabels()[types.LabelClusterGroup]; !ok {
+		return nil, ErrClusterGroupNotExists
+	}
+	return cm, nil
+}
+
+// GetClusterGroup returns the cluster group with given name
+func GetClusterGroup(ctx context.Context, cli client.Client, name string) (*ClusterGroup, error) {
+	cm, err := getClusterGroupConfigMap(ctx, cli, name)
+	if err != nil {
+		return nil, err
+	}
+	return NewClusterGroupFromConfigMap(cm)
+}
+
+// ListClusterGroups list all the cluster groups
+func ListClusterGroups(ctx context.Context, cli client.Client) ([]ClusterGroup, error) {
+	cms := &corev1.ConfigMapList{}
+	if err := cli.List(ctx, cms, client.InNamespace(ClusterGatewaySecretNamespace), client.HasLabels{types.LabelClusterGroup}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list cluster groups")
+	}
+	var groups []ClusterGroup
+	for i := range cms.Items {
+		if group, err := NewClusterGroupFromConfigMap(&cms.Items[i]); err == nil {
+			groups = append(groups, *group)
+		}
+	}
+	return groups, nil
+}
+
+// CreateClusterGroup creates the cluster group
+func CreateClusterGroup(ctx context.Context, cli client.Client, group *ClusterGroup) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterGroupConfigMapPrefix + group.Name,
+			Namespace: ClusterGatewaySecretNamespace,
+			Labels:    map[string]string{types.LabelClusterGroup: group.Name},
+		},
+	}
+	if err := group.setConfigMapData(cm); err != nil {
+		return errors.Wrapf(err, "failed to encode cluster group %s", group.Name)
+	}
+	if err := cli.Create(ctx, cm); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return ErrClusterGroupExists
+		}
+		return errors.Wrapf(err, "failed to create cluster group %s", group.Name)
+	}
+	return nil
+}
+
+// UpdateClusterGroup updates the members of the cluster group
+func UpdateClusterGroup(ctx context.Context, cli client.Client, group *ClusterGroup) error {
+	cm, err := getClusterGroupConfigMap(ctx, cli, group.Name)
+	if err != nil {
+		return err
+	}
+	if err = group.setConfigMapData(cm); err != nil {
+		return errors.Wrapf(err, "failed to encode cluster group %s", group.Name)
+	}
+	if err = cli.Update(ctx, cm); err != nil {
+		return errors.Wrapf(err, "failed to update cluster group %s", group.Name)
+	}
+	return nil
+}
+
+// DeleteClusterGroup deletes the cluster group
+func DeleteClusterGroup(ctx context.Context, cli client.Client, name string) error {
+	cm, err := getClusterGroupConfigMap(ctx, cli, name)
+	if err != nil {
+		return err
+	}
+	if err = cli.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete cluster group %s", name)
+	}
+	return nil
+}
+
+// GetClustersInClusterGroups returns the members of the cluster groups without duplicates
+func GetClustersInClusterGroups(ctx context.Context, cli client.Client, names []string) ([]string, error) {
+	var clusters []string
+	found := map[string]bool{}
+	for _, name := range names {
+		group, err := GetClusterGroup(ctx, cli, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get cluster group %s", name)
+		}
+		members, err := group.GetClusters(ctx, cli)
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range members {
+			if !found[cluster] {
+				found[cluster] = true
+				clusters = append(clusters, cluster)
+			}
+		}
+	}
+	return clusters, nil
+}
+
+// replaceClusterInClusterGroups replaces the static member of the cluster groups with the new cluster name, or
+// removes the member if the new cluster name is empty. It keeps the cluster groups valid after the cluster is
+// renamed or detached.
+func replaceClusterInClusterGroups(ctx context.Context, cli client.Client, oldClusterName string, newClusterName string) error {
+	groups, err := ListClusterGroups(ctx, cli)
+	if err != nil {
+		return err
+	}
+	for i := range groups {
+		group := &groups[i]
+		var clusters []string
+		changed := false
+		for _, cluster := range group.Clusters {
+			switch {
+			case cluster != oldClusterName:
+				clusters = append(clusters, cluster)
+			case newClusterName != "":
+				clusters, changed = append(clusters, newClusterName), true
+			default:
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		group.Clusters = clusters
+		if err = UpdateClusterGroup(ctx, cli, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
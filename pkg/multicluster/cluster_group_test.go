@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1alpha1 "github.com/oam-dev/cluster-gateway/pkg/apis/cluster/v1alpha1"
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestClusterGroup(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	oldClusterGatewaySecretNamespace := ClusterGatewaySecretNamespace
+	ClusterGatewaySecretNamespace = types.DefaultKubeVelaNS
+	defer func() {
+		ClusterGatewaySecretNamespace = oldClusterGatewaySecretNamespace
+	}()
+	newSecret := func(name string, labels map[string]string) *corev1.Secret {
+		secret := FakeSecret(name)
+		secret.Namespace = ClusterGatewaySecretNamespace
+		secret.Labels[clustercommon.LabelKeyClusterEndpointType] = string(clusterv1alpha1.ClusterEndpointTypeConst)
+		for k, v := range labels {
+			secret.Labels[k] = v
+		}
+		return secret
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newSecret("cluster-a", map[string]string{"region": "east"}),
+		newSecret("cluster-b", map[string]string{"region": "east"}),
+		newSecret("cluster-c", map[string]string{"region": "west"}),
+	).Build()
+
+	r.Contains((&ClusterGroup{Name: "Invalid_Name", Clusters: []string{"cluster-a"}}).Validate(ctx, cli).Error(), "invalid cluster group name")
+	r.Contains((&ClusterGroup{Name: "empty"}).Validate(ctx, cli).Error(), "must have clusters or cluster label selector")
+	r.Contains((&ClusterGroup{Name: "missing", Clusters: []string{"cluster-x"}}).Validate(ctx, cli).Error(), "failed to get cluster cluster-x")
+
+	east := &ClusterGroup{Name: "east", Clusters: []string{"cluster-c"}, ClusterLabelSelector: map[string]string{"region": "east"}}
+	r.NoError(east.Validate(ctx, cli))
+	r.NoError(CreateClusterGroup(ctx, cli, east))
+	r.Equal(ErrClusterGroupExists, CreateClusterGroup(ctx, cli, east))
+	r.NoError(CreateClusterGroup(ctx, cli, &ClusterGroup{Name: "static", Clusters: []string{"local", "cluster-a"}}))
+
+	group, err := GetClusterGroup(ctx, cli, "east")
+	r.NoError(err)
+	r.Equal(east, group)
+	clusters, err := group.GetClusters(ctx, cli)
+	r.NoError(err)
+	r.Equal([]string{"cluster-c", "cluster-a", "cluster-b"}, clusters)
+	_, err = GetClusterGroup(ctx, cli, "west")
+	r.Equal(ErrClusterGroupNotExists, err)
+
+	groups, err := ListClusterGroups(ctx, cli)
+	r.NoError(err)
+	r.Equal(2, len(groups))
+
+	clusters, err = GetClustersInClusterGroups(ctx, cli, []string{"static", "east"})
+	r.NoError(err)
+	r.Equal([]string{"local", "cluster-a", "cluster-c", "cluster-b"}, clusters)
+	_, err = GetClustersInClusterGroups(ctx, cli, []string{"west"})
+	r.NotNil(err)
+	r.Contains(err.Error(), "no such cluster group")
+
+	r.NoError(UpdateClusterGroup(ctx, cli, &ClusterGroup{Name: "east", ClusterLabelSelector: map[string]string{"region": "west"}}))
+	clusters, err = GetClustersInClusterGroups(ctx, cli, []string{"east"})
+	r.NoError(err)
+	r.Equal([]string{"cluster-c"}, clusters)
+	r.Equal(ErrClusterGroupNotExists, UpdateClusterGroup(ctx, cli, &ClusterGroup{Name: "west", Clusters: []string{"local"}}))
+
+	r.NoError(DeleteClusterGroup(ctx, cli, "east"))
+	r.Equal(ErrClusterGroupNotExists, DeleteClusterGroup(ctx, cli, "east"))
+	groups, err = ListClusterGroups(ctx, cli)
+	r.NoError(err)
+	r.Equal(1, len(groups))
+	r.Equal("static", groups[0].Name)
+
+	r.NoError(replaceClusterInClusterGroups(ctx, cli, "cluster-a", "cluster-x"))
+	clusters, err = GetClustersInClusterGroups(ctx, cli, []string{"static"})
+	r.NoError(err)
+	r.Equal([]string{"local", "cluster-x"}, clusters)
+	r.NoError(replaceClusterInClusterGroups(ctx, cli, "cluster-x", ""))
+	clusters, err = GetClustersInClusterGroups(ctx, cli, []string{"static"})
+	r.NoError(err)
+	r.Equal([]string{"local"}, clusters)
+}
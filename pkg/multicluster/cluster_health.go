@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/cluster-gateway/pkg/generated/clientset/versioned"
+
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+// ClusterHealthStatus the health status of cluster
+type ClusterHealthStatus string
+
+const (
+	// ClusterHealthy the cluster passes the health probe
+	ClusterHealthy ClusterHealthStatus = "Healthy"
+	// ClusterUnhealthy the cluster continuously fails the health probe
+	ClusterUnhealthy ClusterHealthStatus = "Unhealthy"
+	// ClusterHealthUnknown the cluster has not been probed yet
+	ClusterHealthUnknown ClusterHealthStatus = "Unknown"
+)
+
+const (
+	// ClusterHealthReasonDisconnected the cluster cannot be connected
+	ClusterHealthReasonDisconnected = "Disconnected"
+	// ClusterHealthReasonProbeFailed the cluster is connected but the health probe fails
+	ClusterHealthReasonProbeFailed = "ProbeFailed"
+)
+
+// ClusterHealth records the health status of cluster
+type ClusterHealth struct {
+	Status             ClusterHealthStatus
+	Reason             string
+	LastTransitionTime time.Time
+}
+
+// IsHealthy check if the cluster is regarded as healthy. Clusters that have not been probed are regarded as healthy.
+func (h *ClusterHealth) IsHealthy() bool {
+	return h == nil || h.Status != ClusterUnhealthy
+}
+
+// getClusterHealth extract the health status from the annotations of cluster object
+func getClusterHealth(o client.Object) *ClusterHealth {
+	annots := o.GetAnnotations()
+	if annots == nil || annots[types.AnnotationClusterHealth] == "" {
+		return &ClusterHealth{Status: ClusterHealthUnknown}
+	}
+	health := &ClusterHealth{
+		Status: ClusterHealthStatus(annots[types.AnnotationClusterHealth]),
+		Reason: annots[types.AnnotationClusterHealthReason],
+	}
+	if t, err := time.Parse(time.RFC3339, annots[types.AnnotationClusterHealthLastTransitionTime]); err == nil {
+		health.LastTransitionTime = t
+	}
+	return health
+}
+
+// SetClusterHealth record the health status into the annotations of the cluster secret or managed cluster. The
+// cluster object will not be patched if the health status is not changed.
+func SetClusterHealth(ctx context.Context, cli client.Client, vc *VirtualCluster, status ClusterHealthStatus, reason string) error {
+	if vc.Object == nil {
+		return errors.Errorf("cannot set health status for cluster %s", vc.Name)
+	}
+	if vc.Health != nil && vc.Health.Status == status && vc.Health.Reason == reason {
+		return nil
+	}
+	now := time.Now()
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				types.AnnotationClusterHealth:                   string(status),
+				types.AnnotationClusterHealthReason:             reason,
+				types.AnnotationClusterHealthLastTransitionTime: now.Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = cli.Patch(ContextInLocalCluster(ctx), vc.Object, client.RawPatch(apitypes.MergePatchType, patch)); err != nil {
+		return errors.Wrapf(err, "failed to set health status for cluster %s", vc.Name)
+	}
+	vc.Health = &ClusterHealth{Status: status, Reason: reason, LastTransitionTime: now}
+	return nil
+}
+
+// ProbeClusterHealth request the healthz endpoint of the cluster through cluster-gateway
+func ProbeClusterHealth(ctx context.Context, config *rest.Config, clusterName string) ([]byte, error) {
+	cli, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return cli.ClusterV1alpha1().ClusterGateways().RESTClient(clusterName).Get().AbsPath("healthz").DoRaw(ctx)
+}
+
+// ClusterProber probes the health of the cluster, returns error if the cluster is not healthy
+type ClusterProber func(ctx context.Context, clusterName string) error
+
+// ClusterHealthMgr periodically probes the health of clusters and records the health status on clusters
+type ClusterHealthMgr struct {
+	kubeClient       client.Client
+	prober           ClusterProber
+	probePeriod      time.Duration
+	failureThreshold int
+	failures         map[string]int
+}
+
+// NewClusterHealthMgr will create a cluster health manager which probes clusters through cluster-gateway
+func NewClusterHealthMgr(ctx context.Context, kubeClient client.Client, config *rest.Config, probePeriod time.Duration, failureThreshold int) (*ClusterHealthMgr, error) {
+	if failureThreshold <= 0 {
+		return nil, errors.Errorf("invalid failure threshold %d for cluster health probe", failureThreshold)
+	}
+	mgr := &ClusterHealthMgr{
+		kubeClient: kubeClient,
+		prober: func(ctx context.Context, clusterName string) error {
+			_, err := ProbeClusterHealth(ctx, config, clusterName)
+			return err
+		},
+		probePeriod:      probePeriod,
+		failureThreshold: failureThreshold,
+		failures:         map[string]int{},
+	}
+	go mgr.Start(ctx)
+	return mgr, nil
+}
+
+// Refresh will probe all the remote clusters and update their health status. A cluster will be marked as unhealthy
+// only if it fails the probe for failureThreshold times continuously.
+func (chm *ClusterHealthMgr) Refresh(ctx context.Context) error {
+	clusters, err := FindVirtualClustersByLabels(ctx, chm.kubeClient, map[string]string{})
+	if err != nil {
+		return err
+	}
+	failures := map[string]int{}
+	for i := range clusters {
+		cluster := &clusters[i]
+		status, reason := ClusterHealthy, ""
+		if err = chm.prober(ctx, cluster.Name); err != nil {
+			klog.Warningf("failed to probe cluster-(%s): %v", cluster.Name, err)
+			failures[cluster.Name] = chm.failures[cluster.Name] + 1
+			if failures[cluster.Name] < chm.failureThreshold {
+				continue
+			}
+			status, reason = ClusterUnhealthy, ClusterHealthReasonProbeFailed
+			if IsClusterDisconnect(err) {
+				reason = ClusterHealthReasonDisconnected
+			}
+		}
+		if err = SetClusterHealth(ctx, chm.kubeClient, cluster, status, reason); err != nil {
+			klog.Warningf("failed to update health status of cluster-(%s): %v", cluster.Name, err)
+		}
+	}
+	chm.failures = failures
+	return nil
+}
+
+// Start will start polling clusters to probe their health
+func (chm *ClusterHealthMgr) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			klog.Warning("Stop cluster health probing loop.")
+			return
+		default:
+			if err := chm.Refresh(ctx); err != nil {
+				klog.Warningf("failed to probe cluster health: %v", err)
+			}
+			time.Sleep(chm.probePeriod)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestClusterHealthRefresh(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).
+		WithObjects(FakeSecret(NormalClusterName), FakeSecret(DisconnectedClusterName), FakeSecret("failed-cluster")).
+		Build()
+	mgr := &ClusterHealthMgr{
+		kubeClient: cli,
+		prober: func(ctx context.Context, clusterName string) error {
+			switch clusterName {
+			case DisconnectedClusterName:
+				return errors.New("dial tcp 127.0.0.1:6443: connect: connection refused")
+			case "failed-cluster":
+				return errors.New("the server is currently unable to handle the request")
+			}
+			return nil
+		},
+		failureThreshold: 2,
+		failures:         map[string]int{},
+	}
+	getHealth := func(name string) *ClusterHealth {
+		vc, err := GetVirtualCluster(ctx, cli, name)
+		r.NoError(err)
+		return vc.Health
+	}
+
+	r.NoError(mgr.Refresh(ctx))
+	r.Equal(ClusterHealthy, getHealth(NormalClusterName).Status)
+	r.Equal(ClusterHealthUnknown, getHealth(DisconnectedClusterName).Status)
+	r.True(getHealth(DisconnectedClusterName).IsHealthy())
+
+	r.NoError(mgr.Refresh(ctx))
+	r.Equal(ClusterHealthy, getHealth(NormalClusterName).Status)
+	health := getHealth(DisconnectedClusterName)
+	r.Equal(ClusterUnhealthy, health.Status)
+	r.Equal(ClusterHealthReasonDisconnected, health.Reason)
+	r.False(health.IsHealthy())
+	r.False(health.LastTransitionTime.IsZero())
+	health = getHealth("failed-cluster")
+	r.Equal(ClusterUnhealthy, health.Status)
+	r.Equal(ClusterHealthReasonProbeFailed, health.Reason)
+
+	mgr.prober = func(ctx context.Context, clusterName string) error { return nil }
+	r.NoError(mgr.Refresh(ctx))
+	r.Equal(ClusterHealthy, getHealth(DisconnectedClusterName).Status)
+	r.True(getHealth(ClusterLocalName).IsHealthy())
+}
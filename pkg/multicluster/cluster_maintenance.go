@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+// ClusterMaintenanceStatus the maintenance status of cluster
+type ClusterMaintenanceStatus string
+
+const (
+	// ClusterInService the cluster can be selected by topology policies
+	ClusterInService ClusterMaintenanceStatus = ""
+	// ClusterCordoned the cluster will not be selected by topology policies for new placements, the applications
+	// already deployed to the cluster are kept
+	ClusterCordoned ClusterMaintenanceStatus = "Cordoned"
+	// ClusterDrained the cluster will not be selected by any topology policy, the resources dispatched to the cluster
+	// will be recycled once the applications finish their workflows
+	ClusterDrained ClusterMaintenanceStatus = "Drained"
+)
+
+// getClusterMaintenance extract the maintenance status from the annotations of cluster object
+func getClusterMaintenance(o client.Object) ClusterMaintenanceStatus {
+	if annots := o.GetAnnotations(); annots != nil {
+		return ClusterMaintenanceStatus(annots[types.AnnotationClusterMaintenance])
+	}
+	return ClusterInService
+}
+
+// SetClusterMaintenance record the maintenance status into the annotations of the cluster secret or managed cluster.
+// Setting the status to ClusterInService removes the annotation.
+func SetClusterMaintenance(ctx context.Context, cli client.Client, clusterName string, status ClusterMaintenanceStatus) error {
+	if clusterName == ClusterLocalName {
+		return ErrReservedLocalClusterName
+	}
+	switch status {
+	case ClusterInService, ClusterCordoned, ClusterDrained:
+	default:
+		return errors.Errorf("invalid maintenance status %s", status)
+	}
+	vc, err := GetVirtualCluster(ctx, cli, clusterName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cluster %s", clusterName)
+	}
+	if vc.Maintenance == status {
+		return nil
+	}
+	var value interface{} = string(status)
+	if status == ClusterInService {
+		value = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{types.AnnotationClusterMaintenance: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = cli.Patch(ContextInLocalCluster(ctx), vc.Object, client.RawPatch(apitypes.MergePatchType, patch)); err != nil {
+		return errors.Wrapf(err, "failed to set maintenance status for cluster %s", clusterName)
+	}
+	return nil
+}
+
+// isApplicationInCluster check if the application has resources dispatched to the cluster
+func isApplicationInCluster(app *v1beta1.Application, clusterName string) bool {
+	for _, res := range app.Status.AppliedResources {
+		if res.Cluster == clusterName {
+			return true
+		}
+	}
+	return false
+}
+
+// DrainCluster mark the cluster as drained and restart the workflows of the applications that have resources in the
+// cluster, so that their topology policies re-run and move the components to other clusters. The resources left in
+// the drained cluster are recycled once the workflows succeed. The rescheduled applications are returned.
+func DrainCluster(ctx context.Context, cli client.Client, clusterName string) ([]apitypes.NamespacedName, error) {
+	if err := SetClusterMaintenance(ctx, cli, clusterName, ClusterDrained); err != nil {
+		return nil, err
+	}
+	apps := &v1beta1.ApplicationList{}
+	if err := cli.List(ctx, apps); err != nil {
+		return nil, errors.Wrap(err, "failed to find applications in the drained cluster")
+	}
+	var rescheduled []apitypes.NamespacedName
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		if !isApplicationInCluster(app, clusterName) {
+			continue
+		}
+		if app.Status.Workflow != nil {
+			app.Status.Workflow = nil
+			if err := cli.Status().Update(ctx, app); err != nil {
+				return rescheduled, errors.Wrapf(err, "failed to restart the workflow of application %s/%s", app.Namespace, app.Name)
+			}
+		}
+		rescheduled = append(rescheduled, client.ObjectKeyFromObject(app))
+	}
+	return rescheduled, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	utilscommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestClusterMaintenance(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	newApp := func(name string, clusters ...string) *v1beta1.Application {
+		app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		for _, cluster := range clusters {
+			app.Status.AppliedResources = append(app.Status.AppliedResources, common.ClusterObjectReference{Cluster: cluster})
+		}
+		app.Status.Workflow = &common.WorkflowStatus{Finished: true}
+		return app
+	}
+	cli := fake.NewClientBuilder().WithScheme(utilscommon.Scheme).WithObjects(
+		FakeSecret("cluster-a"),
+		FakeSecret("cluster-b"),
+		newApp("app-a", "cluster-a"),
+		newApp("app-ab", "", "cluster-a", "cluster-b"),
+		newApp("app-b", "cluster-b"),
+	).Build()
+	getMaintenance := func(name string) ClusterMaintenanceStatus {
+		vc, err := GetVirtualCluster(ctx, cli, name)
+		r.NoError(err)
+		return vc.Maintenance
+	}
+
+	r.Equal(ClusterInService, getMaintenance("cluster-a"))
+	r.NoError(SetClusterMaintenance(ctx, cli, "cluster-a", ClusterCordoned))
+	r.Equal(ClusterCordoned, getMaintenance("cluster-a"))
+	r.NoError(SetClusterMaintenance(ctx, cli, "cluster-a", ClusterInService))
+	r.Equal(ClusterInService, getMaintenance("cluster-a"))
+	vc, err := GetVirtualCluster(ctx, cli, "cluster-a")
+	r.NoError(err)
+	r.NotContains(vc.Object.GetAnnotations(), types.AnnotationClusterMaintenance)
+
+	r.ErrorIs(SetClusterMaintenance(ctx, cli, ClusterLocalName, ClusterCordoned), ErrReservedLocalClusterName)
+	r.Error(SetClusterMaintenance(ctx, cli, "cluster-a", "Unknown"))
+	r.Error(SetClusterMaintenance(ctx, cli, "cluster-c", ClusterCordoned))
+
+	apps, err := DrainCluster(ctx, cli, "cluster-a")
+	r.NoError(err)
+	r.Equal(ClusterDrained, getMaintenance("cluster-a"))
+	r.Equal([]apitypes.NamespacedName{{Namespace: "default", Name: "app-a"}, {Namespace: "default", Name: "app-ab"}}, apps)
+	for name, restarted := range map[string]bool{"app-a": true, "app-ab": true, "app-b": false} {
+		app := &v1beta1.Application{}
+		r.NoError(cli.Get(ctx, apitypes.NamespacedName{Namespace: "default", Name: name}, app))
+		r.Equal(restarted, app.Status.Workflow == nil, name)
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/policy/envbinding"
 	"github.com/oam-dev/kubevela/pkg/utils"
 	velaerrors "github.com/oam-dev/kubevela/pkg/utils/errors"
@@ -56,6 +57,8 @@ type KubeClusterConfig struct {
 	*clientcmdapi.Config
 	*clientcmdapi.Cluster
 	*clientcmdapi.AuthInfo
+	// Credential records the service account token provisioned for the cluster, nil if not provisioned
+	Credential *ClusterCredential
 
 	// Logs records intermediate logs (which do not return error) during running
 	Logs bytes.Buffer
@@ -133,13 +136,26 @@ func (clusterConfig *KubeClusterConfig) createClusterSecret(ctx context.Context,
 			Labels: map[string]string{
 				clustercommon.LabelKeyClusterCredentialType: string(credentialType),
 			},
+			Annotations: clusterCredentialAnnotations(clusterConfig.Credential),
 		},
 		Type: corev1.SecretTypeOpaque,
 		Data: data,
 	}
+	if withEndpoint && clusterConfig.Cluster.InsecureSkipTLSVerify {
+		secret.Annotations[types.AnnotationClusterInsecureSkipTLSVerify] = "true"
+	}
 	return cli.Create(ctx, secret)
 }
 
+// GetRESTConfig build the rest config to access the cluster with the kubeconfig, exec plugins are supported
+func (clusterConfig *KubeClusterConfig) GetRESTConfig() (*rest.Config, error) {
+	restConfig, err := clientcmd.NewDefaultClientConfig(*clusterConfig.Config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build rest config for cluster %s", clusterConfig.ClusterName)
+	}
+	return restConfig, nil
+}
+
 // RegisterByVelaSecret create cluster secrets for KubeVela to use
 func (clusterConfig *KubeClusterConfig) RegisterByVelaSecret(ctx context.Context, cli client.Client) error {
 	if err := ensureClusterNotExists(ctx, cli, clusterConfig.ClusterName); err != nil {
@@ -330,6 +346,8 @@ type JoinClusterArgs struct {
 	hubConfig              *rest.Config
 	inClusterBootstrap     *bool
 	trackingSpinnerFactory func(string) *spinner.Spinner
+	tokenExpiration        time.Duration
+	serviceAccountRole     string
 }
 
 func newJoinClusterArgs(options ...JoinClusterOption) *JoinClusterArgs {
@@ -363,6 +381,25 @@ func (op JoinClusterEngineOption) ApplyToArgs(args *JoinClusterArgs) {
 	args.engine = string(op)
 }
 
+// JoinClusterServiceAccountTokenOption provision a service account in the managed cluster when join cluster, and use
+// its token with the given expiration as the credential, so that the credential can be rotated by the controller.
+// Clusters whose kubeconfig uses exec plugins or auth providers always use the provisioned token.
+type JoinClusterServiceAccountTokenOption time.Duration
+
+// ApplyToArgs apply to args
+func (op JoinClusterServiceAccountTokenOption) ApplyToArgs(args *JoinClusterArgs) {
+	args.tokenExpiration = time.Duration(op)
+}
+
+// JoinClusterServiceAccountRoleOption set the cluster role bound to the service account provisioned in the managed
+// cluster, the least privileged DefaultClusterServiceAccountRole is used if not set
+type JoinClusterServiceAccountRoleOption string
+
+// ApplyToArgs apply to args
+func (op JoinClusterServiceAccountRoleOption) ApplyToArgs(args *JoinClusterArgs) {
+	args.serviceAccountRole = string(op)
+}
+
 // JoinClusterOCMOptions options used when joining clusters by ocm, only support cli for now
 type JoinClusterOCMOptions struct {
 	IoStreams              cmdutil.IOStreams
@@ -391,6 +428,15 @@ func JoinClusterByKubeConfig(ctx context.Context, cli client.Client, kubeconfigP
 	}
 	switch args.engine {
 	case ClusterGateWayEngine:
+		if args.tokenExpiration > 0 || clusterConfig.IsExecCredential() {
+			expiration := args.tokenExpiration
+			if expiration <= 0 {
+				expiration = DefaultClusterTokenExpiration
+			}
+			if err = clusterConfig.ProvisionServiceAccountToken(ctx, types.DefaultKubeVelaNS, args.serviceAccountRole, expiration); err != nil {
+				return nil, errors.Wrapf(err, "failed to provision service account token in cluster %s", clusterConfig.ClusterName)
+			}
+		}
 		if err = clusterConfig.RegisterByVelaSecret(ctx, cli); err != nil {
 			return nil, err
 		}
@@ -476,6 +522,9 @@ func DetachCluster(ctx context.Context, cli client.Client, clusterName string, o
 			}
 		}
 	}
+	if err = replaceClusterInClusterGroups(ctx, cli, clusterName, ""); err != nil {
+		return errors.Wrapf(err, "failed to remove cluster %s from cluster groups", clusterName)
+	}
 	return nil
 }
 
@@ -503,6 +552,9 @@ func RenameCluster(ctx context.Context, k8sClient client.Client, oldClusterName
 	if err := k8sClient.Create(ctx, clusterSecret); err != nil {
 		return errors.Wrapf(err, "failed to rename cluster from %s to %s", oldClusterName, newClusterName)
 	}
+	if err := replaceClusterInClusterGroups(ctx, k8sClient, oldClusterName, newClusterName); err != nil {
+		return errors.Wrapf(err, "failed to rename cluster from %s to %s in cluster groups", oldClusterName, newClusterName)
+	}
 	return nil
 }
 
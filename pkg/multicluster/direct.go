@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1alpha1 "github.com/oam-dev/cluster-gateway/pkg/apis/cluster/v1alpha1"
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/utils"
+)
+
+const (
+	// ClusterClientModeGateway accesses the managed clusters through the cluster-gateway aggregated API
+	ClusterClientModeGateway = "gateway"
+	// ClusterClientModeDirect accesses the managed clusters directly with the credentials in the cluster secrets
+	ClusterClientModeDirect = "direct"
+)
+
+var (
+	// ClusterClientMode decides how the multicluster client accesses the managed clusters
+	ClusterClientMode = ClusterClientModeGateway
+	// DirectClusterQPS is the qps limit of the requests to each managed cluster in direct mode
+	DirectClusterQPS float32 = 50
+	// DirectClusterBurst is the burst limit of the requests to each managed cluster in direct mode
+	DirectClusterBurst = 100
+	// DirectClusterCacheTTL is the period to re-check the cluster secret of the cached cluster clients in direct mode
+	DirectClusterCacheTTL = 5 * time.Minute
+
+	// ErrDirectConnectNotSupported the cluster cannot be accessed directly
+	ErrDirectConnectNotSupported = errors.New("cluster does not support direct connection")
+)
+
+// NewRestConfigFromClusterSecret builds the rest config to access the managed cluster directly from the cluster secret
+func NewRestConfigFromClusterSecret(secret *corev1.Secret) (*rest.Config, error) {
+	endpointType := secret.GetLabels()[clustercommon.LabelKeyClusterEndpointType]
+	if endpointType != "" && endpointType != string(clusterv1alpha1.ClusterEndpointTypeConst) {
+		return nil, errors.Wrapf(ErrDirectConnectNotSupported, "endpoint type %s of cluster %s", endpointType, secret.Name)
+	}
+	endpoint := strings.TrimSuffix(string(secret.Data["endpoint"]), "\n")
+	if endpoint == "" {
+		return nil, errors.Errorf("no endpoint found in cluster secret %s", secret.Name)
+	}
+	cfg := &rest.Config{Host: endpoint}
+	if ca, ok := secret.Data["ca.crt"]; ok {
+		cfg.CAData = ca
+	} else if ca, ok := secret.Data["ca"]; ok {
+		cfg.CAData = ca
+	} else if secret.GetAnnotations()[types.AnnotationClusterInsecureSkipTLSVerify] == "true" {
+		cfg.Insecure = true
+	} else {
+		return nil, errors.Errorf("no CA found in cluster secret %s, set the annotation %s=true to access the cluster without TLS verification", secret.Name, types.AnnotationClusterInsecureSkipTLSVerify)
+	}
+	switch clusterv1alpha1.CredentialType(secret.GetLabels()[clustercommon.LabelKeyClusterCredentialType]) {
+	case clusterv1alpha1.CredentialTypeX509Certificate:
+		cfg.CertData = secret.Data[corev1.TLSCertKey]
+		cfg.KeyData = secret.Data[corev1.TLSPrivateKeyKey]
+	case clusterv1alpha1.CredentialTypeServiceAccountToken:
+		cfg.BearerToken = string(secret.Data[corev1.ServiceAccountTokenKey])
+	default:
+		return nil, errors.Wrapf(ErrDirectConnectNotSupported, "credential type of cluster %s", secret.Name)
+	}
+	return cfg, nil
+}
+
+type directCluster struct {
+	rt              http.RoundTripper
+	endpoint        *url.URL
+	limiter         flowcontrol.RateLimiter
+	resourceVersion string
+	checkTime       time.Time
+	// unsupported marks the cluster to be accessed through cluster-gateway
+	unsupported bool
+}
+
+var _ utilnet.RoundTripperWrapper = &directMultiClusterRoundTripper{}
+
+type directMultiClusterRoundTripper struct {
+	rt       http.RoundTripper
+	reader   client.Reader
+	wrappers transport.WrapperFunc
+
+	mu       sync.Mutex
+	clusters map[string]*directCluster
+}
+
+// NewDirectModeMultiClusterRoundTripperGenerator create RoundTripper WrapperFunc that sends the requests to the target
+// cluster directly, with the clients built lazily from the cluster secrets and cached. The wrappers are applied to the
+// transport of each cluster. The clusters which cannot be connected directly will still be accessed through the
+// cluster-gateway.
+func NewDirectModeMultiClusterRoundTripperGenerator(reader client.Reader, wrappers transport.WrapperFunc) transport.WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &directMultiClusterRoundTripper{
+			rt:       rt,
+			reader:   reader,
+			wrappers: wrappers,
+			clusters: map[string]*directCluster{},
+		}
+	}
+}
+
+// RoundTrip sends the request to the cluster in the context directly
+func (rt *directMultiClusterRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	clusterName, ok := ctx.Value(ClusterContextKey).(string)
+	if !ok || clusterName == "" || clusterName == ClusterLocalName {
+		return rt.rt.RoundTrip(req)
+	}
+	cluster, err := rt.getCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(ctx)
+	if cluster.unsupported {
+		req.URL.Path = FormatProxyURL(clusterName, req.URL.Path)
+		return rt.rt.RoundTrip(req)
+	}
+	if err = cluster.limiter.Wait(ctx); err != nil {
+		return nil, errors.Wrapf(err, "client rate limiter of cluster %s", clusterName)
+	}
+	req.URL.Scheme = cluster.endpoint.Scheme
+	req.URL.Host = cluster.endpoint.Host
+	req.URL.Path = strings.TrimSuffix(cluster.endpoint.Path, "/") + req.URL.Path
+	req.Host = ""
+	// the credential of the hub cluster is replaced by the one of the managed cluster
+	req.Header.Del("Authorization")
+	return cluster.rt.RoundTrip(req)
+}
+
+func (rt *directMultiClusterRoundTripper) getCluster(ctx context.Context, clusterName string) (*directCluster, error) {
+	rt.mu.Lock()
+	cached, found := rt.clusters[clusterName]
+	fresh := found && time.Since(cached.checkTime) < DirectClusterCacheTTL
+	rt.mu.Unlock()
+	if fresh {
+		return cached, nil
+	}
+	secret := &corev1.Secret{}
+	// the secret is read from the hub cluster without the cluster name in context
+	if err := rt.reader.Get(ContextInLocalCluster(ctx), client.ObjectKey{Namespace: ClusterGatewaySecretNamespace, Name: clusterName}, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get cluster secret of %s", clusterName)
+		}
+		// clusters without secrets like ocm managed clusters are accessed through cluster-gateway
+		secret = nil
+	}
+	cluster := cached
+	if secret == nil || !found || cached.resourceVersion != secret.ResourceVersion {
+		var err error
+		if cluster, err = rt.newCluster(secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to build client for cluster %s", clusterName)
+		}
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	// the connections of the replaced client will not be used any more
+	if current, ok := rt.clusters[clusterName]; ok && current != cluster && current.rt != nil {
+		utilnet.CloseIdleConnectionsFor(current.rt)
+	}
+	cluster.checkTime = time.Now()
+	rt.clusters[clusterName] = cluster
+	return cluster, nil
+}
+
+func (rt *directMultiClusterRoundTripper) newCluster(secret *corev1.Secret) (*directCluster, error) {
+	if secret == nil {
+		return &directCluster{unsupported: true}, nil
+	}
+	cluster := &directCluster{resourceVersion: secret.ResourceVersion}
+	cfg, err := NewRestConfigFromClusterSecret(secret)
+	if errors.Is(err, ErrDirectConnectNotSupported) {
+		klog.Infof("cluster %s will be accessed through cluster-gateway: %v", secret.Name, err)
+		cluster.unsupported = true
+		return cluster, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cluster.endpoint, err = url.Parse(cfg.Host); err != nil {
+		return nil, errors.Wrapf(err, "invalid endpoint %s", cfg.Host)
+	}
+	cfg.WrapTransport = rt.wrappers
+	if cluster.rt, err = rest.TransportFor(cfg); err != nil {
+		return nil, err
+	}
+	cluster.limiter = flowcontrol.NewTokenBucketRateLimiter(DirectClusterQPS, DirectClusterBurst)
+	return cluster, nil
+}
+
+// CancelRequest will try cancel request with the inner round tripper
+func (rt *directMultiClusterRoundTripper) CancelRequest(req *http.Request) {
+	utils.TryCancelRequest(rt.WrappedRoundTripper(), req)
+}
+
+// WrappedRoundTripper can get the wrapped RoundTripper
+func (rt *directMultiClusterRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.rt
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1alpha1 "github.com/oam-dev/cluster-gateway/pkg/apis/cluster/v1alpha1"
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+type recordRoundTripper struct {
+	requests []*http.Request
+}
+
+func (rt *recordRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestNewRestConfigFromClusterSecret(t *testing.T) {
+	r := require.New(t)
+	secret := FakeSecret("cluster-a")
+	secret.Data = map[string][]byte{"endpoint": []byte("https://1.2.3.4:6443\n"), "token": []byte("token")}
+	_, err := NewRestConfigFromClusterSecret(secret)
+	r.Error(err)
+	secret.Annotations = map[string]string{types.AnnotationClusterInsecureSkipTLSVerify: "true"}
+	cfg, err := NewRestConfigFromClusterSecret(secret)
+	r.NoError(err)
+	r.Equal("https://1.2.3.4:6443", cfg.Host)
+	r.Equal("token", cfg.BearerToken)
+	r.True(cfg.Insecure)
+
+	secret.Labels[clustercommon.LabelKeyClusterCredentialType] = string(clusterv1alpha1.CredentialTypeX509Certificate)
+	secret.Data["ca.crt"] = []byte("ca")
+	secret.Data["tls.crt"] = []byte("cert")
+	cfg, err = NewRestConfigFromClusterSecret(secret)
+	r.NoError(err)
+	r.Equal([]byte("ca"), cfg.CAData)
+	r.Equal([]byte("cert"), cfg.CertData)
+	r.False(cfg.Insecure)
+
+	secret.Labels[clustercommon.LabelKeyClusterEndpointType] = string(clusterv1alpha1.ClusterEndpointTypeClusterProxy)
+	_, err = NewRestConfigFromClusterSecret(secret)
+	r.ErrorIs(err, ErrDirectConnectNotSupported)
+}
+
+func TestDirectMultiClusterRoundTripper(t *testing.T) {
+	r := require.New(t)
+	oldClusterGatewaySecretNamespace := ClusterGatewaySecretNamespace
+	ClusterGatewaySecretNamespace = types.DefaultKubeVelaNS
+	defer func() {
+		ClusterGatewaySecretNamespace = oldClusterGatewaySecretNamespace
+	}()
+	var received []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received = append(received, req)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	directSecret := FakeSecret("direct")
+	directSecret.Namespace = ClusterGatewaySecretNamespace
+	directSecret.Data = map[string][]byte{"endpoint": []byte(server.URL + "/prefix"), "token": []byte("remote-token")}
+	directSecret.Annotations = map[string]string{types.AnnotationClusterInsecureSkipTLSVerify: "true"}
+	proxySecret := FakeSecret("proxy")
+	proxySecret.Namespace = ClusterGatewaySecretNamespace
+	proxySecret.Labels[clustercommon.LabelKeyClusterEndpointType] = string(clusterv1alpha1.ClusterEndpointTypeClusterProxy)
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(directSecret, proxySecret).Build()
+
+	hub := &recordRoundTripper{}
+	rt := NewDirectModeMultiClusterRoundTripperGenerator(cli, nil)(hub)
+	send := func(cluster string) error {
+		ctx := ContextWithClusterName(context.Background(), cluster)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://hub:6443/api/v1/pods", nil)
+		r.NoError(err)
+		req.Header.Set("Authorization", "Bearer hub-token")
+		resp, err := rt.RoundTrip(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	r.NoError(send(ClusterLocalName))
+	r.Equal(1, len(hub.requests))
+	r.Equal("/api/v1/pods", hub.requests[0].URL.Path)
+
+	for i := 0; i < 2; i++ {
+		r.NoError(send("direct"))
+	}
+	r.Equal(2, len(received))
+	r.Equal("/prefix/api/v1/pods", received[0].URL.Path)
+	r.Equal("Bearer remote-token", received[0].Header.Get("Authorization"))
+	r.Equal(1, len(rt.(*directMultiClusterRoundTripper).clusters))
+
+	// the client is rebuilt once the cluster secret changes
+	oldDirectClusterCacheTTL := DirectClusterCacheTTL
+	DirectClusterCacheTTL = 0
+	defer func() {
+		DirectClusterCacheTTL = oldDirectClusterCacheTTL
+	}()
+	directSecret.Data["token"] = []byte("new-remote-token")
+	r.NoError(cli.Update(context.Background(), directSecret))
+	r.NoError(send("direct"))
+	r.Equal(3, len(received))
+	r.Equal("Bearer new-remote-token", received[2].Header.Get("Authorization"))
+	DirectClusterCacheTTL = oldDirectClusterCacheTTL
+
+	r.NoError(send("proxy"))
+	r.NoError(send("ocm-managed"))
+	r.Equal(3, len(hub.requests))
+	r.Equal(FormatProxyURL("proxy", "/api/v1/pods"), hub.requests[1].URL.Path)
+	r.Equal(FormatProxyURL("ocm-managed", "/api/v1/pods"), hub.requests[2].URL.Path)
+}
@@ -30,6 +30,10 @@ var (
 	ErrClusterNotExists = ClusterManagementError(fmt.Errorf("no such cluster"))
 	// ErrReservedLocalClusterName reserved cluster name is used
 	ErrReservedLocalClusterName = ClusterManagementError(fmt.Errorf("cluster name `local` is reserved for kubevela hub cluster"))
+	// ErrClusterGroupExists cluster group already exists
+	ErrClusterGroupExists = ClusterManagementError(fmt.Errorf("cluster group already exists"))
+	// ErrClusterGroupNotExists cluster group not exists
+	ErrClusterGroupNotExists = ClusterManagementError(fmt.Errorf("no such cluster group"))
 	// ErrDetectClusterGateway fail to wait for ClusterGateway service ready
 	ErrDetectClusterGateway = ClusterManagementError(fmt.Errorf("failed to wait for cluster gateway, unable to use multi-cluster"))
 )
@@ -113,3 +113,28 @@ func GetClusterMetricsFromMetricsAPI(ctx context.Context, k8sClient client.Clien
 		MemoryUsage: memoryUsage,
 	}, nil
 }
+
+// GetFreeResource returns the allocatable resource (cpu or memory) not used in the cluster. Zero will be returned
+// if the cluster info is not collected, and the usage will be ignored if the metrics api is unavailable.
+func (cm *ClusterMetrics) GetFreeResource(name corev1.ResourceName) resource.Quantity {
+	free := resource.Quantity{}
+	if cm == nil || cm.ClusterInfo == nil {
+		return free
+	}
+	switch name {
+	case corev1.ResourceCPU:
+		free = cm.ClusterInfo.CPUAllocatable.DeepCopy()
+		if cm.ClusterUsageMetrics != nil {
+			free.Sub(cm.ClusterUsageMetrics.CPUUsage)
+		}
+	case corev1.ResourceMemory:
+		free = cm.ClusterInfo.MemoryAllocatable.DeepCopy()
+		if cm.ClusterUsageMetrics != nil {
+			free.Sub(cm.ClusterUsageMetrics.MemoryUsage)
+		}
+	}
+	if free.Sign() < 0 {
+		return resource.Quantity{}
+	}
+	return free
+}
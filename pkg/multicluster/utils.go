@@ -147,14 +147,38 @@ func Initialize(restConfig *rest.Config, autoUpgrade bool) (client.Client, error
 	if err != nil {
 		return nil, errors2.Wrapf(err, "unable to get client to find cluster gateway service")
 	}
-	svc, err := WaitUntilClusterGatewayReady(context.Background(), c, 60, 5*time.Second)
-	if err != nil {
-		return nil, ErrDetectClusterGateway
+	switch ClusterClientMode {
+	case ClusterClientModeDirect:
+		// cluster-gateway is optional in direct mode, it is only used by the clusters which cannot be connected directly
+		if svc, err := GetClusterGatewayService(context.Background(), c); err == nil {
+			ClusterGatewaySecretNamespace = svc.Namespace
+		} else {
+			klog.Infof("cluster gateway service is not ready, clusters will only be accessed directly: %v", err)
+			ClusterGatewaySecretNamespace = velatypes.DefaultKubeVelaNS
+		}
+		prismclusterv1alpha1.StorageNamespace = ClusterGatewaySecretNamespace
+		klog.Infof("access clusters directly with the cluster secrets in %s", ClusterGatewaySecretNamespace)
+		// the cluster secrets are read with the credential of the hub cluster itself, without the wrappers like
+		// impersonation which could be applied to the rest config already
+		secretConfig := rest.CopyConfig(restConfig)
+		secretConfig.WrapTransport = nil
+		secretReader, err := client.New(secretConfig, client.Options{Scheme: common.Scheme})
+		if err != nil {
+			return nil, errors2.Wrapf(err, "unable to get client to read cluster secrets")
+		}
+		restConfig.Wrap(NewDirectModeMultiClusterRoundTripperGenerator(secretReader, restConfig.WrapTransport))
+	case ClusterClientModeGateway:
+		svc, err := WaitUntilClusterGatewayReady(context.Background(), c, 60, 5*time.Second)
+		if err != nil {
+			return nil, ErrDetectClusterGateway
+		}
+		ClusterGatewaySecretNamespace = svc.Namespace
+		prismclusterv1alpha1.StorageNamespace = ClusterGatewaySecretNamespace
+		klog.Infof("find cluster gateway service %s/%s:%d", svc.Namespace, svc.Name, *svc.Port)
+		restConfig.Wrap(NewSecretModeMultiClusterRoundTripper)
+	default:
+		return nil, errors2.Errorf("unsupported cluster client mode %s", ClusterClientMode)
 	}
-	ClusterGatewaySecretNamespace = svc.Namespace
-	prismclusterv1alpha1.StorageNamespace = ClusterGatewaySecretNamespace
-	klog.Infof("find cluster gateway service %s/%s:%d", svc.Namespace, svc.Name, *svc.Port)
-	restConfig.Wrap(NewSecretModeMultiClusterRoundTripper)
 	if autoUpgrade {
 		if err = UpgradeExistingClusterSecret(context.Background(), c); err != nil {
 			// this error do not affect the running of current version
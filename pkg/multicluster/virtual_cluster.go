@@ -50,7 +50,12 @@ type VirtualCluster struct {
 	Accepted bool
 	Labels   map[string]string
 	Metrics  *ClusterMetrics
-	Object   client.Object
+	Health   *ClusterHealth
+	// Maintenance records whether the cluster is cordoned or drained
+	Maintenance ClusterMaintenanceStatus
+	// Credential records the information of the credential, only available for clusters registered by secret
+	Credential *ClusterCredential
+	Object     client.Object
 }
 
 // FullName the name with alias if available
@@ -86,6 +91,7 @@ func NewVirtualClusterFromLocal() *VirtualCluster {
 		Accepted: true,
 		Labels:   map[string]string{},
 		Metrics:  metricsMap[ClusterLocalName],
+		Health:   &ClusterHealth{Status: ClusterHealthy},
 	}
 }
 
@@ -104,14 +110,17 @@ func NewVirtualClusterFromSecret(secret *corev1.Secret) (*VirtualCluster, error)
 		return nil, errors.Errorf("secret is not a valid cluster secret, no credential type found")
 	}
 	return &VirtualCluster{
-		Name:     secret.Name,
-		Alias:    getClusterAlias(secret),
-		Type:     v1alpha1.CredentialType(credType),
-		EndPoint: endpoint,
-		Accepted: true,
-		Labels:   labels,
-		Metrics:  metricsMap[secret.Name],
-		Object:   secret,
+		Name:        secret.Name,
+		Alias:       getClusterAlias(secret),
+		Type:        v1alpha1.CredentialType(credType),
+		EndPoint:    endpoint,
+		Accepted:    true,
+		Labels:      labels,
+		Metrics:     metricsMap[secret.Name],
+		Health:      getClusterHealth(secret),
+		Maintenance: getClusterMaintenance(secret),
+		Credential:  getClusterCredential(secret),
+		Object:      secret,
 	}, nil
 }
 
@@ -121,14 +130,16 @@ func NewVirtualClusterFromManagedCluster(managedCluster *clusterv1.ManagedCluste
 		return nil, errors.Errorf("managed cluster has no client config")
 	}
 	return &VirtualCluster{
-		Name:     managedCluster.Name,
-		Alias:    getClusterAlias(managedCluster),
-		Type:     types.CredentialTypeOCMManagedCluster,
-		EndPoint: types.ClusterBlankEndpoint,
-		Accepted: managedCluster.Spec.HubAcceptsClient,
-		Labels:   managedCluster.GetLabels(),
-		Metrics:  metricsMap[managedCluster.Name],
-		Object:   managedCluster,
+		Name:        managedCluster.Name,
+		Alias:       getClusterAlias(managedCluster),
+		Type:        types.CredentialTypeOCMManagedCluster,
+		EndPoint:    types.ClusterBlankEndpoint,
+		Accepted:    managedCluster.Spec.HubAcceptsClient,
+		Labels:      managedCluster.GetLabels(),
+		Metrics:     metricsMap[managedCluster.Name],
+		Health:      getClusterHealth(managedCluster),
+		Maintenance: getClusterMaintenance(managedCluster),
+		Object:      managedCluster,
 	}, nil
 }
 
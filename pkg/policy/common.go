@@ -64,3 +64,12 @@ func ParseSharedResourcePolicy(app *v1beta1.Application) (*v1alpha1.SharedResour
 	}
 	return nil, nil
 }
+
+// ParseAppDependencyPolicy parse app-dependency policy
+func ParseAppDependencyPolicy(app *v1beta1.Application) (*v1alpha1.AppDependencyPolicySpec, error) {
+	spec := &v1alpha1.AppDependencyPolicySpec{}
+	if exists, err := parsePolicy(app, v1alpha1.AppDependencyPolicyType, spec); exists {
+		return spec, err
+	}
+	return nil, nil
+}
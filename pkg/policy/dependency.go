@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+// GetAppDependencies return the applications that the given application depends on, declared by app-dependency policy.
+// The dependency applications must be in the same namespace as the given application.
+func GetAppDependencies(app *v1beta1.Application) ([]types.NamespacedName, error) {
+	spec, err := ParseAppDependencyPolicy(app)
+	if err != nil || spec == nil {
+		return nil, err
+	}
+	var deps []types.NamespacedName
+	for _, dep := range spec.Applications {
+		if dep.Namespace != "" && dep.Namespace != app.Namespace {
+			return nil, errors.Errorf("dependency application %s/%s is not in the namespace %s of the application", dep.Namespace, dep.Name, app.Namespace)
+		}
+		deps = append(deps, types.NamespacedName{Namespace: app.Namespace, Name: dep.Name})
+	}
+	return deps, nil
+}
+
+// IsAppHealthy check if the application is running and all of its components are healthy
+func IsAppHealthy(app *v1beta1.Application) bool {
+	if app.Status.Phase != common.ApplicationRunning {
+		return false
+	}
+	for _, svc := range app.Status.Services {
+		if !svc.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckAppDependencies return the dependency applications which are not healthy or not found
+func CheckAppDependencies(ctx context.Context, cli client.Reader, app *v1beta1.Application) ([]types.NamespacedName, error) {
+	deps, err := GetAppDependencies(app)
+	if err != nil {
+		return nil, err
+	}
+	var unhealthy []types.NamespacedName
+	for _, dep := range deps {
+		_app := &v1beta1.Application{}
+		if err = cli.Get(ctx, dep, _app); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "failed to get dependency application %s", dep)
+			}
+			unhealthy = append(unhealthy, dep)
+			continue
+		}
+		if !IsAppHealthy(_app) {
+			unhealthy = append(unhealthy, dep)
+		}
+	}
+	return unhealthy, nil
+}
+
+// FindAppDependencyCycle return the dependency path leading back to the given application, such as [app, db, app].
+// Nil will be returned if there is no cycle. The dependency applications which are not found are skipped.
+func FindAppDependencyCycle(ctx context.Context, cli client.Reader, app *v1beta1.Application) ([]types.NamespacedName, error) {
+	start := types.NamespacedName{Namespace: app.Namespace, Name: app.Name}
+	visited := map[types.NamespacedName]bool{start: true}
+	var visit func(current *v1beta1.Application, path []types.NamespacedName) ([]types.NamespacedName, error)
+	visit = func(current *v1beta1.Application, path []types.NamespacedName) ([]types.NamespacedName, error) {
+		deps, err := GetAppDependencies(current)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if dep == start {
+				return append(path, dep), nil
+			}
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			_app := &v1beta1.Application{}
+			if err := cli.Get(ctx, dep, _app); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get dependency application %s", dep)
+			}
+			// invalid policies in other applications are reported by themselves
+			if cycle, _ := visit(_app, append(path, dep)); cycle != nil {
+				return cycle, nil
+			}
+		}
+		return nil, nil
+	}
+	return visit(app, []types.NamespacedName{start})
+}
+
+// ListDependentApplications list the applications in the same namespace that depend on the given application.
+// Applications being deleted are not included.
+func ListDependentApplications(ctx context.Context, cli client.Reader, app *v1beta1.Application) ([]v1beta1.Application, error) {
+	apps := &v1beta1.ApplicationList{}
+	if err := cli.List(ctx, apps, client.InNamespace(app.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list applications")
+	}
+	key := types.NamespacedName{Namespace: app.Namespace, Name: app.Name}
+	var dependents []v1beta1.Application
+	for i := range apps.Items {
+		_app := apps.Items[i]
+		if _app.GetDeletionTimestamp() != nil {
+			continue
+		}
+		// invalid policies in other applications should not block the current one
+		deps, _ := GetAppDependencies(&_app)
+		for _, dep := range deps {
+			if dep == key {
+				dependents = append(dependents, _app)
+				break
+			}
+		}
+	}
+	return dependents, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func newDependencyTestApp(namespace, name string, phase common.ApplicationPhase, deps string) *v1beta1.Application {
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status:     common.AppStatus{Phase: phase},
+	}
+	if deps != "" {
+		app.Spec.Policies = []v1beta1.AppPolicy{{
+			Name:       "deps",
+			Type:       "app-dependency",
+			Properties: &runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"applications":%s}`, deps))},
+		}}
+	}
+	return app
+}
+
+func TestGetAppDependencies(t *testing.T) {
+	r := require.New(t)
+	deps, err := GetAppDependencies(newDependencyTestApp("default", "app", "", ""))
+	r.NoError(err)
+	r.Nil(deps)
+	deps, err = GetAppDependencies(newDependencyTestApp("default", "app", "", `[{"name":"db"},{"name":"mq","namespace":"default"}]`))
+	r.NoError(err)
+	r.Equal([]types.NamespacedName{{Namespace: "default", Name: "db"}, {Namespace: "default", Name: "mq"}}, deps)
+	// the applications in other namespaces could not be depended on
+	_, err = GetAppDependencies(newDependencyTestApp("default", "app", "", `[{"name":"mq","namespace":"infra"}]`))
+	r.Error(err)
+	_, err = GetAppDependencies(newDependencyTestApp("default", "app", "", `"bad"`))
+	r.Error(err)
+}
+
+func TestIsAppHealthy(t *testing.T) {
+	r := require.New(t)
+	app := newDependencyTestApp("default", "app", common.ApplicationRunning, "")
+	r.True(IsAppHealthy(app))
+	app.Status.Services = []common.ApplicationComponentStatus{{Name: "a", Healthy: true}, {Name: "b", Healthy: false}}
+	r.False(IsAppHealthy(app))
+	app.Status.Services[1].Healthy = true
+	r.True(IsAppHealthy(app))
+	app.Status.Phase = common.ApplicationRunningWorkflow
+	r.False(IsAppHealthy(app))
+}
+
+func TestCheckAppDependencies(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(
+		newDependencyTestApp("default", "db", common.ApplicationRunning, ""),
+		newDependencyTestApp("default", "mq", common.ApplicationRunningWorkflow, ""),
+	).Build()
+	app := newDependencyTestApp("default", "app", "", `[{"name":"db"},{"name":"mq"},{"name":"cache"}]`)
+	unhealthy, err := CheckAppDependencies(ctx, cli, app)
+	r.NoError(err)
+	r.Equal([]types.NamespacedName{{Namespace: "default", Name: "mq"}, {Namespace: "default", Name: "cache"}}, unhealthy)
+}
+
+func TestFindAppDependencyCycle(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(
+		newDependencyTestApp("default", "db", common.ApplicationRunning, `[{"name":"storage"}]`),
+		newDependencyTestApp("default", "storage", common.ApplicationRunning, `[{"name":"app","namespace":"default"}]`),
+		newDependencyTestApp("default", "cache", common.ApplicationRunning, `[{"name":"missing"}]`),
+		newDependencyTestApp("default", "a", "", `[{"name":"b"}]`),
+		newDependencyTestApp("default", "b", "", `[{"name":"a"}]`),
+	).Build()
+
+	cycle, err := FindAppDependencyCycle(ctx, cli, newDependencyTestApp("default", "app", "", `[{"name":"cache"},{"name":"db"}]`))
+	r.NoError(err)
+	r.Equal([]types.NamespacedName{{Namespace: "default", Name: "app"}, {Namespace: "default", Name: "db"},
+		{Namespace: "default", Name: "storage"}, {Namespace: "default", Name: "app"}}, cycle)
+
+	cycle, err = FindAppDependencyCycle(ctx, cli, newDependencyTestApp("default", "app", "", `[{"name":"app"}]`))
+	r.NoError(err)
+	r.Equal([]types.NamespacedName{{Namespace: "default", Name: "app"}, {Namespace: "default", Name: "app"}}, cycle)
+
+	// the cycle between a and b does not involve the current application
+	cycle, err = FindAppDependencyCycle(ctx, cli, newDependencyTestApp("default", "app", "", `[{"name":"a"},{"name":"cache"}]`))
+	r.NoError(err)
+	r.Nil(cycle)
+}
+
+func TestListDependentApplications(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	deleting := newDependencyTestApp("default", "deleting", "", `[{"name":"db"}]`)
+	deleting.SetDeletionTimestamp(&metav1.Time{Time: metav1.Now().Time})
+	deleting.SetFinalizers([]string{"test"})
+	db := newDependencyTestApp("default", "db", common.ApplicationRunning, "")
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(
+		db,
+		newDependencyTestApp("default", "app", "", `[{"name":"db"}]`),
+		newDependencyTestApp("other", "app", "", `[{"name":"db","namespace":"default"}]`),
+		newDependencyTestApp("other", "local", "", `[{"name":"db"}]`),
+		newDependencyTestApp("other", "invalid", "", `"bad"`),
+		deleting,
+	).Build()
+	dependents, err := ListDependentApplications(ctx, cli, db)
+	r.NoError(err)
+	var keys []string
+	for _, app := range dependents {
+		keys = append(keys, app.Namespace+"/"+app.Name)
+	}
+	r.ElementsMatch([]string{"default/app"}, keys)
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+)
+
+// clusterCapacity records the free capacity of the cluster
+type clusterCapacity struct {
+	Cluster string
+	Free    float64
+}
+
+// getClusterCapacities get the free capacity of clusters from the metrics collected by ClusterMetricsMgr
+func getClusterCapacities(ctx context.Context, cli client.Client, clusters []string, resourceName corev1.ResourceName) ([]clusterCapacity, error) {
+	var capacities []clusterCapacity
+	for _, cluster := range clusters {
+		vc, err := multicluster.GetVirtualCluster(ctx, cli, cluster)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get cluster %s", cluster)
+		}
+		free := vc.Metrics.GetFreeResource(resourceName)
+		capacities = append(capacities, clusterCapacity{Cluster: cluster, Free: free.AsApproximateFloat64()})
+	}
+	return capacities, nil
+}
+
+type schedulingRecordsKey struct{}
+
+// schedulingRecords records the scheduling decisions of the topology policies. The last decisions are read from the
+// status of the application, and the current decisions are made while getting placements.
+type schedulingRecords struct {
+	mu      sync.Mutex
+	last    map[string]*v1alpha1.TopologySchedulingStatus
+	current map[string]*v1alpha1.TopologySchedulingStatus
+}
+
+// contextWithSchedulingRecords records the last scheduling decisions of the topology policies in the application
+func contextWithSchedulingRecords(ctx context.Context, app *v1beta1.Application) context.Context {
+	records := &schedulingRecords{
+		last:    map[string]*v1alpha1.TopologySchedulingStatus{},
+		current: map[string]*v1alpha1.TopologySchedulingStatus{},
+	}
+	for _, policyStatus := range app.Status.PolicyStatus {
+		if policyStatus.Type != v1alpha1.TopologyPolicyType || policyStatus.Status == nil {
+			continue
+		}
+		status := &v1alpha1.TopologyPolicyStatus{}
+		if err := json.Unmarshal(policyStatus.Status.Raw, status); err != nil || status.Scheduling == nil {
+			continue
+		}
+		records.last[policyStatus.Name] = status.Scheduling
+	}
+	return context.WithValue(ctx, schedulingRecordsKey{}, records)
+}
+
+// getSchedulingRecord get the scheduling decision made for the topology policy in the context
+func getSchedulingRecord(ctx context.Context, topology string) *v1alpha1.TopologySchedulingStatus {
+	records, ok := ctx.Value(schedulingRecordsKey{}).(*schedulingRecords)
+	if !ok {
+		return nil
+	}
+	records.mu.Lock()
+	defer records.mu.Unlock()
+	return records.current[topology].DeepCopy()
+}
+
+// getScheduledPlacements reuse the last scheduling decision of the topology policy if neither the scheduling spec
+// nor the candidate clusters are changed, so that the placements will not move with the free capacity of clusters
+// in every reconcile. Otherwise, the clusters will be scheduled again.
+func getScheduledPlacements(ctx context.Context, cli client.Client, topology string, clusters []string, topologySpec *v1alpha1.TopologyPolicySpec) ([]v1alpha1.PlacementDecision, error) {
+	records, ok := ctx.Value(schedulingRecordsKey{}).(*schedulingRecords)
+	if !ok {
+		return scheduleClusters(ctx, cli, clusters, topologySpec.Namespace, topologySpec.Scheduling)
+	}
+	records.mu.Lock()
+	defer records.mu.Unlock()
+	candidates := append([]string{}, clusters...)
+	sort.Strings(candidates)
+	var placements []v1alpha1.PlacementDecision
+	if last := records.last[topology]; last != nil && equality.Semantic.DeepEqual(last.Spec, *topologySpec.Scheduling) && equality.Semantic.DeepEqual(last.Clusters, candidates) {
+		for _, placement := range last.Placements {
+			placement = *placement.DeepCopy()
+			placement.Namespace = topologySpec.Namespace
+			placements = append(placements, placement)
+		}
+	} else {
+		var err error
+		if placements, err = scheduleClusters(ctx, cli, clusters, topologySpec.Namespace, topologySpec.Scheduling); err != nil {
+			return nil, err
+		}
+	}
+	record := &v1alpha1.TopologySchedulingStatus{Spec: *topologySpec.Scheduling.DeepCopy(), Clusters: candidates}
+	for _, placement := range placements {
+		record.Placements = append(record.Placements, *placement.DeepCopy())
+	}
+	records.current[topology] = record
+	return placements, nil
+}
+
+// scheduleClusters schedule the clusters in topology by their free capacity
+func scheduleClusters(ctx context.Context, cli client.Client, clusters []string, ns string, scheduling *v1alpha1.TopologyScheduling) ([]v1alpha1.PlacementDecision, error) {
+	resourceName := scheduling.Resource
+	if resourceName == "" {
+		resourceName = corev1.ResourceCPU
+	}
+	if resourceName != corev1.ResourceCPU && resourceName != corev1.ResourceMemory {
+		return nil, errors.Errorf("unsupported scheduling resource %s, only cpu and memory are supported", resourceName)
+	}
+	capacities, err := getClusterCapacities(ctx, cli, clusters, resourceName)
+	if err != nil {
+		return nil, err
+	}
+	return schedulePlacements(capacities, ns, scheduling)
+}
+
+// schedulePlacements rank the clusters by their free capacity in descending order and make placement decisions
+// with replicas split by the free capacity. The components with anti-affinity will be placed onto different clusters.
+func schedulePlacements(capacities []clusterCapacity, ns string, scheduling *v1alpha1.TopologyScheduling) ([]v1alpha1.PlacementDecision, error) {
+	sort.SliceStable(capacities, func(i, j int) bool { return capacities[i].Free > capacities[j].Free })
+	switch scheduling.Strategy {
+	case "", v1alpha1.TopologySchedulingStrategySpread:
+	case v1alpha1.TopologySchedulingStrategyLeastLoaded:
+		if scheduling.TopK <= 0 {
+			return nil, errors.Errorf("topK must be positive for %s strategy", scheduling.Strategy)
+		}
+		if scheduling.TopK < len(capacities) {
+			capacities = capacities[:scheduling.TopK]
+		}
+	default:
+		return nil, errors.Errorf("unsupported scheduling strategy %s", scheduling.Strategy)
+	}
+	var frees []float64
+	for _, capacity := range capacities {
+		frees = append(frees, capacity.Free)
+	}
+	var replicas []int
+	if scheduling.Replicas > 0 {
+		replicas = splitByWeights(frees, scheduling.Replicas)
+	}
+	var placements []v1alpha1.PlacementDecision
+	for i, capacity := range capacities {
+		placement := v1alpha1.PlacementDecision{Cluster: capacity.Cluster, Namespace: ns}
+		if replicas != nil {
+			// clusters without replicas assigned will not be used
+			if replicas[i] == 0 {
+				continue
+			}
+			placement.Replicas = replicas[i]
+		}
+		placements = append(placements, placement)
+	}
+	if err := applyComponentAffinity(placements, scheduling.ComponentAffinity); err != nil {
+		return nil, err
+	}
+	return placements, nil
+}
+
+// splitByWeights split the total into integers proportional to the weights by the largest remainder method.
+// The total will be split evenly if all the weights are zero.
+func splitByWeights(weights []float64, total int) []int {
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	results := make([]int, len(weights))
+	if len(weights) == 0 {
+		return results
+	}
+	remainders := make([]float64, len(weights))
+	assigned := 0
+	for i, w := range weights {
+		quota := float64(total) / float64(len(weights))
+		if sum > 0 {
+			quota = float64(total) * w / sum
+		}
+		results[i] = int(math.Floor(quota))
+		remainders[i] = quota - float64(results[i])
+		assigned += results[i]
+	}
+	indexes := make([]int, len(weights))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool { return remainders[indexes[i]] > remainders[indexes[j]] })
+	for i := 0; assigned < total; i++ {
+		results[indexes[i%len(indexes)]]++
+		assigned++
+	}
+	return results
+}
+
+// applyComponentAffinity exclude components from placements to satisfy the component affinity rules. Components
+// with affinity are grouped together. Groups with anti-affinity are assigned to the placements in turn, so that
+// they never share a cluster. Other components are dispatched to all placements.
+func applyComponentAffinity(placements []v1alpha1.PlacementDecision, rules []v1alpha1.ComponentAffinity) error {
+	groupOf := map[string]string{}
+	var find func(comp string) string
+	find = func(comp string) string {
+		parent, found := groupOf[comp]
+		if !found || parent == comp {
+			groupOf[comp] = comp
+			return comp
+		}
+		root := find(parent)
+		groupOf[comp] = root
+		return root
+	}
+	for _, rule := range rules {
+		switch rule.Type {
+		case v1alpha1.ComponentAffinityTypeAffinity:
+			for _, comp := range rule.Components {
+				groupOf[find(comp)] = find(rule.Components[0])
+			}
+		case v1alpha1.ComponentAffinityTypeAntiAffinity:
+		default:
+			return errors.Errorf("unsupported component affinity type %s", rule.Type)
+		}
+	}
+	var groups []string
+	members := map[string][]string{}
+	for _, rule := range rules {
+		if rule.Type != v1alpha1.ComponentAffinityTypeAntiAffinity {
+			continue
+		}
+		ruleGroups := map[string]bool{}
+		for _, comp := range rule.Components {
+			group := find(comp)
+			if ruleGroups[group] {
+				return errors.Errorf("component %s cannot have both affinity and anti-affinity with %s", comp, group)
+			}
+			ruleGroups[group] = true
+			if _, found := members[group]; !found {
+				groups = append(groups, group)
+				members[group] = nil
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	if len(groups) > len(placements) {
+		return errors.Errorf("not enough clusters for component anti-affinity, %d required but %d scheduled", len(groups), len(placements))
+	}
+	for comp := range groupOf {
+		group := find(comp)
+		if _, found := members[group]; found {
+			members[group] = append(members[group], comp)
+		}
+	}
+	for i := range placements {
+		assigned := groups[i%len(groups)]
+		for _, group := range groups {
+			if group != assigned {
+				placements[i].ExcludedComponents = append(placements[i].ExcludedComponents, members[group]...)
+			}
+		}
+		sort.Strings(placements[i].ExcludedComponents)
+	}
+	return nil
+}
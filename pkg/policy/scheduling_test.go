@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1alpha1 "github.com/oam-dev/cluster-gateway/pkg/apis/cluster/v1alpha1"
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestSplitByWeights(t *testing.T) {
+	r := require.New(t)
+	r.Equal([]int{5, 3, 2}, splitByWeights([]float64{50, 30, 20}, 10))
+	r.Equal([]int{4, 3, 3}, splitByWeights([]float64{0, 0, 0}, 10))
+	r.Equal([]int{3, 2, 2}, splitByWeights([]float64{34, 33, 33}, 7))
+	r.Equal([]int{}, splitByWeights(nil, 10))
+}
+
+func TestSchedulePlacements(t *testing.T) {
+	capacities := func() []clusterCapacity {
+		return []clusterCapacity{{Cluster: "cluster-a", Free: 1000}, {Cluster: "cluster-b", Free: 3000}, {Cluster: "cluster-c", Free: 6000}}
+	}
+	testCases := map[string]struct {
+		Scheduling v1alpha1.TopologyScheduling
+		Outputs    []v1alpha1.PlacementDecision
+		Error      string
+	}{
+		"spread": {
+			Scheduling: v1alpha1.TopologyScheduling{Replicas: 10},
+			Outputs: []v1alpha1.PlacementDecision{
+				{Cluster: "cluster-c", Namespace: "ns", Replicas: 6},
+				{Cluster: "cluster-b", Namespace: "ns", Replicas: 3},
+				{Cluster: "cluster-a", Namespace: "ns", Replicas: 1},
+			},
+		},
+		"spread-without-enough-replicas": {
+			Scheduling: v1alpha1.TopologyScheduling{Replicas: 2},
+			Outputs: []v1alpha1.PlacementDecision{
+				{Cluster: "cluster-c", Namespace: "ns", Replicas: 1},
+				{Cluster: "cluster-b", Namespace: "ns", Replicas: 1},
+			},
+		},
+		"least-loaded": {
+			Scheduling: v1alpha1.TopologyScheduling{Strategy: v1alpha1.TopologySchedulingStrategyLeastLoaded, TopK: 2},
+			Outputs: []v1alpha1.PlacementDecision{
+				{Cluster: "cluster-c", Namespace: "ns"},
+				{Cluster: "cluster-b", Namespace: "ns"},
+			},
+		},
+		"least-loaded-without-topK": {
+			Scheduling: v1alpha1.TopologyScheduling{Strategy: v1alpha1.TopologySchedulingStrategyLeastLoaded},
+			Error:      "topK must be positive",
+		},
+		"invalid-strategy": {
+			Scheduling: v1alpha1.TopologyScheduling{Strategy: "random"},
+			Error:      "unsupported scheduling strategy random",
+		},
+		"component-affinity": {
+			Scheduling: v1alpha1.TopologyScheduling{ComponentAffinity: []v1alpha1.ComponentAffinity{
+				{Type: v1alpha1.ComponentAffinityTypeAntiAffinity, Components: []string{"frontend", "db"}},
+				{Type: v1alpha1.ComponentAffinityTypeAffinity, Components: []string{"db", "cache"}},
+			}},
+			Outputs: []v1alpha1.PlacementDecision{
+				{Cluster: "cluster-c", Namespace: "ns", ExcludedComponents: []string{"cache", "db"}},
+				{Cluster: "cluster-b", Namespace: "ns", ExcludedComponents: []string{"frontend"}},
+				{Cluster: "cluster-a", Namespace: "ns", ExcludedComponents: []string{"cache", "db"}},
+			},
+		},
+		"conflict-component-affinity": {
+			Scheduling: v1alpha1.TopologyScheduling{ComponentAffinity: []v1alpha1.ComponentAffinity{
+				{Type: v1alpha1.ComponentAffinityTypeAffinity, Components: []string{"frontend", "db"}},
+				{Type: v1alpha1.ComponentAffinityTypeAntiAffinity, Components: []string{"frontend", "db"}},
+			}},
+			Error: "cannot have both affinity and anti-affinity",
+		},
+		"not-enough-clusters-for-anti-affinity": {
+			Scheduling: v1alpha1.TopologyScheduling{Strategy: v1alpha1.TopologySchedulingStrategyLeastLoaded, TopK: 1, ComponentAffinity: []v1alpha1.ComponentAffinity{
+				{Type: v1alpha1.ComponentAffinityTypeAntiAffinity, Components: []string{"frontend", "db"}},
+			}},
+			Error: "not enough clusters for component anti-affinity",
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			pds, err := schedulePlacements(capacities(), "ns", tt.Scheduling.DeepCopy())
+			if tt.Error != "" {
+				r.NotNil(err)
+				r.Contains(err.Error(), tt.Error)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.Outputs, pds)
+		})
+	}
+}
+
+func TestSchedulingInTopology(t *testing.T) {
+	r := require.New(t)
+	multicluster.ClusterGatewaySecretNamespace = types.DefaultKubeVelaNS
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-a",
+			Namespace: multicluster.ClusterGatewaySecretNamespace,
+			Labels: map[string]string{
+				clustercommon.LabelKeyClusterEndpointType:   string(clusterv1alpha1.ClusterEndpointTypeConst),
+				clustercommon.LabelKeyClusterCredentialType: string(clusterv1alpha1.CredentialTypeX509Certificate),
+			},
+		},
+	}).Build()
+	policies := []v1beta1.AppPolicy{{
+		Name:       "topology-policy",
+		Type:       "topology",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"clusters":["local","cluster-a"],"scheduling":{"replicas":3}}`)},
+	}}
+	// clusters without metrics collected are weighted evenly
+	pds, err := GetPlacementsFromTopologyPolicies(context.Background(), cli, "test", policies, false)
+	r.NoError(err)
+	r.Equal([]v1alpha1.PlacementDecision{
+		{Cluster: "local", Replicas: 2},
+		{Cluster: "cluster-a", Replicas: 1},
+	}, pds)
+
+	// the last scheduling decision is reused until the candidate clusters change
+	app := &v1beta1.Application{}
+	ctx := ContextWithScheduledClusters(context.Background(), app)
+	_, err = GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, false)
+	r.NoError(err)
+	r.NoError(WriteTopologyPolicyStatus(ctx, app, policies, pds))
+	r.Equal(1, len(app.Status.PolicyStatus))
+	status := &v1alpha1.TopologyPolicyStatus{}
+	r.NoError(json.Unmarshal(app.Status.PolicyStatus[0].Status.Raw, status))
+	r.Equal([]string{"cluster-a", "local"}, status.Scheduling.Clusters)
+	status.Scheduling.Placements = []v1alpha1.PlacementDecision{{Cluster: "local", Replicas: 1}, {Cluster: "cluster-a", Replicas: 2}}
+	bs, err := json.Marshal(status)
+	r.NoError(err)
+	app.Status.PolicyStatus[0].Status.Raw = bs
+	pds, err = GetPlacementsFromTopologyPolicies(ContextWithScheduledClusters(context.Background(), app), cli, "test", policies, false)
+	r.NoError(err)
+	r.Equal(status.Scheduling.Placements, pds)
+	policies[0].Properties.Raw = []byte(`{"clusters":["local"],"scheduling":{"replicas":3}}`)
+	pds, err = GetPlacementsFromTopologyPolicies(ContextWithScheduledClusters(context.Background(), app), cli, "test", policies, false)
+	r.NoError(err)
+	r.Equal([]v1alpha1.PlacementDecision{{Cluster: "local", Replicas: 3}}, pds)
+
+	policies[0].Properties.Raw = []byte(`{"clusters":["local"],"scheduling":{"resource":"storage"}}`)
+	_, err = GetPlacementsFromTopologyPolicies(context.Background(), cli, "test", policies, false)
+	r.NotNil(err)
+	r.Contains(err.Error(), "unsupported scheduling resource storage")
+}
@@ -18,15 +18,20 @@ package policy
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 
 	prismclusterv1alpha1 "github.com/kubevela/prism/pkg/apis/cluster/v1alpha1"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/utils"
@@ -43,20 +48,81 @@ func GetClusterLabelSelectorInTopology(topology *v1alpha1.TopologyPolicySpec) ma
 	return nil
 }
 
+// FailoverDecision records the cluster replaced by a standby cluster in the topology
+type FailoverDecision struct {
+	Topology string
+	From     string
+	To       string
+}
+
+type scheduledClustersKey struct{}
+
+// ContextWithScheduledClusters records the clusters that the application has resources in and the last scheduling
+// decisions of the topology policies. Cordoned clusters are still selected by the topology policies if the
+// application has been scheduled to them.
+func ContextWithScheduledClusters(ctx context.Context, app *v1beta1.Application) context.Context {
+	clusters := map[string]bool{}
+	for _, res := range app.Status.AppliedResources {
+		cluster := res.Cluster
+		if cluster == "" {
+			cluster = multicluster.ClusterLocalName
+		}
+		clusters[cluster] = true
+	}
+	ctx = context.WithValue(ctx, scheduledClustersKey{}, clusters)
+	return contextWithSchedulingRecords(ctx, app)
+}
+
+// filterSchedulableClusters remove the drained clusters and the cordoned clusters that the application has not been
+// scheduled to
+func filterSchedulableClusters(ctx context.Context, cli client.Client, clusters []string) ([]string, error) {
+	scheduled, _ := ctx.Value(scheduledClustersKey{}).(map[string]bool)
+	var schedulable []string
+	for _, cluster := range clusters {
+		vc, err := multicluster.GetVirtualCluster(ctx, cli, cluster)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get cluster %s", cluster)
+		}
+		switch vc.Maintenance {
+		case multicluster.ClusterDrained:
+			continue
+		case multicluster.ClusterCordoned:
+			if !scheduled[cluster] {
+				continue
+			}
+		default:
+		}
+		schedulable = append(schedulable, cluster)
+	}
+	return schedulable, nil
+}
+
 // GetPlacementsFromTopologyPolicies get placements from topology policies with provided client
 func GetPlacementsFromTopologyPolicies(ctx context.Context, cli client.Client, appNs string, policies []v1beta1.AppPolicy, allowCrossNamespace bool) ([]v1alpha1.PlacementDecision, error) {
+	placements, _, err := GetPlacementsAndFailoversFromTopologyPolicies(ctx, cli, appNs, policies, allowCrossNamespace)
+	return placements, err
+}
+
+// GetPlacementsAndFailoversFromTopologyPolicies get placements from topology policies with provided client, the
+// unhealthy clusters in topology policies with failover enabled will be replaced by healthy standby clusters
+func GetPlacementsAndFailoversFromTopologyPolicies(ctx context.Context, cli client.Client, appNs string, policies []v1beta1.AppPolicy, allowCrossNamespace bool) ([]v1alpha1.PlacementDecision, []FailoverDecision, error) {
 	var placements []v1alpha1.PlacementDecision
+	var failovers []FailoverDecision
 	placementMap := map[string]struct{}{}
-	addCluster := func(cluster string, ns string, validateCluster bool) error {
+	clusterIdentities := map[string]*v1alpha1.ClusterIdentity{}
+	addPlacement := func(placement v1alpha1.PlacementDecision, validateCluster bool) error {
 		if validateCluster {
-			if _, e := prismclusterv1alpha1.NewClusterClient(cli).Get(ctx, cluster); e != nil {
-				return errors.Wrapf(e, "failed to get cluster %s", cluster)
+			if _, e := prismclusterv1alpha1.NewClusterClient(cli).Get(ctx, placement.Cluster); e != nil {
+				return errors.Wrapf(e, "failed to get cluster %s", placement.Cluster)
 			}
 		}
-		if !allowCrossNamespace && (ns != appNs && ns != "") {
+		if ns := placement.Namespace; !allowCrossNamespace && (ns != appNs && ns != "") {
 			return errors.Errorf("cannot cross namespace")
 		}
-		placement := v1alpha1.PlacementDecision{Cluster: cluster, Namespace: ns}
+		if identity, found := clusterIdentities[placement.Cluster]; found && !reflect.DeepEqual(identity, placement.Identity) {
+			return errors.Errorf("conflicting identities to impersonate in cluster %s", placement.Cluster)
+		}
+		clusterIdentities[placement.Cluster] = placement.Identity
 		name := placement.String()
 		if _, found := placementMap[name]; !found {
 			placementMap[name] = struct{}{}
@@ -68,33 +134,77 @@ func GetPlacementsFromTopologyPolicies(ctx context.Context, cli client.Client, a
 	for _, policy := range policies {
 		if policy.Type == v1alpha1.TopologyPolicyType {
 			if policy.Properties == nil {
-				return nil, fmt.Errorf("topology policy %s must not have empty properties", policy.Name)
+				return nil, nil, fmt.Errorf("topology policy %s must not have empty properties", policy.Name)
 			}
 			hasTopologyPolicy = true
 			topologySpec := &v1alpha1.TopologyPolicySpec{}
 			if err := utils.StrictUnmarshal(policy.Properties.Raw, topologySpec); err != nil {
-				return nil, errors.Wrapf(err, "failed to parse topology policy %s", policy.Name)
+				return nil, nil, errors.Wrapf(err, "failed to parse topology policy %s", policy.Name)
+			}
+			if topologySpec.Identity != nil {
+				if err := auth.NewIdentityFromClusterIdentity(topologySpec.Identity, appNs).Validate(); err != nil {
+					return nil, nil, errors.Wrapf(err, "invalid identity in topology policy %s", policy.Name)
+				}
 			}
 			clusterLabelSelector := GetClusterLabelSelectorInTopology(topologySpec)
+			var clusters []string
+			validateCluster := false
 			switch {
 			case topologySpec.Clusters != nil:
-				for _, cluster := range topologySpec.Clusters {
-					if err := addCluster(cluster, topologySpec.Namespace, true); err != nil {
-						return nil, err
-					}
-				}
+				clusters, validateCluster = topologySpec.Clusters, true
 			case clusterLabelSelector != nil:
 				clusterList, err := prismclusterv1alpha1.NewClusterClient(cli).List(ctx, client.MatchingLabels(clusterLabelSelector))
 				if err != nil {
-					return nil, errors.Wrapf(err, "failed to find clusters in topology %s", policy.Name)
+					return nil, nil, errors.Wrapf(err, "failed to find clusters in topology %s", policy.Name)
 				}
 				if len(clusterList.Items) == 0 {
-					return nil, errors.New("failed to find any cluster matches given labels")
+					return nil, nil, errors.New("failed to find any cluster matches given labels")
 				}
 				for _, cluster := range clusterList.Items {
-					if err = addCluster(cluster.Name, topologySpec.Namespace, false); err != nil {
-						return nil, err
-					}
+					clusters = append(clusters, cluster.Name)
+				}
+			case topologySpec.ClusterGroups != nil:
+				var err error
+				if clusters, err = multicluster.GetClustersInClusterGroups(ctx, cli, topologySpec.ClusterGroups); err != nil {
+					return nil, nil, errors.Wrapf(err, "failed to find clusters in topology %s", policy.Name)
+				}
+				if len(clusters) == 0 {
+					return nil, nil, errors.Errorf("failed to find any cluster in cluster groups %v", topologySpec.ClusterGroups)
+				}
+				validateCluster = true
+			}
+			if len(clusters) > 0 {
+				var err error
+				if clusters, err = filterSchedulableClusters(ctx, cli, clusters); err != nil {
+					return nil, nil, err
+				}
+				if len(clusters) == 0 {
+					return nil, nil, errors.Errorf("all clusters in topology %s are cordoned or drained", policy.Name)
+				}
+			}
+			if topologySpec.Failover != nil {
+				var _failovers []FailoverDecision
+				var err error
+				if clusters, _failovers, err = failoverClusters(ctx, cli, policy.Name, clusters, topologySpec.Failover); err != nil {
+					return nil, nil, err
+				}
+				failovers = append(failovers, _failovers...)
+			}
+			var _placements []v1alpha1.PlacementDecision
+			if topologySpec.Scheduling != nil {
+				var err error
+				if _placements, err = getScheduledPlacements(ctx, cli, policy.Name, clusters, topologySpec); err != nil {
+					return nil, nil, errors.Wrapf(err, "failed to schedule clusters in topology %s", policy.Name)
+				}
+			} else {
+				for _, cluster := range clusters {
+					_placements = append(_placements, v1alpha1.PlacementDecision{Cluster: cluster, Namespace: topologySpec.Namespace})
+				}
+			}
+			for _, placement := range _placements {
+				placement.Identity = topologySpec.Identity
+				if err := addPlacement(placement, validateCluster); err != nil {
+					return nil, nil, err
 				}
 			}
 		}
@@ -102,5 +212,136 @@ func GetPlacementsFromTopologyPolicies(ctx context.Context, cli client.Client, a
 	if !hasTopologyPolicy {
 		placements = []v1alpha1.PlacementDecision{{Cluster: multicluster.ClusterLocalName}}
 	}
-	return placements, nil
+	return placements, failovers, nil
+}
+
+// CheckPlacementIdentities check if the requester of the application is allowed to impersonate the identities given
+// by the topology policies. The identities configured on the clusters are managed by the administrators and not checked.
+func CheckPlacementIdentities(ctx context.Context, cli client.Client, app *v1beta1.Application, placements []v1alpha1.PlacementDecision) error {
+	var checked []*v1alpha1.ClusterIdentity
+	for _, placement := range placements {
+		if placement.Identity == nil {
+			continue
+		}
+		found := false
+		for _, identity := range checked {
+			found = found || reflect.DeepEqual(identity, placement.Identity)
+		}
+		if found {
+			continue
+		}
+		identity := auth.NewIdentityFromClusterIdentity(placement.Identity, app.Namespace)
+		if err := auth.CheckImpersonatePermission(ctx, cli, auth.GetUserInfoInAnnotation(&app.ObjectMeta), identity); err != nil {
+			return errors.Wrapf(err, "cannot impersonate the identity in cluster %s", placement.Cluster)
+		}
+		checked = append(checked, placement.Identity)
+	}
+	return nil
+}
+
+// WriteTopologyPolicyStatus records the identities impersonated in the placed clusters into the status of the
+// topology policies, so that the resources dispatched can be recycled later with the same identities. The scheduling
+// decisions made in the context are recorded as well, so that they can be reused in the following reconciles.
+func WriteTopologyPolicyStatus(ctx context.Context, app *v1beta1.Application, policies []v1beta1.AppPolicy, placements []v1alpha1.PlacementDecision) error {
+	for _, policy := range policies {
+		if policy.Type != v1alpha1.TopologyPolicyType || policy.Properties == nil {
+			continue
+		}
+		topologySpec := &v1alpha1.TopologyPolicySpec{}
+		if err := json.Unmarshal(policy.Properties.Raw, topologySpec); err != nil {
+			return errors.Wrapf(err, "failed to parse topology policy %s", policy.Name)
+		}
+		status := &v1alpha1.TopologyPolicyStatus{}
+		if topologySpec.Identity != nil {
+			status.ClusterIdentities = map[string]v1alpha1.ClusterIdentity{}
+			for _, placement := range placements {
+				if reflect.DeepEqual(placement.Identity, topologySpec.Identity) {
+					status.ClusterIdentities[placement.Cluster] = *placement.Identity
+				}
+			}
+		}
+		if topologySpec.Scheduling != nil {
+			status.Scheduling = getSchedulingRecord(ctx, policy.Name)
+		}
+		bs, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		policyStatus := common.PolicyStatus{Name: policy.Name, Type: v1alpha1.TopologyPolicyType, Status: &runtime.RawExtension{Raw: bs}}
+		found := false
+		for idx := range app.Status.PolicyStatus {
+			if app.Status.PolicyStatus[idx].Name == policy.Name && app.Status.PolicyStatus[idx].Type == v1alpha1.TopologyPolicyType {
+				app.Status.PolicyStatus[idx], found = policyStatus, true
+			}
+		}
+		if !found && (status.ClusterIdentities != nil || status.Scheduling != nil) {
+			app.Status.PolicyStatus = append(app.Status.PolicyStatus, policyStatus)
+		}
+	}
+	return nil
+}
+
+// isClusterHealthy check the health status recorded on the cluster
+func isClusterHealthy(ctx context.Context, cli client.Client, cluster string) (bool, error) {
+	vc, err := multicluster.GetVirtualCluster(ctx, cli, cluster)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get cluster %s", cluster)
+	}
+	return vc.Health.IsHealthy(), nil
+}
+
+// failoverClusters replace the unhealthy clusters with healthy standby clusters in order. If no healthy standby
+// cluster is available, the unhealthy cluster will be kept.
+func failoverClusters(ctx context.Context, cli client.Client, topology string, clusters []string, failover *v1alpha1.TopologyFailover) ([]string, []FailoverDecision, error) {
+	standbyClusters := failover.StandbyClusters
+	if standbyClusters == nil && failover.StandbyClusterLabelSelector != nil {
+		clusterList, err := prismclusterv1alpha1.NewClusterClient(cli).List(ctx, client.MatchingLabels(failover.StandbyClusterLabelSelector))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to find standby clusters in topology %s", topology)
+		}
+		for _, cluster := range clusterList.Items {
+			standbyClusters = append(standbyClusters, cluster.Name)
+		}
+	}
+	standbyClusters, err := filterSchedulableClusters(ctx, cli, standbyClusters)
+	if err != nil {
+		return nil, nil, err
+	}
+	used := map[string]bool{}
+	for _, cluster := range clusters {
+		used[cluster] = true
+	}
+	var placed []string
+	var failovers []FailoverDecision
+	for _, cluster := range clusters {
+		healthy, err := isClusterHealthy(ctx, cli, cluster)
+		if err != nil {
+			return nil, nil, err
+		}
+		if healthy {
+			placed = append(placed, cluster)
+			continue
+		}
+		standby := ""
+		for _, candidate := range standbyClusters {
+			if used[candidate] {
+				continue
+			}
+			used[candidate] = true
+			if healthy, err = isClusterHealthy(ctx, cli, candidate); err != nil {
+				return nil, nil, err
+			}
+			if healthy {
+				standby = candidate
+				break
+			}
+		}
+		if standby == "" {
+			placed = append(placed, cluster)
+			continue
+		}
+		placed = append(placed, standby)
+		failovers = append(failovers, FailoverDecision{Topology: topology, From: cluster, To: standby})
+	}
+	return placed, failovers, nil
 }
@@ -26,16 +26,19 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1alpha1 "github.com/oam-dev/cluster-gateway/pkg/apis/cluster/v1alpha1"
 	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
 
+	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/utils/common"
 )
 
@@ -168,3 +171,243 @@ func TestGetClusterLabelSelectorInTopology(t *testing.T) {
 		})
 	}
 }
+
+func TestFailoverInTopology(t *testing.T) {
+	multicluster.ClusterGatewaySecretNamespace = types.DefaultKubeVelaNS
+	newClusterSecret := func(name string, health multicluster.ClusterHealthStatus, labels map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: multicluster.ClusterGatewaySecretNamespace,
+			Labels: map[string]string{
+				clustercommon.LabelKeyClusterEndpointType:   string(clusterv1alpha1.ClusterEndpointTypeConst),
+				clustercommon.LabelKeyClusterCredentialType: string(clusterv1alpha1.CredentialTypeX509Certificate),
+			},
+		}}
+		for k, v := range labels {
+			secret.Labels[k] = v
+		}
+		if health != "" {
+			secret.Annotations = map[string]string{types.AnnotationClusterHealth: string(health)}
+		}
+		return secret
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newClusterSecret("cluster-a", multicluster.ClusterHealthy, map[string]string{"region": "primary"}),
+		newClusterSecret("cluster-b", multicluster.ClusterUnhealthy, map[string]string{"region": "primary"}),
+		newClusterSecret("cluster-c", "", nil),
+		newClusterSecret("standby-a", multicluster.ClusterUnhealthy, map[string]string{"region": "standby"}),
+		newClusterSecret("standby-b", multicluster.ClusterHealthy, map[string]string{"region": "standby"}),
+	).Build()
+	testCases := map[string]struct {
+		Properties string
+		Outputs    []v1alpha1.PlacementDecision
+		Failovers  []FailoverDecision
+		Error      string
+	}{
+		"no-failover": {
+			Properties: `{"clusters":["cluster-a","cluster-b"]}`,
+			Outputs:    []v1alpha1.PlacementDecision{{Cluster: "cluster-a"}, {Cluster: "cluster-b"}},
+		},
+		"failover-by-standby-clusters": {
+			Properties: `{"clusters":["cluster-a","cluster-b","cluster-c"],"failover":{"standbyClusters":["standby-a","standby-b"]}}`,
+			Outputs:    []v1alpha1.PlacementDecision{{Cluster: "cluster-a"}, {Cluster: "standby-b"}, {Cluster: "cluster-c"}},
+			Failovers:  []FailoverDecision{{Topology: "topology-policy", From: "cluster-b", To: "standby-b"}},
+		},
+		"failover-by-standby-cluster-label-selector": {
+			Properties: `{"clusterLabelSelector":{"region":"primary"},"failover":{"standbyClusterLabelSelector":{"region":"standby"}}}`,
+			Outputs:    []v1alpha1.PlacementDecision{{Cluster: "cluster-a"}, {Cluster: "standby-b"}},
+			Failovers:  []FailoverDecision{{Topology: "topology-policy", From: "cluster-b", To: "standby-b"}},
+		},
+		"no-healthy-standby-cluster": {
+			Properties: `{"clusters":["cluster-b"],"failover":{"standbyClusters":["standby-a"]}}`,
+			Outputs:    []v1alpha1.PlacementDecision{{Cluster: "cluster-b"}},
+		},
+		"standby-cluster-not-found": {
+			Properties: `{"clusters":["cluster-b"],"failover":{"standbyClusters":["standby-x"]}}`,
+			Error:      "failed to get cluster standby-x",
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			policies := []v1beta1.AppPolicy{{
+				Name:       "topology-policy",
+				Type:       "topology",
+				Properties: &runtime.RawExtension{Raw: []byte(tt.Properties)},
+			}}
+			pds, failovers, err := GetPlacementsAndFailoversFromTopologyPolicies(context.Background(), cli, "test", policies, false)
+			if tt.Error != "" {
+				r.NotNil(err)
+				r.Contains(err.Error(), tt.Error)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.Outputs, pds)
+			r.Equal(tt.Failovers, failovers)
+		})
+	}
+}
+
+func TestClusterGroupInTopology(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	multicluster.ClusterGatewaySecretNamespace = types.DefaultKubeVelaNS
+	newClusterSecret := func(name string, region string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: multicluster.ClusterGatewaySecretNamespace,
+				Labels: map[string]string{
+					clustercommon.LabelKeyClusterEndpointType:   string(clusterv1alpha1.ClusterEndpointTypeConst),
+					clustercommon.LabelKeyClusterCredentialType: string(clusterv1alpha1.CredentialTypeX509Certificate),
+					"region": region,
+				},
+			},
+		}
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newClusterSecret("cluster-a", "east"),
+		newClusterSecret("cluster-b", "west"),
+	).Build()
+	r.NoError(multicluster.CreateClusterGroup(ctx, cli, &multicluster.ClusterGroup{Name: "east", Clusters: []string{"local"}, ClusterLabelSelector: map[string]string{"region": "east"}}))
+	r.NoError(multicluster.CreateClusterGroup(ctx, cli, &multicluster.ClusterGroup{Name: "empty", ClusterLabelSelector: map[string]string{"region": "north"}}))
+	policies := []v1beta1.AppPolicy{{
+		Name:       "topology-policy",
+		Type:       "topology",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"clusterGroups":["east"],"namespace":"demo"}`)},
+	}}
+	pds, err := GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, true)
+	r.NoError(err)
+	r.Equal([]v1alpha1.PlacementDecision{
+		{Cluster: "local", Namespace: "demo"},
+		{Cluster: "cluster-a", Namespace: "demo"},
+	}, pds)
+	policies[0].Properties.Raw = []byte(`{"clusterGroups":["empty"]}`)
+	_, err = GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, true)
+	r.NotNil(err)
+	r.Contains(err.Error(), "failed to find any cluster in cluster groups")
+	policies[0].Properties.Raw = []byte(`{"clusterGroups":["west"]}`)
+	_, err = GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, true)
+	r.NotNil(err)
+	r.Contains(err.Error(), "no such cluster group")
+}
+
+func TestCordonedClusterInTopology(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	multicluster.ClusterGatewaySecretNamespace = types.DefaultKubeVelaNS
+	newClusterSecret := func(name string, maintenance multicluster.ClusterMaintenanceStatus) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: multicluster.ClusterGatewaySecretNamespace,
+				Labels: map[string]string{
+					clustercommon.LabelKeyClusterEndpointType:   string(clusterv1alpha1.ClusterEndpointTypeConst),
+					clustercommon.LabelKeyClusterCredentialType: string(clusterv1alpha1.CredentialTypeX509Certificate),
+					"region": "east",
+				},
+			},
+		}
+		if maintenance != multicluster.ClusterInService {
+			secret.Annotations = map[string]string{types.AnnotationClusterMaintenance: string(maintenance)}
+		}
+		return secret
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newClusterSecret("cluster-a", multicluster.ClusterInService),
+		newClusterSecret("cluster-b", multicluster.ClusterCordoned),
+		newClusterSecret("cluster-c", multicluster.ClusterDrained),
+	).Build()
+	policies := []v1beta1.AppPolicy{{
+		Name:       "topology-policy",
+		Type:       "topology",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"clusterLabelSelector":{"region":"east"}}`)},
+	}}
+	// cordoned and drained clusters are not selected for new applications
+	pds, err := GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, false)
+	r.NoError(err)
+	r.Equal([]v1alpha1.PlacementDecision{{Cluster: "cluster-a"}}, pds)
+	// cordoned clusters are kept for applications already scheduled to them, drained clusters are removed
+	app := &v1beta1.Application{}
+	app.Status.AppliedResources = []apicommon.ClusterObjectReference{{Cluster: "cluster-b"}, {Cluster: "cluster-c"}}
+	pds, err = GetPlacementsFromTopologyPolicies(ContextWithScheduledClusters(ctx, app), cli, "test", policies, false)
+	r.NoError(err)
+	r.Equal([]v1alpha1.PlacementDecision{{Cluster: "cluster-a"}, {Cluster: "cluster-b"}}, pds)
+
+	policies[0].Properties.Raw = []byte(`{"clusters":["cluster-b","cluster-c"]}`)
+	_, err = GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, false)
+	r.NotNil(err)
+	r.Contains(err.Error(), "all clusters in topology topology-policy are cordoned or drained")
+	// cordoned standby clusters are skipped in failover
+	policies[0].Properties.Raw = []byte(`{"clusters":["cluster-a"],"failover":{"standbyClusters":["cluster-b","local"]}}`)
+	secret := &corev1.Secret{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: multicluster.ClusterGatewaySecretNamespace, Name: "cluster-a"}, secret))
+	secret.Annotations = map[string]string{types.AnnotationClusterHealth: string(multicluster.ClusterUnhealthy)}
+	r.NoError(cli.Update(ctx, secret))
+	pds, err = GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, false)
+	r.NoError(err)
+	r.Equal([]v1alpha1.PlacementDecision{{Cluster: "local"}}, pds)
+}
+
+func TestIdentityInTopology(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	multicluster.ClusterGatewaySecretNamespace = types.DefaultKubeVelaNS
+	newClusterSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: multicluster.ClusterGatewaySecretNamespace,
+				Labels: map[string]string{
+					clustercommon.LabelKeyClusterEndpointType:   string(clusterv1alpha1.ClusterEndpointTypeConst),
+					clustercommon.LabelKeyClusterCredentialType: string(clusterv1alpha1.CredentialTypeX509Certificate),
+				},
+			},
+		}
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(newClusterSecret("cluster-a"), newClusterSecret("cluster-b")).Build()
+	policies := []v1beta1.AppPolicy{{
+		Name:       "topology-a",
+		Type:       "topology",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"clusters":["cluster-a"],"identity":{"serviceAccount":"deployer"}}`)},
+	}, {
+		Name:       "topology-b",
+		Type:       "topology",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"clusters":["cluster-b"]}`)},
+	}}
+	pds, err := GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, false)
+	r.NoError(err)
+	identity := &v1alpha1.ClusterIdentity{ServiceAccount: "deployer"}
+	r.Equal([]v1alpha1.PlacementDecision{{Cluster: "cluster-a", Identity: identity}, {Cluster: "cluster-b"}}, pds)
+
+	app := &v1beta1.Application{}
+	r.NoError(WriteTopologyPolicyStatus(ctx, app, policies, pds))
+	r.Equal(1, len(app.Status.PolicyStatus))
+	r.Equal("topology-a", app.Status.PolicyStatus[0].Name)
+	r.Equal(`{"clusterIdentities":{"cluster-a":{"serviceAccount":"deployer"}}}`, string(app.Status.PolicyStatus[0].Status.Raw))
+	// the status is cleared once the identity is removed from the policy
+	policies[0].Properties.Raw = []byte(`{"clusters":["cluster-a"]}`)
+	r.NoError(WriteTopologyPolicyStatus(ctx, app, policies, []v1alpha1.PlacementDecision{{Cluster: "cluster-a"}}))
+	r.Equal(`{}`, string(app.Status.PolicyStatus[0].Status.Raw))
+
+	policies[1].Properties.Raw = []byte(`{"clusters":["cluster-a"],"identity":{"user":"alice"}}`)
+	policies[0].Properties.Raw = []byte(`{"clusters":["cluster-a"],"identity":{"serviceAccount":"deployer"}}`)
+	_, err = GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, false)
+	r.NotNil(err)
+	r.Contains(err.Error(), "conflicting identities to impersonate in cluster cluster-a")
+
+	policies[1].Properties.Raw = []byte(`{"clusters":["cluster-b"],"identity":{"groups":["dev"]}}`)
+	_, err = GetPlacementsFromTopologyPolicies(ctx, cli, "test", policies, false)
+	r.NotNil(err)
+	r.Contains(err.Error(), "invalid identity in topology policy topology-b")
+
+	// the requester of the application must be allowed to impersonate the identity
+	pds = []v1alpha1.PlacementDecision{{Cluster: "cluster-a", Identity: &v1alpha1.ClusterIdentity{User: "admin", Groups: []string{"system:masters"}}}}
+	app = &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test"}}
+	err = CheckPlacementIdentities(ctx, cli, app, pds)
+	r.NotNil(err)
+	r.Contains(err.Error(), "cannot impersonate the identity in cluster cluster-a")
+	app.Annotations = map[string]string{oam.AnnotationApplicationUsername: "alice"}
+	r.NotNil(CheckPlacementIdentities(ctx, cli, app, pds))
+	r.NoError(CheckPlacementIdentities(ctx, cli, app, []v1alpha1.PlacementDecision{{Cluster: "cluster-b"}}))
+}
@@ -64,6 +64,9 @@ func (h *resourceKeeper) Dispatch(ctx context.Context, manifests []*unstructured
 	if err = h.AdmissionCheck(ctx, manifests); err != nil {
 		return err
 	}
+	if err = h.quotaCheck(ctx, manifests); err != nil {
+		return err
+	}
 	// 1. record manifests in resourcetracker
 	if err = h.record(ctx, manifests, options...); err != nil {
 		return err
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/jsonpatch/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
+)
+
+// isDetectOnly check if the configuration drift should only be detected instead of being corrected
+func (h *resourceKeeper) isDetectOnly() bool {
+	return h.applyOncePolicy != nil && h.applyOncePolicy.DetectOnly
+}
+
+// detectDrift compare the live state of the resource with the manifest recorded in resourcetracker and record the
+// drift if exists. Previous detected time will be kept if the resource has already drifted before.
+func (h *resourceKeeper) detectDrift(mr v1beta1.ManagedResource, manifest *unstructured.Unstructured, live *unstructured.Unstructured, exists bool, drifts []common.ResourceDrift) ([]common.ResourceDrift, error) {
+	drift := common.ResourceDrift{
+		ClusterObjectReference: common.ClusterObjectReference{
+			Cluster:         mr.Cluster,
+			Creator:         mr.Creator,
+			ObjectReference: mr.ObjectReference,
+		},
+		DetectedTime: metav1.Now(),
+	}
+	if !exists {
+		drift.Missing = true
+	} else {
+		patch, err := computeDriftPatch(manifest, live)
+		if err != nil {
+			return drifts, errors.Wrapf(err, "failed to compute drift for resource %s", mr.ResourceKey())
+		}
+		if patch == "" {
+			return drifts, nil
+		}
+		drift.Patch = patch
+	}
+	for _, prev := range h.app.Status.Drifts {
+		if prev.ClusterObjectReference.Equal(drift.ClusterObjectReference) {
+			drift.DetectedTime = prev.DetectedTime
+			break
+		}
+	}
+	return append(drifts, drift), nil
+}
+
+// updateDrifts set the detected drifts into application status and report the number of drifted resources
+func (h *resourceKeeper) updateDrifts(drifts []common.ResourceDrift) {
+	h.app.Status.Drifts = drifts
+	metrics.ApplicationResourceDriftGauge.WithLabelValues(h.app.Name, h.app.Namespace).Set(float64(len(drifts)))
+}
+
+// computeDriftPatch generate the JSON patch from the recorded manifest to the live state of the resource. Only fields
+// declared in the recorded manifest will be compared, fields filled by the cluster (like status or defaulted fields)
+// will be ignored. Empty string will be returned if no drift found.
+func computeDriftPatch(manifest *unstructured.Unstructured, live *unstructured.Unstructured) (string, error) {
+	desired, err := json.Marshal(manifest.Object)
+	if err != nil {
+		return "", err
+	}
+	current, err := json.Marshal(pruneToShape(manifest.Object, live.Object))
+	if err != nil {
+		return "", err
+	}
+	ops, err := jsonpatch.CreatePatch(desired, current)
+	if err != nil {
+		return "", err
+	}
+	if len(ops) == 0 {
+		return "", nil
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Path < ops[j].Path })
+	bs, err := json.Marshal(ops)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// pruneToShape remove the fields in the live object that are not declared in the recorded object. Elements appended
+// to the lists will be kept as they are regarded as drift.
+func pruneToShape(recorded interface{}, live interface{}) interface{} {
+	switch r := recorded.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		pruned := map[string]interface{}{}
+		for k, v := range r {
+			if lv, found := l[k]; found {
+				pruned[k] = pruneToShape(v, lv)
+			}
+		}
+		return pruned
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		pruned := make([]interface{}, len(l))
+		for i := range l {
+			if i < len(r) {
+				pruned[i] = pruneToShape(r[i], l[i])
+			} else {
+				pruned[i] = l[i]
+			}
+		}
+		return pruned
+	default:
+		return live
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+func TestComputeDriftPatch(t *testing.T) {
+	manifest := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "example", "labels": map[string]interface{}{"app": "example"}},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "main", "image": "nginx:1.20"}},
+			}},
+		},
+	}}
+	testCases := map[string]struct {
+		live   *unstructured.Unstructured
+		expect string
+	}{
+		"no drift with extra fields filled by cluster": {
+			live: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "example", "resourceVersion": "10", "labels": map[string]interface{}{"app": "example", "extra": "val"}},
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+					"template": map[string]interface{}{"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{"name": "main", "image": "nginx:1.20", "imagePullPolicy": "IfNotPresent"}},
+					}},
+				},
+				"status": map[string]interface{}{"replicas": int64(1)},
+			}},
+			expect: "",
+		},
+		"drift on declared fields": {
+			live: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "example"},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"template": map[string]interface{}{"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "main", "image": "nginx:1.21"},
+							map[string]interface{}{"name": "debug", "image": "busybox"},
+						},
+					}},
+				},
+			}},
+			expect: `[{"op":"remove","path":"/metadata/labels"},` +
+				`{"op":"replace","path":"/spec/replicas","value":3},` +
+				`{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"nginx:1.21"},` +
+				`{"op":"add","path":"/spec/template/spec/containers/1","value":{"image":"busybox","name":"debug"}}]`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			patch, err := computeDriftPatch(manifest, tc.live)
+			r.NoError(err)
+			if tc.expect == "" {
+				r.Equal("", patch)
+			} else {
+				r.JSONEq(tc.expect, patch)
+			}
+		})
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	r := require.New(t)
+	detectedTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	mr := v1beta1.ManagedResource{
+		ClusterObjectReference: common.ClusterObjectReference{
+			ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "example", Namespace: "default"},
+		},
+	}
+	h := &resourceKeeper{
+		app: &v1beta1.Application{Status: common.AppStatus{Drifts: []common.ResourceDrift{{
+			ClusterObjectReference: mr.ClusterObjectReference,
+			DetectedTime:           detectedTime,
+		}}}},
+		applyOncePolicy: &v1alpha1.ApplyOncePolicySpec{DetectOnly: true},
+	}
+	r.True(h.isDetectOnly())
+	manifest := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       map[string]interface{}{"key": "val"},
+	}}
+	live := manifest.DeepCopy()
+
+	drifts, err := h.detectDrift(mr, manifest, live, true, nil)
+	r.NoError(err)
+	r.Equal(0, len(drifts))
+
+	r.NoError(unstructured.SetNestedField(live.Object, "changed", "data", "key"))
+	drifts, err = h.detectDrift(mr, manifest, live, true, nil)
+	r.NoError(err)
+	r.Equal(1, len(drifts))
+	r.Equal(detectedTime, drifts[0].DetectedTime)
+	r.JSONEq(`[{"op":"replace","path":"/data/key","value":"changed"}]`, drifts[0].Patch)
+
+	mr.Name = "missing"
+	drifts, err = h.detectDrift(mr, manifest, nil, false, drifts)
+	r.NoError(err)
+	r.Equal(2, len(drifts))
+	r.True(drifts[1].Missing)
+	r.NotEqual(detectedTime, drifts[1].DetectedTime)
+
+	h.updateDrifts(drifts)
+	r.Equal(drifts, h.app.Status.Drifts)
+}
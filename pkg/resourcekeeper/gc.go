@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
 
@@ -66,7 +67,8 @@ type gcConfig struct {
 	disableComponentRevisionGC bool
 	disableLegacyGC            bool
 
-	order v1alpha1.GarbageCollectOrder
+	order       v1alpha1.GarbageCollectOrder
+	orderGroups []v1alpha1.GarbageCollectOrderGroup
 }
 
 func newGCConfig(options ...GCOption) *gcConfig {
@@ -108,6 +110,8 @@ func (h *resourceKeeper) GarbageCollect(ctx context.Context, options ...GCOption
 		switch h.garbageCollectPolicy.Order {
 		case v1alpha1.OrderDependency:
 			options = append(options, DependencyGCOption{})
+		case v1alpha1.OrderGroups:
+			options = append(options, GroupsGCOption{Groups: h.garbageCollectPolicy.Groups})
 		default:
 		}
 	}
@@ -123,6 +127,9 @@ func (h *resourceKeeper) garbageCollect(ctx context.Context, cfg *gcConfig) (fin
 		if err = gc.Mark(ctx); err != nil {
 			return false, waiting, errors.Wrapf(err, "failed to mark inactive resourcetrackers")
 		}
+		if err = gc.RecycleResourcesInDrainedClusters(ctx); err != nil {
+			return false, waiting, errors.Wrapf(err, "failed to recycle resources in drained clusters")
+		}
 	}
 	// Sweep Stage
 	if !cfg.disableSweep {
@@ -227,7 +234,11 @@ func (h *gcHandler) Mark(ctx context.Context) error {
 
 // checkAndRemoveResourceTrackerFinalizer return (all resource recycled, error)
 func (h *gcHandler) checkAndRemoveResourceTrackerFinalizer(ctx context.Context, rt *v1beta1.ResourceTracker) (bool, v1beta1.ManagedResource, error) {
-	for _, mr := range rt.Spec.ManagedResources {
+	mrs := rt.Spec.ManagedResources
+	if h.cfg.order == v1alpha1.OrderGroups {
+		mrs = h.sortByOrderGroup(auth.ContextWithUserInfo(ctx, h.app), mrs)
+	}
+	for _, mr := range mrs {
 		entry := h.cache.get(auth.ContextWithUserInfo(ctx, h.app), mr)
 		if entry.err != nil {
 			return false, entry.mr, entry.err
@@ -256,6 +267,10 @@ func (h *gcHandler) Sweep(ctx context.Context) (finished bool, waiting []v1beta1
 			}
 		}
 	}
+	if h.cfg.order == v1alpha1.OrderGroups {
+		// report the resources in the earliest unfinished group first
+		waiting = h.sortByOrderGroup(auth.ContextWithUserInfo(ctx, h.app), waiting)
+	}
 	return finished, waiting, nil
 }
 
@@ -279,6 +294,50 @@ func (h *gcHandler) recycleResourceTracker(ctx context.Context, rt *v1beta1.Reso
 	return nil
 }
 
+// findOrderGroup return the index of the order group that the managed resource belongs to
+func (h *gcHandler) findOrderGroup(ctx context.Context, mr v1beta1.ManagedResource) int {
+	entry := h.cache.get(ctx, mr)
+	spec := v1alpha1.GarbageCollectPolicySpec{Groups: h.cfg.orderGroups}
+	return spec.FindOrderGroup(entry.obj)
+}
+
+// sortByOrderGroup return a copy of the managed resources stably sorted by the order groups they belong to
+func (h *gcHandler) sortByOrderGroup(ctx context.Context, mrs []v1beta1.ManagedResource) []v1beta1.ManagedResource {
+	sorted := make([]v1beta1.ManagedResource, len(mrs))
+	copy(sorted, mrs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return h.findOrderGroup(ctx, sorted[i]) < h.findOrderGroup(ctx, sorted[j])
+	})
+	return sorted
+}
+
+// recycleResourceTrackersInGroups recycle the resources in the given resourcetrackers group by group. Resources in the
+// next group will only be deleted after all resources in the previous groups are gone.
+func (h *gcHandler) recycleResourceTrackersInGroups(ctx context.Context, rts []*v1beta1.ResourceTracker) error {
+	ctx = auth.ContextWithUserInfo(ctx, h.app)
+	for idx := 0; idx <= len(h.cfg.orderGroups); idx++ {
+		recycled := true
+		for _, rt := range rts {
+			for _, mr := range rt.Spec.ManagedResources {
+				if h.findOrderGroup(ctx, mr) != idx {
+					continue
+				}
+				entry := h.cache.get(ctx, mr)
+				if entry.gcExecutorRT == rt && (entry.err != nil || entry.exists) {
+					recycled = false
+				}
+				if err := h.deleteManagedResource(ctx, mr, rt); err != nil {
+					return err
+				}
+			}
+		}
+		if !recycled {
+			return nil
+		}
+	}
+	return nil
+}
+
 func (h *gcHandler) deleteIndependentComponent(ctx context.Context, mr v1beta1.ManagedResource, rt *v1beta1.ResourceTracker) error {
 	dependent := h.checkDependentComponent(mr)
 	if len(dependent) == 0 {
@@ -391,11 +450,18 @@ func (h *gcHandler) checkDependentComponent(mr v1beta1.ManagedResource) []string
 func (h *gcHandler) Finalize(ctx context.Context) error {
 	cb := h.monitor("finalize")
 	defer cb()
+	var rts []*v1beta1.ResourceTracker
 	for _, rt := range append(h._historyRTs, h._currentRT, h._rootRT) {
 		if rt != nil && rt.GetDeletionTimestamp() != nil && meta.FinalizerExists(rt, resourcetracker.Finalizer) {
-			if err := h.recycleResourceTracker(ctx, rt); err != nil {
-				return err
-			}
+			rts = append(rts, rt)
+		}
+	}
+	if h.cfg.order == v1alpha1.OrderGroups {
+		return h.recycleResourceTrackersInGroups(ctx, rts)
+	}
+	for _, rt := range rts {
+		if err := h.recycleResourceTracker(ctx, rt); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -444,6 +510,48 @@ func (h *gcHandler) GarbageCollectComponentRevisionResourceTracker(ctx context.C
 	return nil
 }
 
+// RecycleResourcesInDrainedClusters recycle the resources recorded in the current resourcetracker which are
+// dispatched to drained clusters. Drained clusters are never selected by topology policies, so these resources are
+// left over by the workflow runs before the cluster is drained.
+func (h *gcHandler) RecycleResourcesInDrainedClusters(ctx context.Context) error {
+	cb := h.monitor("drained-cluster")
+	defer cb()
+	rt := h._currentRT
+	if rt == nil || rt.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	drained := map[string]bool{}
+	var managedResources []v1beta1.ManagedResource
+	for _, mr := range rt.Spec.ManagedResources {
+		if mr.Cluster == "" || mr.Cluster == multicluster.ClusterLocalName {
+			managedResources = append(managedResources, mr)
+			continue
+		}
+		if _, checked := drained[mr.Cluster]; !checked {
+			vc, err := multicluster.GetVirtualCluster(multicluster.ContextInLocalCluster(ctx), h.Client, mr.Cluster)
+			if err != nil && !errors.Is(err, multicluster.ErrClusterNotExists) {
+				return errors.Wrapf(err, "failed to get cluster %s", mr.Cluster)
+			}
+			drained[mr.Cluster] = err == nil && vc.Maintenance == multicluster.ClusterDrained
+		}
+		if !drained[mr.Cluster] {
+			managedResources = append(managedResources, mr)
+			continue
+		}
+		if err := h.deleteManagedResource(auth.ContextWithUserInfo(ctx, h.app), mr, rt); err != nil {
+			return err
+		}
+	}
+	if len(managedResources) == len(rt.Spec.ManagedResources) {
+		return nil
+	}
+	rt.Spec.ManagedResources = managedResources
+	if err := h.Client.Update(ctx, rt); err != nil {
+		return errors.Wrapf(err, "failed to update resourcetracker %s", rt.Name)
+	}
+	return nil
+}
+
 const velaVersionNumberToUpgradeResourceTracker = "v1.2.0"
 
 func (h *gcHandler) GarbageCollectLegacyResourceTrackers(ctx context.Context) error {
@@ -19,20 +19,26 @@ package resourcekeeper
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+
 	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/resourcetracker"
 	"github.com/oam-dev/kubevela/pkg/utils/common"
@@ -256,6 +262,92 @@ func TestResourceKeeperGarbageCollect(t *testing.T) {
 	r.True(finished)
 }
 
+func TestResourceKeeperGarbageCollectInGroups(t *testing.T) {
+	r := require.New(t)
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).Build()
+	ctx := context.Background()
+
+	rt := &v1beta1.ResourceTracker{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-v1", Labels: map[string]string{
+			oam.LabelAppName:      "app",
+			oam.LabelAppNamespace: "default",
+			oam.LabelAppUID:       "uid",
+		}, Finalizers: []string{resourcetracker.Finalizer}},
+		Spec: v1beta1.ResourceTrackerSpec{
+			Type:                  v1beta1.ResourceTrackerTypeVersioned,
+			ApplicationGeneration: 1,
+		},
+	}
+	r.NoError(cli.Create(ctx, rt))
+
+	var objs []*unstructured.Unstructured
+	for _, kind := range []string{"Secret", "ConfigMap", "Service"} {
+		obj := &unstructured.Unstructured{}
+		obj.SetName(strings.ToLower(kind))
+		obj.SetNamespace("default")
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(kind))
+		obj.SetLabels(map[string]string{
+			oam.LabelAppComponent: "comp",
+			oam.LabelAppNamespace: "default",
+			oam.LabelAppName:      "app",
+		})
+		r.NoError(cli.Create(ctx, obj))
+		objs = append(objs, obj)
+	}
+	r.NoError(resourcetracker.RecordManifestsInResourceTracker(ctx, cli, rt, objs, true, false, ""))
+
+	exists := func(kind string) bool {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(kind))
+		return cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: strings.ToLower(kind)}, obj) == nil
+	}
+	runGC := func() (bool, []v1beta1.ManagedResource) {
+		dt := metav1.Now()
+		_rk, err := NewResourceKeeper(ctx, cli, &v1beta1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid", Generation: 1, DeletionTimestamp: &dt},
+		})
+		r.NoError(err)
+		rk := _rk.(*resourceKeeper)
+		rk.garbageCollectPolicy = &v1alpha1.GarbageCollectPolicySpec{
+			Order: v1alpha1.OrderGroups,
+			Groups: []v1alpha1.GarbageCollectOrderGroup{{
+				Name:     "network",
+				Selector: &v1alpha1.ResourcePolicyRuleSelector{ResourceTypes: []string{"Service"}},
+			}, {
+				Name:     "config",
+				Selector: &v1alpha1.ResourcePolicyRuleSelector{ResourceTypes: []string{"ConfigMap"}},
+			}},
+		}
+		finished, waiting, err := rk.GarbageCollect(ctx, DisableLegacyGCOption{})
+		r.NoError(err)
+		return finished, waiting
+	}
+
+	// services are recycled first
+	finished, waiting := runGC()
+	r.False(finished)
+	r.Equal("Service", waiting[0].Kind)
+	r.False(exists("Service"))
+	r.True(exists("ConfigMap"))
+	r.True(exists("Secret"))
+
+	// configmaps are recycled after services are gone
+	finished, waiting = runGC()
+	r.False(finished)
+	r.Equal("ConfigMap", waiting[0].Kind)
+	r.False(exists("ConfigMap"))
+	r.True(exists("Secret"))
+
+	// resources not matched by any group are recycled at last
+	finished, waiting = runGC()
+	r.False(finished)
+	r.Equal("Secret", waiting[0].Kind)
+	r.False(exists("Secret"))
+
+	finished, _ = runGC()
+	r.True(finished)
+}
+
 func TestCheckDependentComponent(t *testing.T) {
 	rk := &resourceKeeper{
 		app: &v1beta1.Application{
@@ -338,3 +430,65 @@ func TestCheckDependentComponent(t *testing.T) {
 		r.Equal(gcHandler.checkDependentComponent(mr), tc.result)
 	}
 }
+
+func TestRecycleResourcesInDrainedClusters(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	newClusterSecret := func(name string, maintenance multicluster.ClusterMaintenanceStatus) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   multicluster.ClusterGatewaySecretNamespace,
+			Labels:      map[string]string{clustercommon.LabelKeyClusterCredentialType: "X509Certificate"},
+			Annotations: map[string]string{types.AnnotationClusterMaintenance: string(maintenance)},
+		}}
+		return secret
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newClusterSecret("drained-cluster", multicluster.ClusterDrained),
+		newClusterSecret("cordoned-cluster", multicluster.ClusterCordoned),
+	).Build()
+	rt := &v1beta1.ResourceTracker{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-v1", Labels: map[string]string{
+			oam.LabelAppName:      "app",
+			oam.LabelAppNamespace: "default",
+			oam.LabelAppUID:       "uid",
+		}, Finalizers: []string{resourcetracker.Finalizer}},
+		Spec: v1beta1.ResourceTrackerSpec{
+			Type:                  v1beta1.ResourceTrackerTypeVersioned,
+			ApplicationGeneration: 1,
+		},
+	}
+	r.NoError(cli.Create(ctx, rt))
+	var objs []*unstructured.Unstructured
+	for _, cluster := range []string{"", "drained-cluster", "cordoned-cluster", "detached-cluster"} {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		obj.SetName("cm-" + cluster)
+		obj.SetNamespace("default")
+		obj.SetLabels(map[string]string{oam.LabelAppNamespace: "default", oam.LabelAppName: "app"})
+		r.NoError(cli.Create(ctx, obj))
+		oam.SetCluster(obj, cluster)
+		objs = append(objs, obj)
+	}
+	r.NoError(resourcetracker.RecordManifestsInResourceTracker(ctx, cli, rt, objs, true, false, ""))
+
+	_rk, err := NewResourceKeeper(ctx, cli, &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid", Generation: 1},
+	})
+	r.NoError(err)
+	// resources in drained clusters are not recycled while the workflow is running
+	_, _, err = _rk.GarbageCollect(ctx, DisableMarkStageGCOption{}, DisableLegacyGCOption{})
+	r.NoError(err)
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cm-drained-cluster"}, &corev1.ConfigMap{}))
+
+	_, _, err = _rk.GarbageCollect(ctx, DisableLegacyGCOption{})
+	r.NoError(err)
+	r.True(kerrors.IsNotFound(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cm-drained-cluster"}, &corev1.ConfigMap{})))
+	r.NoError(cli.Get(ctx, client.ObjectKeyFromObject(rt), rt))
+	var clusters []string
+	for _, mr := range rt.Spec.ManagedResources {
+		clusters = append(clusters, mr.Cluster)
+		r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: mr.Name}, &corev1.ConfigMap{}))
+	}
+	r.Equal([]string{"", "cordoned-cluster", "detached-cluster"}, clusters)
+}
@@ -74,6 +74,18 @@ func (option DependencyGCOption) ApplyToGCConfig(cfg *gcConfig) {
 	cfg.order = v1alpha1.OrderDependency
 }
 
+// GroupsGCOption recycle the resource group by group, resources in the next group will not be recycled until all
+// resources in the previous groups are gone
+type GroupsGCOption struct {
+	Groups []v1alpha1.GarbageCollectOrderGroup
+}
+
+// ApplyToGCConfig apply change to gc config
+func (option GroupsGCOption) ApplyToGCConfig(cfg *gcConfig) {
+	cfg.order = v1alpha1.OrderGroups
+	cfg.orderGroups = option.Groups
+}
+
 // DisableMarkStageGCOption disable the mark stage in gc process (no rt will be marked to be deleted)
 // this option should be switched on when application workflow is suspending/terminating since workflow is not
 // finished so outdated versions should be kept
@@ -0,0 +1,335 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	velaerrors "github.com/oam-dev/kubevela/pkg/utils/errors"
+)
+
+// quotaCheck check whether the resources to dispatch exceed the quotas of the application. If admitted, the usage of
+// the application will be reserved in the status of the quotas. The check and the reservation of each quota are done
+// in one conflict-checked update, so concurrent applications cannot exceed the quota together.
+func (h *resourceKeeper) quotaCheck(ctx context.Context, manifests []*unstructured.Unstructured) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx = multicluster.ContextInLocalCluster(ctx)
+	quotas, err := h.listApplicationQuotas(ctx)
+	if err != nil || len(quotas) == 0 {
+		return err
+	}
+	usage := computeQuotaUsage([]*v1beta1.ResourceTracker{h._rootRT, h._currentRT}, manifests)
+	var reserved []*v1alpha1.ApplicationQuota
+	var previous []*v1alpha1.ApplicationQuotaUsage
+	for _, quota := range quotas {
+		prev, err := updateQuotaUsage(ctx, h.Client, quota, h.app, &usage, true)
+		if err != nil {
+			// revert the reservations in the admitted quotas
+			for i := range reserved {
+				if _, revertErr := updateQuotaUsage(ctx, h.Client, reserved[i], h.app, previous[i], false); revertErr != nil {
+					return errors.Wrapf(revertErr, "failed to revert the usage in quota %s", reserved[i].Name)
+				}
+			}
+			return err
+		}
+		reserved = append(reserved, quota)
+		previous = append(previous, prev)
+	}
+	return nil
+}
+
+// ReleaseQuotaUsage remove the usage of the deleted application from the status of all the quotas
+func ReleaseQuotaUsage(ctx context.Context, cli client.Client, app *v1beta1.Application) error {
+	ctx = multicluster.ContextInLocalCluster(ctx)
+	quotaList := &v1alpha1.ApplicationQuotaList{}
+	if err := cli.List(ctx, quotaList); err != nil {
+		if velaerrors.IsCRDNotExists(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to list application quotas")
+	}
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+		for _, appUsage := range quota.Status.Applications {
+			if appUsage.Name == app.Name && appUsage.Namespace == app.Namespace {
+				if _, err := updateQuotaUsage(ctx, cli, quota, app, nil, false); err != nil {
+					return errors.Wrapf(err, "failed to release the usage in quota %s", quota.Name)
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// listApplicationQuotas list the quotas that restrict the application
+func (h *resourceKeeper) listApplicationQuotas(ctx context.Context) ([]*v1alpha1.ApplicationQuota, error) {
+	quotaList := &v1alpha1.ApplicationQuotaList{}
+	if err := h.Client.List(ctx, quotaList); err != nil {
+		if velaerrors.IsCRDNotExists(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to list application quotas")
+	}
+	var quotas []*v1alpha1.ApplicationQuota
+	for i := range quotaList.Items {
+		if matchApplicationQuota(&quotaList.Items[i], h.app) {
+			quotas = append(quotas, quotaList.Items[i].DeepCopy())
+		}
+	}
+	return quotas, nil
+}
+
+// matchApplicationQuota check if the application is restricted by the quota. Quota without project and application
+// specified restricts all applications.
+func matchApplicationQuota(quota *v1alpha1.ApplicationQuota, app *v1beta1.Application) bool {
+	if target := quota.Spec.Application; target != nil && (target.Name != app.Name || target.Namespace != app.Namespace) {
+		return false
+	}
+	if quota.Spec.Project != "" && quota.Spec.Project != app.GetLabels()[oam.LabelProject] {
+		return false
+	}
+	return true
+}
+
+// computeQuotaUsage compute the usage of the application, including the resources recorded in the given
+// resourcetrackers and the resources to dispatch
+func computeQuotaUsage(rts []*v1beta1.ResourceTracker, manifests []*unstructured.Unstructured) v1alpha1.ApplicationQuotaUsage {
+	type entry struct {
+		cluster string
+		obj     *unstructured.Unstructured
+	}
+	entries := map[string]entry{}
+	for _, rt := range rts {
+		if rt == nil {
+			continue
+		}
+		for _, mr := range rt.Spec.ManagedResources {
+			if mr.Deleted {
+				continue
+			}
+			obj, err := mr.ToUnstructuredWithData()
+			if err != nil {
+				obj = mr.ToUnstructured()
+			}
+			entries[mr.ResourceKey()] = entry{cluster: mr.Cluster, obj: obj}
+		}
+	}
+	for _, manifest := range manifests {
+		if manifest == nil {
+			continue
+		}
+		mr := v1beta1.ManagedResource{ClusterObjectReference: common.ClusterObjectReference{
+			Cluster: oam.GetCluster(manifest),
+			ObjectReference: corev1.ObjectReference{
+				APIVersion: manifest.GetAPIVersion(),
+				Kind:       manifest.GetKind(),
+				Name:       manifest.GetName(),
+				Namespace:  manifest.GetNamespace(),
+			},
+		}}
+		entries[mr.ResourceKey()] = entry{cluster: mr.Cluster, obj: manifest}
+	}
+	usage := v1alpha1.ApplicationQuotaUsage{Objects: map[string]int64{}}
+	clusters := map[string]bool{}
+	for _, e := range entries {
+		usage.Objects[e.obj.GetKind()]++
+		cpu, memory := getWorkloadRequests(e.obj)
+		usage.CPU.Add(cpu)
+		usage.Memory.Add(memory)
+		cluster := e.cluster
+		if cluster == "" {
+			cluster = multicluster.ClusterLocalName
+		}
+		clusters[cluster] = true
+	}
+	for cluster := range clusters {
+		usage.Clusters = append(usage.Clusters, cluster)
+	}
+	sort.Strings(usage.Clusters)
+	return usage
+}
+
+// getWorkloadRequests compute the total requested cpu and memory of the workload, multiplied by the replicas
+func getWorkloadRequests(obj *unstructured.Unstructured) (cpu resource.Quantity, memory resource.Quantity) {
+	path := []string{"spec", "template", "spec"}
+	switch obj.GetKind() {
+	case "Pod":
+		path = []string{"spec"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+	_podSpec, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return cpu, memory
+	}
+	podSpec := &corev1.PodSpec{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(_podSpec, podSpec); err != nil {
+		return cpu, memory
+	}
+	for _, container := range podSpec.Containers {
+		if q, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			cpu.Add(q)
+		}
+		if q, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			memory.Add(q)
+		}
+	}
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err == nil && found && replicas != 1 {
+		cpu = *resource.NewMilliQuantity(cpu.MilliValue()*replicas, cpu.Format)
+		memory = *resource.NewQuantity(memory.Value()*replicas, memory.Format)
+	}
+	return cpu, memory
+}
+
+// aggregateQuotaUsage sum up the usage of applications
+func aggregateQuotaUsage(usages ...v1alpha1.ApplicationQuotaUsage) v1alpha1.ApplicationQuotaUsage {
+	total := v1alpha1.ApplicationQuotaUsage{Objects: map[string]int64{}}
+	clusters := map[string]bool{}
+	for _, usage := range usages {
+		for kind, cnt := range usage.Objects {
+			total.Objects[kind] += cnt
+		}
+		total.CPU.Add(usage.CPU)
+		total.Memory.Add(usage.Memory)
+		for _, cluster := range usage.Clusters {
+			if !clusters[cluster] {
+				clusters[cluster] = true
+				total.Clusters = append(total.Clusters, cluster)
+			}
+		}
+	}
+	sort.Strings(total.Clusters)
+	return total
+}
+
+// getOtherApplicationUsages return the recorded usages of applications in the quota except the given one
+func getOtherApplicationUsages(quota *v1alpha1.ApplicationQuota, app *v1beta1.Application) []v1alpha1.ApplicationQuotaUsage {
+	var usages []v1alpha1.ApplicationQuotaUsage
+	for _, appUsage := range quota.Status.Applications {
+		if appUsage.Name != app.Name || appUsage.Namespace != app.Namespace {
+			usages = append(usages, appUsage.Used)
+		}
+	}
+	return usages
+}
+
+// checkApplicationQuota check if the usage of the application, together with the usage of other applications
+// restricted by the same quota, exceeds the limits
+func checkApplicationQuota(quota *v1alpha1.ApplicationQuota, app *v1beta1.Application, usage v1alpha1.ApplicationQuotaUsage) error {
+	total := aggregateQuotaUsage(append(getOtherApplicationUsages(quota, app), usage)...)
+	exceeded := func(msg string, args ...interface{}) error {
+		return errors.Errorf("exceeded quota %s: application %s/%s %s", quota.Name, app.Namespace, app.Name, fmt.Sprintf(msg, args...))
+	}
+	hard := quota.Spec.Hard
+	var kinds []string
+	for kind := range hard.Objects {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		if total.Objects[kind] > hard.Objects[kind] {
+			return exceeded("requests %d %s objects in total, limited to %d", total.Objects[kind], kind, hard.Objects[kind])
+		}
+	}
+	if hard.CPU != nil && total.CPU.Cmp(*hard.CPU) > 0 {
+		return exceeded("requests cpu %s in total, limited to %s", total.CPU.String(), hard.CPU.String())
+	}
+	if hard.Memory != nil && total.Memory.Cmp(*hard.Memory) > 0 {
+		return exceeded("requests memory %s in total, limited to %s", total.Memory.String(), hard.Memory.String())
+	}
+	if hard.Clusters != nil && int64(len(total.Clusters)) > *hard.Clusters {
+		return exceeded("dispatches resources to %d clusters in total, limited to %d", len(total.Clusters), *hard.Clusters)
+	}
+	return nil
+}
+
+// updateQuotaUsage set the usage of the application in the quota status with a conflict-checked update, the
+// application is removed from the status if the usage is nil. The records of applications that no longer exist will
+// be removed. If check is true, the usage is checked against the latest status of the quota before the update.
+// The previous usage of the application is returned.
+func updateQuotaUsage(ctx context.Context, cli client.Client, quota *v1alpha1.ApplicationQuota, app *v1beta1.Application, usage *v1alpha1.ApplicationQuotaUsage, check bool) (*v1alpha1.ApplicationQuotaUsage, error) {
+	var previous *v1alpha1.ApplicationQuotaUsage
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := cli.Get(ctx, types.NamespacedName{Name: quota.Name}, quota); err != nil {
+			return err
+		}
+		previous = nil
+		var appUsages []v1alpha1.ApplicationUsage
+		for _, appUsage := range quota.Status.Applications {
+			if appUsage.Name == app.Name && appUsage.Namespace == app.Namespace {
+				used := appUsage.Used
+				previous = &used
+				continue
+			}
+			if err := cli.Get(ctx, types.NamespacedName{Namespace: appUsage.Namespace, Name: appUsage.Name}, &v1beta1.Application{}); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			appUsages = append(appUsages, appUsage)
+		}
+		if usage != nil {
+			if check {
+				latest := quota.DeepCopy()
+				latest.Status.Applications = appUsages
+				if err := checkApplicationQuota(latest, app, *usage); err != nil {
+					return err
+				}
+			}
+			appUsages = append(appUsages, v1alpha1.ApplicationUsage{Name: app.Name, Namespace: app.Namespace, Used: *usage})
+		}
+		sort.Slice(appUsages, func(i, j int) bool {
+			if appUsages[i].Namespace != appUsages[j].Namespace {
+				return appUsages[i].Namespace < appUsages[j].Namespace
+			}
+			return appUsages[i].Name < appUsages[j].Name
+		})
+		var usages []v1alpha1.ApplicationQuotaUsage
+		for _, appUsage := range appUsages {
+			usages = append(usages, appUsage.Used)
+		}
+		status := v1alpha1.ApplicationQuotaStatus{Used: aggregateQuotaUsage(usages...), Applications: appUsages}
+		if equality.Semantic.DeepEqual(status, quota.Status) {
+			return nil
+		}
+		quota.Status = status
+		return cli.Status().Update(ctx, quota)
+	})
+	return previous, err
+}
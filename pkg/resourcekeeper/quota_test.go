@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestGetWorkloadRequests(t *testing.T) {
+	r := require.New(t)
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "main", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "500m", "memory": "1Gi"}}},
+					map[string]interface{}{"name": "sidecar", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "100m"}}},
+				},
+			}},
+		},
+	}}
+	cpu, memory := getWorkloadRequests(deploy)
+	r.Equal(0, cpu.Cmp(resource.MustParse("1800m")))
+	r.Equal(0, memory.Cmp(resource.MustParse("3Gi")))
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "main", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": int64(2)}}},
+		}},
+	}}
+	cpu, memory = getWorkloadRequests(pod)
+	r.Equal(0, cpu.Cmp(resource.MustParse("2")))
+	r.True(memory.IsZero())
+	cpu, _ = getWorkloadRequests(&unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}})
+	r.True(cpu.IsZero())
+}
+
+func TestQuotaCheck(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	newApp := func(name string, project string) *v1beta1.Application {
+		return &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{oam.LabelProject: project}}}
+	}
+	newDeploy := func(name string, cluster string, cpu string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "main", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": cpu}}}},
+			}}},
+		}}
+		oam.SetCluster(obj, cluster)
+		return obj
+	}
+	cpuLimit := resource.MustParse("1")
+	projectQuota := &v1alpha1.ApplicationQuota{
+		ObjectMeta: v1.ObjectMeta{Name: "project-quota"},
+		Spec: v1alpha1.ApplicationQuotaSpec{
+			Project: "team",
+			Hard:    v1alpha1.ApplicationQuotaHard{Objects: map[string]int64{"Deployment": 3}, CPU: &cpuLimit},
+		},
+	}
+	appQuota := &v1alpha1.ApplicationQuota{
+		ObjectMeta: v1.ObjectMeta{Name: "app-quota"},
+		Spec: v1alpha1.ApplicationQuotaSpec{
+			Application: &v1alpha1.ApplicationQuotaTarget{Name: "app-1", Namespace: "default"},
+			Hard:        v1alpha1.ApplicationQuotaHard{Clusters: pointer.Int64(1)},
+		},
+	}
+	app1, app2, other := newApp("app-1", "team"), newApp("app-2", "team"), newApp("other", "")
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(projectQuota, appQuota, app1, app2, other).Build()
+
+	dispatch := func(h *resourceKeeper, manifests ...*unstructured.Unstructured) error {
+		if err := h.quotaCheck(ctx, manifests); err != nil {
+			return err
+		}
+		return h.record(ctx, manifests)
+	}
+
+	h := &resourceKeeper{Client: cli, app: app1}
+	r.NoError(dispatch(h, newDeploy("a", "", "300m"), newDeploy("b", "", "300m")))
+	err := dispatch(h, newDeploy("c", "cluster-a", "100m"))
+	r.Error(err)
+	r.Contains(err.Error(), "exceeded quota app-quota: application default/app-1 dispatches resources to 2 clusters in total, limited to 1")
+
+	h2 := &resourceKeeper{Client: cli, app: app2}
+	err = dispatch(h2, newDeploy("c", "", "500m"))
+	r.Error(err)
+	r.Contains(err.Error(), "exceeded quota project-quota: application default/app-2 requests cpu 1100m in total, limited to 1")
+	r.NoError(dispatch(h2, newDeploy("c", "", "400m")))
+	err = dispatch(h2, newDeploy("c", "", "100m"), newDeploy("d", "", "100m"))
+	r.Error(err)
+	r.Contains(err.Error(), "requests 4 Deployment objects in total, limited to 3")
+
+	quota := &v1alpha1.ApplicationQuota{}
+	r.NoError(cli.Get(ctx, client.ObjectKeyFromObject(projectQuota), quota))
+	r.Equal(2, len(quota.Status.Applications))
+	r.Equal(int64(3), quota.Status.Used.Objects["Deployment"])
+	r.Equal("1", quota.Status.Used.CPU.String())
+	r.Equal([]string{"local"}, quota.Status.Used.Clusters)
+
+	h = &resourceKeeper{Client: cli, app: other}
+	r.NoError(dispatch(h, newDeploy("x", "", "10")))
+
+	r.NoError(cli.Delete(ctx, app1))
+	r.NoError(dispatch(h2, newDeploy("c", "", "400m")))
+	r.NoError(cli.Get(ctx, client.ObjectKeyFromObject(projectQuota), quota))
+	r.Equal(1, len(quota.Status.Applications))
+	r.Equal("400m", quota.Status.Used.CPU.String())
+
+	r.Equal([]string{"project-quota/default/app-2"}, usageKeys(cli, "project-quota"))
+
+	r.NoError(ReleaseQuotaUsage(ctx, cli, app2))
+	r.NoError(cli.Get(ctx, client.ObjectKeyFromObject(projectQuota), quota))
+	r.Equal(0, len(quota.Status.Applications))
+	r.True(quota.Status.Used.CPU.IsZero())
+}
+
+func usageKeys(cli client.Client, name string) []string {
+	quota := &v1alpha1.ApplicationQuota{}
+	if err := cli.Get(context.Background(), types.NamespacedName{Name: name}, quota); err != nil {
+		return nil
+	}
+	var keys []string
+	for _, appUsage := range quota.Status.Applications {
+		keys = append(keys, name+"/"+appUsage.Namespace+"/"+appUsage.Name)
+	}
+	return keys
+}
+
+func TestQuotaCheckConcurrently(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cpuLimit := resource.MustParse("1")
+	quota := &v1alpha1.ApplicationQuota{
+		ObjectMeta: v1.ObjectMeta{Name: "quota"},
+		Spec:       v1alpha1.ApplicationQuotaSpec{Project: "team", Hard: v1alpha1.ApplicationQuotaHard{CPU: &cpuLimit}},
+	}
+	var apps []client.Object
+	for i := 0; i < 5; i++ {
+		apps = append(apps, &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Name: fmt.Sprintf("app-%d", i), Namespace: "default", Labels: map[string]string{oam.LabelProject: "team"}}})
+	}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(append(apps, quota)...).Build()
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "deploy", "namespace": "default"},
+		"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "main", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "300m"}}}},
+		}}},
+	}}
+	var admitted int32
+	wg := sync.WaitGroup{}
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app *v1beta1.Application) {
+			defer wg.Done()
+			h := &resourceKeeper{Client: cli, app: app}
+			if err := h.quotaCheck(ctx, []*unstructured.Unstructured{deploy.DeepCopy()}); err == nil {
+				atomic.AddInt32(&admitted, 1)
+			}
+		}(app.(*v1beta1.Application))
+	}
+	wg.Wait()
+	r.Equal(int32(3), admitted)
+	r.Equal(3, len(usageKeys(cli, "quota")))
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
@@ -32,10 +33,15 @@ import (
 
 // StateKeep run this function to keep resources up-to-date
 func (h *resourceKeeper) StateKeep(ctx context.Context) error {
-	if h.applyOncePolicy != nil && h.applyOncePolicy.Enable && h.applyOncePolicy.Rules == nil {
+	detectOnly := h.isDetectOnly()
+	if !detectOnly && h.app.Status.Drifts != nil {
+		h.updateDrifts(nil)
+	}
+	if !detectOnly && h.applyOncePolicy != nil && h.applyOncePolicy.Enable && h.applyOncePolicy.Rules == nil {
 		return nil
 	}
 	ctx = auth.ContextWithUserInfo(ctx, h.app)
+	var drifts []common.ResourceDrift
 	for _, rt := range []*v1beta1.ResourceTracker{h._currentRT, h._rootRT} {
 		if rt != nil && rt.GetDeletionTimestamp() == nil {
 			for _, mr := range rt.Spec.ManagedResources {
@@ -60,6 +66,17 @@ func (h *resourceKeeper) StateKeep(ctx context.Context) error {
 						return errors.Wrapf(err, "failed to decode resource %s from resourcetracker", mr.ResourceKey())
 					}
 					applyCtx := multicluster.ContextWithClusterName(ctx, mr.Cluster)
+					if detectOnly {
+						if entry.exists {
+							if manifest, err = ApplyStrategies(applyCtx, h, manifest); err != nil {
+								return errors.Wrapf(err, "failed to apply once resource %s from resourcetracker %s", mr.ResourceKey(), rt.Name)
+							}
+						}
+						if drifts, err = h.detectDrift(mr, manifest, entry.obj, entry.exists, drifts); err != nil {
+							return err
+						}
+						continue
+					}
 					manifest, err = ApplyStrategies(applyCtx, h, manifest)
 					if err != nil {
 						return errors.Wrapf(err, "failed to apply once resource %s from resourcetracker %s", mr.ResourceKey(), rt.Name)
@@ -75,6 +92,9 @@ func (h *resourceKeeper) StateKeep(ctx context.Context) error {
 			}
 		}
 	}
+	if detectOnly {
+		h.updateDrifts(drifts)
+	}
 	return nil
 }
 
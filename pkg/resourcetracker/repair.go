@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcetracker
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+)
+
+// ManagedResourceState records the managed resource in resourcetracker and whether it still exists in its cluster
+type ManagedResourceState struct {
+	v1beta1.ManagedResource
+	Exists bool
+}
+
+// CheckManagedResourceExists check if the managed resource still exists in its cluster. Resources whose cluster or
+// resource type no longer exists are regarded as not existing.
+func CheckManagedResourceExists(ctx context.Context, cli client.Client, mr v1beta1.ManagedResource) (bool, error) {
+	obj := mr.ToUnstructured()
+	if err := cli.Get(multicluster.ContextWithClusterName(ctx, mr.Cluster), mr.NamespacedName(), obj); err != nil {
+		if multicluster.IsNotFoundOrClusterNotExists(err) || apimeta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get resource %s", mr.ResourceKey())
+	}
+	return true, nil
+}
+
+// GetManagedResourceStates check the existence of all managed resources recorded in the resourcetracker
+func GetManagedResourceStates(ctx context.Context, cli client.Client, rt *v1beta1.ResourceTracker) ([]ManagedResourceState, error) {
+	var states []ManagedResourceState
+	for _, mr := range rt.Spec.ManagedResources {
+		exists, err := CheckManagedResourceExists(ctx, cli, mr)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, ManagedResourceState{ManagedResource: mr, Exists: exists})
+	}
+	return states, nil
+}
+
+// PruneMissingManagedResources remove the records of managed resources that no longer exist in clusters from the
+// resourcetracker. The pruned resources will be returned.
+func PruneMissingManagedResources(ctx context.Context, cli client.Client, rt *v1beta1.ResourceTracker) ([]v1beta1.ManagedResource, error) {
+	states, err := GetManagedResourceStates(ctx, cli, rt)
+	if err != nil {
+		return nil, err
+	}
+	var kept, pruned []v1beta1.ManagedResource
+	for _, state := range states {
+		if state.Exists {
+			kept = append(kept, state.ManagedResource)
+		} else {
+			pruned = append(pruned, state.ManagedResource)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+	rt.Spec.ManagedResources = kept
+	if err = cli.Update(ctx, rt); err != nil {
+		return nil, errors.Wrapf(err, "failed to update resourcetracker %s", rt.Name)
+	}
+	return pruned, nil
+}
+
+// RemoveResourceTrackerFinalizer force remove the finalizer of the resourcetracker without recycling its managed
+// resources. It returns false if the finalizer does not exist.
+func RemoveResourceTrackerFinalizer(ctx context.Context, cli client.Client, rt *v1beta1.ResourceTracker) (bool, error) {
+	if !meta.FinalizerExists(rt, Finalizer) {
+		return false, nil
+	}
+	meta.RemoveFinalizer(rt, Finalizer)
+	if err := cli.Update(ctx, rt); err != nil {
+		return false, errors.Wrapf(err, "failed to remove finalizer for resourcetracker %s", rt.Name)
+	}
+	return true, nil
+}
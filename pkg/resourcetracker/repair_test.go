@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcetracker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestRepairResourceTracker(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).Build()
+	r.NoError(cli.Create(ctx, &corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: "exists", Namespace: "default"}}))
+	newMR := func(name string) v1beta1.ManagedResource {
+		return v1beta1.ManagedResource{ClusterObjectReference: apicommon.ClusterObjectReference{
+			ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: name, Namespace: "default"},
+		}}
+	}
+	rt := &v1beta1.ResourceTracker{
+		ObjectMeta: v1.ObjectMeta{Name: "app-default", Finalizers: []string{Finalizer}},
+		Spec: v1beta1.ResourceTrackerSpec{
+			Type:             v1beta1.ResourceTrackerTypeRoot,
+			ManagedResources: []v1beta1.ManagedResource{newMR("exists"), newMR("missing")},
+		},
+	}
+	r.NoError(cli.Create(ctx, rt))
+
+	states, err := GetManagedResourceStates(ctx, cli, rt)
+	r.NoError(err)
+	r.Equal(2, len(states))
+	r.True(states[0].Exists)
+	r.False(states[1].Exists)
+
+	pruned, err := PruneMissingManagedResources(ctx, cli, rt)
+	r.NoError(err)
+	r.Equal([]v1beta1.ManagedResource{newMR("missing")}, pruned)
+	_rt := &v1beta1.ResourceTracker{}
+	r.NoError(cli.Get(ctx, client.ObjectKeyFromObject(rt), _rt))
+	r.Equal([]v1beta1.ManagedResource{newMR("exists")}, _rt.Spec.ManagedResources)
+	pruned, err = PruneMissingManagedResources(ctx, cli, _rt)
+	r.NoError(err)
+	r.Nil(pruned)
+
+	removed, err := RemoveResourceTrackerFinalizer(ctx, cli, _rt)
+	r.NoError(err)
+	r.True(removed)
+	r.NoError(cli.Get(ctx, types.NamespacedName{Name: rt.Name}, _rt))
+	r.Empty(_rt.GetFinalizers())
+	removed, err = RemoveResourceTrackerFinalizer(ctx, cli, _rt)
+	r.NoError(err)
+	r.False(removed)
+}
@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
@@ -43,8 +45,8 @@ type DeployWorkflowStepExecutor interface {
 	Deploy(ctx context.Context, policyNames []string, parallelism int) (healthy bool, reason string, err error)
 }
 
-// NewDeployWorkflowStepExecutor .
-func NewDeployWorkflowStepExecutor(cli client.Client, af *appfile.Appfile, apply oamProvider.ComponentApply, healthCheck oamProvider.ComponentHealthCheck, renderer oamProvider.WorkloadRenderer, ignoreTerraformComponent bool) DeployWorkflowStepExecutor {
+// NewDeployWorkflowStepExecutor . The failover events of topology policies will be recorded on app if recorder is set.
+func NewDeployWorkflowStepExecutor(cli client.Client, af *appfile.Appfile, apply oamProvider.ComponentApply, healthCheck oamProvider.ComponentHealthCheck, renderer oamProvider.WorkloadRenderer, ignoreTerraformComponent bool, app *v1beta1.Application, recorder event.Recorder) DeployWorkflowStepExecutor {
 	return &deployWorkflowStepExecutor{
 		cli:                      cli,
 		af:                       af,
@@ -52,6 +54,8 @@ func NewDeployWorkflowStepExecutor(cli client.Client, af *appfile.Appfile, apply
 		healthCheck:              healthCheck,
 		renderer:                 renderer,
 		ignoreTerraformComponent: ignoreTerraformComponent,
+		app:                      app,
+		recorder:                 recorder,
 	}
 }
 
@@ -62,6 +66,8 @@ type deployWorkflowStepExecutor struct {
 	healthCheck              oamProvider.ComponentHealthCheck
 	renderer                 oamProvider.WorkloadRenderer
 	ignoreTerraformComponent bool
+	app                      *v1beta1.Application
+	recorder                 event.Recorder
 }
 
 // Deploy execute deploy workflow step
@@ -74,15 +80,46 @@ func (executor *deployWorkflowStepExecutor) Deploy(ctx context.Context, policyNa
 	if err != nil {
 		return false, "", err
 	}
-	placements, err := pkgpolicy.GetPlacementsFromTopologyPolicies(ctx, executor.cli, executor.af.Namespace, policies, resourcekeeper.AllowCrossNamespaceResource)
+	if executor.app != nil {
+		ctx = pkgpolicy.ContextWithScheduledClusters(ctx, executor.app)
+	}
+	placements, failovers, err := pkgpolicy.GetPlacementsAndFailoversFromTopologyPolicies(ctx, executor.cli, executor.af.Namespace, policies, resourcekeeper.AllowCrossNamespaceResource)
 	if err != nil {
 		return false, "", err
 	}
+	executor.recordFailovers(failovers)
+	if executor.app != nil {
+		if err = pkgpolicy.CheckPlacementIdentities(ctx, executor.cli, executor.app, placements); err != nil {
+			return false, "", err
+		}
+		if err = pkgpolicy.WriteTopologyPolicyStatus(ctx, executor.app, policies, placements); err != nil {
+			return false, "", err
+		}
+	}
 	components, err = overrideConfiguration(policies, components)
 	if err != nil {
 		return false, "", err
 	}
-	return applyComponents(executor.apply, executor.healthCheck, components, placements, parallelism)
+	replicaSplits, err := getReplicaSplits(policies)
+	if err != nil {
+		return false, "", err
+	}
+	tasks, err := newApplyTasks(components, placements, replicaSplits)
+	if err != nil {
+		return false, "", err
+	}
+	return applyTasks(executor.apply, executor.healthCheck, tasks, parallelism)
+}
+
+// recordFailovers emit events for the clusters replaced by standby clusters in topology policies
+func (executor *deployWorkflowStepExecutor) recordFailovers(failovers []pkgpolicy.FailoverDecision) {
+	if executor.app == nil || executor.recorder == nil {
+		return
+	}
+	for _, failover := range failovers {
+		executor.recorder.Event(executor.app, event.Warning(types.ReasonFailover,
+			errors.Errorf(types.MessageFailover, failover.From, failover.To, failover.Topology)))
+	}
 }
 
 func selectPolicies(policies []v1beta1.AppPolicy, policyNames []string) ([]v1beta1.AppPolicy, error) {
@@ -142,6 +179,46 @@ func overrideConfiguration(policies []v1beta1.AppPolicy, components []common.App
 	return components, nil
 }
 
+// getReplicaSplits get the replica splits in override policies
+func getReplicaSplits(policies []v1beta1.AppPolicy) ([]v1alpha1.OverrideReplicaSplit, error) {
+	var replicaSplits []v1alpha1.OverrideReplicaSplit
+	for _, policy := range policies {
+		if policy.Type == v1alpha1.OverridePolicyType && policy.Properties != nil {
+			overrideSpec := &v1alpha1.OverridePolicySpec{}
+			if err := utils.StrictUnmarshal(policy.Properties.Raw, overrideSpec); err != nil {
+				return nil, errors.Wrapf(err, "failed to parse override policy %s", policy.Name)
+			}
+			if overrideSpec.ReplicaSplit != nil {
+				replicaSplits = append(replicaSplits, *overrideSpec.ReplicaSplit)
+			}
+		}
+	}
+	return replicaSplits, nil
+}
+
+// splitReplicas set the replicas scheduled for the placement into the component with the replica splits
+func splitReplicas(comp common.ApplicationComponent, placement v1alpha1.PlacementDecision, replicaSplits []v1alpha1.OverrideReplicaSplit) (*common.ApplicationComponent, error) {
+	if placement.Replicas <= 0 {
+		return &comp, nil
+	}
+	replicas := &runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"replicas":%d}`, placement.Replicas))}
+	for _, replicaSplit := range replicaSplits {
+		if len(replicaSplit.Components) > 0 && !utils.StringsContain(replicaSplit.Components, comp.Name) {
+			continue
+		}
+		patch := &v1alpha1.EnvComponentPatch{Properties: replicas}
+		if replicaSplit.Trait != "" {
+			patch = &v1alpha1.EnvComponentPatch{Traits: []v1alpha1.EnvTraitPatch{{Type: replicaSplit.Trait, Properties: replicas}}}
+		}
+		_comp, err := envbinding.MergeComponent(&comp, patch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to split replicas for component %s in cluster %s", comp.Name, placement.Cluster)
+		}
+		comp = *_comp
+	}
+	return &comp, nil
+}
+
 type applyTask struct {
 	component common.ApplicationComponent
 	placement v1alpha1.PlacementDecision
@@ -164,13 +241,34 @@ type applyTaskResult struct {
 	err     error
 }
 
-func applyComponents(apply oamProvider.ComponentApply, healthCheck oamProvider.ComponentHealthCheck, components []common.ApplicationComponent, placements []v1alpha1.PlacementDecision, parallelism int) (bool, string, error) {
+// newApplyTasks create tasks to apply components in placements. The components excluded by the placement will be
+// skipped, and the replicas scheduled for the placement will be set into the components by replica splits.
+func newApplyTasks(components []common.ApplicationComponent, placements []v1alpha1.PlacementDecision, replicaSplits []v1alpha1.OverrideReplicaSplit) ([]*applyTask, error) {
 	var tasks []*applyTask
 	for _, comp := range components {
 		for _, pl := range placements {
-			tasks = append(tasks, &applyTask{component: comp, placement: pl})
+			if utils.StringsContain(pl.ExcludedComponents, comp.Name) {
+				continue
+			}
+			_comp, err := splitReplicas(comp, pl, replicaSplits)
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, &applyTask{component: *_comp, placement: pl})
 		}
 	}
+	return tasks, nil
+}
+
+func applyComponents(apply oamProvider.ComponentApply, healthCheck oamProvider.ComponentHealthCheck, components []common.ApplicationComponent, placements []v1alpha1.PlacementDecision, parallelism int) (bool, string, error) {
+	tasks, err := newApplyTasks(components, placements, nil)
+	if err != nil {
+		return false, "", err
+	}
+	return applyTasks(apply, healthCheck, tasks, parallelism)
+}
+
+func applyTasks(apply oamProvider.ComponentApply, healthCheck oamProvider.ComponentHealthCheck, tasks []*applyTask, parallelism int) (bool, string, error) {
 	healthCheckResults := parallel.Run(func(task *applyTask) *applyTaskResult {
 		healthy, err := healthCheck(task.component, nil, task.placement.Cluster, task.placement.Namespace, "")
 		return &applyTaskResult{healthy: healthy, err: err}
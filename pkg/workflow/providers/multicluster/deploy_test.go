@@ -144,3 +144,44 @@ func TestApplyComponents(t *testing.T) {
 	r.True(healthy)
 	r.Equal(3*n*m, countMap())
 }
+
+func TestNewApplyTasksWithScheduling(t *testing.T) {
+	r := require.New(t)
+	policies := []v1beta1.AppPolicy{{
+		Name:       "override-policy",
+		Type:       "override",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"replicaSplit":{"components":["web"],"trait":"scaler"}}`)},
+	}, {
+		Name:       "override-policy-properties",
+		Type:       "override",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"replicaSplit":{"components":["worker"]}}`)},
+	}}
+	replicaSplits, err := getReplicaSplits(policies)
+	r.NoError(err)
+	r.Equal(2, len(replicaSplits))
+	components := []apicommon.ApplicationComponent{{
+		Name:   "web",
+		Traits: []apicommon.ApplicationTrait{{Type: "scaler", Properties: &runtime.RawExtension{Raw: []byte(`{"replicas":1}`)}}},
+	}, {
+		Name:       "worker",
+		Properties: &runtime.RawExtension{Raw: []byte(`{"image":"busybox"}`)},
+	}, {
+		Name: "db",
+	}}
+	placements := []v1alpha1.PlacementDecision{
+		{Cluster: "cluster-a", Replicas: 3},
+		{Cluster: "cluster-b", Replicas: 2, ExcludedComponents: []string{"db"}},
+	}
+	tasks, err := newApplyTasks(components, placements, replicaSplits)
+	r.NoError(err)
+	taskMap := map[string]apicommon.ApplicationComponent{}
+	for _, task := range tasks {
+		taskMap[task.key()] = task.component
+	}
+	r.Equal(5, len(taskMap))
+	r.NotContains(taskMap, "cluster-b//db")
+	r.Equal(`{"replicas":3}`, string(taskMap["cluster-a//web"].Traits[0].Properties.Raw))
+	r.Equal(`{"replicas":2}`, string(taskMap["cluster-b//web"].Traits[0].Properties.Raw))
+	r.Equal(`{"image":"busybox","replicas":3}`, string(taskMap["cluster-a//worker"].Properties.Raw))
+	r.Nil(taskMap["cluster-a//db"].Properties)
+}
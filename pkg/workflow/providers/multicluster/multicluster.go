@@ -19,6 +19,7 @@ package multicluster
 import (
 	"context"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -46,6 +47,7 @@ type provider struct {
 	apply       oamProvider.ComponentApply
 	healthCheck oamProvider.ComponentHealthCheck
 	renderer    oamProvider.WorkloadRenderer
+	recorder    event.Recorder
 }
 
 // ReadPlacementDecisions
@@ -187,7 +189,7 @@ func (p *provider) Deploy(ctx wfContext.Context, v *value.Value, act wfTypes.Act
 	if err != nil {
 		return err
 	}
-	executor := NewDeployWorkflowStepExecutor(p.Client, p.af, p.apply, p.healthCheck, p.renderer, ignoreTerraformComponent)
+	executor := NewDeployWorkflowStepExecutor(p.Client, p.af, p.apply, p.healthCheck, p.renderer, ignoreTerraformComponent, p.app, p.recorder)
 	healthy, reason, err := executor.Deploy(context.Background(), policyNames, int(parallelism))
 	if err != nil {
 		return err
@@ -199,8 +201,8 @@ func (p *provider) Deploy(ctx wfContext.Context, v *value.Value, act wfTypes.Act
 }
 
 // Install register handlers to provider discover.
-func Install(p providers.Providers, c client.Client, app *v1beta1.Application, af *appfile.Appfile, apply oamProvider.ComponentApply, healthCheck oamProvider.ComponentHealthCheck, renderer oamProvider.WorkloadRenderer) {
-	prd := &provider{Client: c, app: app, af: af, apply: apply, healthCheck: healthCheck, renderer: renderer}
+func Install(p providers.Providers, c client.Client, app *v1beta1.Application, af *appfile.Appfile, apply oamProvider.ComponentApply, healthCheck oamProvider.ComponentHealthCheck, renderer oamProvider.WorkloadRenderer, recorder event.Recorder) {
+	prd := &provider{Client: c, app: app, af: af, apply: apply, healthCheck: healthCheck, renderer: renderer, recorder: recorder}
 	p.Register(ProviderName, map[string]providers.Handler{
 		"read-placement-decisions": prd.ReadPlacementDecisions,
 		"make-placement-decisions": prd.MakePlacementDecisions,
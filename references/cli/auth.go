@@ -30,14 +30,18 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 
 	prismclusterv1alpha1 "github.com/kubevela/prism/pkg/apis/cluster/v1alpha1"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
 	"github.com/oam-dev/kubevela/pkg/auth"
 	velacmd "github.com/oam-dev/kubevela/pkg/cmd"
 	cmdutil "github.com/oam-dev/kubevela/pkg/cmd/util"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/policy"
+	"github.com/oam-dev/kubevela/pkg/utils"
 	"github.com/oam-dev/kubevela/pkg/utils/util"
 )
 
@@ -53,12 +57,15 @@ func AuthCommandGroup(f velacmd.Factory, streams util.IOStreams) *cobra.Command
 	cmd.AddCommand(NewGenKubeConfigCommand(f, streams))
 	cmd.AddCommand(NewListPrivilegesCommand(f, streams))
 	cmd.AddCommand(NewGrantPrivilegesCommand(f, streams))
+	cmd.AddCommand(NewCheckAppPrivilegesCommand(f, streams))
 	return cmd
 }
 
 // GenKubeConfigOptions options for create kubeconfig
 type GenKubeConfigOptions struct {
 	auth.Identity
+	OIDC        bool
+	OIDCOptions auth.KubeConfigGenerateOIDCOptions
 	util.IOStreams
 }
 
@@ -68,13 +75,38 @@ func (opt *GenKubeConfigOptions) Complete(f velacmd.Factory, cmd *cobra.Command)
 		opt.Identity.ServiceAccountNamespace = velacmd.GetNamespace(f, cmd)
 	}
 	opt.Regularize()
+	if opt.OIDC {
+		opt.OIDCOptions.User = opt.Identity.User
+	}
 }
 
 // Validate .
 func (opt *GenKubeConfigOptions) Validate() error {
+	if opt.OIDC {
+		if opt.ServiceAccount != "" || len(opt.Groups) > 0 {
+			return fmt.Errorf("cannot set `serviceaccount` or `group` for OIDC kubeconfig, the identity is provided by the OIDC issuer")
+		}
+		return nil
+	}
 	return opt.Identity.Validate()
 }
 
+// loadDexOIDCOptions fill the issuer and the public kubelogin client of the Dex configured in VelaUX if not set
+func (opt *GenKubeConfigOptions) loadDexOIDCOptions(ctx context.Context, f velacmd.Factory) error {
+	if opt.OIDCOptions.IssuerURL != "" {
+		return nil
+	}
+	dexOptions, err := auth.GetDexKubeLoginOIDCOptions(ctx, f.Client())
+	if err != nil {
+		return fmt.Errorf("failed to load the dex config, the --oidc-issuer-url should be set if dex is not configured: %w", err)
+	}
+	opt.OIDCOptions.IssuerURL = dexOptions.IssuerURL
+	if opt.OIDCOptions.ClientID == "" {
+		opt.OIDCOptions.ClientID = dexOptions.ClientID
+	}
+	return nil
+}
+
 // Run .
 func (opt *GenKubeConfigOptions) Run(f velacmd.Factory) error {
 	ctx := context.Background()
@@ -86,7 +118,14 @@ func (opt *GenKubeConfigOptions) Run(f velacmd.Factory) error {
 	if err != nil {
 		return err
 	}
-	cfg, err = auth.GenerateKubeConfig(ctx, cli, cfg, opt.IOStreams.ErrOut, auth.KubeConfigWithIdentityGenerateOption(opt.Identity))
+	var option auth.KubeConfigGenerateOption = auth.KubeConfigWithIdentityGenerateOption(opt.Identity)
+	if opt.OIDC {
+		if err = opt.loadDexOIDCOptions(ctx, f); err != nil {
+			return err
+		}
+		option = auth.KubeConfigWithOIDCGenerateOption(opt.OIDCOptions)
+	}
+	cfg, err = auth.GenerateKubeConfig(ctx, cli, cfg, opt.IOStreams.ErrOut, option)
 	if err != nil {
 		return err
 	}
@@ -116,7 +155,14 @@ var (
 
 		To generate a kubeconfig based on existing ServiceAccount in your cluster, use the 
 		--serviceaccount flag. The corresponding secret token and ca data will be embedded in 
-		the generated kubeconfig, which allows you to act as the serviceaccount.`))
+		the generated kubeconfig, which allows you to act as the serviceaccount.
+
+		To generate a kubeconfig that authenticates with the SSO identity through OIDC, use the 
+		--oidc flag. The id token will be retrieved by the kubectl oidc-login plugin 
+		(https://github.com/int128/kubelogin), or by the legacy oidc auth-provider of kubectl if 
+		--oidc-auth-provider is set. If --oidc-issuer-url is not set, the issuer and client of the 
+		Dex configured in VelaUX will be used. The --user flag only names the user in the generated 
+		kubeconfig, the user and groups are recognized from the id token by the kubernetes apiserver.`))
 
 	generateKubeConfigExample = templates.Examples(i18n.T(`
 		# Generate a kubeconfig with provided user
@@ -129,7 +175,13 @@ var (
 		vela auth gen-kubeconfig --user new-user --group kubevela:developer --group my-org:my-team
 
 		# Generate a kubeconfig with provided serviceaccount
-		vela auth gen-kubeconfig --serviceaccount default -n demo`))
+		vela auth gen-kubeconfig --serviceaccount default -n demo
+
+		# Generate a kubeconfig authenticated by the Dex configured in VelaUX
+		vela auth gen-kubeconfig --oidc
+
+		# Generate a kubeconfig authenticated by the provided OIDC issuer
+		vela auth gen-kubeconfig --oidc --oidc-issuer-url https://dex.example.com --oidc-client-id kubernetes`))
 )
 
 // NewGenKubeConfigCommand generate kubeconfig for given user and groups
@@ -154,6 +206,12 @@ func NewGenKubeConfigCommand(f velacmd.Factory, streams util.IOStreams) *cobra.C
 	cmd.Flags().StringVarP(&o.User, "user", "u", o.User, "The user of the generated kubeconfig. If set, an X509-based kubeconfig will be intended to create. It will be embedded as the Subject in the X509 certificate.")
 	cmd.Flags().StringSliceVarP(&o.Groups, "group", "g", o.Groups, "The groups of the generated kubeconfig. This flag only works when `--user` is set. It will be embedded as the Organization in the X509 certificate.")
 	cmd.Flags().StringVarP(&o.ServiceAccount, "serviceaccount", "", o.ServiceAccount, "The serviceaccount of the generated kubeconfig. If set, a kubeconfig will be generated based on the secret token of the serviceaccount. Cannot be set when `--user` presents.")
+	cmd.Flags().BoolVarP(&o.OIDC, "oidc", "", o.OIDC, "If set, a kubeconfig authenticated by the OIDC id token will be generated.")
+	cmd.Flags().StringVarP(&o.OIDCOptions.IssuerURL, "oidc-issuer-url", "", o.OIDCOptions.IssuerURL, "The issuer url of the OIDC provider. If not set, the Dex configured in VelaUX will be used. This flag only works when `--oidc` is set.")
+	cmd.Flags().StringVarP(&o.OIDCOptions.ClientID, "oidc-client-id", "", o.OIDCOptions.ClientID, "The client id registered in the OIDC provider. This flag only works when `--oidc` is set.")
+	cmd.Flags().StringVarP(&o.OIDCOptions.ClientSecret, "oidc-client-secret", "", o.OIDCOptions.ClientSecret, "The client secret registered in the OIDC provider. This flag only works when `--oidc` is set.")
+	cmd.Flags().StringSliceVarP(&o.OIDCOptions.ExtraScopes, "oidc-extra-scope", "", auth.DefaultOIDCExtraScopes, "The scopes to request besides openid. This flag only works when `--oidc` is set.")
+	cmd.Flags().BoolVarP(&o.OIDCOptions.UseAuthProvider, "oidc-auth-provider", "", o.OIDCOptions.UseAuthProvider, "If set, the legacy oidc auth-provider of kubectl will be used instead of the kubectl oidc-login plugin. This flag only works when `--oidc` is set.")
 	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
 		"serviceaccount", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if strings.TrimSpace(o.User) != "" {
@@ -308,6 +366,9 @@ type GrantPrivilegesOptions struct {
 	ReadOnly        bool
 	CreateNamespace bool
 
+	OIDCUsernamePrefix string
+	OIDCGroupsPrefix   string
+
 	util.IOStreams
 }
 
@@ -323,6 +384,7 @@ func (opt *GrantPrivilegesOptions) Complete(f velacmd.Factory, cmd *cobra.Comman
 		opt.Identity.ServiceAccountNamespace = velacmd.GetNamespace(f, cmd)
 	}
 	opt.Regularize()
+	opt.AddOIDCPrefix(opt.OIDCUsernamePrefix, opt.OIDCGroupsPrefix)
 	if len(opt.GrantClusters) == 0 {
 		opt.GrantClusters = []string{types.ClusterLocalName}
 	}
@@ -408,7 +470,12 @@ var (
 		intended privileges respectively.
 
 		If --kubeconfig is set, the user/serviceaccount information in the kubeconfig will be used as
-		the identity to grant privileges. Groups will be ignored.`))
+		the identity to grant privileges. Groups will be ignored.
+
+		For users authenticated through OIDC (like the SSO identities in Dex), the groups in the id token
+		can be granted privileges directly. If the kubernetes apiserver is configured with
+		--oidc-username-prefix or --oidc-groups-prefix, set --oidc-username-prefix and --oidc-groups-prefix
+		with the same values so that the prefixes will be added to the granted user and groups.`))
 
 	grantPrivilegesExample = templates.Examples(i18n.T(`
 		# Grant privileges for User alice in the namespace demo of the control plane
@@ -427,7 +494,10 @@ var (
 		vela auth grant-privileges --serviceaccount observer -n test --for-namespace test --readonly
 
 		# Grant privileges for identity in kubeconfig in cluster-1
-		vela auth grant-privileges --kubeconfig ./example.kubeconfig --for-cluster cluster-1`))
+		vela auth grant-privileges --kubeconfig ./example.kubeconfig --for-cluster cluster-1
+
+		# Grant privileges for the Dex group my-org:dev-team, recognized by the apiserver with --oidc-groups-prefix=oidc:
+		vela auth grant-privileges --group my-org:dev-team --oidc-groups-prefix oidc: --for-namespace dev`))
 )
 
 // NewGrantPrivilegesCommand grant privileges to given identity
@@ -457,6 +527,8 @@ func NewGrantPrivilegesCommand(f velacmd.Factory, streams util.IOStreams) *cobra
 	cmd.Flags().StringSliceVarP(&o.GrantNamespaces, "for-namespace", "", o.GrantNamespaces, "The namespaces privileges to grant. If empty, cluster-scoped privileges will be granted.")
 	cmd.Flags().BoolVarP(&o.ReadOnly, "readonly", "", o.ReadOnly, "If set, only read privileges of resources will be granted. Otherwise, read/write privileges will be granted.")
 	cmd.Flags().BoolVarP(&o.CreateNamespace, "create-namespace", "", o.CreateNamespace, "If set, non-exist namespace will be created automatically.")
+	cmd.Flags().StringVarP(&o.OIDCUsernamePrefix, "oidc-username-prefix", "", o.OIDCUsernamePrefix, "The prefix added to the user, which should be the same as the --oidc-username-prefix of the kubernetes apiserver.")
+	cmd.Flags().StringVarP(&o.OIDCGroupsPrefix, "oidc-groups-prefix", "", o.OIDCGroupsPrefix, "The prefix added to the groups, which should be the same as the --oidc-groups-prefix of the kubernetes apiserver.")
 	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
 		"serviceaccount", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if strings.TrimSpace(o.User) != "" {
@@ -472,3 +544,139 @@ func NewGrantPrivilegesCommand(f velacmd.Factory, streams util.IOStreams) *cobra
 		WithResponsiveWriter().
 		Build()
 }
+
+// CheckAppPrivilegesOptions options for check the privileges of application
+type CheckAppPrivilegesOptions struct {
+	AppName   string
+	Namespace string
+	File      string
+	util.IOStreams
+}
+
+// Complete .
+func (opt *CheckAppPrivilegesOptions) Complete(f velacmd.Factory, cmd *cobra.Command, args []string) {
+	if len(args) > 0 {
+		opt.AppName = args[0]
+	}
+	opt.Namespace = velacmd.GetNamespace(f, cmd)
+}
+
+// Validate .
+func (opt *CheckAppPrivilegesOptions) Validate() error {
+	if (opt.AppName == "") == (opt.File == "") {
+		return fmt.Errorf("either the application name or the application file (-f) should be set")
+	}
+	return nil
+}
+
+// loadApplication load the application from file or from the control plane
+func (opt *CheckAppPrivilegesOptions) loadApplication(f velacmd.Factory, cmd *cobra.Command) (*v1beta1.Application, error) {
+	app := &v1beta1.Application{}
+	if opt.File == "" {
+		if err := f.Client().Get(cmd.Context(), apitypes.NamespacedName{Namespace: opt.Namespace, Name: opt.AppName}, app); err != nil {
+			return nil, fmt.Errorf("failed to get application %s/%s: %w", opt.Namespace, opt.AppName, err)
+		}
+		return app, nil
+	}
+	body, err := utils.ReadRemoteOrLocalPath(opt.File, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(body, app); err != nil {
+		return nil, fmt.Errorf("failed to parse application in %s: %w", opt.File, err)
+	}
+	if app.Namespace == "" || (opt.Namespace != "" && opt.Namespace != types.DefaultAppNamespace) {
+		app.SetNamespace(opt.Namespace)
+	}
+	return app, nil
+}
+
+// Run .
+func (opt *CheckAppPrivilegesOptions) Run(f velacmd.Factory, cmd *cobra.Command) error {
+	ctx := cmd.Context()
+	app, err := opt.loadApplication(f, cmd)
+	if err != nil {
+		return err
+	}
+	placements, err := policy.GetPlacementsFromTopologyPolicies(policy.ContextWithScheduledClusters(ctx, app), f.Client(), app.Namespace, app.Spec.Policies, true)
+	if err != nil {
+		return fmt.Errorf("failed to get the placements of application %s: %w", app.Name, err)
+	}
+	width, _, err := term.GetSize(0)
+	if err != nil {
+		width = 80
+	}
+	checked := map[string]bool{}
+	for _, placement := range placements {
+		if checked[placement.Cluster] {
+			continue
+		}
+		checked[placement.Cluster] = true
+		identity, err := auth.GetEffectiveIdentity(ctx, f.Client(), app, placement)
+		if err != nil {
+			return err
+		}
+		if identity == nil {
+			_, _ = fmt.Fprintf(opt.Out, "Cluster %s: no identity to impersonate, the privileges of the controller will be used.\n", placement.Cluster)
+			continue
+		}
+		m, err := auth.ListPrivileges(ctx, f.Client(), []string{placement.Cluster}, identity)
+		if err != nil {
+			return fmt.Errorf("failed to list privileges in cluster %s: %w", placement.Cluster, err)
+		}
+		_, _ = opt.Out.Write([]byte(auth.PrettyPrintPrivileges(identity, m, []string{placement.Cluster}, uint(width)-40)))
+	}
+	return nil
+}
+
+var (
+	checkAppPrivilegesLong = templates.LongDesc(i18n.T(`
+		Check the privileges of application
+
+		Check the privileges of the identities used to dispatch the resources of application into
+		each cluster selected by its topology policies. The application can be loaded from the
+		control plane by name, or from a file by -f before it is deployed.
+
+		The identity used in each cluster is decided in order:
+		1. The identity set in the topology policy which selects the cluster.
+		2. The identity configured in the annotations of the cluster (cluster.core.oam.dev/impersonate-user,
+		   cluster.core.oam.dev/impersonate-groups, cluster.core.oam.dev/impersonate-service-account).
+		3. The identity of the application (the creator or the service account of the application).
+
+		If no identity is found, the controller will use its own privileges.`))
+
+	checkAppPrivilegesExample = templates.Examples(i18n.T(`
+		# Check the privileges of the application example-app in namespace demo
+		vela auth check-app-privileges example-app -n demo
+
+		# Check the privileges of the application in file before deploying it
+		vela auth check-app-privileges -f ./app.yaml`))
+)
+
+// NewCheckAppPrivilegesCommand check the privileges of application in each cluster
+func NewCheckAppPrivilegesCommand(f velacmd.Factory, streams util.IOStreams) *cobra.Command {
+	o := &CheckAppPrivilegesOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:                   "check-app-privileges [APP_NAME]",
+		DisableFlagsInUseLine: true,
+		Short:                 i18n.T("Check the privileges of application in each cluster"),
+		Long:                  checkAppPrivilegesLong,
+		Example:               checkAppPrivilegesExample,
+		Annotations: map[string]string{
+			types.TagCommandType: types.TypeCD,
+		},
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Complete(f, cmd, args)
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(f, cmd))
+		},
+	}
+	cmd.Flags().StringVarP(&o.File, "file", "f", o.File, "The file of the application to check privileges.")
+
+	return velacmd.NewCommandBuilder(f, cmd).
+		WithNamespaceFlag(velacmd.UsageOption("The namespace of the application.")).
+		WithStreams(streams).
+		WithResponsiveWriter().
+		Build()
+}
@@ -97,6 +97,7 @@ func NewCommandWithIOStreams(ioStream util.IOStreams) *cobra.Command {
 		NewLiveDiffCommand(commandArgs, "2", ioStream),
 		NewDryRunCommand(commandArgs, ioStream),
 		RevisionCommandGroup(commandArgs),
+		ResourceTrackerCommandGroup(commandArgs, ioStream),
 
 		// Workflows
 		NewWorkflowCommand(commandArgs, ioStream),
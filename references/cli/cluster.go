@@ -19,20 +19,23 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/fatih/color"
 	prismclusterv1alpha1 "github.com/kubevela/prism/pkg/apis/cluster/v1alpha1"
 	"github.com/oam-dev/cluster-gateway/pkg/config"
-	"github.com/oam-dev/cluster-gateway/pkg/generated/clientset/versioned"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cloudprovider"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/utils/common"
 	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
@@ -52,6 +55,22 @@ const (
 
 	// CreateNamespace specifies the namespace need to create in managedCluster
 	CreateNamespace = "create-namespace"
+
+	// FlagServiceAccountToken specifies to provision a service account in the managed cluster and use its token
+	FlagServiceAccountToken = "service-account-token"
+	// FlagTokenExpiration specifies the expiration of the provisioned service account token
+	FlagTokenExpiration = "token-expiration"
+	// FlagServiceAccountRole specifies the cluster role bound to the provisioned service account
+	FlagServiceAccountRole = "service-account-role"
+
+	// FlagProviderSource specifies where the cluster provider finds clusters, eg: the kubeconfig directory
+	FlagProviderSource = "source"
+	// FlagAccessKeyID specifies the access key id of the cloud provider
+	FlagAccessKeyID = "access-key-id"
+	// FlagAccessKeySecret specifies the access key secret of the cloud provider
+	FlagAccessKeySecret = "access-key-secret"
+	// FlagImportAll specifies to import all the clusters of the provider
+	FlagImportAll = "all"
 )
 
 // ClusterCommandGroup create a group of cluster command
@@ -85,11 +104,16 @@ func ClusterCommandGroup(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Comm
 	cmd.AddCommand(
 		NewClusterListCommand(&c),
 		NewClusterJoinCommand(&c, ioStreams),
+		NewClusterImportCommand(&c),
 		NewClusterRenameCommand(&c),
 		NewClusterDetachCommand(&c),
 		NewClusterProbeCommand(&c),
 		NewClusterLabelCommandGroup(&c),
+		NewClusterGroupCommandGroup(&c),
 		NewClusterAliasCommand(&c),
+		NewClusterCordonCommand(&c),
+		NewClusterUncordonCommand(&c),
+		NewClusterDrainCommand(&c),
 	)
 	return cmd
 }
@@ -103,7 +127,7 @@ func NewClusterListCommand(c *common.Args) *cobra.Command {
 		Long:    "list worker clusters managed by KubeVela.",
 		Args:    cobra.ExactValidArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			table := newUITable().AddRow("CLUSTER", "ALIAS", "TYPE", "ENDPOINT", "ACCEPTED", "LABELS")
+			table := newUITable().AddRow("CLUSTER", "ALIAS", "CREDENTIAL-TYPE", "EXPIRY", "ENDPOINT", "ACCEPTED", "MAINTENANCE", "LABELS")
 			client, err := c.GetClient()
 			if err != nil {
 				return err
@@ -112,6 +136,18 @@ func NewClusterListCommand(c *common.Args) *cobra.Command {
 			if err != nil {
 				return errors.Wrap(err, "fail to get registered cluster")
 			}
+			vcs, err := multicluster.FindVirtualClustersByLabels(context.Background(), client, map[string]string{})
+			if err != nil {
+				return errors.Wrap(err, "fail to get the credentials of registered cluster")
+			}
+			credentials := map[string]*multicluster.ClusterCredential{}
+			maintenances := map[string]string{}
+			for _, vc := range vcs {
+				credentials[vc.Name] = vc.Credential
+				if vc.Maintenance != multicluster.ClusterInService {
+					maintenances[vc.Name] = string(vc.Maintenance)
+				}
+			}
 			for _, cluster := range clusters.Items {
 				var labels []string
 				for k, v := range cluster.Labels {
@@ -125,9 +161,9 @@ func NewClusterListCommand(c *common.Args) *cobra.Command {
 				}
 				for i, l := range labels {
 					if i == 0 {
-						table.AddRow(cluster.Name, cluster.Spec.Alias, cluster.Spec.CredentialType, cluster.Spec.Endpoint, fmt.Sprintf("%v", cluster.Spec.Accepted), l)
+						table.AddRow(cluster.Name, cluster.Spec.Alias, cluster.Spec.CredentialType, formatCredentialExpiry(credentials[cluster.Name]), cluster.Spec.Endpoint, fmt.Sprintf("%v", cluster.Spec.Accepted), maintenances[cluster.Name], l)
 					} else {
-						table.AddRow("", "", "", "", "", l)
+						table.AddRow("", "", "", "", "", "", "", l)
 					}
 				}
 			}
@@ -142,6 +178,24 @@ func NewClusterListCommand(c *common.Args) *cobra.Command {
 	return cmd
 }
 
+// formatCredentialExpiry print the expiration time of the credential with the remaining time, and mark the rotatable
+// service account token
+func formatCredentialExpiry(credential *multicluster.ClusterCredential) string {
+	if credential == nil || credential.ExpirationTime == nil {
+		return "-"
+	}
+	expiry := credential.ExpirationTime.Format(time.RFC3339)
+	if remaining := time.Until(*credential.ExpirationTime); remaining > 0 {
+		expiry += fmt.Sprintf(" (%s)", duration.HumanDuration(remaining))
+	} else {
+		expiry += " " + color.RedString("(expired)")
+	}
+	if credential.IsRotatable() {
+		expiry += ", rotating"
+	}
+	return expiry
+}
+
 // NewClusterJoinCommand create command to help user join cluster to multicluster management
 func NewClusterJoinCommand(c *common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
@@ -149,7 +203,9 @@ func NewClusterJoinCommand(c *common.Args, ioStreams cmdutil.IOStreams) *cobra.C
 		Short: "join managed cluster.",
 		Long:  "join managed cluster by kubeconfig.",
 		Example: "# Join cluster declared in my-child-cluster.kubeconfig\n" +
-			"> vela cluster join my-child-cluster.kubeconfig --name example-cluster",
+			"> vela cluster join my-child-cluster.kubeconfig --name example-cluster\n" +
+			"# Join cluster with a rotating service account token provisioned in the cluster\n" +
+			"> vela cluster join my-child-cluster.kubeconfig --name example-cluster --service-account-token --token-expiration 12h",
 		Args: cobra.ExactValidArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// get ClusterName from flag or config
@@ -166,6 +222,18 @@ func NewClusterJoinCommand(c *common.Args, ioStreams cmdutil.IOStreams) *cobra.C
 			if err != nil {
 				return errors.Wrapf(err, "failed to get create namespace")
 			}
+			serviceAccountToken, err := cmd.Flags().GetBool(FlagServiceAccountToken)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get service account token flag")
+			}
+			tokenExpiration, err := cmd.Flags().GetDuration(FlagTokenExpiration)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get token expiration flag")
+			}
+			serviceAccountRole, err := cmd.Flags().GetString(FlagServiceAccountRole)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get service account role flag")
+			}
 			client, err := c.GetClient()
 			if err != nil {
 				return err
@@ -181,7 +249,7 @@ func NewClusterJoinCommand(c *common.Args, ioStreams cmdutil.IOStreams) *cobra.C
 			}
 
 			managedClusterKubeConfig := args[0]
-			clusterConfig, err := multicluster.JoinClusterByKubeConfig(context.Background(), client, managedClusterKubeConfig, clusterName,
+			options := []multicluster.JoinClusterOption{
 				multicluster.JoinClusterCreateNamespaceOption(createNamespace),
 				multicluster.JoinClusterEngineOption(clusterManagementType),
 				multicluster.JoinClusterOCMOptions{
@@ -189,11 +257,24 @@ func NewClusterJoinCommand(c *common.Args, ioStreams cmdutil.IOStreams) *cobra.C
 					IoStreams:              ioStreams,
 					HubConfig:              restConfig,
 					TrackingSpinnerFactory: newTrackingSpinner,
-				})
+				},
+			}
+			if serviceAccountToken || cmd.Flags().Changed(FlagTokenExpiration) {
+				options = append(options, multicluster.JoinClusterServiceAccountTokenOption(tokenExpiration))
+			}
+			if serviceAccountRole != "" {
+				options = append(options, multicluster.JoinClusterServiceAccountRoleOption(serviceAccountRole))
+			}
+			clusterConfig, err := multicluster.JoinClusterByKubeConfig(context.Background(), client, managedClusterKubeConfig, clusterName, options...)
 			if err != nil {
 				return err
 			}
 			cmd.Printf("Successfully add cluster %s, endpoint: %s.\n", clusterName, clusterConfig.Cluster.Server)
+			if clusterConfig.Credential != nil && clusterConfig.Credential.ExpirationTime != nil {
+				cmd.Printf("The cluster uses the token of service account %s, which expires at %s. "+
+					"Enable the credential rotation of the controller to rotate it automatically.\n",
+					clusterConfig.Credential.ServiceAccount, clusterConfig.Credential.ExpirationTime.Format(time.RFC3339))
+			}
 			return nil
 		},
 	}
@@ -203,9 +284,156 @@ func NewClusterJoinCommand(c *common.Args, ioStreams cmdutil.IOStreams) *cobra.C
 	cmd.Flags().BoolP(FlagInClusterBootstrap, "", true, "If true, the registering managed cluster "+
 		`will use the internal endpoint prescribed in the hub cluster's configmap "kube-public/cluster-info to register "`+
 		"itself to the hub cluster. Otherwise use the original endpoint from the hub kubeconfig.")
+	cmd.Flags().BoolP(FlagServiceAccountToken, "", false, "If true, create a service account in the managed cluster and use its token as the credential, "+
+		"which can be rotated by the controller. Always enabled if the kubeconfig uses exec plugins or auth providers.")
+	cmd.Flags().DurationP(FlagTokenExpiration, "", multicluster.DefaultClusterTokenExpiration, "Specify the expiration of the service account token.")
+	cmd.Flags().StringP(FlagServiceAccountRole, "", "", "Specify the existing cluster role bound to the provisioned service account. "+
+		"If not set, the "+multicluster.DefaultClusterServiceAccountRole+" role is created, which aggregates the edit role and the cluster roles labeled with "+
+		multicluster.LabelAggregateToClusterServiceAccountRole+"=true.")
+	return cmd
+}
+
+// NewClusterImportCommand create command to import clusters from cluster providers
+func NewClusterImportCommand(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import PROVIDER [CLUSTER_ID]...",
+		Short: "import clusters from cluster provider.",
+		Long: "import clusters from cluster provider. The clusters of the provider will be listed if no cluster id is given.\n" +
+			"Supported providers: " + strings.Join(cloudprovider.ListClusterProviders(), ", ") + ".\n" +
+			"The source of the kubeconfig provider is the directory of kubeconfig files, and the source of the cluster-api " +
+			"provider is the namespace of the Cluster objects in the current cluster.",
+		Example: "# List the contexts in the kubeconfig files under ~/.kube/clusters\n" +
+			"> vela cluster import kubeconfig --source ~/.kube/clusters\n" +
+			"# Import the context my-context in the kubeconfig file eks.kubeconfig as cluster eks\n" +
+			"> vela cluster import kubeconfig eks.kubeconfig/my-context --source ~/.kube/clusters --name eks\n" +
+			"# Import all the clusters provisioned by Cluster API in the default namespace\n" +
+			"> vela cluster import cluster-api --source default --all\n" +
+			"# Import the local kind cluster worker\n" +
+			"> vela cluster import kind worker",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName, err := cmd.Flags().GetString(FlagClusterName)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get cluster name flag")
+			}
+			createNamespace, err := cmd.Flags().GetString(CreateNamespace)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get create namespace")
+			}
+			source, err := cmd.Flags().GetString(FlagProviderSource)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get source flag")
+			}
+			accessKeyID, err := cmd.Flags().GetString(FlagAccessKeyID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get access key id flag")
+			}
+			accessKeySecret, err := cmd.Flags().GetString(FlagAccessKeySecret)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get access key secret flag")
+			}
+			all, err := cmd.Flags().GetBool(FlagImportAll)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get all flag")
+			}
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			if accessKeyID == "" {
+				accessKeyID = source
+			}
+			provider, err := cloudprovider.GetClusterProvider(args[0], accessKeyID, accessKeySecret, cli)
+			if err != nil {
+				return err
+			}
+			clusterIDs := args[1:]
+			if len(clusterIDs) == 0 {
+				clusters, _, err := provider.ListCloudClusters(0, 0)
+				if err != nil {
+					return errors.Wrapf(err, "failed to list clusters of provider %s", args[0])
+				}
+				if !all {
+					printCloudClusters(cmd, clusters)
+					return nil
+				}
+				for _, cluster := range clusters {
+					clusterIDs = append(clusterIDs, cluster.ID)
+				}
+			}
+			if clusterName != "" && len(clusterIDs) != 1 {
+				return errors.Errorf("--%s can only be set when importing one cluster", FlagClusterName)
+			}
+			var failed int
+			for _, clusterID := range clusterIDs {
+				if err = importCloudCluster(cmd, cli, provider, clusterID, clusterName, createNamespace); err != nil {
+					cmd.Printf("Failed to import cluster %s: %v\n", clusterID, err)
+					failed++
+				}
+			}
+			if failed > 0 {
+				return errors.Errorf("failed to import %d of %d clusters", failed, len(clusterIDs))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringP(FlagClusterName, "n", "", "Specify the cluster name when importing one cluster. If empty, it will use the cluster name in the provider.")
+	cmd.Flags().StringP(CreateNamespace, "", types.DefaultKubeVelaNS, "Specifies the namespace need to create in managedCluster")
+	cmd.Flags().StringP(FlagProviderSource, "s", "", "Specify where the provider finds clusters, the kubeconfig directory for kubeconfig provider "+
+		"or the namespace for cluster-api provider.")
+	cmd.Flags().StringP(FlagAccessKeyID, "", "", "Specify the access key id of the cloud provider.")
+	cmd.Flags().StringP(FlagAccessKeySecret, "", "", "Specify the access key secret of the cloud provider.")
+	cmd.Flags().BoolP(FlagImportAll, "", false, "If true, import all the clusters of the provider.")
 	return cmd
 }
 
+func printCloudClusters(cmd *cobra.Command, clusters []*cloudprovider.CloudCluster) {
+	if len(clusters) == 0 {
+		cmd.Println("No cluster found.")
+		return
+	}
+	table := newUITable().AddRow("ID", "NAME", "TYPE", "STATUS", "ENDPOINT")
+	for _, cluster := range clusters {
+		table.AddRow(cluster.ID, cluster.Name, cluster.Type, cluster.Status, cluster.APIServerURL)
+	}
+	cmd.Println(table.String())
+}
+
+func importCloudCluster(cmd *cobra.Command, cli client.Client, provider cloudprovider.CloudClusterProvider, clusterID string, clusterName string, createNamespace string) error {
+	cluster, err := provider.GetClusterInfo(clusterID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cluster info")
+	}
+	kubeConfig, err := provider.GetClusterKubeConfig(clusterID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cluster kubeconfig")
+	}
+	if clusterName == "" {
+		clusterName = cluster.Name
+	}
+	file, err := os.CreateTemp("", "vela-cluster-*.kubeconfig")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temp kubeconfig file")
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	_, err = file.WriteString(kubeConfig)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to write temp kubeconfig file")
+	}
+	clusterConfig, err := multicluster.JoinClusterByKubeConfig(context.Background(), cli, file.Name(), clusterName,
+		multicluster.JoinClusterCreateNamespaceOption(createNamespace))
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Successfully import cluster %s as %s, endpoint: %s.\n", clusterID, clusterName, clusterConfig.Cluster.Server)
+	return nil
+}
+
 // NewClusterRenameCommand create command to help user rename cluster
 func NewClusterRenameCommand(c *common.Args) *cobra.Command {
 	cmd := &cobra.Command{
@@ -280,8 +508,76 @@ func NewClusterAliasCommand(c *common.Args) *cobra.Command {
 	return cmd
 }
 
+// NewClusterCordonCommand create command to stop new placements onto the cluster
+func NewClusterCordonCommand(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cordon CLUSTER_NAME",
+		Short: "mark cluster as unschedulable.",
+		Long:  "mark cluster as unschedulable. Topology policies will not place new applications onto the cluster, applications already deployed to the cluster are kept.",
+		Args:  cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setClusterMaintenanceAndPrint(cmd, c, args[0], multicluster.ClusterCordoned, "cordoned")
+		},
+	}
+	return cmd
+}
+
+// NewClusterUncordonCommand create command to make the cordoned or drained cluster schedulable again
+func NewClusterUncordonCommand(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uncordon CLUSTER_NAME",
+		Short: "mark cluster as schedulable.",
+		Long:  "mark the cordoned or drained cluster as schedulable.",
+		Args:  cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setClusterMaintenanceAndPrint(cmd, c, args[0], multicluster.ClusterInService, "uncordoned")
+		},
+	}
+	return cmd
+}
+
+func setClusterMaintenanceAndPrint(cmd *cobra.Command, c *common.Args, clusterName string, status multicluster.ClusterMaintenanceStatus, action string) error {
+	cli, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+	if err = multicluster.SetClusterMaintenance(context.Background(), cli, clusterName, status); err != nil {
+		return err
+	}
+	cmd.Printf("Cluster %s %s.\n", clusterName, action)
+	return nil
+}
+
+// NewClusterDrainCommand create command to move the applications out of the cluster
+func NewClusterDrainCommand(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drain CLUSTER_NAME",
+		Short: "drain applications from cluster.",
+		Long: "drain applications from cluster. The cluster will not be selected by any topology policy. The workflows of " +
+			"the applications in the cluster will be restarted to move their components to other matching clusters, " +
+			"and the resources in the drained cluster will be recycled once the workflows succeed.",
+		Args: cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			apps, err := multicluster.DrainCluster(context.Background(), cli, clusterName)
+			for _, app := range apps {
+				cmd.Printf("Application %s/%s rescheduled.\n", app.Namespace, app.Name)
+			}
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Cluster %s drained.\n", clusterName)
+			return nil
+		},
+	}
+	return cmd
+}
+
 // NewClusterProbeCommand create command to help user try health probe for existing cluster
-// TODO(somefive): move prob logic into cluster management
 func NewClusterProbeCommand(c *common.Args) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "probe [CLUSTER_NAME]",
@@ -297,7 +593,7 @@ func NewClusterProbeCommand(c *common.Args) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			content, err := versioned.NewForConfigOrDie(config).ClusterV1alpha1().ClusterGateways().RESTClient(clusterName).Get().AbsPath("healthz").DoRaw(context.TODO())
+			content, err := multicluster.ProbeClusterHealth(context.TODO(), config, clusterName)
 			if err != nil {
 				return errors.Wrapf(err, "failed connect cluster %s", clusterName)
 			}
@@ -356,13 +652,9 @@ func NewClusterAddLabelsCommand(c *common.Args) *cobra.Command {
 		Args:    cobra.ExactValidArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
-			addLabels := map[string]string{}
-			for _, kv := range strings.Split(args[1], ",") {
-				parts := strings.Split(kv, "=")
-				if len(parts) != 2 {
-					return errors.Errorf("invalid label key-value pair %s, should use the format LABEL_KEY=LABEL_VAL", kv)
-				}
-				addLabels[parts[0]] = parts[1]
+			addLabels, err := parseLabels(args[1])
+			if err != nil {
+				return err
 			}
 			cli, err := c.GetClient()
 			if err != nil {
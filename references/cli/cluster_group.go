@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/utils/strings/slices"
+
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+const (
+	// FlagClusterGroupLabels specifies the cluster label selector of the cluster group
+	FlagClusterGroupLabels = "labels"
+)
+
+// NewClusterGroupCommandGroup create a group of commands to manage cluster groups
+func NewClusterGroupCommandGroup(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group",
+		Short: "Manage Kubernetes Cluster Groups",
+		Long: "Manage Kubernetes Cluster Groups, which can be selected by the clusterGroups field of topology policy. " +
+			"The members of the group are the specified clusters and the clusters matching the label selector.",
+	}
+	cmd.AddCommand(
+		NewClusterGroupListCommand(c),
+		NewClusterGroupAddCommand(c),
+		NewClusterGroupDelCommand(c),
+	)
+	return cmd
+}
+
+// NewClusterGroupListCommand create command to list cluster groups
+func NewClusterGroupListCommand(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "list cluster groups",
+		Long:    "list cluster groups and their members.",
+		Args:    cobra.ExactValidArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			groups, err := multicluster.ListClusterGroups(ctx, cli)
+			if err != nil {
+				return err
+			}
+			if len(groups) == 0 {
+				cmd.Println("No cluster group found.")
+				return nil
+			}
+			table := newUITable().AddRow("GROUP", "CLUSTERS", "LABEL-SELECTOR", "MEMBERS")
+			for i := range groups {
+				members, err := groups[i].GetClusters(ctx, cli)
+				if err != nil {
+					return err
+				}
+				table.AddRow(groups[i].Name, strings.Join(groups[i].Clusters, ","), formatLabels(groups[i].ClusterLabelSelector), strings.Join(members, ","))
+			}
+			cmd.Println(table.String())
+			return nil
+		},
+	}
+	return cmd
+}
+
+// NewClusterGroupAddCommand create command to create cluster group or add members to the cluster group
+func NewClusterGroupAddCommand(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add GROUP [CLUSTER_NAME]...",
+		Short: "add clusters to cluster group",
+		Long:  "add clusters or cluster label selector to cluster group, the cluster group will be created if not exists.",
+		Example: "# Create cluster group with cluster-a and cluster-b\n" +
+			"> vela cluster group add my-group cluster-a cluster-b\n" +
+			"# Add the clusters with label region=hangzhou to cluster group\n" +
+			"> vela cluster group add my-group --labels region=hangzhou",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			labels, err := cmd.Flags().GetString(FlagClusterGroupLabels)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get labels flag")
+			}
+			selector, err := parseLabels(labels)
+			if err != nil {
+				return err
+			}
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			group, err := multicluster.GetClusterGroup(ctx, cli, args[0])
+			exists := err == nil
+			if err != nil && !errors.Is(err, multicluster.ErrClusterGroupNotExists) {
+				return err
+			}
+			if !exists {
+				group = &multicluster.ClusterGroup{Name: args[0]}
+			}
+			for _, cluster := range args[1:] {
+				if !slices.Contains(group.Clusters, cluster) {
+					group.Clusters = append(group.Clusters, cluster)
+				}
+			}
+			for k, v := range selector {
+				if group.ClusterLabelSelector == nil {
+					group.ClusterLabelSelector = map[string]string{}
+				}
+				group.ClusterLabelSelector[k] = v
+			}
+			if err = group.Validate(ctx, cli); err != nil {
+				return err
+			}
+			if exists {
+				err = multicluster.UpdateClusterGroup(ctx, cli, group)
+			} else {
+				err = multicluster.CreateClusterGroup(ctx, cli, group)
+			}
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Successfully update cluster group %s.\n", group.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(FlagClusterGroupLabels, "l", "", "Specify the cluster label selector of the cluster group, eg: region=hangzhou,env=prod.")
+	return cmd
+}
+
+// NewClusterGroupDelCommand create command to delete cluster group or remove members from the cluster group
+func NewClusterGroupDelCommand(c *common.Args) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "del GROUP [CLUSTER_NAME]...",
+		Aliases: []string{"delete", "remove"},
+		Short:   "delete cluster group or remove clusters from cluster group",
+		Long:    "delete cluster group, or remove clusters and label selector keys from cluster group if specified.",
+		Example: "# Delete cluster group\n" +
+			"> vela cluster group del my-group\n" +
+			"# Remove cluster-a and the label selector on region from cluster group\n" +
+			"> vela cluster group del my-group cluster-a --labels region",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			labels, err := cmd.Flags().GetString(FlagClusterGroupLabels)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get labels flag")
+			}
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			if len(args) == 1 && labels == "" {
+				if err = multicluster.DeleteClusterGroup(ctx, cli, args[0]); err != nil {
+					return err
+				}
+				cmd.Printf("Successfully delete cluster group %s.\n", args[0])
+				return nil
+			}
+			group, err := multicluster.GetClusterGroup(ctx, cli, args[0])
+			if err != nil {
+				return err
+			}
+			var clusters []string
+			for _, cluster := range group.Clusters {
+				if !slices.Contains(args[1:], cluster) {
+					clusters = append(clusters, cluster)
+				}
+			}
+			group.Clusters = clusters
+			if labels != "" {
+				for _, k := range strings.Split(labels, ",") {
+					delete(group.ClusterLabelSelector, k)
+				}
+			}
+			if len(group.Clusters) == 0 && len(group.ClusterLabelSelector) == 0 {
+				return errors.Errorf("cluster group %s will be empty, delete the cluster group instead", group.Name)
+			}
+			if err = multicluster.UpdateClusterGroup(ctx, cli, group); err != nil {
+				return err
+			}
+			cmd.Printf("Successfully update cluster group %s.\n", group.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(FlagClusterGroupLabels, "l", "", "Specify the keys of the cluster label selector to remove, eg: region,env.")
+	return cmd
+}
+
+// parseLabels parse the labels in the format of k1=v1,k2=v2
+func parseLabels(s string) (map[string]string, error) {
+	labels := map[string]string{}
+	if s == "" {
+		return labels, nil
+	}
+	for _, kv := range strings.Split(s, ",") {
+		parts := strings.Split(kv, "=")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid label key-value pair %s, should use the format LABEL_KEY=LABEL_VAL", kv)
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels, nil
+}
+
+func formatLabels(labels map[string]string) string {
+	var kvs []string
+	for k, v := range labels {
+		kvs = append(kvs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, ",")
+}
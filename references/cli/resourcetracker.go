@@ -0,0 +1,282 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/resourcetracker"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+const (
+	// FlagPruneMissing the flag for pruning records of missing resources in resourcetracker
+	FlagPruneMissing = "prune-missing"
+	// FlagRemoveFinalizer the flag for force removing finalizer of resourcetracker
+	FlagRemoveFinalizer = "remove-finalizer"
+)
+
+// ResourceTrackerCommandGroup the commands for inspecting and repairing resourcetrackers
+func ResourceTrackerCommandGroup(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "resourcetracker",
+		Aliases: []string{"rt"},
+		Short:   "Manage ResourceTrackers",
+		Long:    "Inspect and repair the ResourceTrackers that record the resources dispatched by applications.",
+		Annotations: map[string]string{
+			types.TagCommandType: types.TypeApp,
+		},
+	}
+	cmd.AddCommand(
+		NewResourceTrackerListCommand(c, ioStreams),
+		NewResourceTrackerGetCommand(c, ioStreams),
+		NewResourceTrackerRepairCommand(c, ioStreams),
+	)
+	return cmd
+}
+
+// NewResourceTrackerListCommand list the resourcetrackers of applications
+func NewResourceTrackerListCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list [app-name]",
+		Aliases: []string{"ls"},
+		Short:   "list resourcetrackers",
+		Long:    "list the resourcetrackers of the application, or all applications in the namespace if no application specified.",
+		Example: "# list resourcetrackers of application my-app in namespace default\n" +
+			"> vela resourcetracker list my-app -n default\n" +
+			"# list resourcetrackers of applications in all namespaces\n" +
+			"> vela resourcetracker list -A",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := GetFlagNamespaceOrEnv(cmd, c)
+			if err != nil {
+				return err
+			}
+			if AllNamespace {
+				namespace = ""
+			}
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			rts, err := listResourceTrackers(context.Background(), cli, name, namespace)
+			if err != nil {
+				return err
+			}
+			if len(rts) == 0 {
+				ioStreams.Info("No resourcetrackers found.")
+				return nil
+			}
+			ioStreams.Info(printResourceTrackerList(rts).String())
+			return nil
+		},
+	}
+	addNamespaceAndEnvArg(cmd)
+	cmd.Flags().BoolVarP(&AllNamespace, "all-namespaces", "A", false, "If true, check the specified action in all namespaces.")
+	return cmd
+}
+
+// NewResourceTrackerGetCommand show the managed resources recorded in resourcetracker
+func NewResourceTrackerGetCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get <resourcetracker-name>",
+		Aliases: []string{"show"},
+		Short:   "show the managed resources recorded in resourcetracker",
+		Long:    "show the managed resources recorded in resourcetracker and check whether each of them still exists in its cluster.",
+		Example: "> vela resourcetracker get my-app-v1-default",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			rt, err := getResourceTracker(ctx, cli, args[0])
+			if err != nil {
+				return err
+			}
+			states, err := resourcetracker.GetManagedResourceStates(ctx, cli, rt)
+			if err != nil {
+				return err
+			}
+			if len(states) == 0 {
+				ioStreams.Infof("No managed resources recorded in resourcetracker %s.\n", rt.Name)
+				return nil
+			}
+			ioStreams.Info(printManagedResourceStates(states).String())
+			return nil
+		},
+	}
+	return cmd
+}
+
+// NewResourceTrackerRepairCommand repair the resourcetracker that is out of sync with clusters
+func NewResourceTrackerRepairCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repair <resourcetracker-name>",
+		Short: "repair resourcetracker",
+		Long: "repair the resourcetracker that is out of sync with clusters, by pruning the records of resources no longer exist " +
+			"or force removing the finalizer of the resourcetracker. Force removing the finalizer will skip recycling the resources " +
+			"recorded in the resourcetracker.",
+		Example: "# prune the records of resources that have been deleted manually\n" +
+			"> vela resourcetracker repair my-app-v1-default --prune-missing\n" +
+			"# force remove the finalizer of the resourcetracker stuck in deleting\n" +
+			"> vela resourcetracker repair my-app-v1-default --remove-finalizer",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pruneMissing, err := cmd.Flags().GetBool(FlagPruneMissing)
+			if err != nil {
+				return err
+			}
+			removeFinalizer, err := cmd.Flags().GetBool(FlagRemoveFinalizer)
+			if err != nil {
+				return err
+			}
+			if !pruneMissing && !removeFinalizer {
+				return fmt.Errorf("at least one of --%s and --%s should be set", FlagPruneMissing, FlagRemoveFinalizer)
+			}
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			return repairResourceTracker(context.Background(), cli, args[0], pruneMissing, removeFinalizer, ioStreams)
+		},
+	}
+	cmd.Flags().BoolP(FlagPruneMissing, "", false, "If true, remove the records of resources that no longer exist in clusters from the resourcetracker.")
+	cmd.Flags().BoolP(FlagRemoveFinalizer, "", false, "If true, force remove the finalizer of the resourcetracker without recycling its resources.")
+	return cmd
+}
+
+func getResourceTracker(ctx context.Context, cli client.Client, name string) (*v1beta1.ResourceTracker, error) {
+	rt := &v1beta1.ResourceTracker{}
+	if err := cli.Get(ctx, apitypes.NamespacedName{Name: name}, rt); err != nil {
+		return nil, errors.Wrapf(err, "failed to get resourcetracker %s", name)
+	}
+	return rt, nil
+}
+
+func listResourceTrackers(ctx context.Context, cli client.Client, appName string, appNamespace string) ([]v1beta1.ResourceTracker, error) {
+	labels := client.MatchingLabels{}
+	if appName != "" {
+		labels[oam.LabelAppName] = appName
+	}
+	if appNamespace != "" {
+		labels[oam.LabelAppNamespace] = appNamespace
+	}
+	rts := &v1beta1.ResourceTrackerList{}
+	if err := cli.List(ctx, rts, labels); err != nil {
+		return nil, errors.Wrapf(err, "failed to list resourcetrackers")
+	}
+	return rts.Items, nil
+}
+
+func printResourceTrackerList(rts []v1beta1.ResourceTracker) *uitable.Table {
+	table := newUITable().AddRow("NAME", "APP", "TYPE", "GENERATION", "RESOURCES", "DELETED", "COMPRESSION", "STATUS")
+	for _, rt := range rts {
+		var app, rtType, compression, status string
+		if labels := rt.GetLabels(); labels != nil {
+			app = labels[oam.LabelAppNamespace] + "/" + labels[oam.LabelAppName]
+		}
+		rtType = string(rt.Spec.Type)
+		if rtType == "" {
+			rtType = "legacy"
+		}
+		compression = string(rt.Spec.Compression.Type)
+		if compression == "" {
+			compression = "none"
+		}
+		status = "Active"
+		if rt.GetDeletionTimestamp() != nil {
+			status = "Deleting"
+		}
+		deleted := 0
+		for _, mr := range rt.Spec.ManagedResources {
+			if mr.Deleted {
+				deleted++
+			}
+		}
+		table.AddRow(rt.Name, app, rtType, rt.Spec.ApplicationGeneration, len(rt.Spec.ManagedResources), deleted, compression, status)
+	}
+	return table
+}
+
+func printManagedResourceStates(states []resourcetracker.ManagedResourceState) *uitable.Table {
+	table := newUITable().AddRow("CLUSTER", "NAMESPACE", "KIND", "NAME", "COMPONENT", "DELETED", "EXISTS")
+	for _, state := range states {
+		cluster := state.Cluster
+		if cluster == "" {
+			cluster = multicluster.ClusterLocalName
+		}
+		namespace := state.Namespace
+		if namespace == "" {
+			namespace = "-"
+		}
+		component := state.Component
+		if component == "" {
+			component = "-"
+		}
+		table.AddRow(cluster, namespace, state.Kind, state.Name, component, state.Deleted, state.Exists)
+	}
+	return table
+}
+
+func repairResourceTracker(ctx context.Context, cli client.Client, name string, pruneMissing bool, removeFinalizer bool, ioStreams cmdutil.IOStreams) error {
+	rt, err := getResourceTracker(ctx, cli, name)
+	if err != nil {
+		return err
+	}
+	if pruneMissing {
+		pruned, err := resourcetracker.PruneMissingManagedResources(ctx, cli, rt)
+		if err != nil {
+			return err
+		}
+		for _, mr := range pruned {
+			ioStreams.Infof("Record of %s pruned from resourcetracker %s.\n", mr.DisplayName(), rt.Name)
+		}
+		if len(pruned) == 0 {
+			ioStreams.Infof("No missing resources found in resourcetracker %s.\n", rt.Name)
+		}
+	}
+	if removeFinalizer {
+		removed, err := resourcetracker.RemoveResourceTrackerFinalizer(ctx, cli, rt)
+		if err != nil {
+			return err
+		}
+		if removed {
+			ioStreams.Infof("Finalizer of resourcetracker %s removed.\n", rt.Name)
+		} else {
+			ioStreams.Infof("No finalizer found in resourcetracker %s.\n", rt.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/resourcetracker"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+func TestResourceTrackerCommands(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	newRT := func(name string, app string, namespace string, rtType v1beta1.ResourceTrackerType, mrs ...string) *v1beta1.ResourceTracker {
+		rt := &v1beta1.ResourceTracker{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Labels:     map[string]string{oam.LabelAppName: app, oam.LabelAppNamespace: namespace},
+				Finalizers: []string{resourcetracker.Finalizer},
+			},
+			Spec: v1beta1.ResourceTrackerSpec{Type: rtType, ApplicationGeneration: 1},
+		}
+		for _, mr := range mrs {
+			rt.Spec.ManagedResources = append(rt.Spec.ManagedResources, v1beta1.ManagedResource{
+				ClusterObjectReference: common.ClusterObjectReference{ObjectReference: corev1.ObjectReference{
+					APIVersion: "v1", Kind: "ConfigMap", Name: mr, Namespace: namespace,
+				}},
+			})
+		}
+		return rt
+	}
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(
+		newRT("app-v1-default", "app", "default", v1beta1.ResourceTrackerTypeVersioned, "exists", "missing"),
+		newRT("app-default", "app", "default", v1beta1.ResourceTrackerTypeRoot),
+		newRT("other-v1-test", "other", "test", ""),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "exists", Namespace: "default"}},
+	).Build()
+
+	rts, err := listResourceTrackers(ctx, cli, "app", "default")
+	r.NoError(err)
+	r.Equal(2, len(rts))
+	rts, err = listResourceTrackers(ctx, cli, "", "")
+	r.NoError(err)
+	r.Equal(3, len(rts))
+	table := printResourceTrackerList(rts).String()
+	r.Contains(table, "legacy")
+	r.Contains(table, "test/other")
+
+	buffer := bytes.NewBuffer(nil)
+	ioStreams := cmdutil.IOStreams{Out: buffer}
+	r.NoError(repairResourceTracker(ctx, cli, "app-v1-default", true, true, ioStreams))
+	r.Contains(buffer.String(), "Record of ConfigMap missing (Namespace: default) pruned from resourcetracker app-v1-default.")
+	r.Contains(buffer.String(), "Finalizer of resourcetracker app-v1-default removed.")
+	rt := &v1beta1.ResourceTracker{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Name: "app-v1-default"}, rt))
+	r.Equal(1, len(rt.Spec.ManagedResources))
+	r.Empty(rt.GetFinalizers())
+	r.Error(repairResourceTracker(ctx, cli, "not-exist", true, false, ioStreams))
+}
@@ -28,7 +28,9 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/xlab/treeprint"
 	"golang.org/x/term"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	pkgtypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,6 +49,7 @@ import (
 	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
 	types2 "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
 	"github.com/oam-dev/kubevela/references/appfile"
+	velacommon "github.com/oam-dev/kubevela/references/common"
 )
 
 // HealthStatus represents health status strings.
@@ -108,6 +111,9 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
   # Show detailed info in tree
   vela status first-vela-app --tree --detail --detail-format list
 
+  # Show the dependency graph declared by app-dependency policy
+  vela status first-vela-app --deps
+
   # Show pod list
   vela status first-vela-app --pod
   vela status first-vela-app --pod --component express-server --cluster local
@@ -135,6 +141,9 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
 			if printTree, err := cmd.Flags().GetBool("tree"); err == nil && printTree {
 				return printApplicationTree(c, cmd, appName, namespace)
 			}
+			if printDeps, err := cmd.Flags().GetBool("deps"); err == nil && printDeps {
+				return printApplicationDependencies(ctx, c, cmd.OutOrStdout(), appName, namespace)
+			}
 			if printPod, err := cmd.Flags().GetBool("pod"); err == nil && printPod {
 				component, _ := cmd.Flags().GetString("component")
 				cluster, _ := cmd.Flags().GetString("cluster")
@@ -174,6 +183,7 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
 	cmd.Flags().StringP("cluster", "", "", "filter the endpoints or pods by cluster name")
 	cmd.Flags().BoolP("tree", "t", false, "display the application resources into tree structure")
 	cmd.Flags().BoolP("pod", "", false, "show pod list of the application")
+	cmd.Flags().BoolP("deps", "", false, "display the applications that the application depends on and the applications depending on it")
 	cmd.Flags().BoolP("detail", "d", false, "display the realtime details of application resources, must be used with --tree")
 	cmd.Flags().StringP("detail-format", "", "inline", "the format for displaying details, must be used with --detail. Can be one of inline, wide, list, table, raw.")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "raw Application output format. One of: (json, yaml, jsonpath)")
@@ -294,6 +304,7 @@ func loopCheckStatus(c client.Client, ioStreams cmdutil.IOStreams, appName strin
 	if err != nil {
 		return err
 	}
+	printClusterStatusMatrix(ioStreams, remoteApp)
 	if len(remoteApp.Status.Services) > 0 {
 		ioStreams.Infof("Services:\n\n")
 	}
@@ -349,6 +360,26 @@ func loopCheckStatus(c client.Client, ioStreams cmdutil.IOStreams, appName strin
 	return nil
 }
 
+// printClusterStatusMatrix prints the health of the components in each cluster if the application is deployed into
+// multiple clusters
+func printClusterStatusMatrix(ioStreams cmdutil.IOStreams, app *v1beta1.Application) {
+	summary := app.Status.Summary
+	if summary == nil || summary.Clusters <= 1 {
+		return
+	}
+	ioStreams.Infof("Clusters: %d/%d healthy, Components: %d/%d healthy\n\n",
+		summary.HealthyClusters, summary.Clusters, summary.HealthyComponents, summary.Components)
+	table := newUITable()
+	for _, row := range velacommon.BuildClusterStatusMatrix(app.Status.Services) {
+		cells := make([]interface{}, len(row))
+		for i := range row {
+			cells[i] = row[i]
+		}
+		table.AddRow(cells...)
+	}
+	ioStreams.Infof("%s\n\n", table.String())
+}
+
 func printTrackingDeployStatus(c common.Args, ioStreams cmdutil.IOStreams, appName string, namespace string) (CompStatus, error) {
 	sDeploy := newTrackingSpinnerWithDelay("Checking Status ...", trackingInterval)
 	sDeploy.Start()
@@ -466,7 +497,7 @@ func printApplicationTree(c common.Args, cmd *cobra.Command, appName string, app
 	var placements []v1alpha1.PlacementDecision
 	af, err := pkgappfile.NewApplicationParser(cli, dm, pd).GenerateAppFile(context.Background(), app)
 	if err == nil {
-		placements, _ = policy.GetPlacementsFromTopologyPolicies(context.Background(), cli, app.GetNamespace(), af.Policies, true)
+		placements, _ = policy.GetPlacementsFromTopologyPolicies(policy.ContextWithScheduledClusters(context.Background(), app), cli, app.GetNamespace(), af.Policies, true)
 	}
 	format, _ := cmd.Flags().GetString("detail-format")
 	var maxWidth *int
@@ -486,6 +517,80 @@ func printApplicationTree(c common.Args, cmd *cobra.Command, appName string, app
 	return nil
 }
 
+func printApplicationDependencies(ctx context.Context, c common.Args, out io.Writer, appName string, appNs string) error {
+	cli, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+	app, err := loadRemoteApplication(cli, appNs, appName)
+	if err != nil {
+		return err
+	}
+	tree, err := buildApplicationDependencyTree(ctx, cli, app)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write([]byte(tree))
+	return err
+}
+
+// buildApplicationDependencyTree build the tree of the applications that the application depends on recursively and
+// the applications directly depending on it
+func buildApplicationDependencyTree(ctx context.Context, cli client.Reader, app *v1beta1.Application) (string, error) {
+	describe := func(key pkgtypes.NamespacedName, app *v1beta1.Application) string {
+		if app == nil {
+			return fmt.Sprintf("%s (not found)", key)
+		}
+		health := "healthy"
+		if !policy.IsAppHealthy(app) {
+			health = "unhealthy"
+		}
+		return fmt.Sprintf("%s (%s, %s)", key, app.Status.Phase, health)
+	}
+	tree := treeprint.New()
+	tree.SetValue(describe(pkgtypes.NamespacedName{Namespace: app.Namespace, Name: app.Name}, app))
+	var addDependencies func(branch treeprint.Tree, app *v1beta1.Application, visited map[pkgtypes.NamespacedName]bool) error
+	addDependencies = func(branch treeprint.Tree, app *v1beta1.Application, visited map[pkgtypes.NamespacedName]bool) error {
+		deps, err := policy.GetAppDependencies(app)
+		if err != nil {
+			return err
+		}
+		for _, dep := range deps {
+			if visited[dep] {
+				branch.AddMetaNode("DependsOn", fmt.Sprintf("%s (circular dependency)", dep))
+				continue
+			}
+			depApp := &v1beta1.Application{}
+			if err = cli.Get(ctx, dep, depApp); err != nil {
+				if !kerrors.IsNotFound(err) {
+					return err
+				}
+				branch.AddMetaNode("DependsOn", describe(dep, nil))
+				continue
+			}
+			visited[dep] = true
+			if err = addDependencies(branch.AddMetaBranch("DependsOn", describe(dep, depApp)), depApp, visited); err != nil {
+				return err
+			}
+			delete(visited, dep)
+		}
+		return nil
+	}
+	visited := map[pkgtypes.NamespacedName]bool{{Namespace: app.Namespace, Name: app.Name}: true}
+	if err := addDependencies(tree, app, visited); err != nil {
+		return "", err
+	}
+	dependents, err := policy.ListDependentApplications(ctx, cli, app)
+	if err != nil {
+		return "", err
+	}
+	for i := range dependents {
+		dependent := dependents[i]
+		tree.AddMetaNode("DependedBy", describe(pkgtypes.NamespacedName{Namespace: dependent.Namespace, Name: dependent.Name}, &dependent))
+	}
+	return tree.String(), nil
+}
+
 // printRawApplication prints raw Application in yaml/json/jsonpath (without managedFields).
 func printRawApplication(ctx context.Context, c common.Args, format string, out io.Writer, ns, appName string) error {
 	var err error
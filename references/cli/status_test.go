@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestBuildApplicationDependencyTree(t *testing.T) {
+	r := require.New(t)
+	newApp := func(name string, phase common.ApplicationPhase, deps string) *v1beta1.Application {
+		app := &v1beta1.Application{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Status:     common.AppStatus{Phase: phase},
+		}
+		if deps != "" {
+			app.Spec.Policies = []v1beta1.AppPolicy{{
+				Name:       "deps",
+				Type:       "app-dependency",
+				Properties: &runtime.RawExtension{Raw: []byte(`{"applications":` + deps + `}`)},
+			}}
+		}
+		return app
+	}
+	app := newApp("backend", common.ApplicationRunning, `[{"name":"db"},{"name":"cache"}]`)
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(
+		app,
+		newApp("db", common.ApplicationRunningWorkflow, `[{"name":"backend"}]`),
+		newApp("frontend", common.ApplicationStarting, `[{"name":"backend"}]`),
+	).Build()
+	tree, err := buildApplicationDependencyTree(context.Background(), cli, app)
+	r.NoError(err)
+	r.Equal(`default/backend (running, healthy)
+├── [DependsOn]  default/db (runningWorkflow, unhealthy)
+│   └── [DependsOn]  default/backend (circular dependency)
+├── [DependsOn]  default/cache (not found)
+├── [DependedBy]  default/db (runningWorkflow, unhealthy)
+└── [DependedBy]  default/frontend (starting, unhealthy)
+`, tree)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	velacommon "github.com/oam-dev/kubevela/references/common"
+)
+
+// ComponentStatusList is the cluster × component status matrix of the application
+type ComponentStatusList struct {
+	title []string
+	data  [][]string
+}
+
+// Header generate header of table in component status view
+func (l *ComponentStatusList) Header() []string {
+	return l.title
+}
+
+// Body generate body of table in component status view
+func (l *ComponentStatusList) Body() [][]string {
+	return l.data
+}
+
+// ListComponentStatus list the status of the application components in each cluster
+func ListComponentStatus(ctx context.Context, c client.Client) *ComponentStatusList {
+	list := &ComponentStatusList{title: []string{"Cluster"}}
+	name := ctx.Value(&CtxKeyAppName).(string)
+	ns := ctx.Value(&CtxKeyNamespace).(string)
+	app, err := LoadApplication(c, name, ns)
+	if err != nil {
+		return list
+	}
+	return newComponentStatusList(app.Status.Services)
+}
+
+func newComponentStatusList(services []apicommon.ApplicationComponentStatus) *ComponentStatusList {
+	matrix := velacommon.BuildClusterStatusMatrix(services)
+	list := &ComponentStatusList{title: append([]string{"Cluster"}, matrix[0][1:]...)}
+	list.data = matrix[1:]
+	return list
+}
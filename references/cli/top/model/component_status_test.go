@@ -0,0 +1,34 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+)
+
+func TestComponentStatusList(t *testing.T) {
+	list := newComponentStatusList([]common.ApplicationComponentStatus{
+		{Name: "web", Healthy: true},
+		{Name: "web", Cluster: "cluster-a", Healthy: false, Replicas: &common.WorkloadReplicas{Ready: 1, Desired: 3}},
+	})
+	assert.Equal(t, []string{"Cluster", "web"}, list.Header())
+	assert.Equal(t, [][]string{{"local", "healthy"}, {"cluster-a", "unhealthy 1/3"}}, list.Body())
+}
@@ -104,6 +104,7 @@ func (v *ApplicationView) bindKeys() {
 	v.Actions().Add(model.KeyActions{
 		tcell.KeyEnter:    model.KeyAction{Description: "Goto", Action: v.k8sObjectView, Visible: true, Shared: true},
 		component.KeyN:    model.KeyAction{Description: "Select Namespace", Action: v.namespaceView, Visible: true, Shared: true},
+		component.KeyS:    model.KeyAction{Description: "Component Status", Action: v.componentStatusView, Visible: true, Shared: true},
 		tcell.KeyESC:      model.KeyAction{Description: "Back", Action: v.app.Back, Visible: true, Shared: true},
 		component.KeyHelp: model.KeyAction{Description: "Help", Action: v.app.helpView, Visible: true, Shared: true},
 	})
@@ -123,6 +124,20 @@ func (v *ApplicationView) k8sObjectView(event *tcell.EventKey) *tcell.EventKey {
 	return event
 }
 
+func (v *ApplicationView) componentStatusView(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	name, namespace := v.GetCell(row, 0).Text, v.GetCell(row, 1).Text
+
+	v.ctx = context.WithValue(v.ctx, &model.CtxKeyAppName, name)
+	v.ctx = context.WithValue(v.ctx, &model.CtxKeyNamespace, namespace)
+
+	v.app.command.run(v.ctx, "status")
+	return event
+}
+
 func (v *ApplicationView) namespaceView(event *tcell.EventKey) *tcell.EventKey {
 	v.app.content.Clear()
 	v.app.command.run(v.ctx, "ns")
@@ -70,7 +70,7 @@ func TestApplicationView(t *testing.T) {
 	})
 
 	t.Run("hint", func(t *testing.T) {
-		assert.Equal(t, len(appView.Hint()), 4)
+		assert.Equal(t, len(appView.Hint()), 5)
 	})
 
 	t.Run("object view", func(t *testing.T) {
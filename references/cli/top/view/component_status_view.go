@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/oam-dev/kubevela/references/cli/top/component"
+	"github.com/oam-dev/kubevela/references/cli/top/config"
+	"github.com/oam-dev/kubevela/references/cli/top/model"
+)
+
+// ComponentStatusView is the component status view, this view display the status of components in each cluster
+type ComponentStatusView struct {
+	*ResourceView
+	ctx context.Context
+}
+
+// NewComponentStatusView return a new component status view
+func NewComponentStatusView(ctx context.Context, app *App) model.Component {
+	v := &ComponentStatusView{
+		ResourceView: NewResourceView(app),
+		ctx:          ctx,
+	}
+	return v
+}
+
+// Init component status view init
+func (v *ComponentStatusView) Init() {
+	title := fmt.Sprintf("[ %s ]", v.Name())
+	v.SetTitle(title).SetTitleColor(config.ResourceTableTitleColor)
+
+	resourceList := v.ListComponentStatus()
+	v.ResourceView.Init(resourceList)
+	v.ColorizeStatusText(len(resourceList.Body()), len(resourceList.Header()))
+	v.bindKeys()
+}
+
+// ListComponentStatus list the status of the components in each cluster
+func (v *ComponentStatusView) ListComponentStatus() model.ResourceList {
+	return model.ListComponentStatus(v.ctx, v.app.client)
+}
+
+// ColorizeStatusText colorize the status cells
+func (v *ComponentStatusView) ColorizeStatusText(rowNum int, colNum int) {
+	for i := 1; i < rowNum+1; i++ {
+		for j := 1; j < colNum; j++ {
+			status := v.Table.GetCell(i, j).Text
+			switch {
+			case strings.HasPrefix(status, "healthy"):
+				status = config.ObjectHealthyStatusColor + status
+			case strings.HasPrefix(status, "unhealthy"):
+				status = config.ObjectUnhealthyStatusColor + status
+			default:
+			}
+			v.Table.GetCell(i, j).SetText(status)
+		}
+	}
+}
+
+// Name return component status view name
+func (v *ComponentStatusView) Name() string {
+	return "Component Status"
+}
+
+// Hint return key action menu hints of the component status view
+func (v *ComponentStatusView) Hint() []model.MenuHint {
+	return v.Actions().Hint()
+}
+
+func (v *ComponentStatusView) bindKeys() {
+	v.Actions().Delete([]tcell.Key{tcell.KeyEnter})
+	v.Actions().Add(model.KeyActions{
+		tcell.KeyESC:      model.KeyAction{Description: "Back", Action: v.app.Back, Visible: true, Shared: true},
+		component.KeyHelp: model.KeyAction{Description: "Help", Action: v.app.helpView, Visible: true, Shared: true},
+	})
+}
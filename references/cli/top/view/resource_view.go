@@ -53,6 +53,9 @@ var ResourceMap = map[string]ResourceViewer{
 	"cns": {
 		viewFunc: NewClusterNamespaceView,
 	},
+	"status": {
+		viewFunc: NewComponentStatusView,
+	},
 }
 
 // NewResourceView return a new resource view
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"k8s.io/utils/strings/slices"
+
+	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+)
+
+// BuildClusterStatusMatrix builds the cluster × component matrix from the services of the application. The first row
+// is the header, each following row records the status of the components in one cluster, the components deployed into
+// multiple namespaces of one cluster are aggregated.
+func BuildClusterStatusMatrix(services []apicommon.ApplicationComponentStatus) [][]string {
+	type cell struct {
+		healthy  bool
+		replicas *apicommon.WorkloadReplicas
+	}
+	var clusters, components []string
+	cells := map[string]map[string]*cell{}
+	for _, svc := range services {
+		cluster := svc.Cluster
+		if cluster == "" {
+			cluster = multicluster.ClusterLocalName
+		}
+		if svc.Env != "" {
+			cluster = fmt.Sprintf("%s (%s)", cluster, svc.Env)
+		}
+		if _, found := cells[cluster]; !found {
+			cells[cluster] = map[string]*cell{}
+			clusters = append(clusters, cluster)
+		}
+		if !slices.Contains(components, svc.Name) {
+			components = append(components, svc.Name)
+		}
+		healthy := svc.Healthy
+		for _, tr := range svc.Traits {
+			healthy = healthy && tr.Healthy
+		}
+		c, found := cells[cluster][svc.Name]
+		if !found {
+			c = &cell{healthy: true}
+			cells[cluster][svc.Name] = c
+		}
+		c.healthy = c.healthy && healthy
+		if svc.Replicas != nil {
+			if c.replicas == nil {
+				c.replicas = &apicommon.WorkloadReplicas{}
+			}
+			c.replicas.Ready += svc.Replicas.Ready
+			c.replicas.Desired += svc.Replicas.Desired
+		}
+	}
+	matrix := [][]string{append([]string{"CLUSTER"}, components...)}
+	for _, cluster := range clusters {
+		row := []string{cluster}
+		for _, component := range components {
+			c, found := cells[cluster][component]
+			if !found {
+				row = append(row, "-")
+				continue
+			}
+			status := "healthy"
+			if !c.healthy {
+				status = "unhealthy"
+			}
+			if c.replicas != nil {
+				status += fmt.Sprintf(" %d/%d", c.replicas.Ready, c.replicas.Desired)
+			}
+			row = append(row, status)
+		}
+		matrix = append(matrix, row)
+	}
+	return matrix
+}
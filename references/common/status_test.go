@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+)
+
+func TestBuildClusterStatusMatrix(t *testing.T) {
+	r := require.New(t)
+	r.Equal([][]string{{"CLUSTER"}}, BuildClusterStatusMatrix(nil))
+	matrix := BuildClusterStatusMatrix([]apicommon.ApplicationComponentStatus{
+		{Name: "web", Healthy: true, Replicas: &apicommon.WorkloadReplicas{Ready: 2, Desired: 2}},
+		{Name: "db", Healthy: true},
+		{Name: "web", Cluster: "cluster-a", Env: "prod", Namespace: "ns-1", Healthy: true, Replicas: &apicommon.WorkloadReplicas{Ready: 1, Desired: 1}},
+		{Name: "web", Cluster: "cluster-a", Env: "prod", Namespace: "ns-2", Healthy: false, Replicas: &apicommon.WorkloadReplicas{Ready: 0, Desired: 2}},
+		{Name: "db", Cluster: "cluster-b", Healthy: true, Traits: []apicommon.ApplicationTraitStatus{{Type: "scaler", Healthy: false}}},
+	})
+	r.Equal([][]string{
+		{"CLUSTER", "web", "db"},
+		{"local", "healthy 2/2", "healthy"},
+		{"cluster-a (prod)", "unhealthy 1/3", "-"},
+		{"cluster-b", "-", "unhealthy"},
+	}, matrix)
+}
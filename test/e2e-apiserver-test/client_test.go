@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e_apiserver_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/client"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
+)
+
+var _ = Describe("Test the typed client of the apiserver", func() {
+	var (
+		ctx                                         context.Context
+		c                                           *client.Client
+		projectName, targetName, envName, clientApp string
+	)
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = client.New(baseDomain, client.WithToken(strings.TrimPrefix(token, "Bearer ")))
+		suffix := strconv.FormatInt(time.Now().UnixNano(), 10)
+		projectName = "client-project-" + suffix
+		targetName = testNSprefix + "client-" + suffix
+		envName = "client-env-" + suffix
+		clientApp = "client-app-" + suffix
+	})
+
+	It("Test the OpenAPI 3 document", func() {
+		doc, err := c.OpenAPI(ctx)
+		Expect(err).Should(BeNil())
+		Expect(doc["openapi"]).Should(HavePrefix("3."))
+		Expect(doc["paths"]).Should(HaveKey("/api/v1/applications/{appName}/workflows/{workflowName}"))
+	})
+
+	It("Test managing the projects, targets, envs, applications and workflows", func() {
+		defer GinkgoRecover()
+		By("create the project")
+		project, err := c.CreateProject(ctx, apisv1.CreateProjectRequest{Name: projectName, Description: "created by the client"})
+		Expect(err).Should(BeNil())
+		Expect(project.Name).Should(Equal(projectName))
+		project, err = c.UpdateProject(ctx, projectName, apisv1.UpdateProjectRequest{Alias: "client", Description: "updated by the client"})
+		Expect(err).Should(BeNil())
+		Expect(project.Description).Should(Equal("updated by the client"))
+
+		By("create the target and the env")
+		_, err = c.CreateTarget(ctx, apisv1.CreateTargetRequest{
+			Name:    targetName,
+			Project: projectName,
+			Cluster: &apisv1.ClusterTarget{ClusterName: "local", Namespace: targetName},
+		})
+		Expect(err).Should(BeNil())
+		targets, err := c.ListProjectTargets(ctx, projectName)
+		Expect(err).Should(BeNil())
+		Expect(targets.Targets).Should(HaveLen(1))
+		_, err = c.CreateEnv(ctx, apisv1.CreateEnvRequest{Name: envName, Project: projectName, Namespace: targetName, Targets: []string{targetName}})
+		Expect(err).Should(BeNil())
+		envs, err := c.ListEnvs(ctx, projectName, client.ListOptions{})
+		Expect(err).Should(BeNil())
+		Expect(envs.Total).Should(Equal(int64(1)))
+
+		By("create the application bound to the env")
+		_, err = c.CreateApplication(ctx, apisv1.CreateApplicationRequest{
+			Name:       clientApp,
+			Project:    projectName,
+			EnvBinding: []*apisv1.EnvBinding{{Name: envName}},
+			Component: &apisv1.CreateComponentRequest{
+				Name:          "web",
+				ComponentType: "webservice",
+				Properties:    `{"image":"nginx"}`,
+			},
+		})
+		Expect(err).Should(BeNil())
+		apps, err := c.ListApplications(ctx, client.ListApplicationOptions{Project: projectName})
+		Expect(err).Should(BeNil())
+		Expect(apps.Applications).Should(HaveLen(1))
+		components, err := c.ListApplicationComponents(ctx, clientApp, "")
+		Expect(err).Should(BeNil())
+		Expect(components.Components).Should(HaveLen(1))
+		appEnvs, err := c.ListApplicationEnvs(ctx, clientApp)
+		Expect(err).Should(BeNil())
+		Expect(appEnvs.EnvBindings).Should(HaveLen(1))
+
+		By("list the workflow of the env")
+		workflows, err := c.ListApplicationWorkflows(ctx, clientApp)
+		Expect(err).Should(BeNil())
+		Expect(workflows.Workflows).Should(HaveLen(1))
+		workflow, err := c.GetApplicationWorkflow(ctx, clientApp, workflows.Workflows[0].Name)
+		Expect(err).Should(BeNil())
+		Expect(workflow.EnvName).Should(Equal(envName))
+
+		By("the failures are the business codes")
+		_, err = c.GetProject(ctx, projectName+"-missing")
+		Expect(client.IsCode(err, bcode.ErrProjectIsNotExist)).Should(BeTrue())
+
+		By("delete the resources")
+		Expect(c.DeleteApplication(ctx, clientApp)).Should(Succeed())
+		Expect(c.DeleteEnv(ctx, envName)).Should(Succeed())
+		Expect(c.DeleteTarget(ctx, targetName)).Should(Succeed())
+		Expect(c.DeleteProject(ctx, projectName)).Should(Succeed())
+	})
+
+	It("Test listing the addons", func() {
+		defer GinkgoRecover()
+		addons, err := c.ListEnabledAddons(ctx)
+		Expect(err).Should(BeNil())
+		Expect(addons).ShouldNot(BeNil())
+	})
+})
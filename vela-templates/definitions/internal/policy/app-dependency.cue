@@ -0,0 +1,22 @@
+"app-dependency": {
+	annotations: {}
+	description: "Declare the applications that the application depends on."
+	labels: {}
+	attributes: {}
+	type: "policy"
+}
+
+template: {
+	#AppDependency: {
+		// +usage=Specify the name of the dependency application
+		name: string
+		// +usage=Specify the namespace of the dependency application, it must be the namespace of the current application if set
+		namespace?: string
+	}
+
+	parameter: {
+		// +usage=Specify the applications that the application depends on. The workflow will not start until all of them are healthy,
+		// and the application cannot be deleted while other applications depend on it.
+		applications: [...#AppDependency]
+	}
+}
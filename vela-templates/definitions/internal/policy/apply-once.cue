@@ -39,5 +39,7 @@ template: {
 		enable: *false | bool
 		// +usage=Specify the rules for configuring apply-once policy in resource level
 		rules?: [...#ApplyOncePolicyRule]
+		// +usage=If is set, configuration drift will not be corrected but only be detected and reported in application status, events and metrics
+		detectOnly: *false | bool
 	}
 }
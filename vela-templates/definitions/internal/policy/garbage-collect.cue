@@ -29,10 +29,23 @@ template: {
 		resourceNames?: [...string]
 	}
 
+	#GarbageCollectOrderGroup: {
+		// +usage=Specify the name of the group
+		name?: string
+		// +usage=Specify how to select the resources in the group
+		selector?: #ResourcePolicyRuleSelector
+		// +usage=Select the resources in the group by their labels
+		labels?: [string]: string
+	}
+
 	parameter: {
 		// +usage=If is set, outdated versioned resourcetracker will not be recycled automatically, outdated resources will be kept until resourcetracker be deleted manually
 		keepLegacyResource: *false | bool
 		// +usage=Specify the list of rules to control gc strategy at resource level, if one resource is controlled by multiple rules, first rule will be used
 		rules?: [...#GarbageCollectPolicyRule]
+		// +usage=Specify the order of garbage collect, dependency recycles resources in the reverse order of component dependency, groups recycles resources group by group
+		order?: "dependency" | "groups"
+		// +usage=Specify the ordered groups of resources to recycle when order is groups, resources in one group will not be recycled until all resources in the previous groups are gone
+		groups?: [...#GarbageCollectOrderGroup]
 	}
 }
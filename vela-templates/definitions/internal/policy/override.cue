@@ -31,5 +31,12 @@ template: {
 		components: [...#PatchParams]
 		// +usage=Specify a list of component names to use, if empty, all components will be selected.
 		selector?: [...string]
+		// +usage=Specify how to set the replicas scheduled by topology policy into components in each cluster.
+		replicaSplit?: {
+			// +usage=Specify the names of the components to split replicas, if empty, all components will be selected.
+			components?: [...string]
+			// +usage=Specify the type of the trait to set replicas, like scaler. If empty, the replicas will be set in the properties of components.
+			trait?: string
+		}
 	}
 }
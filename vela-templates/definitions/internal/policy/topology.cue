@@ -14,7 +14,45 @@ template: {
 		clusterLabelSelector?: [string]: string
 		// +usage=Deprecated: Use clusterLabelSelector instead.
 		clusterSelector?: [string]: string
+		// +usage=Specify the names of the cluster groups to select.
+		clusterGroups?: [...string]
 		// +usage=Specify the target namespace to deploy in the selected clusters, default inherit the original namespace.
 		namespace?: string
+		// +usage=Specify the standby clusters to replace the unhealthy clusters.
+		failover?: {
+			// +usage=Specify the names of the standby clusters, used in order.
+			standbyClusters?: [...string]
+			// +usage=Specify the label selector for standby clusters.
+			standbyClusterLabelSelector?: [string]: string
+		}
+		// +usage=Specify how to schedule the components onto the selected clusters by their free capacity.
+		scheduling?: {
+			// +usage=Specify the scheduling strategy, spread uses all selected clusters, least-loaded picks the topK clusters with the most free capacity.
+			strategy: *"spread" | "least-loaded"
+			// +usage=Specify the number of clusters to pick in the least-loaded strategy.
+			topK?: int
+			// +usage=Specify the resource to measure the free capacity of clusters.
+			resource: *"cpu" | "memory"
+			// +usage=Specify the number of replicas to split across the scheduled clusters by their weights.
+			replicas?: int
+			// +usage=Specify the affinity rules between components.
+			componentAffinity?: [...{
+				// +usage=Specify the type of the rule, affinity places the components into the same clusters, anti-affinity places them into different clusters.
+				type: "affinity" | "anti-affinity"
+				// +usage=Specify the names of the components.
+				components: [...string]
+			}]
+		}
+		// +usage=Specify the identity to impersonate while dispatching components to the selected clusters.
+		identity?: {
+			// +usage=Specify the user to impersonate.
+			user?: string
+			// +usage=Specify the groups of the user to impersonate.
+			groups?: [...string]
+			// +usage=Specify the service account to impersonate, exclusive to user and groups.
+			serviceAccount?: string
+			// +usage=Specify the namespace of the service account, default to the namespace of the application.
+			serviceAccountNamespace?: string
+		}
 	}
 }